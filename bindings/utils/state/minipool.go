@@ -155,6 +155,14 @@ func GetAllNativeMinipoolDetails(rp *rocketpool.RocketPool, contracts *NetworkCo
 	if err != nil {
 		return nil, fmt.Errorf("error getting minipool addresses: %w", err)
 	}
+	return GetBulkNativeMinipoolDetails(rp, contracts, addresses)
+}
+
+// Gets the details for a set of minipools using the efficient multicall contract
+func GetBulkNativeMinipoolDetails(rp *rocketpool.RocketPool, contracts *NetworkContracts, addresses []common.Address) ([]NativeMinipoolDetails, error) {
+	opts := &bind.CallOpts{
+		BlockNumber: contracts.ElBlockNumber,
+	}
 
 	// Get the list of minipool versions
 	versions, err := getMinipoolVersionsFast(rp, contracts, addresses, opts)
@@ -148,6 +148,14 @@ func GetAllNativeNodeDetails(rp *rocketpool.RocketPool, contracts *NetworkContra
 	if err != nil {
 		return nil, fmt.Errorf("error getting node addresses: %w", err)
 	}
+	return GetBulkNativeNodeDetails(rp, contracts, addresses)
+}
+
+// Gets the details for a set of nodes using the efficient multicall contract
+func GetBulkNativeNodeDetails(rp *rocketpool.RocketPool, contracts *NetworkContracts, addresses []common.Address) ([]NativeNodeDetails, error) {
+	opts := &bind.CallOpts{
+		BlockNumber: contracts.ElBlockNumber,
+	}
 	count := len(addresses)
 	nodeDetails := make([]NativeNodeDetails, count)
 
@@ -0,0 +1,118 @@
+package fleet
+
+import (
+	"github.com/urfave/cli"
+
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+)
+
+// Register commands
+func RegisterCommands(app *cli.App, name string, aliases []string) {
+	app.Commands = append(app.Commands, cli.Command{
+		Name:    name,
+		Aliases: aliases,
+		Usage:   "Manage several nodes from this machine",
+		Subcommands: []cli.Command{
+
+			{
+				Name:      "status",
+				Aliases:   []string{"s"},
+				Usage:     "Get a combined status summary of your nodes",
+				UsageText: "rocketpool fleet status [options]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "nodes, n",
+						Usage: "A comma-separated list of the nodes to include (defaults to all of them)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return getStatus(c)
+
+				},
+			},
+
+			{
+				Name:      "list",
+				Aliases:   []string{"l"},
+				Usage:     "List the nodes you've added",
+				UsageText: "rocketpool fleet list",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return listNodes(c)
+
+				},
+			},
+
+			{
+				Name:      "add",
+				Aliases:   []string{"a"},
+				Usage:     "Add a node so it can be managed with the --node flag",
+				UsageText: "rocketpool fleet add [options] name",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "config-path, c",
+						Usage: "The config folder of a node on this machine",
+					},
+					cli.StringFlag{
+						Name:  "daemon-path, d",
+						Usage: "The path of the service daemon of a native mode node on this machine",
+					},
+					cli.StringFlag{
+						Name:  "api-url, u",
+						Usage: "The URL of a remote node's API server; it must use https unless it's on this machine (e.g. https://192.168.1.10:8280)",
+					},
+					cli.StringFlag{
+						Name:  "api-token-path, t",
+						Usage: "The path of a copy of the remote node's API token",
+					},
+					cli.StringFlag{
+						Name:  "api-ca-cert",
+						Usage: "The path of a CA certificate to trust for the remote node's API server, if its certificate is self-signed",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					return addNode(c, c.Args().Get(0))
+
+				},
+			},
+
+			{
+				Name:      "remove",
+				Aliases:   []string{"r"},
+				Usage:     "Remove a node from the list of managed nodes",
+				UsageText: "rocketpool fleet remove name",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					return removeNode(c, c.Args().Get(0))
+
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,112 @@
+package fleet
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+// Colors
+const (
+	colorReset  string = "\033[0m"
+	colorRed    string = "\033[31m"
+	colorGreen  string = "\033[32m"
+	colorYellow string = "\033[33m"
+)
+
+func addNode(c *cli.Context, name string) error {
+
+	// Build the profile
+	profile := rocketpool.NodeProfile{
+		ConfigPath:    c.String("config-path"),
+		DaemonPath:    c.String("daemon-path"),
+		ApiUrl:        c.String("api-url"),
+		ApiTokenPath:  c.String("api-token-path"),
+		ApiCaCertPath: c.String("api-ca-cert"),
+	}
+	if profile.ConfigPath == "" && profile.ApiUrl == "" {
+		return fmt.Errorf("Please provide either the config folder of a node on this machine or the API server URL of a remote node.")
+	}
+	if profile.ApiUrl != "" {
+		if err := rocketpool.ValidateApiUrl(profile.ApiUrl); err != nil {
+			return err
+		}
+		if profile.ApiTokenPath == "" && profile.ConfigPath == "" {
+			return fmt.Errorf("Please provide the path of the remote node's API token.")
+		}
+	}
+
+	// Save it
+	path, profiles, err := loadProfiles(c)
+	if err != nil {
+		return err
+	}
+	if _, exists := profiles.Nodes[name]; exists {
+		fmt.Printf("%sReplacing the existing node named '%s'.%s\n", colorYellow, name, colorReset)
+	}
+	profiles.Nodes[name] = profile
+	if err := profiles.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("%sAdded node '%s'. You can now manage it with `rocketpool --node %s ...`.%s\n", colorGreen, name, name, colorReset)
+	return nil
+
+}
+
+func removeNode(c *cli.Context, name string) error {
+
+	path, profiles, err := loadProfiles(c)
+	if err != nil {
+		return err
+	}
+	if _, err := profiles.Get(name); err != nil {
+		return err
+	}
+	delete(profiles.Nodes, name)
+	if err := profiles.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed node '%s'.\n", name)
+	return nil
+
+}
+
+func listNodes(c *cli.Context) error {
+
+	_, profiles, err := loadProfiles(c)
+	if err != nil {
+		return err
+	}
+	if len(profiles.Nodes) == 0 {
+		fmt.Println("You haven't added any nodes yet. Use `rocketpool fleet add` to add one.")
+		return nil
+	}
+
+	for _, name := range profiles.Names() {
+		profile := profiles.Nodes[name]
+		if profile.ApiUrl != "" {
+			fmt.Printf("%s: remote node at %s\n", name, profile.ApiUrl)
+		} else {
+			fmt.Printf("%s: local node with config at %s\n", name, profile.ConfigPath)
+		}
+	}
+	return nil
+
+}
+
+// Load the node profiles and the path they're stored at
+func loadProfiles(c *cli.Context) (string, *rocketpool.NodeProfiles, error) {
+	path, err := rocketpool.GetNodeProfilesPath(c.GlobalString("config-path"))
+	if err != nil {
+		return "", nil, err
+	}
+	profiles, err := rocketpool.LoadNodeProfiles(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, profiles, nil
+}
@@ -0,0 +1,179 @@
+package fleet
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/utils/math"
+)
+
+// The summary of a single node
+type nodeSummary struct {
+	name               string
+	address            common.Address
+	minipools          int
+	megapoolValidators uint32
+	bondedEth          *big.Int
+	rplStake           *big.Int
+	pendingRewards     *big.Int
+	activeAlerts       int
+	err                error
+}
+
+func getStatus(c *cli.Context) error {
+
+	// Get the nodes to query
+	_, profiles, err := loadProfiles(c)
+	if err != nil {
+		return err
+	}
+	names := profiles.Names()
+	if nodes := c.String("nodes"); nodes != "" {
+		names = []string{}
+		for name := range strings.SplitSeq(nodes, ",") {
+			name = strings.TrimSpace(name)
+			if _, err := profiles.Get(name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("You haven't added any nodes yet. Use `rocketpool fleet add` to add one.")
+		return nil
+	}
+
+	// Query them all at once
+	summaries := make([]nodeSummary, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			summaries[i] = getNodeSummary(c, name, profiles.Nodes[name])
+		}(i, name)
+	}
+	wg.Wait()
+
+	// Print the table with totals
+	totalBondedEth := big.NewInt(0)
+	totalRplStake := big.NewInt(0)
+	totalPendingRewards := big.NewInt(0)
+	totalMinipools := 0
+	totalMegapoolValidators := uint32(0)
+	failed := 0
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Node\tAddress\tMinipools\tMegapool Validators\tBonded ETH\tRPL Stake\tPending Rewards (ETH)\tAlerts")
+	for _, summary := range summaries {
+		if summary.err != nil {
+			fmt.Fprintf(w, "%s\t%sError: %s%s\t\t\t\t\t\t\n", summary.name, colorRed, summary.err.Error(), colorReset)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.6f\t%.6f\t%.6f\t%d\n",
+			summary.name,
+			summary.address.Hex(),
+			summary.minipools,
+			summary.megapoolValidators,
+			math.RoundDown(eth.WeiToEth(summary.bondedEth), 6),
+			math.RoundDown(eth.WeiToEth(summary.rplStake), 6),
+			math.RoundDown(eth.WeiToEth(summary.pendingRewards), 6),
+			summary.activeAlerts,
+		)
+		totalBondedEth.Add(totalBondedEth, summary.bondedEth)
+		totalRplStake.Add(totalRplStake, summary.rplStake)
+		totalPendingRewards.Add(totalPendingRewards, summary.pendingRewards)
+		totalMinipools += summary.minipools
+		totalMegapoolValidators += summary.megapoolValidators
+	}
+	fmt.Fprintf(w, "Total\t\t%d\t%d\t%.6f\t%.6f\t%.6f\t\n",
+		totalMinipools,
+		totalMegapoolValidators,
+		math.RoundDown(eth.WeiToEth(totalBondedEth), 6),
+		math.RoundDown(eth.WeiToEth(totalRplStake), 6),
+		math.RoundDown(eth.WeiToEth(totalPendingRewards), 6),
+	)
+	w.Flush()
+
+	if failed > 0 {
+		fmt.Printf("\n%s%d of %d node(s) could not be reached and are not included in the totals.%s\n", colorYellow, failed, len(summaries), colorReset)
+	}
+	return nil
+
+}
+
+// Get the status of a single node
+func getNodeSummary(c *cli.Context, name string, profile rocketpool.NodeProfile) nodeSummary {
+	summary := nodeSummary{
+		name:           name,
+		bondedEth:      big.NewInt(0),
+		rplStake:       big.NewInt(0),
+		pendingRewards: big.NewInt(0),
+	}
+
+	rp := rocketpool.NewClientFromProfile(c, profile)
+	defer rp.Close()
+
+	status, err := rp.NodeStatus()
+	if err != nil {
+		summary.err = err
+		return summary
+	}
+	if !status.Registered {
+		summary.err = fmt.Errorf("node %s is not registered", status.AccountAddress.Hex())
+		return summary
+	}
+	summary.address = status.AccountAddress
+
+	// Minipools
+	for _, minipool := range status.Minipools {
+		if minipool.Finalised {
+			continue
+		}
+		summary.minipools++
+		if minipool.Node.DepositBalance != nil {
+			summary.bondedEth.Add(summary.bondedEth, minipool.Node.DepositBalance)
+		}
+	}
+
+	// Megapool
+	if status.MegapoolDeployed {
+		megapoolStatus, err := rp.MegapoolStatus()
+		if err != nil {
+			summary.err = err
+			return summary
+		}
+		megapool := megapoolStatus.Megapool
+		summary.megapoolValidators = megapool.ActiveValidatorCount
+		if megapool.NodeBond != nil {
+			summary.bondedEth.Add(summary.bondedEth, megapool.NodeBond)
+		}
+		if megapool.PendingRewardSplit.NodeRewards != nil {
+			summary.pendingRewards.Add(summary.pendingRewards, megapool.PendingRewardSplit.NodeRewards)
+		}
+	}
+
+	// RPL and rewards
+	if status.RplStake != nil {
+		summary.rplStake.Set(status.RplStake)
+	}
+	if status.UnclaimedRewards != nil {
+		summary.pendingRewards.Add(summary.pendingRewards, status.UnclaimedRewards)
+	}
+	for _, alert := range status.Alerts {
+		if alert.IsActive() {
+			summary.activeAlerts++
+		}
+	}
+	return summary
+}
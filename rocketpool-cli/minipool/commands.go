@@ -110,6 +110,10 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 						Name:  "no-restart",
 						Usage: "Don't restart the Validator Client after importing the key. Note that the key won't be loaded (and won't attest) until you restart the VC to load it.",
 					},
+					cli.StringFlag{
+						Name:  "slashing-protection",
+						Usage: "An EIP-3076 slashing protection file with the signing history of the imported validator key, which will be imported into your validator client",
+					},
 					cli.BoolFlag{
 						Name:  "yes, y",
 						Usage: "Automatically confirm all interactive questions",
@@ -118,6 +118,34 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "verify-rewards-tree",
+				Aliases:   []string{"v"},
+				Usage:     "Verify your local rewards tree file for an interval by rebuilding its Merkle tree and checking your node's rewards against its recorded performance",
+				UsageText: "rocketpool network verify-rewards-tree [options] interval",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "compare, c",
+						Usage: "The path of an independently regenerated rewards tree file for the same interval (e.g. from treegen) to compare against, field by field. It must be in the Smartnode data folder so the daemon can read it.",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					interval, err := cliutils.ValidateUint("interval", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					return verifyRewardsTree(c, interval)
+
+				},
+			},
+
 			{
 				Name:      "dao-proposals",
 				Aliases:   []string{"d"},
@@ -0,0 +1,169 @@
+package network
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+const colorRed string = "\033[31m"
+
+func verifyRewardsTree(c *cli.Context, interval uint64) error {
+
+	// Get RP client
+	rp, err := rocketpool.NewClientFromCtx(c).WithReady()
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	// Verify the tree
+	response, err := rp.VerifyRewardsTree(interval)
+	if err != nil {
+		return err
+	}
+	if !response.TreeFileExists {
+		fmt.Printf("You don't have the rewards tree file for interval %d (%s).\nYou can download it with `rocketpool node claim-rewards` or regenerate it with `rocketpool network generate-rewards-tree`.\n", interval, response.TreeFilePath)
+		return nil
+	}
+	verification := response.Verification
+	valid := true
+
+	// Merkle root
+	fmt.Printf("Canonical Merkle root:  %s\n", response.CanonicalMerkleRoot.Hex())
+	fmt.Printf("Root stored in file:    %s\n", verification.FileMerkleRoot.Hex())
+	fmt.Printf("Root rebuilt from file: %s\n\n", verification.ComputedMerkleRoot.Hex())
+	if verification.ComputedMerkleRoot != response.CanonicalMerkleRoot {
+		fmt.Printf("%sThe rewards in this file do not produce the canonical Merkle root. This file cannot be trusted or used for claiming.%s\n", colorRed, colorReset)
+		valid = false
+	} else if verification.FileMerkleRoot != response.CanonicalMerkleRoot {
+		fmt.Printf("%sThe rewards in this file produce the canonical Merkle root, but the root stored in the file is wrong.%s\n", colorYellow, colorReset)
+		valid = false
+	} else {
+		fmt.Printf("%sThe rewards in this file produce the canonical Merkle root.%s\n", colorGreen, colorReset)
+	}
+
+	// The node's entry
+	fmt.Println()
+	if !verification.NodeIncluded {
+		fmt.Printf("Your node (%s) has no rewards in this interval.\n", response.NodeAddress.Hex())
+	} else {
+		fmt.Printf("Your node (%s) earned:\n", response.NodeAddress.Hex())
+		fmt.Printf("\t%.6f RPL in collateral rewards\n", eth.WeiToEth(response.NodeCollateralRpl))
+		fmt.Printf("\t%.6f RPL in Oracle DAO rewards\n", eth.WeiToEth(response.NodeOracleDaoRpl))
+		fmt.Printf("\t%.6f ETH from the Smoothing Pool\n", eth.WeiToEth(response.NodeSmoothingPoolEth))
+		if verification.NodeProofValid {
+			fmt.Printf("%sThe Merkle proof for your node matches the rebuilt tree.%s\n", colorGreen, colorReset)
+		} else {
+			fmt.Printf("%sThe Merkle proof for your node does not match the rebuilt tree.%s\n", colorRed, colorReset)
+			valid = false
+		}
+	}
+
+	// The node's attestation performance
+	fmt.Println()
+	if !response.PerformanceFileExists {
+		fmt.Printf("There is no local minipool performance file for interval %d, so your node's Smoothing Pool rewards can't be checked against its attestation performance.\n", interval)
+	} else if len(response.Validators) == 0 {
+		fmt.Println("None of your node's validators are in the minipool performance file for this interval.")
+		if response.NodeSmoothingPoolEth.Sign() > 0 {
+			fmt.Printf("%sYour node has Smoothing Pool rewards that aren't backed by any recorded validator performance.%s\n", colorYellow, colorReset)
+			valid = false
+		}
+	} else {
+		fmt.Println("Attestation performance for your validators in the minipool performance file:")
+		for _, validator := range response.Validators {
+			fmt.Printf("\t%s: %d attested, %d missed, %.6f ETH earned\n", validator.Address.Hex(), validator.SuccessfulAttestations, validator.MissedAttestations, eth.WeiToEth(validator.EthEarned))
+		}
+		if response.ValidatorSmoothingPoolEth.Cmp(response.NodeSmoothingPoolEth) == 0 {
+			fmt.Printf("%sYour validators' earnings add up to your node's Smoothing Pool rewards.%s\n", colorGreen, colorReset)
+		} else {
+			difference := big.NewInt(0).Sub(response.NodeSmoothingPoolEth, response.ValidatorSmoothingPoolEth)
+			fmt.Printf("%sYour validators' earnings add up to %s wei, but your node's Smoothing Pool rewards are %s wei (a difference of %s wei).%s\n", colorYellow, response.ValidatorSmoothingPoolEth.String(), response.NodeSmoothingPoolEth.String(), difference.String(), colorReset)
+			valid = false
+		}
+	}
+
+	// The node's own record of its attestations
+	fmt.Println()
+	if !response.AttestationRecordExists {
+		fmt.Printf("Your node has no record of its own attestations during interval %d, so the recorded attestation performance can't be checked independently.\n", interval)
+	} else if len(response.AttestationRecords) == 0 {
+		fmt.Println("Your node's attestation records don't cover any of the validators in the minipool performance file.")
+	} else {
+		fmt.Println("Recorded attestation performance compared with your node's own attestation records:")
+		for _, record := range response.AttestationRecords {
+			fmt.Printf("\t%s: %d epochs checked locally", record.Address.Hex(), record.CheckedEpochs)
+			if len(record.DisputedSlots) > 0 {
+				fmt.Printf(", %s%d missed attestation(s) your node saw included in time (slots %v)%s", colorRed, len(record.DisputedSlots), record.DisputedSlots, colorReset)
+				valid = false
+			}
+			if len(record.UnpenalizedSlots) > 0 {
+				fmt.Printf(", %d missed attestation(s) that weren't penalized", len(record.UnpenalizedSlots))
+			}
+			fmt.Println()
+		}
+	}
+
+	// Compare against an independent regeneration
+	if path := c.String("compare"); path != "" {
+		fmt.Println()
+		match, err := compareRewardsTrees(rp, interval, path, response.NodeAddress.Hex())
+		if err != nil {
+			return err
+		}
+		valid = valid && match
+	}
+
+	fmt.Println()
+	if valid {
+		fmt.Printf("%sThe rewards tree for interval %d passed all checks.%s\n", colorGreen, interval, colorReset)
+	} else {
+		fmt.Printf("%sThe rewards tree for interval %d failed one or more checks.%s\n", colorRed, interval, colorReset)
+	}
+	return nil
+
+}
+
+// Compare the local rewards tree with one regenerated independently (e.g. by treegen), printing every field that differs.
+// The daemon does the comparison, so the regenerated tree has to be in the Smartnode data folder.
+func compareRewardsTrees(rp *rocketpool.Client, interval uint64, path string, nodeAddress string) (bool, error) {
+	cfg, _, err := rp.LoadConfig()
+	if err != nil {
+		return false, fmt.Errorf("Error loading configuration: %w", err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("Error getting the absolute path of %s: %w", path, err)
+	}
+	dataPath := cfg.Smartnode.GetDataFolder(false)
+	relPath, err := filepath.Rel(dataPath, absPath)
+	if err != nil || !filepath.IsLocal(relPath) {
+		return false, fmt.Errorf("%s isn't in the Smartnode data folder (%s); please move it there so the daemon can read it", path, dataPath)
+	}
+
+	response, err := rp.CompareRewardsTree(interval, relPath)
+	if err != nil {
+		return false, err
+	}
+	if len(response.Differences) == 0 {
+		fmt.Printf("%sThe local rewards tree is identical to the one at %s.%s\n", colorGreen, path, colorReset)
+		return true, nil
+	}
+
+	fmt.Printf("%sThe local rewards tree differs from the one at %s in %d field(s):%s\n", colorRed, path, len(response.Differences), colorReset)
+	for _, difference := range response.Differences {
+		marker := ""
+		if strings.Contains(difference.Field, nodeAddress) {
+			marker = " (your node)"
+		}
+		fmt.Printf("\t%s%s: regenerated = %s, local = %s\n", difference.Field, marker, difference.Expected, difference.Actual)
+	}
+	return false, nil
+}
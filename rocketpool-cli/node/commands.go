@@ -103,6 +103,34 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "rewards-history",
+				Usage:     "Export a ledger of the node's rewards, distributions and claims for accounting",
+				UsageText: "rocketpool node rewards-history [options]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "format, f",
+						Usage: "The export format ('csv' or 'json')",
+						Value: "csv",
+					},
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "The file to write the ledger to (defaults to the terminal)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return getRewardsHistory(c)
+
+				},
+			},
+
 			{
 				Name:      "set-primary-withdrawal-address",
 				Aliases:   []string{"w"},
@@ -859,6 +887,51 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "pending-transactions",
+				Aliases:   []string{"pt"},
+				Usage:     "List the node's transactions that haven't been included in a block yet",
+				UsageText: "rocketpool node pending-transactions",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return getPendingTransactions(c)
+
+				},
+			},
+
+			{
+				Name:      "cancel-transaction",
+				Usage:     "Cancel a stuck transaction by replacing it with a 0-value transfer to the node that uses the same nonce",
+				UsageText: "rocketpool node cancel-transaction [-y] nonce",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm the cancellation",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					nonce, err := cliutils.ValidateUint("nonce", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					return cancelTransaction(c, nonce)
+
+				},
+			},
+
 			{
 				Name:      "claim-unclaimed-rewards",
 				Aliases:   []string{"cur"},
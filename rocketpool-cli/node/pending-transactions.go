@@ -0,0 +1,125 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+	"github.com/rocket-pool/smartnode/shared/utils/cli/prompt"
+)
+
+func getPendingTransactions(c *cli.Context) error {
+
+	// Get RP client
+	rp, err := rocketpool.NewClientFromCtx(c).WithReady()
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	// Get the pending transactions
+	response, err := rp.PendingTransactions()
+	if err != nil {
+		return err
+	}
+
+	// Print the untracked count
+	pendingCount := response.PendingNonce - response.LatestNonce
+	if pendingCount == 0 {
+		fmt.Println("The node doesn't have any pending transactions.")
+		return nil
+	}
+	fmt.Printf("The node has %d pending transaction(s) (nonces %d to %d).\n", pendingCount, response.LatestNonce, response.PendingNonce-1)
+	untracked := int(pendingCount) - len(response.Transactions)
+	if untracked > 0 {
+		fmt.Printf("%s%d of them weren't sent by the Smartnode daemons, so their details aren't available.%s\n", colorYellow, untracked, colorReset)
+	}
+	fmt.Println()
+
+	// Print the tracked ones
+	for _, tx := range response.Transactions {
+		fmt.Printf("%sNonce %d%s\n", colorGreen, tx.Nonce, colorReset)
+		fmt.Printf("\tHash:            %s\n", tx.Hash.Hex())
+		if tx.Cancelled {
+			fmt.Printf("\tCancelled:       yes\n")
+		} else {
+			fmt.Printf("\tTo:              %s\n", tx.To.Hex())
+			fmt.Printf("\tValue:           %.6f ETH\n", eth.WeiToEth(tx.Value))
+		}
+		fmt.Printf("\tMax Fee:         %.6f Gwei\n", eth.WeiToGwei(tx.MaxFee))
+		fmt.Printf("\tPriority Fee:    %.6f Gwei\n", eth.WeiToGwei(tx.MaxPriorityFee))
+		fmt.Printf("\tSubmitted:       %s (block %d)\n", tx.SubmittedTime.Format(time.RFC822), tx.SubmittedBlock)
+		fmt.Printf("\tTimes Bumped:    %d\n", tx.Bumps)
+		for _, hash := range tx.PreviousHashes {
+			fmt.Printf("\tReplaced:        %s\n", hash.Hex())
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("You can cancel a stuck transaction with `rocketpool node cancel-transaction <nonce>`.")
+	return nil
+
+}
+
+func cancelTransaction(c *cli.Context, nonce uint64) error {
+
+	// Get RP client
+	rp, err := rocketpool.NewClientFromCtx(c).WithReady()
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	// Check if the transaction can be cancelled
+	canCancel, err := rp.CanCancelTransaction(nonce)
+	if err != nil {
+		return err
+	}
+	if !canCancel.CanCancel {
+		fmt.Printf("Cannot cancel the transaction with nonce %d:\n", nonce)
+		if canCancel.NonceUsed {
+			fmt.Println("The transaction with this nonce has already been included in a block.")
+		}
+		if canCancel.NonceTooNew {
+			fmt.Println("The node doesn't have a pending transaction with this nonce.")
+		}
+		return nil
+	}
+	if canCancel.IsTracked {
+		fmt.Printf("NOTE: to replace the pending transaction, the cancellation will use a max fee of at least %.6f Gwei even if you choose a lower one.\n\n", eth.WeiToGwei(canCancel.MinMaxFee))
+	}
+
+	// Assign max fees
+	err = gas.AssignMaxFeeAndLimit(canCancel.GasInfo, rp, c.Bool("yes"))
+	if err != nil {
+		return err
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || prompt.Confirm(fmt.Sprintf("Are you sure you want to cancel the pending transaction with nonce %d?", nonce))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Cancel it
+	response, err := rp.CancelTransaction(nonce)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cancelling the transaction with nonce %d...\n", nonce)
+	cliutils.PrintTransactionHash(rp, response.TxHash)
+	if _, err = rp.WaitForTransaction(response.TxHash); err != nil {
+		return err
+	}
+
+	// Log & return
+	fmt.Printf("Successfully cancelled the transaction with nonce %d.\n", nonce)
+	return nil
+
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli"
+
+	rprewards "github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+)
+
+// A rewards history entry with amounts converted to ETH / RPL for export
+type rewardsHistoryRecord struct {
+	Time      string `json:"time"`
+	Type      string `json:"type"`
+	Interval  string `json:"interval"`
+	Block     uint64 `json:"block"`
+	TxHash    string `json:"txHash"`
+	Validator string `json:"validator"`
+	Pubkey    string `json:"pubkey"`
+	Rpl       string `json:"rpl"`
+	Eth       string `json:"eth"`
+	RplPrice  string `json:"rplPrice"`
+	EthValue  string `json:"ethValue"`
+}
+
+var rewardsHistoryColumns = []string{"Time", "Type", "Interval", "Block", "Tx Hash", "Validator", "Validator Pubkey", "RPL", "ETH", "RPL Price (ETH)", "Total Value (ETH)"}
+
+func getRewardsHistory(c *cli.Context) error {
+
+	// Check the format
+	format := strings.ToLower(c.String("format"))
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("Invalid format '%s'; please use 'csv' or 'json'.", format)
+	}
+
+	// Get RP client
+	rp, err := rocketpool.NewClientFromCtx(c).WithReady()
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	// Get the history, one page of blocks at a time
+	fmt.Fprintln(os.Stderr, "Building rewards history; this may take a while the first time it's run...")
+	response, err := rp.NodeRewardsHistory()
+	if err != nil {
+		return err
+	}
+	for !response.Complete {
+		fmt.Fprintf(os.Stderr, "Scanned up to block %d of %d...\n", response.LastScannedBlock, response.LatestBlock)
+		response, err = rp.NodeRewardsHistory()
+		if err != nil {
+			return err
+		}
+	}
+	if len(response.MissingIntervals) > 0 {
+		intervals := make([]string, len(response.MissingIntervals))
+		for i, interval := range response.MissingIntervals {
+			intervals[i] = strconv.FormatUint(interval, 10)
+		}
+		fmt.Fprintf(os.Stderr, "%sNOTE: the rewards tree files for intervals %s are not available locally, so their rewards are not included. You can download them by running `rocketpool node claim-rewards` or regenerate them with `rocketpool network generate-rewards-tree`.%s\n", colorYellow, strings.Join(intervals, ", "), colorReset)
+	}
+
+	records := make([]rewardsHistoryRecord, len(response.Entries))
+	for i, entry := range response.Entries {
+		records[i] = getRewardsHistoryRecord(entry)
+	}
+
+	// Write it out
+	var output io.Writer = os.Stdout
+	if path := c.String("output"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %w", path, err)
+		}
+		defer file.Close()
+		output = file
+	}
+	if format == "json" {
+		bytes, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializing rewards history: %w", err)
+		}
+		if _, err := fmt.Fprintln(output, string(bytes)); err != nil {
+			return fmt.Errorf("error writing rewards history: %w", err)
+		}
+	} else {
+		writer := csv.NewWriter(output)
+		if err := writer.Write(rewardsHistoryColumns); err != nil {
+			return fmt.Errorf("error writing rewards history: %w", err)
+		}
+		for _, record := range records {
+			if err := writer.Write([]string{
+				record.Time,
+				record.Type,
+				record.Interval,
+				strconv.FormatUint(record.Block, 10),
+				record.TxHash,
+				record.Validator,
+				record.Pubkey,
+				record.Rpl,
+				record.Eth,
+				record.RplPrice,
+				record.EthValue,
+			}); err != nil {
+				return fmt.Errorf("error writing rewards history: %w", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("error writing rewards history: %w", err)
+		}
+	}
+
+	if path := c.String("output"); path != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d entries to %s.\n", len(records), path)
+	}
+	return nil
+
+}
+
+// Convert a ledger entry into an exportable record
+func getRewardsHistoryRecord(entry rprewards.RewardsLedgerEntry) rewardsHistoryRecord {
+	record := rewardsHistoryRecord{
+		Time:  entry.Time.UTC().Format(time.RFC3339),
+		Type:  string(entry.Type),
+		Block: entry.Block,
+	}
+	if entry.Interval != nil {
+		record.Interval = strconv.FormatUint(*entry.Interval, 10)
+	}
+	if entry.TxHash != nil {
+		record.TxHash = entry.TxHash.Hex()
+	}
+	if entry.Validator != nil {
+		record.Validator = entry.Validator.Hex()
+	}
+
+	if entry.Pubkey != nil {
+		record.Pubkey = entry.Pubkey.Hex()
+	}
+	if entry.EthAmount != nil {
+		record.Eth = formatWei(&entry.EthAmount.Int)
+	}
+	if entry.RplAmount != nil {
+		record.Rpl = formatWei(&entry.RplAmount.Int)
+	}
+	if entry.RplPrice != nil {
+		record.RplPrice = formatWei(&entry.RplPrice.Int)
+	}
+
+	// Value everything in ETH using the RPL price at the time; claims aren't valued since the rewards they claim already were
+	if value := entry.GetEthValue(); value != nil {
+		record.EthValue = formatWei(value)
+	}
+	return record
+}
+
+// Format a wei amount as an exact decimal string in ETH
+func formatWei(wei *big.Int) string {
+	sign := ""
+	if wei.Sign() < 0 {
+		sign = "-"
+	}
+	whole, fraction := big.NewInt(0).QuoRem(big.NewInt(0).Abs(wei), big.NewInt(1e18), big.NewInt(0))
+	if fraction.Sign() == 0 {
+		return sign + whole.String()
+	}
+	fractionString := fraction.String()
+	fractionString = strings.Repeat("0", 18-len(fractionString)) + fractionString
+	fractionString = strings.TrimRight(fractionString, "0")
+	return fmt.Sprintf("%s%s.%s", sign, whole.String(), fractionString)
+}
@@ -72,6 +72,9 @@ func nodeStakeRpl(c *cli.Context) error {
 				if c.GlobalUint64("nonce") != 0 {
 					cliutils.PrintMultiTransactionNonceWarning()
 				}
+				if rp.IsOffline() {
+					cliutils.PrintMultiTransactionOfflineWarning()
+				}
 
 				// Calculate max uint256 value
 				maxApproval := big.NewInt(2)
@@ -257,6 +260,9 @@ func nodeStakeRpl(c *cli.Context) error {
 		if c.GlobalUint64("nonce") != 0 {
 			cliutils.PrintMultiTransactionNonceWarning()
 		}
+		if rp.IsOffline() {
+			cliutils.PrintMultiTransactionOfflineWarning()
+		}
 
 		// Calculate max uint256 value
 		maxApproval := big.NewInt(2)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/rocketpool-cli/auction"
+	"github.com/rocket-pool/smartnode/rocketpool-cli/fleet"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/megapool"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/minipool"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/network"
@@ -20,6 +22,7 @@ import (
 	"github.com/rocket-pool/smartnode/rocketpool-cli/service"
 	"github.com/rocket-pool/smartnode/rocketpool-cli/wallet"
 	"github.com/rocket-pool/smartnode/shared"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
 	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
 )
 
@@ -78,10 +81,18 @@ A special thanks to the Rocket Pool community for all their contributions.
 			Name:  "nonce",
 			Usage: "Use this flag to explicitly specify the nonce that this transaction should use, so it can override an existing 'stuck' transaction",
 		},
+		cli.StringFlag{
+			Name:  "node",
+			Usage: "The name of the node to manage, from the profiles added with 'rocketpool fleet add'",
+		},
 		cli.BoolFlag{
 			Name:  "debug",
 			Usage: "Enable debug printing of API commands",
 		},
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Build transactions without signing or sending them, and export them for signing on an offline machine with 'rocketpool wallet sign-offline'",
+		},
 		cli.BoolFlag{
 			Name: "secure-session, s",
 			Usage: "Some commands may print sensitive information to your terminal. " +
@@ -107,6 +118,7 @@ A special thanks to the Rocket Pool community for all their contributions.
 	pdao.RegisterCommands(app, "pdao", []string{"p"})
 	queue.RegisterCommands(app, "queue", []string{"q"})
 	security.RegisterCommands(app, "security", []string{"c"})
+	fleet.RegisterCommands(app, "fleet", []string{"f"})
 	service.RegisterCommands(app, "service", []string{"s"})
 	wallet.RegisterCommands(app, "wallet", []string{"w"})
 
@@ -131,13 +143,39 @@ A special thanks to the Rocket Pool community for all their contributions.
 			c.App.Metadata["nonce"] = nonce
 		}
 
+		// If set, load the node profile
+		if nodeName := c.GlobalString("node"); nodeName != "" {
+			path, err := rocketpool.GetNodeProfilesPath(c.GlobalString("config-path"))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			profiles, err := rocketpool.LoadNodeProfiles(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			profile, err := profiles.Get(nodeName)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			c.App.Metadata["node-profile"] = profile
+		}
+
 		return nil
 	}
 
 	// Run application
 	fmt.Println("")
 	if err := app.Run(os.Args); err != nil {
-		cliutils.PrettyPrintError(err)
+		if errors.Is(err, rocketpool.ErrTransactionNotSubmitted) {
+			// Anything after an exported transaction depends on it, so the command stops until it's been broadcast
+			fmt.Println("The transaction has been exported but not submitted, so the rest of this command was skipped.")
+			fmt.Println("Once it's been signed, broadcast and included in a block, run the command again to continue.")
+		} else {
+			cliutils.PrettyPrintError(err)
+		}
 	}
 	fmt.Println("")
 
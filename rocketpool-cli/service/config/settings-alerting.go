@@ -8,50 +8,106 @@ import (
 )
 
 var alertingParametersNativeMode map[string]interface{} = map[string]interface{}{
-	"enableAlerting":                           nil,
-	"nativeModeHost":                           nil,
-	"nativeModePort":                           nil,
-	"discordWebhookURL":                        nil,
-	"pushoverToken":                            nil,
-	"pushoverUserKey":                          nil,
-	"alertEnabled_FeeRecipientChanged":         nil,
-	"alertEnabled_MinipoolBondReduced":         nil,
-	"alertEnabled_MinipoolBalanceDistributed":  nil,
-	"alertEnabled_MinipoolPromoted":            nil,
-	"alertEnabled_MinipoolStaked":              nil,
-	"alertEnabled_ExecutionClientSyncComplete": nil,
-	"alertEnabled_BeaconClientSyncComplete":    nil,
-	"alertEnabled_LowETHBalance":               nil,
-	"lowETHBalanceThreshold":                   nil,
+	"enableAlerting":                   nil,
+	"nativeModeHost":                   nil,
+	"nativeModePort":                   nil,
+	"discordWebhookURL":                nil,
+	"pushoverToken":                    nil,
+	"pushoverUserKey":                  nil,
+	"sendToAlertmanager":               nil,
+	"webhookURL":                       nil,
+	"telegramBotToken":                 nil,
+	"telegramChatID":                   nil,
+	"ntfyURL":                          nil,
+	"ntfyToken":                        nil,
+	"slackWebhookURL":                  nil,
+	"smtpHost":                         nil,
+	"smtpPort":                         nil,
+	"smtpUsername":                     nil,
+	"smtpPassword":                     nil,
+	"smtpFrom":                         nil,
+	"smtpTo":                           nil,
+	"alertEnabled_FeeRecipientChanged": nil,
+	"alertEnabled_MinipoolBondReduced": nil,
+	"alertEnabled_MinipoolBalanceDistributed":          nil,
+	"alertEnabled_MinipoolPromoted":                    nil,
+	"alertEnabled_MinipoolStaked":                      nil,
+	"alertEnabled_ExecutionClientSyncComplete":         nil,
+	"alertEnabled_BeaconClientSyncComplete":            nil,
+	"alertEnabled_MegapoolValidatorPrestaked":          nil,
+	"alertEnabled_MegapoolValidatorStaked":             nil,
+	"alertEnabled_MegapoolValidatorDissolved":          nil,
+	"alertEnabled_MegapoolValidatorExiting":            nil,
+	"alertEnabled_MegapoolValidatorChallenged":         nil,
+	"alertEnabled_MegapoolChallengeDefended":           nil,
+	"alertEnabled_MegapoolChallengeLost":               nil,
+	"alertEnabled_MegapoolDebtIncurred":                nil,
+	"alertEnabled_MegapoolExpressQueuePositionChanged": nil,
+	"alertEnabled_MegapoolRewardsDistributed":          nil,
+	"alertEnabled_MegapoolRefundClaimed":               nil,
+	"alertEnabled_MissedAttestations":                  nil,
+	"alertEnabled_MissedProposal":                      nil,
+	"missedAttestationsThreshold":                      nil,
+	"missedAttestationsWindow":                         nil,
+	"alertEnabled_LowETHBalance":                       nil,
+	"lowETHBalanceThreshold":                           nil,
 }
 
 var alertingParametersDockerMode map[string]interface{} = map[string]interface{}{
-	"enableAlerting":                           nil,
-	"port":                                     nil,
-	"openPort":                                 nil,
-	"containerTag":                             nil,
-	"discordWebhookURL":                        nil,
-	"pushoverToken":                            nil,
-	"pushoverUserKey":                          nil,
-	"alertEnabled_ClientSyncStatusBeacon":      nil,
-	"alertEnabled_ClientSyncStatusExecution":   nil,
-	"alertEnabled_UpcomingSyncCommittee":       nil,
-	"alertEnabled_ActiveSyncCommittee":         nil,
-	"alertEnabled_UpcomingProposal":            nil,
-	"alertEnabled_RecentProposal":              nil,
-	"alertEnabled_LowDiskSpaceWarning":         nil,
-	"alertEnabled_LowDiskSpaceCritical":        nil,
-	"alertEnabled_OSUpdatesAvailable":          nil,
-	"alertEnabled_RPUpdatesAvailable":          nil,
-	"alertEnabled_FeeRecipientChanged":         nil,
-	"alertEnabled_MinipoolBondReduced":         nil,
-	"alertEnabled_MinipoolBalanceDistributed":  nil,
-	"alertEnabled_MinipoolPromoted":            nil,
-	"alertEnabled_MinipoolStaked":              nil,
-	"alertEnabled_ExecutionClientSyncComplete": nil,
-	"alertEnabled_BeaconClientSyncComplete":    nil,
-	"alertEnabled_LowETHBalance":               nil,
-	"lowETHBalanceThreshold":                   nil,
+	"enableAlerting":                                   nil,
+	"port":                                             nil,
+	"openPort":                                         nil,
+	"containerTag":                                     nil,
+	"discordWebhookURL":                                nil,
+	"pushoverToken":                                    nil,
+	"pushoverUserKey":                                  nil,
+	"sendToAlertmanager":                               nil,
+	"webhookURL":                                       nil,
+	"telegramBotToken":                                 nil,
+	"telegramChatID":                                   nil,
+	"ntfyURL":                                          nil,
+	"ntfyToken":                                        nil,
+	"slackWebhookURL":                                  nil,
+	"smtpHost":                                         nil,
+	"smtpPort":                                         nil,
+	"smtpUsername":                                     nil,
+	"smtpPassword":                                     nil,
+	"smtpFrom":                                         nil,
+	"smtpTo":                                           nil,
+	"alertEnabled_ClientSyncStatusBeacon":              nil,
+	"alertEnabled_ClientSyncStatusExecution":           nil,
+	"alertEnabled_UpcomingSyncCommittee":               nil,
+	"alertEnabled_ActiveSyncCommittee":                 nil,
+	"alertEnabled_UpcomingProposal":                    nil,
+	"alertEnabled_RecentProposal":                      nil,
+	"alertEnabled_LowDiskSpaceWarning":                 nil,
+	"alertEnabled_LowDiskSpaceCritical":                nil,
+	"alertEnabled_OSUpdatesAvailable":                  nil,
+	"alertEnabled_RPUpdatesAvailable":                  nil,
+	"alertEnabled_FeeRecipientChanged":                 nil,
+	"alertEnabled_MinipoolBondReduced":                 nil,
+	"alertEnabled_MinipoolBalanceDistributed":          nil,
+	"alertEnabled_MinipoolPromoted":                    nil,
+	"alertEnabled_MinipoolStaked":                      nil,
+	"alertEnabled_ExecutionClientSyncComplete":         nil,
+	"alertEnabled_BeaconClientSyncComplete":            nil,
+	"alertEnabled_MegapoolValidatorPrestaked":          nil,
+	"alertEnabled_MegapoolValidatorStaked":             nil,
+	"alertEnabled_MegapoolValidatorDissolved":          nil,
+	"alertEnabled_MegapoolValidatorExiting":            nil,
+	"alertEnabled_MegapoolValidatorChallenged":         nil,
+	"alertEnabled_MegapoolChallengeDefended":           nil,
+	"alertEnabled_MegapoolChallengeLost":               nil,
+	"alertEnabled_MegapoolDebtIncurred":                nil,
+	"alertEnabled_MegapoolExpressQueuePositionChanged": nil,
+	"alertEnabled_MegapoolRewardsDistributed":          nil,
+	"alertEnabled_MegapoolRefundClaimed":               nil,
+	"alertEnabled_MissedAttestations":                  nil,
+	"alertEnabled_MissedProposal":                      nil,
+	"missedAttestationsThreshold":                      nil,
+	"missedAttestationsWindow":                         nil,
+	"alertEnabled_LowETHBalance":                       nil,
+	"lowETHBalanceThreshold":                           nil,
 }
 
 // The page wrapper for the alerting config
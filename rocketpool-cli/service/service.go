@@ -15,6 +15,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	cliconfig "github.com/rocket-pool/smartnode/rocketpool-cli/service/config"
+	"github.com/rocket-pool/smartnode/rocketpool-cli/wallet"
 	"github.com/rocket-pool/smartnode/shared"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
@@ -652,6 +653,15 @@ func checkForValidatorChange(rp *rocketpool.Client, cfg *config.RocketPoolConfig
 			}
 		}
 
+		// Move the slashing protection history over to the new client
+		err = wallet.MigrateSlashingProtection(rp, cfg, validatorDutyContainerName, consensusClient, selectedConsensusClientConfig.GetValidatorImage())
+		if err != nil {
+			fmt.Printf("%sWARNING: couldn't migrate your slashing protection history to %s: %s%s\n\n", colorYellow, pendingValidatorName, err.Error(), colorReset)
+			if !prompt.Confirm(fmt.Sprintf("Would you like to start %s with an empty slashing protection database anyway? You can import the history later with `rocketpool wallet import-slashing-protection`.", pendingValidatorName)) {
+				return fmt.Errorf("the slashing protection history wasn't migrated to %s", pendingValidatorName)
+			}
+		}
+
 		// Print the warning and start the time lockout
 		safeStartTime := validatorFinishTime.Add(15 * time.Minute)
 		remainingTime := time.Until(safeStartTime)
@@ -104,6 +104,10 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 						Name:  "address, a",
 						Usage: "If you are recovering a wallet that was not generated by the Smartnode and don't know the derivation path or index of it, enter the address here. The Smartnode will search through its library of paths and indices to try to find it.",
 					},
+					cli.StringFlag{
+						Name:  "slashing-protection",
+						Usage: "An EIP-3076 slashing protection file with the signing history of the recovered validator keys, which will be imported into your validator client",
+					},
 				},
 				Action: func(c *cli.Context) error {
 
@@ -134,7 +138,13 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Name:      "rebuild",
 				Aliases:   []string{"b"},
 				Usage:     "Rebuild validator keystores from derived keys",
-				UsageText: "rocketpool wallet rebuild",
+				UsageText: "rocketpool wallet rebuild [options]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "slashing-protection",
+						Usage: "An EIP-3076 slashing protection file with the signing history of the recovered validator keys, which will be imported into your validator client",
+					},
+				},
 				Action: func(c *cli.Context) error {
 
 					// Validate args
@@ -213,6 +223,106 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 
 				},
 			},
+
+			{
+				Name:      "export-slashing-protection",
+				Usage:     "Export your validator client's slashing protection history in the EIP-3076 interchange format",
+				UsageText: "rocketpool wallet export-slashing-protection [options]",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "The file to write the slashing protection data to (defaults to the validators folder)",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm stopping the validator client",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return exportSlashingProtection(c)
+
+				},
+			},
+
+			{
+				Name:      "import-slashing-protection",
+				Usage:     "Import EIP-3076 slashing protection history into your validator client",
+				UsageText: "rocketpool wallet import-slashing-protection [options] file",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm stopping the validator client",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					return importSlashingProtection(c, c.Args().Get(0))
+
+				},
+			},
+
+			{
+				Name:      "sign-offline",
+				Usage:     "Sign a transaction that was exported with the --offline flag, using the node wallet on this machine",
+				UsageText: "rocketpool wallet sign-offline [options] file-or-payload",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "output, o",
+						Usage: "The file to write the signed transaction to (defaults to <hash>.json in the current directory)",
+					},
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm signing the transaction",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					return signOffline(c, c.Args().Get(0))
+
+				},
+			},
+
+			{
+				Name:      "broadcast",
+				Usage:     "Submit a transaction that was signed with `rocketpool wallet sign-offline`",
+				UsageText: "rocketpool wallet broadcast [options] file-or-payload",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes, y",
+						Usage: "Automatically confirm submitting the transaction",
+					},
+				},
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					return broadcast(c, c.Args().Get(0))
+
+				},
+			},
 			{
 				Name:      "set-ens-name",
 				Aliases:   []string{"ens"},
@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	cliutils "github.com/rocket-pool/smartnode/shared/utils/cli"
+	"github.com/rocket-pool/smartnode/shared/utils/cli/prompt"
+)
+
+func signOffline(c *cli.Context, input string) error {
+
+	// Get RP client
+	rp := rocketpool.NewClientFromCtx(c)
+	defer rp.Close()
+
+	// Load the transaction
+	tx, payload, err := readOfflineTransaction(input)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Transaction to sign:")
+	printTransactionDetails(tx)
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || prompt.Confirm("Are you sure you want to sign this transaction with your node wallet?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Sign it
+	response, err := rp.SignOfflineTransaction(payload)
+	if err != nil {
+		return err
+	}
+
+	// Save it
+	output := c.String("output")
+	if output == "" {
+		output = response.Transaction.Hash.Hex() + ".json"
+	}
+	output, err = homedir.Expand(output)
+	if err != nil {
+		return fmt.Errorf("error expanding output path: %w", err)
+	}
+	bytes, err := json.MarshalIndent(response.Transaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing signed transaction: %w", err)
+	}
+	err = os.WriteFile(output, bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing signed transaction to [%s]: %w", output, err)
+	}
+
+	fmt.Printf("%sSigned transaction %s was saved to %s.%s\n\n", colorGreen, response.Transaction.Hash.Hex(), output, colorReset)
+	fmt.Printf("Payload (e.g. for a QR code):\n%s\n\n", response.Transaction.Payload)
+	fmt.Println("Copy it back to your online node and submit it with `rocketpool wallet broadcast`.")
+	return nil
+
+}
+
+func broadcast(c *cli.Context, input string) error {
+
+	// Get RP client
+	rp, err := rocketpool.NewClientFromCtx(c).WithReady()
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	// Load the transaction
+	tx, payload, err := readOfflineTransaction(input)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Transaction to broadcast:")
+	printTransactionDetails(tx)
+
+	// Prompt for confirmation
+	if !(c.Bool("yes") || prompt.Confirm("Are you sure you want to submit this transaction?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Submit it
+	response, err := rp.BroadcastTransaction(payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Submitting transaction from %s...\n", response.From.Hex())
+	cliutils.PrintTransactionHash(rp, response.TxHash)
+	if _, err = rp.WaitForTransaction(response.TxHash); err != nil {
+		return err
+	}
+
+	fmt.Println("The transaction was successfully included in a block.")
+	return nil
+
+}
+
+// Read an exported transaction from a file, or from the argument itself if it's a raw payload.
+// Returns the transaction and its payload in hex form.
+func readOfflineTransaction(input string) (*types.Transaction, string, error) {
+	data := []byte(input)
+	if !strings.HasPrefix(input, "0x") {
+		path, err := homedir.Expand(input)
+		if err != nil {
+			return nil, "", fmt.Errorf("error expanding file path: %w", err)
+		}
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading transaction file: %w", err)
+		}
+	}
+
+	tx, err := wallet.ParseOfflineTransaction(data)
+	if err != nil {
+		return nil, "", err
+	}
+	bytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, "", fmt.Errorf("error marshalling transaction: %w", err)
+	}
+	return tx, hexutil.Encode(bytes), nil
+}
+
+// Print the details of a transaction so it can be reviewed
+func printTransactionDetails(tx *types.Transaction) {
+	to := "<contract creation>"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	fmt.Printf("\tChain ID:         %s\n", tx.ChainId().String())
+	fmt.Printf("\tNonce:            %d\n", tx.Nonce())
+	fmt.Printf("\tTo:               %s\n", to)
+	fmt.Printf("\tValue:            %.6f ETH\n", eth.WeiToEth(tx.Value()))
+	fmt.Printf("\tData:             %d bytes\n", len(tx.Data()))
+	fmt.Printf("\tGas limit:        %d\n", tx.Gas())
+	fmt.Printf("\tMax fee:          %.6f gwei\n", eth.WeiToGwei(tx.GasFeeCap()))
+	fmt.Printf("\tMax priority fee: %.6f gwei\n", eth.WeiToGwei(tx.GasTipCap()))
+	fmt.Println()
+}
@@ -20,9 +20,27 @@ func purge(c *cli.Context) error {
 		return nil
 	}
 
+	// Remove the keys from the VC first so it stops validating with them right away
+	cfg, _, err := rp.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading configuration: %w", err)
+	}
+	if !cfg.IsNativeMode && cfg.Smartnode.EnableKeymanagerApi.Value.(bool) {
+		response, err := rp.DeleteValidatorKeys()
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't remove the validator keys through the Keymanager API: %s\nThey will still be deleted from disk.%s\n\n", colorYellow, err.Error(), colorReset)
+		} else {
+			fmt.Printf("Removed %d validator keys from your validator client.\n", response.DeletedKeys)
+			if response.SavedSlashingProtection {
+				fmt.Printf("Their slashing protection data was saved to %s. If you use these keys on another machine, import it there with `rocketpool wallet import-slashing-protection` first.\n", cfg.Smartnode.GetSlashingProtectionBackupPathInCLI())
+			}
+			fmt.Println()
+		}
+	}
+
 	// Purge
 	composeFiles := c.Parent().StringSlice("compose-file")
-	err := rp.PurgeAllKeys(composeFiles)
+	err = rp.PurgeAllKeys(composeFiles)
 	if err != nil {
 		return fmt.Errorf("%w\n%sTHERE WAS AN ERROR DELETING YOUR KEYS. They most likely have not been deleted. Proceed with caution.%s", err, colorRed, colorReset)
 	}
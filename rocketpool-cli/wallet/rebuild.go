@@ -70,6 +70,9 @@ func rebuildWallet(c *cli.Context) error {
 		for _, key := range response.ValidatorKeys {
 			fmt.Println(key.Hex())
 		}
+		if err := ImportRecoveredSlashingProtection(c, rp); err != nil {
+			return err
+		}
 	} else {
 		fmt.Println("No validator keys were found.")
 	}
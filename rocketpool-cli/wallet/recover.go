@@ -127,6 +127,9 @@ func recoverWallet(c *cli.Context) error {
 				for _, key := range response.ValidatorKeys {
 					fmt.Println(key.Hex())
 				}
+				if err := ImportRecoveredSlashingProtection(c, rp); err != nil {
+					return err
+				}
 			} else {
 				fmt.Println("No validator keys were found.")
 			}
@@ -169,6 +172,9 @@ func recoverWallet(c *cli.Context) error {
 				for _, key := range response.ValidatorKeys {
 					fmt.Println(key.Hex())
 				}
+				if err := ImportRecoveredSlashingProtection(c, rp); err != nil {
+					return err
+				}
 			} else {
 				fmt.Println("No validator keys were found.")
 			}
@@ -0,0 +1,306 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+	"github.com/rocket-pool/smartnode/shared/utils/cli/prompt"
+	"github.com/rocket-pool/smartnode/shared/utils/validator"
+)
+
+func exportSlashingProtection(c *cli.Context) error {
+
+	// Get RP client
+	rp := rocketpool.NewClientFromCtx(c)
+	defer rp.Close()
+
+	// Load the config
+	cfg, validatorContainer, err := getSlashingProtectionConfig(rp)
+	if err != nil {
+		return err
+	}
+
+	// Get the validator client that's currently in use
+	client, err := rp.GetValidatorContainerClient(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the current validator client: %w", err)
+	}
+	image, err := rp.GetDockerImage(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the current validator image: %w", err)
+	}
+
+	// Export the database while the validator client is stopped
+	if !(c.Bool("yes") || prompt.Confirm(fmt.Sprintf("Exporting the slashing protection database requires stopping your validator client (%s) briefly, so you may miss an attestation. Would you like to continue?", client))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	err = runWithValidatorStopped(rp, validatorContainer, func() error {
+		return rp.ExportSlashingProtection(cfg, client, image)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Check it against the node's validators
+	exportPath, err := getSlashingProtectionPath(cfg)
+	if err != nil {
+		return err
+	}
+	bytes, err := os.ReadFile(exportPath)
+	if err != nil {
+		return fmt.Errorf("error reading exported slashing protection data: %w", err)
+	}
+	check, err := checkSlashingProtection(rp, bytes)
+	if err != nil {
+		return err
+	}
+	printSlashingProtectionCheck(check)
+
+	// Copy it to the requested location
+	if output := c.String("output"); output != "" {
+		output, err = homedir.Expand(output)
+		if err != nil {
+			return fmt.Errorf("error expanding output path: %w", err)
+		}
+		err = os.WriteFile(output, bytes, 0600)
+		if err != nil {
+			return fmt.Errorf("error writing slashing protection data to [%s]: %w", output, err)
+		}
+		exportPath = output
+	}
+
+	fmt.Printf("%sExported the slashing protection history of %d validator(s) to %s.%s\n", colorGreen, check.RecordCount, exportPath, colorReset)
+	return nil
+
+}
+
+func importSlashingProtection(c *cli.Context, path string) error {
+
+	// Get RP client
+	rp := rocketpool.NewClientFromCtx(c)
+	defer rp.Close()
+
+	return ImportSlashingProtectionFile(c, rp, path)
+
+}
+
+// Check an EIP-3076 slashing protection file against the node's validators, then import it into the validator client.
+// Nothing is copied into the validators folder unless the file passes the check.
+func ImportSlashingProtectionFile(c *cli.Context, rp *rocketpool.Client, path string) error {
+
+	// Load the config
+	cfg, validatorContainer, err := getSlashingProtectionConfig(rp)
+	if err != nil {
+		return err
+	}
+
+	// Read and check the file
+	path, err = homedir.Expand(path)
+	if err != nil {
+		return fmt.Errorf("error expanding file path: %w", err)
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading slashing protection file: %w", err)
+	}
+	check, err := checkSlashingProtection(rp, bytes)
+	if err != nil {
+		return err
+	}
+	printSlashingProtectionCheck(check)
+	err = check.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Get the validator client that's currently in use
+	client, err := rp.GetValidatorContainerClient(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the current validator client: %w", err)
+	}
+	image, err := rp.GetDockerImage(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the current validator image: %w", err)
+	}
+
+	// Import the data while the validator client is stopped
+	if !(c.Bool("yes") || prompt.Confirm(fmt.Sprintf("Importing slashing protection data requires stopping your validator client (%s) briefly, so you may miss an attestation. Would you like to continue?", client))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Copy it into the validators folder so the validator client's tool can read it
+	importPath, err := getSlashingProtectionPath(cfg)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(path) != filepath.Clean(importPath) {
+		err = os.WriteFile(importPath, bytes, 0600)
+		if err != nil {
+			return fmt.Errorf("error copying slashing protection data to [%s]: %w", importPath, err)
+		}
+	}
+
+	err = runWithValidatorStopped(rp, validatorContainer, func() error {
+		return rp.ImportSlashingProtection(cfg, client, image)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%sImported the slashing protection history of %d validator(s) into %s.%s\n", colorGreen, check.RecordCount, client, colorReset)
+	return nil
+
+}
+
+// Import the slashing protection file provided with the slashing-protection flag after validator keys were recovered or imported
+func ImportRecoveredSlashingProtection(c *cli.Context, rp *rocketpool.Client) error {
+	path := c.String("slashing-protection")
+	if path == "" {
+		fmt.Println("If these validators were running on another machine or validator client before, import their slashing protection history with `rocketpool wallet import-slashing-protection`.")
+		return nil
+	}
+	fmt.Println()
+	fmt.Println("Importing the slashing protection history of the validator keys...")
+	return ImportSlashingProtectionFile(c, rp, path)
+}
+
+// Move the slashing protection history from the validator client in the given container to a new validator client.
+// The container must already be stopped.
+func MigrateSlashingProtection(rp *rocketpool.Client, cfg *config.RocketPoolConfig, validatorContainer string, newClient cfgtypes.ConsensusClient, newImage string) error {
+
+	// Export from the old client
+	oldClient, err := rp.GetValidatorContainerClient(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the previous validator client: %w", err)
+	}
+	oldImage, err := rp.GetDockerImage(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting the previous validator image: %w", err)
+	}
+	fmt.Printf("Exporting the slashing protection history from %s...\n", oldClient)
+	err = rp.ExportSlashingProtection(cfg, oldClient, oldImage)
+	if err != nil {
+		return fmt.Errorf("error exporting slashing protection data from %s: %w", oldClient, err)
+	}
+
+	// Make sure it's valid before giving it to the new client
+	exportPath, err := getSlashingProtectionPath(cfg)
+	if err != nil {
+		return err
+	}
+	bytes, err := os.ReadFile(exportPath)
+	if err != nil {
+		return fmt.Errorf("error reading exported slashing protection data: %w", err)
+	}
+	check, err := checkSlashingProtection(rp, bytes)
+	if err != nil {
+		return fmt.Errorf("error checking exported slashing protection data: %w", err)
+	}
+	printSlashingProtectionCheck(check)
+	err = check.Validate()
+	if err != nil {
+		return fmt.Errorf("exported slashing protection data failed validation: %w", err)
+	}
+
+	// Import into the new client
+	fmt.Printf("Importing the slashing protection history into %s...\n", newClient)
+	err = rp.ImportSlashingProtection(cfg, newClient, newImage)
+	if err != nil {
+		return fmt.Errorf("error importing slashing protection data into %s: %w", newClient, err)
+	}
+
+	fmt.Printf("%sMigrated the slashing protection history from %s to %s.%s\n", colorGreen, oldClient, newClient, colorReset)
+	return nil
+
+}
+
+// Load the config and get the name of the validator container
+func getSlashingProtectionConfig(rp *rocketpool.Client) (*config.RocketPoolConfig, string, error) {
+	cfg, isNew, err := rp.LoadConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	if isNew {
+		return nil, "", fmt.Errorf("Settings file not found. Please run `rocketpool service config` to set up your Smart Node.")
+	}
+	if cfg.IsNativeMode {
+		return nil, "", fmt.Errorf("Slashing protection data can only be migrated automatically in Docker mode; please use your validator client's own tools in Native mode.")
+	}
+	return cfg, cfg.Smartnode.ProjectName.Value.(string) + validator.ValidatorContainerSuffix, nil
+}
+
+// Parse slashing protection data and check it against the node's validators
+func checkSlashingProtection(rp *rocketpool.Client, bytes []byte) (validator.SlashingProtectionCheck, error) {
+	interchange, err := validator.ParseSlashingProtection(bytes)
+	if err != nil {
+		return validator.SlashingProtectionCheck{}, err
+	}
+	info, err := rp.GetSlashingProtectionInfo()
+	if err != nil {
+		return validator.SlashingProtectionCheck{}, err
+	}
+	return interchange.Check(info.GenesisValidatorsRoot.Bytes(), info.Pubkeys), nil
+}
+
+// Get the path of the slashing protection file in the validators folder
+func getSlashingProtectionPath(cfg *config.RocketPoolConfig) (string, error) {
+	path, err := homedir.Expand(filepath.Join(cfg.Smartnode.GetValidatorKeychainPathInCLI(), config.SlashingProtectionFilename))
+	if err != nil {
+		return "", fmt.Errorf("error expanding slashing protection file path: %w", err)
+	}
+	return path, nil
+}
+
+// Stop the validator container if it's running, run the function, then start it again
+func runWithValidatorStopped(rp *rocketpool.Client, validatorContainer string, run func() error) error {
+	status, err := rp.GetDockerStatus(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error getting validator container status: %w", err)
+	}
+	if status != "running" {
+		return run()
+	}
+
+	fmt.Println("Stopping the validator client...")
+	_, err = rp.StopContainer(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error stopping validator container: %w", err)
+	}
+	runErr := run()
+
+	fmt.Println("Starting the validator client...")
+	_, err = rp.StartContainer(validatorContainer)
+	if err != nil {
+		return fmt.Errorf("error starting validator container: %w", err)
+	}
+	return runErr
+}
+
+// Print the results of validating slashing protection data against the node's validators
+func printSlashingProtectionCheck(check validator.SlashingProtectionCheck) {
+	if !check.GenesisValidatorsRootMatches {
+		fmt.Printf("%sWARNING: the slashing protection data was created for a different network than the one your node is on.%s\n", colorRed, colorReset)
+	}
+	if len(check.UnknownPubkeys) > 0 {
+		fmt.Printf("%sWARNING: the slashing protection data contains history for %d validator(s) that don't belong to this node:%s\n", colorYellow, len(check.UnknownPubkeys), colorReset)
+		for _, pubkey := range check.UnknownPubkeys {
+			fmt.Printf("\t%s\n", pubkey.Hex())
+		}
+	}
+	if len(check.MissingPubkeys) > 0 {
+		fmt.Printf("NOTE: the slashing protection data doesn't have any history for %d of this node's validator(s):\n", len(check.MissingPubkeys))
+		for _, pubkey := range check.MissingPubkeys {
+			fmt.Printf("\t%s\n", pubkey.Hex())
+		}
+	}
+	fmt.Println()
+}
@@ -179,6 +179,50 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "verify-rewards-tree",
+				Usage:     "Rebuild the local rewards tree for the given interval and check it against the canonical Merkle root",
+				UsageText: "rocketpool api network verify-rewards-tree interval",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					interval, err := cliutils.ValidateUint("interval", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(verifyRewardsTree(c, interval))
+					return nil
+
+				},
+			},
+			{
+				Name:      "compare-rewards-tree",
+				Usage:     "Compare the local rewards tree for the given interval with one regenerated independently, given its path relative to the data folder",
+				UsageText: "rocketpool api network compare-rewards-tree interval path",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 2); err != nil {
+						return err
+					}
+
+					interval, err := cliutils.ValidateUint("interval", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(compareRewardsTree(c, interval, c.Args().Get(1)))
+					return nil
+
+				},
+			},
 			{
 				Name:      "is-saturn-deployed",
 				Aliases:   []string{"isd"},
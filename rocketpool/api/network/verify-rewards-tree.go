@@ -0,0 +1,160 @@
+package network
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/minipool"
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+func verifyRewardsTree(c *cli.Context, interval uint64) (*api.NetworkVerifyRewardsTreeResponse, error) {
+
+	// Get services
+	if err := services.RequireEthClientSynced(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := services.GetRocketPool(c)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.NetworkVerifyRewardsTreeResponse{
+		Interval: interval,
+	}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	response.NodeAddress = nodeAccount.Address
+
+	// Get the canonical Merkle root for the interval
+	intervalInfo, err := rewards.GetIntervalInfo(rp, cfg, nodeAccount.Address, interval, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting interval %d info: %w", interval, err)
+	}
+	response.CanonicalMerkleRoot = intervalInfo.MerkleRoot
+	response.TreeFilePath = intervalInfo.TreeFilePath
+	response.TreeFileExists = intervalInfo.TreeFileExists
+	if !response.TreeFileExists {
+		return &response, nil
+	}
+
+	// Rebuild the tree from the file's rewards
+	localRewardsFile, err := rewards.ReadLocalRewardsFile(intervalInfo.TreeFilePath)
+	if err != nil {
+		return nil, err
+	}
+	rewardsFile := localRewardsFile.Impl()
+	response.NodeCollateralRpl = big.NewInt(0).Set(rewardsFile.GetNodeCollateralRpl(nodeAccount.Address))
+	response.NodeOracleDaoRpl = big.NewInt(0).Set(rewardsFile.GetNodeOracleDaoRpl(nodeAccount.Address))
+	response.NodeSmoothingPoolEth = big.NewInt(0).Set(rewardsFile.GetNodeSmoothingPoolEth(nodeAccount.Address))
+	response.Verification, err = rewards.VerifyRewardsFile(rewardsFile, nodeAccount.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error rebuilding the Merkle tree for interval %d: %w", interval, err)
+	}
+
+	// Check the node's Smoothing Pool rewards against the recorded attestation performance
+	performancePath := cfg.Smartnode.GetMinipoolPerformancePath(interval, true)
+	if _, err := os.Stat(performancePath); os.IsNotExist(err) {
+		return &response, nil
+	}
+	response.PerformanceFileExists = true
+	localPerformanceFile, err := rewards.ReadLocalMinipoolPerformanceFile(performancePath)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := minipool.GetNodeMinipoolAddresses(rp, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting node minipool addresses: %w", err)
+	}
+	megapoolDeployed, err := megapool.GetMegapoolDeployed(rp, nodeAccount.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if the node's megapool is deployed: %w", err)
+	}
+	if megapoolDeployed {
+		megapoolAddress, err := megapool.GetMegapoolExpectedAddress(rp, nodeAccount.Address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the node's megapool address: %w", err)
+		}
+		validators = append(validators, megapoolAddress)
+	}
+	response.Validators, response.ValidatorSmoothingPoolEth = rewards.GetValidatorPerformance(localPerformanceFile.Impl(), validators)
+
+	// Check the recorded performance against the daemon's own record of the node's attestations, which doesn't come from the tree.
+	// Duties near the interval's boundaries may have been recorded with the neighboring intervals.
+	records := []*rewards.AttestationRecord{}
+	for i := max(interval, 1) - 1; i <= interval+1; i++ {
+		record, err := rewards.LoadAttestationRecord(cfg.Smartnode.GetAttestationRecordPath(i))
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		return &response, nil
+	}
+	response.AttestationRecordExists = true
+	response.AttestationRecords, err = rewards.CompareAttestationRecords(rewardsFile, localPerformanceFile.Impl(), validators, records)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing attestation performance with the local attestation records: %w", err)
+	}
+
+	// Return response
+	return &response, nil
+
+}
+
+func compareRewardsTree(c *cli.Context, interval uint64, path string) (*api.NetworkCompareRewardsTreeResponse, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// The regenerated tree has to be in the data folder so the daemon can read it
+	if filepath.IsAbs(path) || !filepath.IsLocal(path) {
+		return nil, fmt.Errorf("the path of the tree to compare against must be relative to the Smartnode data folder")
+	}
+	response := api.NetworkCompareRewardsTreeResponse{
+		TreeFilePath: cfg.Smartnode.GetRewardsTreePath(interval, true, config.RewardsExtensionJSON),
+		ComparedPath: filepath.Join(cfg.Smartnode.GetDataFolder(true), path),
+	}
+
+	// Compare them
+	localFile, err := rewards.ReadLocalRewardsFile(response.TreeFilePath)
+	if err != nil {
+		return nil, err
+	}
+	regeneratedFile, err := rewards.ReadLocalRewardsFile(response.ComparedPath)
+	if err != nil {
+		return nil, err
+	}
+	response.Differences = rewards.DiffRewardsFiles(regeneratedFile.Impl(), localFile.Impl())
+
+	// Return response
+	return &response, nil
+
+}
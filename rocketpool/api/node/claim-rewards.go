@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -21,6 +22,7 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rprewards "github.com/rocket-pool/smartnode/shared/services/rewards"
 	updateCheck "github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/services/submission"
 	"github.com/rocket-pool/smartnode/shared/types/api"
 	"github.com/rocket-pool/smartnode/shared/utils/eth1"
 	rputils "github.com/rocket-pool/smartnode/shared/utils/rp"
@@ -271,6 +273,9 @@ func claimRewards(c *cli.Context, indicesString string) (*api.NodeClaimRewardsRe
 		return nil, fmt.Errorf("Error checking for nonce override: %w", err)
 	}
 
+	// Submit the claim with the mode selected for rewards claims
+	opts.Context = submission.WithMode(context.Background(), cfg.Smartnode.GetRewardsClaimTxSubmissionMode())
+
 	// Claim rewards
 	hash, err := rewards.Claim(rp, nodeAccount.Address, indices, amountRPL, amountETH, merkleProofs, opts)
 	if err != nil {
@@ -377,6 +382,9 @@ func claimAndStakeRewards(c *cli.Context, indicesString string, stakeAmount *big
 		return nil, fmt.Errorf("Error checking for nonce override: %w", err)
 	}
 
+	// Submit the claim with the mode selected for rewards claims
+	opts.Context = submission.WithMode(context.Background(), cfg.Smartnode.GetRewardsClaimTxSubmissionMode())
+
 	// Claim rewards
 	hash, err := rewards.ClaimAndStake(rp, nodeAccount.Address, indices, amountRPL, amountETH, merkleProofs, stakeAmount, opts)
 	if err != nil {
@@ -1191,6 +1191,24 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "rewards-history",
+				Usage:     "Get the node's rewards history from the local rewards trees and chain events",
+				UsageText: "rocketpool api node rewards-history",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getRewardsHistory(c))
+					return nil
+
+				},
+			},
+
 			{
 				Name:      "deposit-contract-info",
 				Usage:     "Get information about the deposit contract specified by Rocket Pool and the Beacon Chain client",
@@ -1704,6 +1722,65 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+			{
+				Name:      "pending-transactions",
+				Usage:     "Get the node's transactions that haven't been included in a block yet",
+				UsageText: "rocketpool api node pending-transactions",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getPendingTransactions(c))
+					return nil
+
+				},
+			},
+			{
+				Name:      "can-cancel-transaction",
+				Usage:     "Check whether the node's pending transaction with the given nonce can be cancelled",
+				UsageText: "rocketpool api node can-cancel-transaction nonce",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					nonce, err := cliutils.ValidateUint("nonce", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(canCancelTransaction(c, nonce))
+					return nil
+
+				},
+			},
+			{
+				Name:      "cancel-transaction",
+				Usage:     "Cancel the node's pending transaction with the given nonce by replacing it with a 0-value transfer to the node",
+				UsageText: "rocketpool api node cancel-transaction nonce",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					nonce, err := cliutils.ValidateUint("nonce", c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(cancelTransaction(c, nonce))
+					return nil
+
+				},
+			},
 			{
 				Name:      "get-express-ticket-count",
 				Usage:     "Get the number of express tickets available for the node",
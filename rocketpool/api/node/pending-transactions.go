@@ -0,0 +1,172 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/txtracker"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+func getPendingTransactions(c *cli.Context) (*api.NodePendingTransactionsResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.NodePendingTransactionsResponse{}
+
+	// Get the node's nonces
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	response.LatestNonce, err = ec.NonceAt(context.Background(), nodeAccount.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest nonce: %w", err)
+	}
+	response.PendingNonce, err = ec.PendingNonceAt(context.Background(), nodeAccount.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending nonce: %w", err)
+	}
+
+	// Get the tracked transactions
+	tracker := txtracker.NewTracker(cfg, ec)
+	txs, err := tracker.GetPendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	response.Transactions = []txtracker.PendingTransaction{}
+	for _, tx := range txs {
+		if tx.From == nodeAccount.Address {
+			response.Transactions = append(response.Transactions, tx)
+		}
+	}
+
+	// Return response
+	return &response, nil
+
+}
+
+func canCancelTransaction(c *cli.Context, nonce uint64) (*api.CanNodeCancelTransactionResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.CanNodeCancelTransactionResponse{}
+
+	// Check the nonce
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	latestNonce, err := ec.NonceAt(context.Background(), nodeAccount.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest nonce: %w", err)
+	}
+	pendingNonce, err := ec.PendingNonceAt(context.Background(), nodeAccount.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending nonce: %w", err)
+	}
+	response.NonceUsed = nonce < latestNonce
+	response.NonceTooNew = nonce >= pendingNonce
+
+	// Get the minimum fee needed to replace the tracked transaction
+	tracker := txtracker.NewTracker(cfg, ec)
+	txs, err := tracker.GetPendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		if tx.From == nodeAccount.Address && tx.Nonce == nonce {
+			response.IsTracked = true
+			response.MinMaxFee = txtracker.GetBumpedFee(tx.MaxFee, txtracker.FeeBumpPercent)
+			break
+		}
+	}
+
+	// A 0-value transfer to the node always uses the same amount of gas
+	response.GasInfo = rocketpool.GasInfo{
+		EstGasLimit:  txtracker.CancelGasLimit,
+		SafeGasLimit: txtracker.CancelGasLimit,
+	}
+
+	// Update & return response
+	response.CanCancel = !(response.NonceUsed || response.NonceTooNew)
+	return &response, nil
+
+}
+
+func cancelTransaction(c *cli.Context, nonce uint64) (*api.NodeCancelTransactionResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.NodeCancelTransactionResponse{}
+
+	// Get transactor
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the transaction with a 0-value transfer to the node
+	tracker := txtracker.NewTracker(cfg, ec)
+	hash, err := tracker.Cancel(nonce, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error cancelling transaction with nonce %d: %w", nonce, err)
+	}
+	response.TxHash = hash
+
+	// Return response
+	return &response, nil
+
+}
@@ -0,0 +1,57 @@
+package node
+
+import (
+	"math/big"
+
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	rprewards "github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+func getRewardsHistory(c *cli.Context) (*api.NodeRewardsHistoryResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeRegistered(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := services.GetRocketPool(c)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.NodeRewardsHistoryResponse{}
+
+	// Get node account
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	response.NodeAddress = nodeAccount.Address
+
+	// Bring the cached history up to date, scanning at most one page of blocks
+	intervalSize := big.NewInt(int64(cfg.Geth.EventLogInterval))
+	ledger, err := rprewards.UpdateRewardsLedger(rp, cfg, nodeAccount.Address, intervalSize)
+	if err != nil {
+		return nil, err
+	}
+	response.Entries = ledger.Entries
+	response.MissingIntervals = ledger.MissingIntervals
+	response.LastScannedBlock = ledger.LastScannedBlock
+	response.LatestBlock = ledger.LatestBlock
+	response.Complete = ledger.Complete
+
+	// Return response
+	return &response, nil
+
+}
@@ -0,0 +1,368 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	apitypes "github.com/rocket-pool/smartnode/shared/types/api"
+	"github.com/rocket-pool/smartnode/shared/types/config"
+	"github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Config
+const (
+	ApiVersion     string = "v1"
+	apiCommandName string = "api"
+	maxBodySize    int64  = 1 << 20
+
+	ServerColor = color.FgHiCyan
+	ErrorColor  = color.FgRed
+)
+
+// The HTTP API server, which runs the regular API commands in its own process so they share its services instead of initializing them on every call.
+// Commands run one at a time, since the services they share (like the node wallet) aren't safe for concurrent use.
+type apiServer struct {
+	appName           string
+	globalFlags       []cli.Flag
+	apiCommand        *cli.Command
+	settings          string
+	token             string
+	allowSecretExport bool
+	lock              sync.Mutex
+	log               log.ColorLogger
+	errLog            log.ColorLogger
+}
+
+// Register the API server command
+func RegisterCommands(app *cli.App, name string, aliases []string) {
+	app.Commands = append(app.Commands, cli.Command{
+		Name:    name,
+		Aliases: aliases,
+		Usage:   "Run the Rocket Pool HTTP API server",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "address, a",
+				Usage: "Address to serve the API on",
+				Value: "127.0.0.1",
+			},
+			cli.UintFlag{
+				Name:  "port, p",
+				Usage: "Port to serve the API on (defaults to the configured API server port)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return run(c)
+		},
+	})
+}
+
+// Run the server
+func run(c *cli.Context) error {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return err
+	}
+
+	// Find the API command tree that requests will be routed to
+	var apiCommand *cli.Command
+	for i := range c.App.Commands {
+		if c.App.Commands[i].HasName(apiCommandName) {
+			apiCommand = &c.App.Commands[i]
+			break
+		}
+	}
+	if apiCommand == nil {
+		return fmt.Errorf("the %s command has not been registered", apiCommandName)
+	}
+
+	// Set up logging
+	log.SetJsonOutput(cfg.Smartnode.UseJsonLogs())
+
+	// Get the auth token
+	token, err := api.LoadOrCreateApiToken(cfg.Smartnode.GetApiTokenPath())
+	if err != nil {
+		return err
+	}
+
+	// Initialize the shared services up front, so they don't pick up the flags of whichever request happens to use them first
+	_, err = services.GetRocketPool(c)
+	if err != nil {
+		return fmt.Errorf("error initializing the execution client: %w", err)
+	}
+	_, err = services.GetBeaconClient(c)
+	if err != nil {
+		return fmt.Errorf("error initializing the Beacon client: %w", err)
+	}
+
+	server := &apiServer{
+		appName:           c.App.Name,
+		globalFlags:       c.App.Flags,
+		apiCommand:        apiCommand,
+		settings:          c.GlobalString("settings"),
+		token:             token,
+		allowSecretExport: cfg.Smartnode.ApiServerAllowSecretExport.Value == true,
+		log:               log.NewColorLogger(ServerColor).WithTask("api-server"),
+		errLog:            log.NewColorLogger(ErrorColor).WithLevel(log.LevelError).WithTask("api-server"),
+	}
+
+	// Set up the routes
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("GET /api/%s/{group}/{command}", ApiVersion), server.handleRequest)
+	mux.HandleFunc(fmt.Sprintf("POST /api/%s/{group}/{command}", ApiVersion), server.handleRequest)
+
+	// Start the HTTP server
+	address := c.String("address")
+	port := c.Uint("port")
+	if port == 0 {
+		port = uint(cfg.Smartnode.ApiServerPort.Value.(uint16))
+	}
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", address, port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Serve over TLS if the user provided a certificate, which remote nodes require
+	certPath := cfg.Smartnode.GetApiServerCertPath()
+	keyPath := cfg.Smartnode.GetApiServerKeyPath()
+	useTls, err := hasTlsCertificate(certPath, keyPath)
+	if err != nil {
+		return err
+	}
+	if useTls {
+		server.log.Printlnf("Starting API server on %s:%d with TLS.", address, port)
+		err = httpServer.ListenAndServeTLS(certPath, keyPath)
+	} else {
+		// Plaintext is only served when no other machine can reach the server
+		mode := cfg.Smartnode.ApiServerMode.Value.(config.RPCMode)
+		if !isLocalOnly(address, mode) {
+			return fmt.Errorf("the API server can be reached from other machines, so it requires a TLS certificate [%s] and key [%s]", certPath, keyPath)
+		}
+		server.log.Printlnf("Starting API server on %s:%d without TLS, since it can only be reached from this machine.", address, port)
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil {
+		return fmt.Errorf("Error running API server: %w", err)
+	}
+
+	return nil
+
+}
+
+// Check if the TLS certificate and key for the server exist; having only one of them is an error
+func hasTlsCertificate(certPath string, keyPath string) (bool, error) {
+	_, certErr := os.Stat(certPath)
+	if certErr != nil && !os.IsNotExist(certErr) {
+		return false, fmt.Errorf("error checking API server certificate [%s]: %w", certPath, certErr)
+	}
+	_, keyErr := os.Stat(keyPath)
+	if keyErr != nil && !os.IsNotExist(keyErr) {
+		return false, fmt.Errorf("error checking API server key [%s]: %w", keyPath, keyErr)
+	}
+	certExists := certErr == nil
+	keyExists := keyErr == nil
+	if certExists != keyExists {
+		return false, fmt.Errorf("the API server needs both a certificate [%s] and a key [%s] to use TLS", certPath, keyPath)
+	}
+	return certExists, nil
+}
+
+// Check if the server can only be reached from this machine when it listens on the provided address.
+// Inside the API container the server listens on every interface, and the API server mode decides whether Docker publishes its port on the host's loopback interface or on all of them.
+func isLocalOnly(address string, mode config.RPCMode) bool {
+	if mode == config.RPC_OpenExternal {
+		return false
+	}
+	if address == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	return ip.IsLoopback() || (ip.IsUnspecified() && mode == config.RPC_OpenLocalhost)
+}
+
+// Handle a request for an API route
+func (s *apiServer) handleRequest(w http.ResponseWriter, r *http.Request) {
+
+	// Authenticate
+	if !s.isAuthorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+		return
+	}
+
+	// Make sure the route exists
+	group := r.PathValue("group")
+	command := r.PathValue("command")
+	if !s.routeExists(group, command) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown API route %s/%s", group, command))
+		return
+	}
+
+	// Refuse to hand out the node's secrets unless the user explicitly allowed it
+	if s.isSecretRoute(group, command) && !s.allowSecretExport {
+		writeError(w, http.StatusForbidden, fmt.Errorf("API route %s/%s returns the node's secrets and is disabled on the API server", group, command))
+		return
+	}
+
+	// Parse the request
+	var request apitypes.ServerRequest
+	if r.Method == http.MethodPost {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("error reading request body: %w", err))
+			return
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &request); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding request body: %w", err))
+				return
+			}
+		}
+	} else {
+		request.Args = r.URL.Query()["arg"]
+	}
+
+	// Run the command and capture its response
+	response := s.runCommand(group, command, request)
+	if len(response) == 0 {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("API route %s/%s did not produce a response", group, command))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(response)
+	if err != nil {
+		s.errLog.Printlnf("Error writing response for %s/%s: %s", group, command, err.Error())
+	}
+
+}
+
+// Run an API command in this process with the request's flags, returning the response it printed
+func (s *apiServer) runCommand(group string, command string, request apitypes.ServerRequest) []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The wallet holds the gas settings of the command that loaded it, so each command loads its own
+	services.ResetWallet()
+
+	// Run the command through its own copy of the app, so usage errors don't end up in the response or exit the server
+	response := &bytes.Buffer{}
+	output := &bytes.Buffer{}
+	app := cli.NewApp()
+	app.Name = s.appName
+	app.Flags = s.globalFlags
+	app.Commands = []cli.Command{*s.apiCommand}
+	app.HideVersion = true
+	app.Writer = output
+	app.ErrWriter = output
+	app.ExitErrHandler = func(context *cli.Context, err error) {}
+
+	api.SetResponseOutput(response)
+	defer api.SetResponseOutput(os.Stdout)
+	if err := app.Run(s.getCommandArgs(group, command, request)); err != nil {
+		api.PrintErrorResponse(err)
+	}
+	return response.Bytes()
+}
+
+// Check the request's bearer token
+func (s *apiServer) isAuthorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// Check if an API command exists for the provided group and command names
+func (s *apiServer) routeExists(group string, command string) bool {
+	for _, groupCommand := range s.apiCommand.Subcommands {
+		if !groupCommand.HasName(group) {
+			continue
+		}
+		for _, subcommand := range groupCommand.Subcommands {
+			if subcommand.HasName(command) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Check if an API command returns the node's secrets (its mnemonic, password or private key)
+func (s *apiServer) isSecretRoute(group string, command string) bool {
+	for _, groupCommand := range s.apiCommand.Subcommands {
+		if !groupCommand.HasName(group) {
+			continue
+		}
+		for _, subcommand := range groupCommand.Subcommands {
+			if !subcommand.HasName(command) {
+				continue
+			}
+			return apitypes.IsSecretServerRoute(groupCommand.Name, subcommand.Name)
+		}
+	}
+	return false
+}
+
+// Build the command line that the request corresponds to
+func (s *apiServer) getCommandArgs(group string, command string, request apitypes.ServerRequest) []string {
+	args := []string{s.appName, "--settings", s.settings}
+	if request.IgnoreSyncCheck {
+		args = append(args, "--ignore-sync-check")
+	}
+	if request.ForceFallbacks {
+		args = append(args, "--force-fallbacks")
+	}
+	if request.Offline {
+		args = append(args, "--offline")
+	}
+	if request.MaxFee != 0 {
+		args = append(args, "--maxFee", strconv.FormatFloat(request.MaxFee, 'f', -1, 64))
+	}
+	if request.MaxPrioFee != 0 {
+		args = append(args, "--maxPrioFee", strconv.FormatFloat(request.MaxPrioFee, 'f', -1, 64))
+	}
+	if request.GasLimit != 0 {
+		args = append(args, "--gasLimit", strconv.FormatUint(request.GasLimit, 10))
+	}
+	if request.Nonce != "" {
+		args = append(args, "--nonce", request.Nonce)
+	}
+	args = append(args, apiCommandName, group, command)
+	return append(args, request.Args...)
+}
+
+// Write an error response using the standard API response format
+func writeError(w http.ResponseWriter, status int, err error) {
+	response := apitypes.APIResponse{
+		Status: "error",
+		Error:  err.Error(),
+	}
+	responseBytes, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(responseBytes)
+}
@@ -172,6 +172,42 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:      "get-slashing-protection-info",
+				Usage:     "Get the genesis validators root and validator pubkeys that slashing protection data is checked against",
+				UsageText: "rocketpool api wallet get-slashing-protection-info",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(getSlashingProtectionInfo(c))
+					return nil
+
+				},
+			},
+
+			{
+				Name:      "delete-validator-keys",
+				Usage:     "Remove all validator keys from the validator client through its Keymanager API and save their slashing protection data",
+				UsageText: "rocketpool api wallet delete-validator-keys",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(deleteValidatorKeys(c))
+					return nil
+
+				},
+			},
+
 			{
 				Name:      "test-recovery",
 				Aliases:   []string{"r"},
@@ -337,6 +373,42 @@ func RegisterSubcommands(command *cli.Command, name string, aliases []string) {
 
 				},
 			},
+
+			{
+				Name:      "sign-offline",
+				Usage:     "Sign a transaction that was exported in offline mode",
+				UsageText: "rocketpool api wallet sign-offline payload",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(signOffline(c, c.Args().Get(0)))
+					return nil
+
+				},
+			},
+
+			{
+				Name:      "broadcast",
+				Usage:     "Submit a transaction that was signed offline",
+				UsageText: "rocketpool api wallet broadcast payload",
+				Action: func(c *cli.Context) error {
+
+					// Validate args
+					if err := cliutils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+
+					// Run
+					api.PrintResponse(broadcast(c, c.Args().Get(0)))
+					return nil
+
+				},
+			},
 		},
 	})
 }
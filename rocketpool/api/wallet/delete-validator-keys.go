@@ -0,0 +1,31 @@
+package wallet
+
+import (
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	"github.com/rocket-pool/smartnode/shared/utils/validator"
+)
+
+func deleteValidatorKeys(c *cli.Context) (*api.DeleteValidatorKeysResponse, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.DeleteValidatorKeysResponse{}
+
+	// Remove the keys from the VC
+	response.DeletedKeys, response.SavedSlashingProtection, err = validator.DeleteValidatorKeys(cfg, cfg.Smartnode.GetSlashingProtectionBackupPath())
+	if err != nil {
+		return nil, err
+	}
+
+	// Return response
+	return &response, nil
+
+}
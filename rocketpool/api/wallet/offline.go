@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+func signOffline(c *cli.Context, payload string) (*api.SignOfflineTransactionResponse, error) {
+
+	// Get services
+	if err := services.RequireNodeWallet(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.SignOfflineTransactionResponse{}
+
+	// Parse the transaction
+	tx, err := wallet.ParseOfflineTransaction([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	if tx.ChainId().Cmp(w.GetChainID()) != 0 {
+		return nil, fmt.Errorf("The transaction is for chain %s, but your node is configured for chain %s.", tx.ChainId().String(), w.GetChainID().String())
+	}
+	if v, r, s := tx.RawSignatureValues(); v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0 {
+		return nil, fmt.Errorf("The transaction has already been signed.")
+	}
+
+	// Sign it
+	bytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling TX to binary: %w", err)
+	}
+	signedBytes, err := w.Sign(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error signing TX: %w", err)
+	}
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(signedBytes); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling signed TX: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("Error recovering TX sender: %w", err)
+	}
+
+	export, err := wallet.NewOfflineTransaction(signedTx, from, true)
+	if err != nil {
+		return nil, err
+	}
+	response.Transaction = *export
+
+	// Return response
+	return &response, nil
+
+}
+
+func broadcast(c *cli.Context, payload string) (*api.BroadcastTransactionResponse, error) {
+
+	// Get services
+	if err := services.RequireEthClientSynced(c); err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := services.GetEthClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response
+	response := api.BroadcastTransactionResponse{}
+
+	// Parse the transaction and make sure it was signed by the node
+	tx, err := wallet.ParseOfflineTransaction([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	if tx.ChainId().Cmp(w.GetChainID()) != 0 {
+		return nil, fmt.Errorf("The transaction is for chain %s, but your node is configured for chain %s.", tx.ChainId().String(), w.GetChainID().String())
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("The transaction has not been signed: %w", err)
+	}
+	nodeAccount, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	if from != nodeAccount.Address {
+		return nil, fmt.Errorf("The transaction was signed by %s, which is not your node address (%s).", from.Hex(), nodeAccount.Address.Hex())
+	}
+	response.From = from
+
+	// Send it
+	err = ec.SendTransaction(context.Background(), tx)
+	if err != nil {
+		return nil, fmt.Errorf("Error broadcasting TX: %w", err)
+	}
+	response.TxHash = tx.Hash()
+
+	// Return response
+	return &response, nil
+
+}
@@ -0,0 +1,142 @@
+package collectors
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The attestation and proposal results of one of the node's validators since the daemon started
+type ValidatorDutyStats struct {
+	AttestationsExpected uint64
+	AttestationsIncluded uint64
+	TotalInclusionDelay  uint64
+	ProposalsExpected    uint64
+	ProposalsMade        uint64
+
+	// The number of attestations missed within the configured window of recent epochs
+	RecentMissedAttestations uint64
+}
+
+// Holds the duty stats tracked by the node daemon so the metrics server can read them
+type DutiesLocker struct {
+	lastCheckedEpoch uint64
+	stats            map[string]ValidatorDutyStats
+
+	// Internal fields
+	lock *sync.RWMutex
+}
+
+func NewDutiesLocker() *DutiesLocker {
+	return &DutiesLocker{
+		stats: map[string]ValidatorDutyStats{},
+		lock:  &sync.RWMutex{},
+	}
+}
+
+func (l *DutiesLocker) UpdateStats(lastCheckedEpoch uint64, stats map[string]ValidatorDutyStats) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.lastCheckedEpoch = lastCheckedEpoch
+	l.stats = stats
+}
+
+func (l *DutiesLocker) GetStats() (uint64, map[string]ValidatorDutyStats) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.lastCheckedEpoch, l.stats
+}
+
+// Represents the collector for the node's validator duty metrics
+type DutiesCollector struct {
+	// The most recent epoch that has been checked for missed duties
+	lastCheckedEpoch *prometheus.Desc
+
+	// The fraction of each validator's attestations that were included on chain
+	attestationInclusionRate *prometheus.Desc
+
+	// The average number of slots it took for each validator's attestations to be included
+	averageInclusionDelay *prometheus.Desc
+
+	// The number of attestations each validator missed in the recent window
+	recentMissedAttestations *prometheus.Desc
+
+	// The number of blocks each validator proposed
+	proposals *prometheus.Desc
+
+	// The number of block proposals each validator missed
+	missedProposals *prometheus.Desc
+
+	// The thread-safe locker for the duty stats
+	dutiesLocker *DutiesLocker
+}
+
+// Create a new DutiesCollector instance
+func NewDutiesCollector(dutiesLocker *DutiesLocker) *DutiesCollector {
+	subsystem := "duties"
+	return &DutiesCollector{
+		lastCheckedEpoch: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "last_checked_epoch"),
+			"The most recent finalized epoch checked for missed duties",
+			nil, nil,
+		),
+		attestationInclusionRate: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "attestation_inclusion_rate"),
+			"The fraction of the validator's attestations included on chain since the node daemon started",
+			[]string{"validator"}, nil,
+		),
+		averageInclusionDelay: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "attestation_average_inclusion_delay"),
+			"The average number of slots between the validator's attestations and their inclusion",
+			[]string{"validator"}, nil,
+		),
+		recentMissedAttestations: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "recent_missed_attestations"),
+			"The number of attestations the validator missed in the configured window of recent epochs",
+			[]string{"validator"}, nil,
+		),
+		proposals: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "proposals_total"),
+			"The number of blocks the validator proposed since the node daemon started",
+			[]string{"validator"}, nil,
+		),
+		missedProposals: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "missed_proposals_total"),
+			"The number of block proposals the validator missed since the node daemon started",
+			[]string{"validator"}, nil,
+		),
+		dutiesLocker: dutiesLocker,
+	}
+}
+
+// Write metric descriptions to the Prometheus channel
+func (collector *DutiesCollector) Describe(channel chan<- *prometheus.Desc) {
+	channel <- collector.lastCheckedEpoch
+	channel <- collector.attestationInclusionRate
+	channel <- collector.averageInclusionDelay
+	channel <- collector.recentMissedAttestations
+	channel <- collector.proposals
+	channel <- collector.missedProposals
+}
+
+// Collect the latest metric values and pass them to Prometheus
+func (collector *DutiesCollector) Collect(channel chan<- prometheus.Metric) {
+	lastCheckedEpoch, stats := collector.dutiesLocker.GetStats()
+	if len(stats) == 0 {
+		return
+	}
+
+	channel <- prometheus.MustNewConstMetric(
+		collector.lastCheckedEpoch, prometheus.GaugeValue, float64(lastCheckedEpoch))
+
+	for validator, stat := range stats {
+		if stat.AttestationsExpected > 0 {
+			channel <- prometheus.MustNewConstMetric(
+				collector.attestationInclusionRate, prometheus.GaugeValue, float64(stat.AttestationsIncluded)/float64(stat.AttestationsExpected), validator)
+		}
+		if stat.AttestationsIncluded > 0 {
+			channel <- prometheus.MustNewConstMetric(
+				collector.averageInclusionDelay, prometheus.GaugeValue, float64(stat.TotalInclusionDelay)/float64(stat.AttestationsIncluded), validator)
+		}
+		channel <- prometheus.MustNewConstMetric(
+			collector.recentMissedAttestations, prometheus.GaugeValue, float64(stat.RecentMissedAttestations), validator)
+		channel <- prometheus.MustNewConstMetric(
+			collector.proposals, prometheus.CounterValue, float64(stat.ProposalsMade), validator)
+		channel <- prometheus.MustNewConstMetric(
+			collector.missedProposals, prometheus.CounterValue, float64(stat.ProposalsExpected-stat.ProposalsMade), validator)
+	}
+}
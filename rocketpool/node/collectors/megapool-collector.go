@@ -0,0 +1,335 @@
+package collectors
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+)
+
+// The statuses a megapool validator can be in, in the order they're checked
+const (
+	megapoolValidatorStatus_Dissolved   string = "dissolved"
+	megapoolValidatorStatus_Exited      string = "exited"
+	megapoolValidatorStatus_Locked      string = "locked"
+	megapoolValidatorStatus_Exiting     string = "exiting"
+	megapoolValidatorStatus_Staked      string = "staked"
+	megapoolValidatorStatus_Prestaked   string = "prestaked"
+	megapoolValidatorStatus_InQueue     string = "in_queue"
+	megapoolValidatorStatus_Initialized string = "initialized"
+)
+
+// Represents the collector for the node's megapool metrics
+type MegapoolCollector struct {
+	// Whether the node's megapool has been deployed
+	deployed *prometheus.Desc
+
+	// Whether the megapool's delegate has expired
+	delegateExpired *prometheus.Desc
+
+	// The number of the megapool's validators in each status
+	validatorCount *prometheus.Desc
+
+	// The status of each of the megapool's validators
+	validatorStatus *prometheus.Desc
+
+	// The position of each queued validator in its deposit queue
+	validatorQueuePosition *prometheus.Desc
+
+	// Whether each validator is locked by an exit challenge
+	validatorExitChallenged *prometheus.Desc
+
+	// The ETH bonded by the node
+	bondedEth *prometheus.Desc
+
+	// The ETH borrowed from the deposit pool
+	borrowedEth *prometheus.Desc
+
+	// The debt the node owes the megapool
+	nodeDebt *prometheus.Desc
+
+	// The ETH the node can claim as a refund
+	refundValue *prometheus.Desc
+
+	// The rewards waiting to be distributed
+	pendingRewards *prometheus.Desc
+
+	// The split of the pending rewards between the node, voters, the pDAO and rETH
+	pendingRewardSplit *prometheus.Desc
+
+	// The number of express queue tickets the node has left
+	expressTickets *prometheus.Desc
+
+	// The Rocket Pool contract manager
+	rp *rocketpool.RocketPool
+
+	// The beacon client
+	bc beacon.Client
+
+	// The node's address
+	nodeAddress common.Address
+
+	// The thread-safe locker for the network state
+	stateLocker *StateLocker
+
+	// Cached data, which is loaded again once the network state is updated
+	cacheBlock    uint64
+	cachedDetails *api.MegapoolDetails
+	cachedRewards *api.MegapoolRewardSplitResponse
+	cacheLock     sync.Mutex
+
+	// Prefix for logging
+	logPrefix string
+}
+
+// Create a new MegapoolCollector instance
+func NewMegapoolCollector(rp *rocketpool.RocketPool, bc beacon.Client, nodeAddress common.Address, stateLocker *StateLocker) *MegapoolCollector {
+	subsystem := "megapool"
+	return &MegapoolCollector{
+		deployed: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "deployed"),
+			"Whether the node's megapool has been deployed",
+			nil, nil,
+		),
+		delegateExpired: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "delegate_expired"),
+			"Whether the megapool's delegate has expired",
+			nil, nil,
+		),
+		validatorCount: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "validator_count"),
+			"The number of the megapool's validators, broken down by status",
+			[]string{"status"}, nil,
+		),
+		validatorStatus: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "validator_status"),
+			"The status of the megapool validator, set to 1 for its current status",
+			[]string{"validator", "pubkey", "status"}, nil,
+		),
+		validatorQueuePosition: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "validator_queue_position"),
+			"The position of the validator in its deposit queue, where 1 is the next to be assigned",
+			[]string{"validator", "queue"}, nil,
+		),
+		validatorExitChallenged: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "validator_exit_challenged"),
+			"Whether the validator is locked by an exit challenge",
+			[]string{"validator"}, nil,
+		),
+		bondedEth: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "bonded_eth"),
+			"The amount of ETH bonded by the node in the megapool",
+			nil, nil,
+		),
+		borrowedEth: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "borrowed_eth"),
+			"The amount of ETH the megapool borrowed from the deposit pool",
+			nil, nil,
+		),
+		nodeDebt: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "node_debt"),
+			"The amount of ETH the node owes the megapool",
+			nil, nil,
+		),
+		refundValue: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "refund_value"),
+			"The amount of ETH the node can claim as a refund from the megapool",
+			nil, nil,
+		),
+		pendingRewards: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "pending_rewards"),
+			"The amount of ETH rewards in the megapool waiting to be distributed",
+			nil, nil,
+		),
+		pendingRewardSplit: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "pending_reward_split"),
+			"How the megapool's pending rewards would be split if they were distributed now",
+			[]string{"recipient"}, nil,
+		),
+		expressTickets: prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "express_tickets"),
+			"The number of express queue tickets the node has left",
+			nil, nil,
+		),
+		rp:          rp,
+		bc:          bc,
+		nodeAddress: nodeAddress,
+		stateLocker: stateLocker,
+		logPrefix:   "Megapool Collector",
+	}
+}
+
+// Write metric descriptions to the Prometheus channel
+func (collector *MegapoolCollector) Describe(channel chan<- *prometheus.Desc) {
+	channel <- collector.deployed
+	channel <- collector.delegateExpired
+	channel <- collector.validatorCount
+	channel <- collector.validatorStatus
+	channel <- collector.validatorQueuePosition
+	channel <- collector.validatorExitChallenged
+	channel <- collector.bondedEth
+	channel <- collector.borrowedEth
+	channel <- collector.nodeDebt
+	channel <- collector.refundValue
+	channel <- collector.pendingRewards
+	channel <- collector.pendingRewardSplit
+	channel <- collector.expressTickets
+}
+
+// Collect the latest metric values and pass them to Prometheus
+func (collector *MegapoolCollector) Collect(channel chan<- prometheus.Metric) {
+	// Get the latest state
+	state := collector.stateLocker.GetState()
+	if state == nil || !state.IsSaturnDeployed {
+		return
+	}
+
+	details, rewards, err := collector.getMegapoolDetails(state.ElBlockNumber)
+	if err != nil {
+		collector.logError(err)
+		return
+	}
+
+	channel <- prometheus.MustNewConstMetric(
+		collector.deployed, prometheus.GaugeValue, boolToFloat(details.Deployed))
+	if !details.Deployed {
+		return
+	}
+	channel <- prometheus.MustNewConstMetric(
+		collector.delegateExpired, prometheus.GaugeValue, boolToFloat(details.DelegateExpired))
+	if details.DelegateExpired {
+		// The megapool can't be used until its delegate is upgraded, so the rest of its details aren't loaded
+		return
+	}
+
+	// Validators
+	statusCounts := map[string]float64{
+		megapoolValidatorStatus_Dissolved:   0,
+		megapoolValidatorStatus_Exited:      0,
+		megapoolValidatorStatus_Locked:      0,
+		megapoolValidatorStatus_Exiting:     0,
+		megapoolValidatorStatus_Staked:      0,
+		megapoolValidatorStatus_Prestaked:   0,
+		megapoolValidatorStatus_InQueue:     0,
+		megapoolValidatorStatus_Initialized: 0,
+	}
+	for _, validator := range details.Validators {
+		id := strconv.FormatUint(uint64(validator.ValidatorId), 10)
+		status := getMegapoolValidatorStatus(validator)
+		statusCounts[status]++
+
+		channel <- prometheus.MustNewConstMetric(
+			collector.validatorStatus, prometheus.GaugeValue, 1, id, validator.PubKey.Hex(), status)
+		channel <- prometheus.MustNewConstMetric(
+			collector.validatorExitChallenged, prometheus.GaugeValue, boolToFloat(validator.Locked), id)
+
+		if validator.InQueue && validator.QueuePosition != nil {
+			queue := "standard"
+			if validator.ExpressUsed {
+				queue = "express"
+			}
+			position := float64(validator.QueuePosition.Uint64() + 1)
+			channel <- prometheus.MustNewConstMetric(
+				collector.validatorQueuePosition, prometheus.GaugeValue, position, id, queue)
+		}
+	}
+	for status, count := range statusCounts {
+		channel <- prometheus.MustNewConstMetric(
+			collector.validatorCount, prometheus.GaugeValue, count, status)
+	}
+
+	// Balances
+	channel <- prometheus.MustNewConstMetric(
+		collector.bondedEth, prometheus.GaugeValue, weiToEth(details.NodeBond))
+	channel <- prometheus.MustNewConstMetric(
+		collector.borrowedEth, prometheus.GaugeValue, weiToEth(details.UserCapital))
+	channel <- prometheus.MustNewConstMetric(
+		collector.nodeDebt, prometheus.GaugeValue, weiToEth(details.NodeDebt))
+	channel <- prometheus.MustNewConstMetric(
+		collector.refundValue, prometheus.GaugeValue, weiToEth(details.RefundValue))
+	channel <- prometheus.MustNewConstMetric(
+		collector.pendingRewards, prometheus.GaugeValue, weiToEth(details.PendingRewards))
+	channel <- prometheus.MustNewConstMetric(
+		collector.expressTickets, prometheus.GaugeValue, float64(details.NodeExpressTicketCount))
+
+	// The split of the pending rewards is left out if it couldn't be calculated
+	if rewards == nil {
+		return
+	}
+	channel <- prometheus.MustNewConstMetric(
+		collector.pendingRewardSplit, prometheus.GaugeValue, weiToEth(rewards.RewardSplit.NodeRewards), "node")
+	channel <- prometheus.MustNewConstMetric(
+		collector.pendingRewardSplit, prometheus.GaugeValue, weiToEth(rewards.RewardSplit.VoterRewards), "voter")
+	channel <- prometheus.MustNewConstMetric(
+		collector.pendingRewardSplit, prometheus.GaugeValue, weiToEth(rewards.RewardSplit.ProtocolDAORewards), "pdao")
+	channel <- prometheus.MustNewConstMetric(
+		collector.pendingRewardSplit, prometheus.GaugeValue, weiToEth(rewards.RewardSplit.RethRewards), "reth")
+}
+
+// Get the megapool's details and the split of its pending rewards, loading them again if the network state was updated since they were cached.
+// The reward split is nil if it couldn't be calculated.
+func (collector *MegapoolCollector) getMegapoolDetails(blockNumber uint64) (*api.MegapoolDetails, *api.MegapoolRewardSplitResponse, error) {
+	collector.cacheLock.Lock()
+	defer collector.cacheLock.Unlock()
+
+	if collector.cachedDetails == nil || collector.cacheBlock != blockNumber {
+		details, err := services.GetNodeMegapoolDetails(collector.rp, collector.bc, collector.nodeAddress)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting megapool details: %w", err)
+		}
+		collector.cachedDetails = &details
+		collector.cachedRewards = nil
+		collector.cacheBlock = blockNumber
+	}
+
+	// Try the reward split again on every scrape until it succeeds
+	details := collector.cachedDetails
+	if collector.cachedRewards == nil && details.Deployed && !details.DelegateExpired {
+		rewards, err := services.CalculateRewards(collector.rp, details.PendingRewards, collector.nodeAddress)
+		if err != nil {
+			collector.logError(fmt.Errorf("error calculating the megapool's pending reward split: %w", err))
+		} else {
+			collector.cachedRewards = &rewards
+		}
+	}
+
+	return details, collector.cachedRewards, nil
+}
+
+// Get the status of a megapool validator for its metric label
+func getMegapoolValidatorStatus(validator api.MegapoolValidatorDetails) string {
+	switch {
+	case validator.Dissolved:
+		return megapoolValidatorStatus_Dissolved
+	case validator.Exited:
+		return megapoolValidatorStatus_Exited
+	case validator.Locked:
+		return megapoolValidatorStatus_Locked
+	case validator.Exiting:
+		return megapoolValidatorStatus_Exiting
+	case validator.Staked:
+		return megapoolValidatorStatus_Staked
+	case validator.InPrestake:
+		return megapoolValidatorStatus_Prestaked
+	case validator.InQueue:
+		return megapoolValidatorStatus_InQueue
+	default:
+		return megapoolValidatorStatus_Initialized
+	}
+}
+
+// Convert a wei amount to ETH, treating a missing amount as zero
+func weiToEth(amount *big.Int) float64 {
+	if amount == nil {
+		return 0
+	}
+	return eth.WeiToEth(amount)
+}
+
+// Convert a bool to a gauge value
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Log error messages
+func (collector *MegapoolCollector) logError(err error) {
+	fmt.Printf("[%s] %s\n", collector.logPrefix, err.Error())
+}
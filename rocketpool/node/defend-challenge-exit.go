@@ -1,6 +1,7 @@
 package node
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/docker/docker/client"
@@ -13,6 +14,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
@@ -77,7 +79,7 @@ func newDefendChallengeExit(c *cli.Context, logger log.ColorLogger) (*defendChal
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -161,7 +163,13 @@ func (t *defendChallengeExit) run(state *state.NetworkState) error {
 				t.log.Printlnf("The validator %d was incorrectly challenged and needs a not-exiting proof", validatorInfo[i].ValidatorId)
 			}
 
-			t.defendChallenge(t.rp, mp, validatorInfo[i].ValidatorId, state, types.ValidatorPubkey(validatorInfo[i].PubKey), exiting, opts)
+			success, err := t.defendChallenge(t.rp, mp, validatorInfo[i].ValidatorId, state, types.ValidatorPubkey(validatorInfo[i].PubKey), exiting, opts)
+			if success || err != nil {
+				alerting.AlertMegapoolChallengeDefended(t.cfg, megapoolAddress, validatorInfo[i].ValidatorId, err == nil)
+			}
+			if err != nil {
+				t.log.Println(fmt.Errorf("Could not respond to the exit challenge for validator %d: %w", validatorInfo[i].ValidatorId, err))
+			}
 		}
 
 	}
@@ -171,20 +179,20 @@ func (t *defendChallengeExit) run(state *state.NetworkState) error {
 
 }
 
-func (t *defendChallengeExit) defendChallenge(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, exiting bool, callopts *bind.CallOpts) error {
+func (t *defendChallengeExit) defendChallenge(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, exiting bool, callopts *bind.CallOpts) (bool, error) {
 
 	// Get transactor
 	opts, err := t.w.GetNodeAccountTransactor()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	t.log.Printlnf("[STARTED] Crafting a validator proof. This process can take several seconds and is CPU and memory intensive. If you don't see a [FINISHED] log entry your system may not have enough resources to perform this operation.")
 
 	proof, err := services.GetValidatorProof(t.c, t.w, state.BeaconConfig, mp.GetAddress(), validatorPubkey)
 	if err != nil {
-		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %w", err)
-		return err
+		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %s", err.Error())
+		return false, err
 	}
 
 	t.log.Printlnf("[FINISHED] The beacon state proof has been successfully created.")
@@ -194,12 +202,12 @@ func (t *defendChallengeExit) defendChallenge(rp *rocketpool.RocketPool, mp mega
 		// Get the gas limit
 		gasInfo, err = megapool.EstimateNotifyNotExitGas(rp, mp.GetAddress(), validatorId, proof, opts)
 		if err != nil {
-			return err
+			return false, err
 		}
 	} else {
 		gasInfo, err = megapool.EstimateNotifyExitGas(rp, mp.GetAddress(), validatorId, proof, opts)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
@@ -209,13 +217,13 @@ func (t *defendChallengeExit) defendChallenge(rp *rocketpool.RocketPool, mp mega
 	if maxFee == nil || maxFee.Uint64() == 0 {
 		maxFee, err = rpgas.GetHeadlessMaxFeeWei(t.cfg)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Print the gas info
 	if !api.PrintAndCheckGasInfo(gasInfo, true, t.gasThreshold, &t.log, maxFee, t.gasLimit) {
-		return nil
+		return false, nil
 	}
 
 	opts.GasFeeCap = maxFee
@@ -227,28 +235,28 @@ func (t *defendChallengeExit) defendChallenge(rp *rocketpool.RocketPool, mp mega
 		t.log.Printlnf("Notifying that validator %d is not exiting.", validatorId)
 		tx, err = megapool.NotifyNotExit(rp, mp.GetAddress(), validatorId, proof, opts)
 		if err != nil {
-			return err
+			return false, err
 		}
 	} else {
 		t.log.Printlnf("Notifying that validator %d is exiting.", validatorId)
 		tx, err = megapool.NotifyExit(rp, mp.GetAddress(), validatorId, proof, opts)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Print TX info and wait for it to be included in a block
 	err = api.PrintAndWaitForTransaction(t.cfg, tx.Hash(), t.rp.Client, &t.log)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Log
 	t.log.Printlnf("Successfully responded to exit-challenge for validator %d.", validatorId)
 
 	// Return
-	return nil
+	return true, nil
 }
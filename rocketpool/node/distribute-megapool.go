@@ -0,0 +1,315 @@
+package node
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	"github.com/rocket-pool/smartnode/shared/utils/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Distribute megapool task
+type distributeMegapool struct {
+	c                    *cli.Context
+	log                  log.ColorLogger
+	cfg                  *config.RocketPoolConfig
+	w                    wallet.Wallet
+	rp                   *rocketpool.RocketPool
+	gasThreshold         float64
+	distributeThreshold  *big.Int
+	claimRefundThreshold *big.Int
+	maxFee               *big.Int
+	maxPriorityFee       *big.Int
+}
+
+// Create distribute megapool task
+func newDistributeMegapool(c *cli.Context, logger log.ColorLogger) (*distributeMegapool, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetHdWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := services.GetRocketPool(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if auto-distributing and auto-claiming are disabled; a nil threshold disables that action
+	gasThreshold := cfg.Smartnode.AutoTxGasThreshold.Value.(float64)
+	distributeThreshold := cfg.Smartnode.MegapoolDistributeThreshold.Value.(float64)
+	claimRefundThreshold := cfg.Smartnode.MegapoolClaimRefundThreshold.Value.(float64)
+	var distributeThresholdWei *big.Int
+	var claimRefundThresholdWei *big.Int
+	if gasThreshold == 0 {
+		logger.Println("Automatic tx gas threshold is 0, disabling megapool auto-distribute and auto-claim.")
+	} else {
+		if distributeThreshold == 0 {
+			logger.Println("Megapool auto-distribute threshold is 0, disabling megapool auto-distribute.")
+		} else {
+			distributeThresholdWei = eth.EthToWei(distributeThreshold)
+		}
+		if claimRefundThreshold == 0 {
+			logger.Println("Megapool auto-claim refund threshold is 0, disabling megapool auto-claim.")
+		} else {
+			claimRefundThresholdWei = eth.EthToWei(claimRefundThreshold)
+		}
+	}
+
+	// Get the user-requested max fee
+	maxFeeGwei := cfg.Smartnode.ManualMaxFee.Value.(float64)
+	var maxFee *big.Int
+	if maxFeeGwei == 0 {
+		maxFee = nil
+	} else {
+		maxFee = eth.GweiToWei(maxFeeGwei)
+	}
+
+	// Get the user-requested max fee
+	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
+	var priorityFee *big.Int
+	if priorityFeeGwei == 0 {
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		priorityFee = eth.GweiToWei(2)
+	} else {
+		priorityFee = eth.GweiToWei(priorityFeeGwei)
+	}
+
+	// Return task
+	return &distributeMegapool{
+		c:                    c,
+		log:                  logger,
+		cfg:                  cfg,
+		w:                    w,
+		rp:                   rp,
+		gasThreshold:         gasThreshold,
+		distributeThreshold:  distributeThresholdWei,
+		claimRefundThreshold: claimRefundThresholdWei,
+		maxFee:               maxFee,
+		maxPriorityFee:       priorityFee,
+	}, nil
+
+}
+
+// Distribute the megapool's rewards and claim its refund
+func (t *distributeMegapool) run(state *state.NetworkState) error {
+
+	// Check if both actions are disabled
+	if t.distributeThreshold == nil && t.claimRefundThreshold == nil {
+		return nil
+	}
+	if !state.IsSaturnDeployed {
+		return nil
+	}
+
+	// Get the latest state
+	opts := &bind.CallOpts{
+		BlockNumber: big.NewInt(0).SetUint64(state.ElBlockNumber),
+	}
+
+	// Get node account
+	nodeAccount, err := t.w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+
+	// Check if the megapool is deployed
+	deployed, err := megapool.GetMegapoolDeployed(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return err
+	}
+	if !deployed {
+		return nil
+	}
+
+	// Load the megapool
+	megapoolAddress, err := megapool.GetMegapoolExpectedAddress(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return err
+	}
+	mp, err := megapool.NewMegaPoolV1(t.rp, megapoolAddress, opts)
+	if err != nil {
+		return err
+	}
+
+	// Log
+	t.log.Println("Checking the megapool's pending rewards and refund...")
+
+	// Distribute first, since distributing adds the node's share of the rewards to the refund
+	if t.distributeThreshold != nil {
+		canDistribute, err := t.canDistribute(mp, opts)
+		if err != nil {
+			return err
+		}
+		if canDistribute {
+			success, err := t.distribute(mp)
+			if success || err != nil {
+				alerting.AlertMegapoolRewardsDistributed(t.cfg, megapoolAddress, err == nil)
+			}
+			if err != nil {
+				return fmt.Errorf("Could not distribute the rewards of megapool %s: %w", megapoolAddress.Hex(), err)
+			}
+		}
+	}
+
+	if t.claimRefundThreshold != nil {
+		// Use the latest block so a refund from the distribution above is included
+		refund, err := mp.GetRefundValue(nil)
+		if err != nil {
+			return err
+		}
+		if refund.Cmp(t.claimRefundThreshold) >= 0 {
+			t.log.Printlnf("The megapool's refund of %.6f ETH is above the threshold.", eth.WeiToEth(refund))
+			success, err := t.claimRefund(mp)
+			if success || err != nil {
+				alerting.AlertMegapoolRefundClaimed(t.cfg, megapoolAddress, err == nil)
+			}
+			if err != nil {
+				return fmt.Errorf("Could not claim the refund of megapool %s: %w", megapoolAddress.Hex(), err)
+			}
+		}
+	}
+
+	// Return
+	return nil
+
+}
+
+// Check if the megapool's pending rewards are above the threshold and can be distributed
+func (t *distributeMegapool) canDistribute(mp megapool.Megapool, opts *bind.CallOpts) (bool, error) {
+
+	// The rewards can't be distributed until a validator has been staked
+	lastDistributionBlock, err := mp.GetLastDistributionBlock(opts)
+	if err != nil {
+		return false, err
+	}
+	if lastDistributionBlock == 0 {
+		return false, nil
+	}
+
+	pendingRewards, err := mp.GetPendingRewards(opts)
+	if err != nil {
+		return false, err
+	}
+	if pendingRewards.Cmp(t.distributeThreshold) < 0 {
+		return false, nil
+	}
+
+	// Distributing isn't allowed while validators are locked or exiting
+	lockedCount, err := mp.GetLockedValidatorCount(opts)
+	if err != nil {
+		return false, err
+	}
+	exitingCount, err := mp.GetExitingValidatorCount(opts)
+	if err != nil {
+		return false, err
+	}
+	if lockedCount > 0 || exitingCount > 0 {
+		t.log.Printlnf("The megapool has %.6f ETH of pending rewards, but they can't be distributed while it has %d locked and %d exiting validators.", eth.WeiToEth(pendingRewards), lockedCount, exitingCount)
+		return false, nil
+	}
+
+	t.log.Printlnf("The megapool's pending rewards of %.6f ETH are above the threshold.", eth.WeiToEth(pendingRewards))
+	return true, nil
+
+}
+
+// Distribute the megapool's pending rewards
+func (t *distributeMegapool) distribute(mp megapool.Megapool) (bool, error) {
+
+	// Log
+	t.log.Printlnf("Distributing the rewards of megapool %s...", mp.GetAddress().Hex())
+
+	success, err := t.submitTransaction(mp.EstimateDistributeGas, mp.Distribute)
+	if err != nil || !success {
+		return success, err
+	}
+
+	// Log
+	t.log.Printlnf("Successfully distributed the rewards of megapool %s.", mp.GetAddress().Hex())
+	return true, nil
+
+}
+
+// Claim the megapool's refund
+func (t *distributeMegapool) claimRefund(mp megapool.Megapool) (bool, error) {
+
+	// Log
+	t.log.Printlnf("Claiming the refund of megapool %s...", mp.GetAddress().Hex())
+
+	success, err := t.submitTransaction(mp.EstimateClaimRefundGas, mp.ClaimRefund)
+	if err != nil || !success {
+		return success, err
+	}
+
+	// Log
+	t.log.Printlnf("Successfully claimed the refund of megapool %s.", mp.GetAddress().Hex())
+	return true, nil
+
+}
+
+// Submit a transaction if the gas price is under the threshold, and wait for it to be included
+func (t *distributeMegapool) submitTransaction(estimateGas func(*bind.TransactOpts) (rocketpool.GasInfo, error), submit func(*bind.TransactOpts) (common.Hash, error)) (bool, error) {
+
+	// Get transactor
+	opts, err := t.w.GetNodeAccountTransactor()
+	if err != nil {
+		return false, err
+	}
+
+	// Get the gas limit
+	gasInfo, err := estimateGas(opts)
+	if err != nil {
+		return false, fmt.Errorf("Could not estimate the gas required: %w", err)
+	}
+
+	// Get the max fee
+	maxFee := t.maxFee
+	if maxFee == nil || maxFee.Uint64() == 0 {
+		maxFee, err = rpgas.GetHeadlessMaxFeeWei(t.cfg)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Print the gas info
+	if !api.PrintAndCheckGasInfo(gasInfo, true, t.gasThreshold, &t.log, maxFee, 0) {
+		return false, nil
+	}
+
+	opts.GasFeeCap = maxFee
+	opts.GasTipCap = GetPriorityFee(t.maxPriorityFee, maxFee)
+	opts.GasLimit = gasInfo.SafeGasLimit
+
+	// Submit the transaction
+	hash, err := submit(opts)
+	if err != nil {
+		return false, err
+	}
+
+	// Print TX info and wait for it to be included in a block
+	err = api.PrintAndWaitForTrackedTransaction(t.cfg, hash, t.rp.Client, opts, GetAutoTxMaxFeeCap(t.gasThreshold, maxFee), t.maxPriorityFee, &t.log)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+
+}
@@ -78,7 +78,7 @@ func newDistributeMinipools(c *cli.Context, logger log.ColorLogger) (*distribute
 	} else {
 		// Safety clamp
 		if distributeThreshold >= 8 {
-			logger.Printlnf("WARNING: Auto-distribute threshold is more than 8 ETH (%.6f ETH), reducing to 7.5 ETH for safety", distributeThreshold)
+			logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Auto-distribute threshold is more than 8 ETH (%.6f ETH), reducing to 7.5 ETH for safety", distributeThreshold)
 			distributeThreshold = 7.5
 		} else if distributeThreshold == 0 {
 			logger.Println("Auto-distribute threshold is 0, disabling auto-distribute.")
@@ -99,7 +99,7 @@ func newDistributeMinipools(c *cli.Context, logger log.ColorLogger) (*distribute
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -264,7 +264,7 @@ func (t *distributeMinipools) distributeMinipool(mpd *rpstate.NativeMinipoolDeta
 	}
 
 	// Print TX info and wait for it to be included in a block
-	err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, &t.log)
+	err = api.PrintAndWaitForTrackedTransaction(t.cfg, hash, t.rp.Client, opts, GetAutoTxMaxFeeCap(t.gasThreshold, maxFee), t.maxPriorityFee, &t.log)
 	if err != nil {
 		return false, err
 	}
@@ -109,7 +109,7 @@ func (m *manageFeeRecipient) run(state *state.NetworkState) error {
 	if !fileExists {
 		m.log.Println("Fee recipient files don't all exist, regenerating...")
 	} else if !correctAddress {
-		m.log.Printlnf("WARNING: Fee recipient files did not contain the correct fee recipient of %s, regenerating...", correctFeeRecipient.Hex())
+		m.log.WithLevel(log.LevelWarn).Printlnf("WARNING: Fee recipient files did not contain the correct fee recipient of %s, regenerating...", correctFeeRecipient.Hex())
 	} else {
 		// Files are all correct, return.
 		return nil
@@ -130,15 +130,15 @@ func (m *manageFeeRecipient) run(state *state.NetworkState) error {
 		return nil
 	}
 
-	// Restart the VC
-	m.log.Println("Fee recipient files updated successfully! Restarting validator client...")
-	err = validator.RestartValidator(m.cfg, m.bc, &m.log, m.d)
+	// Update the VC
+	m.log.Println("Fee recipient files updated successfully! Updating validator client...")
+	err = validator.ApplyFeeRecipient(m.cfg, m.bc, &m.log, m.d, correctFeeRecipient)
 	if err != nil {
-		return fmt.Errorf("error restarting validator client: %w", err)
+		return fmt.Errorf("error updating validator client: %w", err)
 	}
 
 	// Log & return
-	m.log.Println("Successfully restarted, you are now validating safely.")
+	m.log.Println("Successfully updated, you are now validating safely.")
 	return nil
 
 }
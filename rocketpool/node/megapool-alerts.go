@@ -0,0 +1,181 @@
+package node
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Megapool alerts task
+type megapoolAlerts struct {
+	log log.ColorLogger
+	cfg *config.RocketPoolConfig
+	w   wallet.Wallet
+	rp  *rocketpool.RocketPool
+	bc  beacon.Client
+
+	// The megapool as of the previous run, to compare against
+	previous *megapoolSnapshot
+}
+
+// The parts of a megapool that alerts are raised for
+type megapoolSnapshot struct {
+	address    common.Address
+	debt       *big.Int
+	validators map[uint32]api.MegapoolValidatorDetails
+}
+
+// Create megapool alerts task
+func newMegapoolAlerts(c *cli.Context, logger log.ColorLogger) (*megapoolAlerts, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := services.GetRocketPool(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return task
+	return &megapoolAlerts{
+		log: logger,
+		cfg: cfg,
+		w:   w,
+		rp:  rp,
+		bc:  bc,
+	}, nil
+
+}
+
+// Raise alerts for the changes to the node's megapool since the last run
+func (t *megapoolAlerts) run(state *state.NetworkState) error {
+	if !state.IsSaturnDeployed {
+		return nil
+	}
+
+	// Nothing to check if alerting is disabled
+	if t.cfg.Alertmanager.EnableAlerting.Value != true {
+		return nil
+	}
+
+	// Get the latest state
+	opts := &bind.CallOpts{
+		BlockNumber: big.NewInt(0).SetUint64(state.ElBlockNumber),
+	}
+
+	// Get node account
+	nodeAccount, err := t.w.GetNodeAccount()
+	if err != nil {
+		return err
+	}
+
+	// Check if the megapool is deployed
+	deployed, err := megapool.GetMegapoolDeployed(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return err
+	}
+	if !deployed {
+		return nil
+	}
+
+	// Get the megapool address
+	megapoolAddress, err := megapool.GetMegapoolExpectedAddress(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return err
+	}
+
+	// Load the megapool
+	mp, err := megapool.NewMegaPoolV1(t.rp, megapoolAddress, opts)
+	if err != nil {
+		return err
+	}
+	debt, err := mp.GetDebt(opts)
+	if err != nil {
+		return err
+	}
+	validatorCount, err := mp.GetValidatorCount(opts)
+	if err != nil {
+		return err
+	}
+	validatorInfo, err := services.GetMegapoolValidatorDetails(t.rp, t.bc, mp, megapoolAddress, uint32(validatorCount))
+	if err != nil {
+		return err
+	}
+	current := &megapoolSnapshot{
+		address:    megapoolAddress,
+		debt:       debt,
+		validators: make(map[uint32]api.MegapoolValidatorDetails, len(validatorInfo)),
+	}
+	for _, validator := range validatorInfo {
+		current.validators[validator.ValidatorId] = validator
+	}
+
+	// The first run only records the megapool, so restarting the daemon doesn't repeat old alerts
+	previous := t.previous
+	t.previous = current
+	if previous == nil || previous.address != current.address {
+		return nil
+	}
+
+	if current.debt.Cmp(previous.debt) > 0 {
+		t.log.Printlnf("The megapool's debt increased to %.6f ETH.", eth.WeiToEth(current.debt))
+		alerting.AlertMegapoolDebtIncurred(t.cfg, megapoolAddress, current.debt)
+	}
+
+	for id, validator := range current.validators {
+		last, exists := previous.validators[id]
+		if !exists {
+			continue
+		}
+
+		if validator.Dissolved && !last.Dissolved {
+			t.log.Printlnf("Validator %d was dissolved.", id)
+			alerting.AlertMegapoolValidatorDissolved(t.cfg, megapoolAddress, id)
+		}
+		if validator.Locked && !last.Locked {
+			t.log.Printlnf("Validator %d was challenged for exiting without notifying the megapool.", id)
+			alerting.AlertMegapoolValidatorChallenged(t.cfg, megapoolAddress, id)
+		}
+
+		// A correct challenge is cleared by notifying the validator's exit, which charges the late notification fine;
+		// an incorrect one is cleared with a proof that the validator isn't exiting
+		if last.Locked && !validator.Locked && (validator.Exiting || validator.Exited) {
+			t.log.Printlnf("The exit challenge against validator %d was lost, so the late exit notification was fined.", id)
+			alerting.AlertMegapoolChallengeLost(t.cfg, megapoolAddress, id)
+		}
+
+		if validator.InQueue && validator.ExpressUsed && validator.QueuePosition != nil {
+			if last.QueuePosition == nil || last.QueuePosition.Cmp(validator.QueuePosition) != 0 {
+				alerting.AlertMegapoolExpressQueuePositionChanged(t.cfg, megapoolAddress, id, validator.QueuePosition)
+			}
+		}
+	}
+
+	// Return
+	return nil
+
+}
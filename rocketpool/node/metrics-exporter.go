@@ -16,7 +16,7 @@ import (
 	"github.com/urfave/cli"
 )
 
-func runMetricsServer(c *cli.Context, logger log.ColorLogger, stateLocker *collectors.StateLocker) error {
+func runMetricsServer(c *cli.Context, logger log.ColorLogger, stateLocker *collectors.StateLocker, dutiesLocker *collectors.DutiesLocker) error {
 
 	// Get services
 	cfg, err := services.GetConfig(c)
@@ -69,6 +69,8 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger, stateLocker *colle
 	beaconCollector := collectors.NewBeaconCollector(rp, bc, ec, nodeAccount.Address, stateLocker)
 	smoothingPoolCollector := collectors.NewSmoothingPoolCollector(rp, ec, stateLocker)
 	governanceCollector := collectors.NewGovernanceCollector(rp)
+	megapoolCollector := collectors.NewMegapoolCollector(rp, bc, nodeAccount.Address, stateLocker)
+	dutiesCollector := collectors.NewDutiesCollector(dutiesLocker)
 
 	// Set up Prometheus
 	registry := prometheus.NewRegistry()
@@ -82,12 +84,14 @@ func runMetricsServer(c *cli.Context, logger log.ColorLogger, stateLocker *colle
 	registry.MustRegister(beaconCollector)
 	registry.MustRegister(smoothingPoolCollector)
 	registry.MustRegister(governanceCollector)
+	registry.MustRegister(megapoolCollector)
+	registry.MustRegister(dutiesCollector)
 
 	// Set up snapshot checking if enabled
 	if cfg.Smartnode.GetRocketSignerRegistryAddress() != "" {
 		signallingAddress, err := reg.NodeToSigner(&bind.CallOpts{}, nodeAccount.Address)
 		if err != nil {
-			logger.Printlnf("Error getting the signalling address: %w", err)
+			logger.Printlnf("Error getting the signalling address: %s", err.Error())
 			// Set signallingAddress to blank address instead of erroring out of the task loop.
 			signallingAddress = common.Address{}
 		}
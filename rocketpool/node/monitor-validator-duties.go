@@ -0,0 +1,504 @@
+package node
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rocket-pool/smartnode/rocketpool/node/collectors"
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Config
+const dutiesThreadLimit int = 6
+
+// Monitor validator duties task
+type monitorValidatorDuties struct {
+	log          log.ColorLogger
+	cfg          *config.RocketPoolConfig
+	w            wallet.Wallet
+	rp           *rocketpool.RocketPool
+	bc           beacon.Client
+	dutiesLocker *collectors.DutiesLocker
+
+	// The last epoch whose duties were checked, once the first run has happened
+	lastCheckedEpoch uint64
+	initialized      bool
+
+	// The results for each validator index since the daemon started
+	stats map[string]*collectors.ValidatorDutyStats
+
+	// The epochs each validator missed an attestation in, for counting misses within the window
+	missedAttestationEpochs map[string][]uint64
+
+	// The node's own record of its validators' attestations in each rewards interval, for verifying rewards trees
+	attestationRecords map[uint64]*rewards.AttestationRecord
+
+	// The proposals assigned to the node's validators in each epoch that hasn't been checked yet.
+	// Beacon Nodes only provide the proposer duties of the current epoch, so they're recorded while each epoch is current.
+	proposerDuties map[uint64]map[string]uint64
+}
+
+// The attestation duties of the node's validators in an epoch
+type attestationDuties struct {
+	// Slot -> committee index -> position in the committee -> validator index
+	validators map[uint64]map[uint64]map[int]string
+
+	// Slot -> committee index -> committee size, for finding positions in post-Electra aggregation bits
+	committeeSizes map[uint64]map[uint64]int
+}
+
+// Create monitor validator duties task
+func newMonitorValidatorDuties(c *cli.Context, logger log.ColorLogger, dutiesLocker *collectors.DutiesLocker) (*monitorValidatorDuties, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	w, err := services.GetWallet(c)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := services.GetRocketPool(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := services.GetBeaconClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return task
+	return &monitorValidatorDuties{
+		log:                     logger,
+		cfg:                     cfg,
+		w:                       w,
+		rp:                      rp,
+		bc:                      bc,
+		dutiesLocker:            dutiesLocker,
+		stats:                   map[string]*collectors.ValidatorDutyStats{},
+		missedAttestationEpochs: map[string][]uint64{},
+		attestationRecords:      map[uint64]*rewards.AttestationRecord{},
+		proposerDuties:          map[uint64]map[string]uint64{},
+	}, nil
+
+}
+
+// Check the duties of the node's validators in each newly finalized epoch
+func (t *monitorValidatorDuties) run(state *state.NetworkState) error {
+
+	// Attestations can be included up to an epoch after their slot, so an epoch's duties are final
+	// once the epoch after it is finalized
+	head, err := t.bc.GetBeaconHead()
+	if err != nil {
+		return fmt.Errorf("error getting Beacon chain head: %w", err)
+	}
+	if head.FinalizedEpoch < 2 {
+		return nil
+	}
+	latestEpoch := head.FinalizedEpoch - 1
+
+	// Get the node's validators
+	indices, err := t.getValidatorIndices(state)
+	if err != nil {
+		return err
+	}
+
+	// Record the proposer duties of the current and next epochs so they can be checked once they're final.
+	// The next epoch's duties are already known, so recording them too covers the epochs that start and end between runs.
+	if len(indices) > 0 {
+		t.recordProposerDuties(head.Epoch, indices)
+		t.recordProposerDuties(head.Epoch+1, indices)
+	}
+
+	// Don't go back through the history when the daemon starts
+	if !t.initialized {
+		t.lastCheckedEpoch = latestEpoch - 1
+		t.initialized = true
+	}
+	if latestEpoch <= t.lastCheckedEpoch {
+		return nil
+	}
+
+	// Only the epochs in the window matter if the daemon fell behind
+	window := t.getMissedAttestationsWindow()
+	startEpoch := t.lastCheckedEpoch + 1
+	if latestEpoch-t.lastCheckedEpoch > window {
+		startEpoch = latestEpoch - window + 1
+	}
+	if len(indices) == 0 {
+		t.lastCheckedEpoch = latestEpoch
+		t.pruneProposerDuties()
+		return nil
+	}
+
+	// Check each epoch
+	updatedIntervals := map[uint64]bool{}
+	for epoch := startEpoch; epoch <= latestEpoch; epoch++ {
+		results, err := t.checkEpoch(epoch, indices, state.BeaconConfig.SlotsPerEpoch)
+		if err != nil {
+			return fmt.Errorf("error checking duties for epoch %d: %w", epoch, err)
+		}
+		t.lastCheckedEpoch = epoch
+
+		interval, err := t.recordAttestations(state, epoch, indices, results)
+		if err != nil {
+			return err
+		}
+		updatedIntervals[interval] = true
+	}
+	t.pruneProposerDuties()
+	for interval := range updatedIntervals {
+		err := t.attestationRecords[interval].Save(t.cfg.Smartnode.GetAttestationRecordPath(interval))
+		if err != nil {
+			return err
+		}
+	}
+	for interval := range t.attestationRecords {
+		if !updatedIntervals[interval] {
+			delete(t.attestationRecords, interval)
+		}
+	}
+
+	// Alert on validators that missed too many attestations in the window
+	threshold := t.getMissedAttestationsThreshold()
+	publishedStats := make(map[string]collectors.ValidatorDutyStats, len(t.stats))
+	for index, stats := range t.stats {
+		missedEpochs := t.missedAttestationEpochs[index]
+		for len(missedEpochs) > 0 && missedEpochs[0]+window <= latestEpoch {
+			missedEpochs = missedEpochs[1:]
+		}
+		t.missedAttestationEpochs[index] = missedEpochs
+
+		stats.RecentMissedAttestations = uint64(len(missedEpochs))
+		if stats.RecentMissedAttestations > threshold {
+			t.log.Printlnf("Validator %s missed %d attestations in the last %d epochs.", index, stats.RecentMissedAttestations, window)
+			alerting.AlertMissedAttestations(t.cfg, index, stats.RecentMissedAttestations, window)
+		}
+		publishedStats[index] = *stats
+	}
+	t.dutiesLocker.UpdateStats(t.lastCheckedEpoch, publishedStats)
+
+	// Return
+	return nil
+
+}
+
+// Get the indices of the node's validators on the Beacon chain
+func (t *monitorValidatorDuties) getValidatorIndices(state *state.NetworkState) (map[string]types.ValidatorPubkey, error) {
+	nodeAccount, err := t.w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	indices := map[string]types.ValidatorPubkey{}
+	for _, mpd := range state.MinipoolDetailsByNode[nodeAccount.Address] {
+		validator := state.MinipoolValidatorDetails[mpd.Pubkey]
+		if validator.Exists {
+			indices[validator.Index] = mpd.Pubkey
+		}
+	}
+
+	if !state.IsSaturnDeployed {
+		return indices, nil
+	}
+
+	// The node's state doesn't include its megapool validators, so get them directly
+	opts := &bind.CallOpts{
+		BlockNumber: big.NewInt(0).SetUint64(state.ElBlockNumber),
+	}
+	deployed, err := megapool.GetMegapoolDeployed(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !deployed {
+		return indices, nil
+	}
+	megapoolAddress, err := megapool.GetMegapoolExpectedAddress(t.rp, nodeAccount.Address, opts)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := megapool.NewMegaPoolV1(t.rp, megapoolAddress, opts)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys, err := mp.GetMegapoolPubkeys(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting megapool validator pubkeys: %w", err)
+	}
+	statuses, err := t.bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{
+		Slot: &state.BeaconSlotNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting megapool validator statuses: %w", err)
+	}
+	for pubkey, validator := range statuses {
+		if validator.Exists {
+			indices[validator.Index] = pubkey
+		}
+	}
+
+	return indices, nil
+}
+
+// Check the attestations and proposals of the node's validators in an epoch.
+// Returns the validators that had an attestation duty in the epoch, along with the slot of the duty if it wasn't included in time to count for rewards.
+func (t *monitorValidatorDuties) checkEpoch(epoch uint64, indices map[string]types.ValidatorPubkey, slotsPerEpoch uint64) (map[string]*uint64, error) {
+
+	// Get the duties and every block that could include the epoch's attestations
+	var duties attestationDuties
+	blocks := make([]beacon.BeaconBlock, 2*slotsPerEpoch)
+	found := make([]bool, 2*slotsPerEpoch)
+	firstSlot := epoch * slotsPerEpoch
+
+	var wg errgroup.Group
+	wg.SetLimit(dutiesThreadLimit)
+	wg.Go(func() error {
+		committees, err := t.bc.GetCommitteesForEpoch(&epoch)
+		if err != nil {
+			return fmt.Errorf("error getting committees: %w", err)
+		}
+		defer committees.Release()
+		duties = getAttestationDuties(committees, indices)
+		return nil
+	})
+	for i := uint64(0); i < 2*slotsPerEpoch; i++ {
+		i := i
+		wg.Go(func() error {
+			block, exists, err := t.bc.GetBeaconBlock(fmt.Sprint(firstSlot + i))
+			if err != nil {
+				return fmt.Errorf("error getting block for slot %d: %w", firstSlot+i, err)
+			}
+			blocks[i] = block
+			found[i] = exists
+			return nil
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return nil, err
+	}
+	results := map[string]*uint64{}
+	for _, committees := range duties.validators {
+		for _, positions := range committees {
+			for _, index := range positions {
+				t.getStats(index).AttestationsExpected++
+				results[index] = nil
+			}
+		}
+	}
+
+	// Go through the blocks in order so each attestation is credited to its earliest inclusion
+	for i, block := range blocks {
+		if !found[i] {
+			continue
+		}
+		inclusionSlot := firstSlot + uint64(i)
+		for _, attestation := range block.Attestations {
+			committees, exists := duties.validators[attestation.SlotIndex]
+			if !exists || inclusionSlot <= attestation.SlotIndex {
+				continue
+			}
+			for _, committeeIndex := range attestation.CommitteeIndices() {
+				positions, exists := committees[uint64(committeeIndex)]
+				if !exists {
+					continue
+				}
+				for position, index := range positions {
+					if !attestation.ValidatorAttested(committeeIndex, position, duties.committeeSizes[attestation.SlotIndex]) {
+						continue
+					}
+					stats := t.getStats(index)
+					stats.AttestationsIncluded++
+					stats.TotalInclusionDelay += inclusionSlot - attestation.SlotIndex
+					delete(positions, position)
+
+					// Rewards only count attestations included within an epoch of their slot
+					if inclusionSlot-attestation.SlotIndex > slotsPerEpoch {
+						slot := attestation.SlotIndex
+						results[index] = &slot
+					}
+				}
+			}
+		}
+	}
+
+	// Anything left wasn't included in time
+	missed := map[string]bool{}
+	for slot, committees := range duties.validators {
+		for _, positions := range committees {
+			for _, index := range positions {
+				missed[index] = true
+				missedSlot := slot
+				results[index] = &missedSlot
+			}
+		}
+	}
+	for index := range missed {
+		t.missedAttestationEpochs[index] = append(t.missedAttestationEpochs[index], epoch)
+	}
+
+	// Compare the proposals each validator was assigned with the blocks it actually made.
+	// If the epoch's duties weren't recorded while it was current, the blocks are all that's known about them,
+	// so missed proposals can't be detected.
+	proposed := map[string]uint64{}
+	for i := uint64(0); i < slotsPerEpoch; i++ {
+		if _, exists := indices[blocks[i].ProposerIndex]; found[i] && exists {
+			proposed[blocks[i].ProposerIndex]++
+		}
+	}
+	proposerDuties, exists := t.proposerDuties[epoch]
+	if !exists {
+		t.log.Printlnf("The proposer duties for epoch %d weren't recorded, so missed proposals in it can't be detected.", epoch)
+		proposerDuties = proposed
+	}
+	proposers := make([]string, 0, len(proposerDuties))
+	for index := range proposerDuties {
+		proposers = append(proposers, index)
+	}
+	sort.Strings(proposers)
+	for _, index := range proposers {
+		expected := proposerDuties[index]
+		if expected == 0 {
+			continue
+		}
+		made := min(proposed[index], expected)
+		stats := t.getStats(index)
+		stats.ProposalsExpected += expected
+		stats.ProposalsMade += made
+		if made < expected {
+			t.log.Printlnf("Validator %s missed %d block proposals in epoch %d.", index, expected-made, epoch)
+			alerting.AlertMissedProposal(t.cfg, index, epoch, expected-made)
+		}
+	}
+
+	return results, nil
+
+}
+
+// Record the proposals assigned to the node's validators in an epoch, if they haven't been recorded yet
+func (t *monitorValidatorDuties) recordProposerDuties(epoch uint64, indices map[string]types.ValidatorPubkey) {
+	if _, exists := t.proposerDuties[epoch]; exists {
+		return
+	}
+	indexList := make([]string, 0, len(indices))
+	for index := range indices {
+		indexList = append(indexList, index)
+	}
+	duties, err := t.bc.GetValidatorProposerDuties(indexList, epoch)
+	if err != nil {
+		// The epoch's proposals will still be credited from its blocks
+		t.log.WithLevel(log.LevelWarn).Printlnf("WARNING: Couldn't get the proposer duties for epoch %d: %s", epoch, err.Error())
+		return
+	}
+	t.proposerDuties[epoch] = duties
+}
+
+// Remove the recorded proposer duties of the epochs that have been checked
+func (t *monitorValidatorDuties) pruneProposerDuties() {
+	for epoch := range t.proposerDuties {
+		if epoch <= t.lastCheckedEpoch {
+			delete(t.proposerDuties, epoch)
+		}
+	}
+}
+
+// Add the results of an epoch's attestation duties to the record for the rewards interval it's in, returning the interval
+func (t *monitorValidatorDuties) recordAttestations(state *state.NetworkState, epoch uint64, indices map[string]types.ValidatorPubkey, results map[string]*uint64) (uint64, error) {
+
+	// Get the interval the epoch is in, which may be after the current one if its rewards haven't been submitted yet
+	interval := state.NetworkDetails.RewardIndex
+	epochTime := state.BeaconConfig.GetSlotTime(epoch * state.BeaconConfig.SlotsPerEpoch)
+	if state.NetworkDetails.IntervalDuration > 0 && epochTime.After(state.NetworkDetails.IntervalStart) {
+		interval += uint64(epochTime.Sub(state.NetworkDetails.IntervalStart) / state.NetworkDetails.IntervalDuration)
+	}
+
+	// Load the record if it isn't in memory yet
+	record, exists := t.attestationRecords[interval]
+	if !exists {
+		var err error
+		record, err = rewards.LoadAttestationRecord(t.cfg.Smartnode.GetAttestationRecordPath(interval))
+		if err != nil {
+			return 0, err
+		}
+		if record == nil {
+			record = rewards.NewAttestationRecord(interval, state.BeaconConfig.SlotsPerEpoch)
+		}
+		t.attestationRecords[interval] = record
+	}
+
+	for index, missedSlot := range results {
+		record.AddDuty(indices[index], epoch, missedSlot)
+	}
+	return interval, nil
+
+}
+
+// Get the stats for a validator, creating them if they don't exist yet
+func (t *monitorValidatorDuties) getStats(index string) *collectors.ValidatorDutyStats {
+	stats, exists := t.stats[index]
+	if !exists {
+		stats = &collectors.ValidatorDutyStats{}
+		t.stats[index] = stats
+	}
+	return stats
+}
+
+// Get the attestation duties of the provided validators from an epoch's committees
+func getAttestationDuties(committees beacon.Committees, indices map[string]types.ValidatorPubkey) attestationDuties {
+	duties := attestationDuties{
+		validators:     map[uint64]map[uint64]map[int]string{},
+		committeeSizes: map[uint64]map[uint64]int{},
+	}
+	for i := 0; i < committees.Count(); i++ {
+		slot := committees.Slot(i)
+		committeeIndex := committees.Index(i)
+		if _, exists := duties.committeeSizes[slot]; !exists {
+			duties.committeeSizes[slot] = map[uint64]int{}
+		}
+		duties.committeeSizes[slot][committeeIndex] = committees.ValidatorCount(i)
+
+		for position, validator := range committees.Validators(i) {
+			if _, exists := indices[validator]; !exists {
+				continue
+			}
+			if _, exists := duties.validators[slot]; !exists {
+				duties.validators[slot] = map[uint64]map[int]string{}
+			}
+			if _, exists := duties.validators[slot][committeeIndex]; !exists {
+				duties.validators[slot][committeeIndex] = map[int]string{}
+			}
+			duties.validators[slot][committeeIndex][position] = validator
+		}
+	}
+	return duties
+}
+
+// Get the number of attestations a validator can miss in the window before it's alerted on
+func (t *monitorValidatorDuties) getMissedAttestationsThreshold() uint64 {
+	threshold, _ := t.cfg.Alertmanager.MissedAttestationsThreshold.Value.(uint64)
+	return threshold
+}
+
+// Get the number of recent epochs to count missed attestations over
+func (t *monitorValidatorDuties) getMissedAttestationsWindow() uint64 {
+	window, _ := t.cfg.Alertmanager.MissedAttestationsWindow.Value.(uint64)
+	if window == 0 {
+		return 1
+	}
+	return window
+}
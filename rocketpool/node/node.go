@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -13,14 +14,17 @@ import (
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
 	"github.com/rocket-pool/smartnode/rocketpool/node/collectors"
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/alerting"
+	"github.com/rocket-pool/smartnode/shared/services/chainevents"
 	"github.com/rocket-pool/smartnode/shared/services/state"
 	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore/lighthouse"
 	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore/nimbus"
 	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore/prysm"
 	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore/teku"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
 
@@ -40,6 +44,7 @@ const (
 	DefendPdaoPropsColor           = color.FgYellow
 	VerifyPdaoPropsColor           = color.FgYellow
 	DistributeMinipoolsColor       = color.FgHiGreen
+	ChainEventsColor               = color.FgHiMagenta
 	ErrorColor                     = color.FgRed
 	WarningColor                   = color.FgYellow
 	UpdateColor                    = color.FgHiWhite
@@ -47,6 +52,10 @@ const (
 	StakeMegapoolValidatorColor    = color.FgHiBlue
 	NotifyValidatorExitColor       = color.FgHiYellow
 	DefendChallengeExitColor       = color.FgHiGreen
+	MegapoolAlertsColor            = color.FgHiRed
+	MonitorValidatorDutiesColor    = color.FgCyan
+	DistributeMegapoolColor        = color.FgGreen
+	RegisterWeb3SignerKeysColor    = color.FgHiCyan
 )
 
 // Register node command
@@ -76,6 +85,12 @@ func run(c *cli.Context) error {
 		return err
 	}
 
+	// Create the Keymanager API token so the VC can pick it up on startup
+	err = deployKeymanagerTokenFile(c)
+	if err != nil {
+		return err
+	}
+
 	// Configure
 	configureHTTP()
 
@@ -115,51 +130,66 @@ func run(c *cli.Context) error {
 	}
 
 	// Initialize loggers
-	errorLog := log.NewColorLogger(ErrorColor)
+	log.SetJsonOutput(cfg.Smartnode.UseJsonLogs())
+	log.SetNodeAddress(nodeAccount.Address.Hex())
+	errorLog := log.NewColorLogger(ErrorColor).WithLevel(log.LevelError)
 	updateLog := log.NewColorLogger(UpdateColor)
 
 	// Create the state manager
 	m := state.NewNetworkStateManager(rp, cfg.Smartnode.GetStateManagerContracts(), bc, &updateLog)
 	stateLocker := collectors.NewStateLocker()
+	dutiesLocker := collectors.NewDutiesLocker()
+
+	// Create the Beacon event watcher, which tasks use to wake the loop when something they're waiting for may have happened
+	chainEventsLog := log.NewColorLogger(ChainEventsColor).WithTask("chain-events")
+	chainEvents := chainevents.NewWatcher(bc, &chainEventsLog)
 
 	// Initialize tasks
-	manageFeeRecipient, err := newManageFeeRecipient(c, log.NewColorLogger(ManageFeeRecipientColor))
+	manageFeeRecipient, err := newManageFeeRecipient(c, log.NewColorLogger(ManageFeeRecipientColor).WithTask("manage-fee-recipient"))
+	if err != nil {
+		return err
+	}
+	registerWeb3SignerKeys, err := newRegisterWeb3SignerKeys(c, log.NewColorLogger(RegisterWeb3SignerKeysColor).WithTask("register-web3signer-keys"))
 	if err != nil {
 		return err
 	}
-	defendChallengeExit, err := newDefendChallengeExit(c, log.NewColorLogger(DefendChallengeExitColor))
+	defendChallengeExit, err := newDefendChallengeExit(c, log.NewColorLogger(DefendChallengeExitColor).WithTask("defend-challenge-exit"))
 	if err != nil {
 		return err
 	}
-	distributeMinipools, err := newDistributeMinipools(c, log.NewColorLogger(DistributeMinipoolsColor))
+	distributeMinipools, err := newDistributeMinipools(c, log.NewColorLogger(DistributeMinipoolsColor).WithTask("distribute-minipools"))
 	if err != nil {
 		return err
 	}
-	stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewColorLogger(StakePrelaunchMinipoolsColor))
+	distributeMegapool, err := newDistributeMegapool(c, log.NewColorLogger(DistributeMegapoolColor).WithTask("distribute-megapool"))
 	if err != nil {
 		return err
 	}
-	stakeMegapoolValidators, err := newStakeMegapoolValidator(c, log.NewColorLogger(StakeMegapoolValidatorColor))
+	stakePrelaunchMinipools, err := newStakePrelaunchMinipools(c, log.NewColorLogger(StakePrelaunchMinipoolsColor).WithTask("stake-prelaunch-minipools"))
 	if err != nil {
 		return err
 	}
-	notifyValidatorExit, err := newNotifyValidatorExit(c, log.NewColorLogger(NotifyValidatorExitColor))
+	stakeMegapoolValidators, err := newStakeMegapoolValidator(c, log.NewColorLogger(StakeMegapoolValidatorColor).WithTask("stake-megapool-validators"), chainEvents)
 	if err != nil {
 		return err
 	}
-	promoteMinipools, err := newPromoteMinipools(c, log.NewColorLogger(PromoteMinipoolsColor))
+	notifyValidatorExit, err := newNotifyValidatorExit(c, log.NewColorLogger(NotifyValidatorExitColor).WithTask("notify-validator-exit"))
 	if err != nil {
 		return err
 	}
-	downloadRewardsTrees, err := newDownloadRewardsTrees(c, log.NewColorLogger(DownloadRewardsTreesColor))
+	promoteMinipools, err := newPromoteMinipools(c, log.NewColorLogger(PromoteMinipoolsColor).WithTask("promote-minipools"))
 	if err != nil {
 		return err
 	}
-	reduceBonds, err := newReduceBonds(c, log.NewColorLogger(ReduceBondAmountColor))
+	downloadRewardsTrees, err := newDownloadRewardsTrees(c, log.NewColorLogger(DownloadRewardsTreesColor).WithTask("download-reward-trees"))
 	if err != nil {
 		return err
 	}
-	defendPdaoProps, err := newDefendPdaoProps(c, log.NewColorLogger(DefendPdaoPropsColor))
+	reduceBonds, err := newReduceBonds(c, log.NewColorLogger(ReduceBondAmountColor).WithTask("reduce-bonds"))
+	if err != nil {
+		return err
+	}
+	defendPdaoProps, err := newDefendPdaoProps(c, log.NewColorLogger(DefendPdaoPropsColor).WithTask("defend-pdao-props"))
 	if err != nil {
 		return err
 	}
@@ -167,18 +197,31 @@ func run(c *cli.Context) error {
 	// Make sure the user opted into this duty
 	verifyEnabled := cfg.Smartnode.VerifyProposals.Value.(bool)
 	if verifyEnabled {
-		verifyPdaoProps, err = newVerifyPdaoProps(c, log.NewColorLogger(VerifyPdaoPropsColor))
+		verifyPdaoProps, err = newVerifyPdaoProps(c, log.NewColorLogger(VerifyPdaoPropsColor).WithTask("verify-pdao-props"))
 		if err != nil {
 			return err
 		}
 	}
 
 	var prestakeMegapoolValidator *prestakeMegapoolValidator
-	prestakeMegapoolValidator, err = newPrestakeMegapoolValidator(c, log.NewColorLogger(PrestakeMegapoolValidatorColor))
+	prestakeMegapoolValidator, err = newPrestakeMegapoolValidator(c, log.NewColorLogger(PrestakeMegapoolValidatorColor).WithTask("prestake-megapool-validator"))
+	if err != nil {
+		return err
+	}
+
+	megapoolAlerts, err := newMegapoolAlerts(c, log.NewColorLogger(MegapoolAlertsColor).WithTask("megapool-alerts"))
 	if err != nil {
 		return err
 	}
 
+	monitorValidatorDuties, err := newMonitorValidatorDuties(c, log.NewColorLogger(MonitorValidatorDutiesColor).WithTask("monitor-validator-duties"), dutiesLocker)
+	if err != nil {
+		return err
+	}
+
+	// Watch the Beacon chain so the tasks can run as soon as something relevant happens
+	go chainEvents.Run(context.Background())
+
 	// Wait group to handle the various threads
 	wg := new(sync.WaitGroup)
 	wg.Add(2)
@@ -228,34 +271,40 @@ func run(c *cli.Context) error {
 				continue
 			}
 			stateLocker.UpdateState(state)
+			chainEvents.UpdateState(state)
 
 			// Manage the fee recipient for the node
 			if err := manageFeeRecipient.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("manage-fee-recipient").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
+			// Register the keys held by Web3Signer with the VC
+			if err := registerWeb3SignerKeys.run(state); err != nil {
+				errorLog.WithTask("register-web3signer-keys").Println(err)
+			}
+
 			// Run the defend challenge exit task
 			if err := defendChallengeExit.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("defend-challenge-exit").Println(err)
 			}
 
 			// Run the rewards download check
 			if err := downloadRewardsTrees.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("download-reward-trees").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the pDAO proposal defender
 			if err := defendPdaoProps.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("defend-pdao-props").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the pDAO proposal verifier
 			if verifyPdaoProps != nil {
 				if err := verifyPdaoProps.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("verify-pdao-props").Println(err)
 				}
 				time.Sleep(taskCooldown)
 			}
@@ -263,56 +312,75 @@ func run(c *cli.Context) error {
 			// Run the megapool prestake check
 			if prestakeMegapoolValidator != nil {
 				if err := prestakeMegapoolValidator.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("prestake-megapool-validator").Println(err)
 				}
 				time.Sleep(taskCooldown)
 			}
 
 			// Run the minipool stake check
 			if err := stakePrelaunchMinipools.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("stake-prelaunch-minipools").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the megapool stake check
 			if err := stakeMegapoolValidators.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("stake-megapool-validators").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the megapool notify validator exit check
 			if err := notifyValidatorExit.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("notify-validator-exit").Println(err)
+			}
+			time.Sleep(taskCooldown)
+
+			// Raise alerts for changes to the megapool
+			if err := megapoolAlerts.run(state); err != nil {
+				errorLog.WithTask("megapool-alerts").Println(err)
+			}
+			time.Sleep(taskCooldown)
+
+			// Check the validators' attestations and proposals in the newly finalized epochs
+			if err := monitorValidatorDuties.run(state); err != nil {
+				errorLog.WithTask("monitor-validator-duties").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the balance distribution check
 			if err := distributeMinipools.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("distribute-minipools").Println(err)
+			}
+			time.Sleep(taskCooldown)
+
+			// Run the megapool distribution and refund check
+			if err := distributeMegapool.run(state); err != nil {
+				errorLog.WithTask("distribute-megapool").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the reduce bond check
 			if err := reduceBonds.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("reduce-bonds").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			// Run the minipool promotion check
 			if err := promoteMinipools.run(state); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("promote-minipools").Println(err)
 			}
 
-			time.Sleep(tasksInterval)
+			// Wait for the next run, or for a Beacon event that needs the tasks to run sooner
+			chainEvents.Wait(tasksInterval)
 		}
 		wg.Done()
 	}()
 
 	// Run metrics loop
 	go func() {
-		err := runMetricsServer(c, log.NewColorLogger(MetricsColor), stateLocker)
+		err := runMetricsServer(c, log.NewColorLogger(MetricsColor).WithTask("metrics"), stateLocker, dutiesLocker)
 		if err != nil {
-			errorLog.Println(err)
+			errorLog.WithTask("metrics").Println(err)
 		}
 		wg.Done()
 	}()
@@ -372,6 +440,25 @@ func deployDefaultFeeRecipientFile(c *cli.Context) error {
 
 }
 
+// Create the token the VC's Keymanager API uses for authentication if it doesn't exist yet
+func deployKeymanagerTokenFile(c *cli.Context) error {
+
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return err
+	}
+	if !cfg.Smartnode.EnableKeymanagerApi.Value.(bool) {
+		return nil
+	}
+
+	_, err = apiutils.LoadOrCreateApiToken(cfg.Smartnode.GetKeymanagerTokenPath())
+	if err != nil {
+		return fmt.Errorf("could not create Keymanager API token: %w", err)
+	}
+	return nil
+
+}
+
 // Remove the old fee recipient files that were created in v1.5.0
 func removeLegacyFeeRecipientFiles(c *cli.Context) error {
 
@@ -428,3 +515,13 @@ func GetPriorityFee(priorityFee *big.Int, maxFee *big.Int) *big.Int {
 		return quarterMaxFee
 	}
 }
+
+// Get the highest max fee a stuck automatic transaction can be re-broadcast with.
+// This is the auto TX gas threshold, unless the transaction was already sent with a higher max fee (e.g. because it was due).
+func GetAutoTxMaxFeeCap(gasThreshold float64, maxFee *big.Int) *big.Int {
+	thresholdWei := eth.GweiToWei(gasThreshold)
+	if thresholdWei.Cmp(maxFee) > 0 {
+		return thresholdWei
+	}
+	return maxFee
+}
@@ -1,6 +1,7 @@
 package node
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/docker/docker/client"
@@ -12,6 +13,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
@@ -78,7 +80,7 @@ func newNotifyValidatorExit(c *cli.Context, logger log.ColorLogger) (*notifyVali
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -158,7 +160,11 @@ func (t *notifyValidatorExit) run(state *state.NetworkState) error {
 			t.log.Printlnf("The validator ID %d needs an exit proof", validatorInfo[i].ValidatorId)
 
 			// Call Stake
-			t.createExitProof(t.rp, mp, validatorInfo[i].ValidatorId, state, types.ValidatorPubkey(validatorInfo[i].PubKey), opts)
+			success, err := t.createExitProof(t.rp, mp, validatorInfo[i].ValidatorId, state, types.ValidatorPubkey(validatorInfo[i].PubKey), opts)
+			alerting.AlertMegapoolValidatorExiting(t.cfg, megapoolAddress, validatorInfo[i].ValidatorId, success && err == nil)
+			if err != nil {
+				t.log.Println(fmt.Errorf("Could not notify the exit of validator %d: %w", validatorInfo[i].ValidatorId, err))
+			}
 		}
 	}
 
@@ -167,20 +173,20 @@ func (t *notifyValidatorExit) run(state *state.NetworkState) error {
 
 }
 
-func (t *notifyValidatorExit) createExitProof(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, callopts *bind.CallOpts) error {
+func (t *notifyValidatorExit) createExitProof(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, callopts *bind.CallOpts) (bool, error) {
 
 	// Get transactor
 	opts, err := t.w.GetNodeAccountTransactor()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	t.log.Printlnf("[STARTED] Crafting an exit proof. This process can take several seconds and is CPU and memory intensive. If you don't see a [FINISHED] log entry your system may not have enough resources to perform this operation.")
 
 	proof, err := services.GetValidatorProof(t.c, t.w, state.BeaconConfig, mp.GetAddress(), validatorPubkey)
 	if err != nil {
-		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %w", err)
-		return err
+		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %s", err.Error())
+		return false, err
 	}
 
 	t.log.Printlnf("[FINISHED] The validator exit proof has been successfully created.")
@@ -188,7 +194,7 @@ func (t *notifyValidatorExit) createExitProof(rp *rocketpool.RocketPool, mp mega
 	// Get the gas limit
 	gasInfo, err := megapool.EstimateNotifyExitGas(rp, mp.GetAddress(), validatorId, proof, opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 	gas := big.NewInt(int64(gasInfo.SafeGasLimit))
 	// Get the max fee
@@ -196,13 +202,13 @@ func (t *notifyValidatorExit) createExitProof(rp *rocketpool.RocketPool, mp mega
 	if maxFee == nil || maxFee.Uint64() == 0 {
 		maxFee, err = rpgas.GetHeadlessMaxFeeWei(t.cfg)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Print the gas info
 	if !api.PrintAndCheckGasInfo(gasInfo, true, t.gasThreshold, &t.log, maxFee, t.gasLimit) {
-		return nil
+		return false, nil
 	}
 
 	opts.GasFeeCap = maxFee
@@ -212,18 +218,18 @@ func (t *notifyValidatorExit) createExitProof(rp *rocketpool.RocketPool, mp mega
 	// Call stake
 	tx, err := megapool.NotifyExit(rp, mp.GetAddress(), validatorId, proof, opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Print TX info and wait for it to be included in a block
 	err = api.PrintAndWaitForTransaction(t.cfg, tx.Hash(), t.rp.Client, &t.log)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Log
 	t.log.Printlnf("Successfully notified validator %d exit.", validatorId)
 
 	// Return
-	return nil
+	return true, nil
 }
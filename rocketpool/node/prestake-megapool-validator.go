@@ -15,6 +15,7 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
 	"github.com/rocket-pool/smartnode/shared/services/state"
@@ -74,7 +75,7 @@ func newPrestakeMegapoolValidator(c *cli.Context, logger log.ColorLogger) (*pres
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -172,7 +173,11 @@ func (t *prestakeMegapoolValidator) run(state *state.NetworkState) error {
 			return nil
 		}
 		// Call assign
-		t.assignDeposit(opts)
+		success, err := t.assignDeposit(opts)
+		alerting.AlertMegapoolValidatorPrestaked(t.cfg, megapoolAddress, success && err == nil)
+		if err != nil {
+			return fmt.Errorf("Could not assign a deposit to megapool %s: %w", megapoolAddress.Hex(), err)
+		}
 	} else {
 		t.log.Printlnf("Time left until the automatic stake %s", remainingTime)
 	}
@@ -182,19 +187,19 @@ func (t *prestakeMegapoolValidator) run(state *state.NetworkState) error {
 
 }
 
-func (t *prestakeMegapoolValidator) assignDeposit(callopts *bind.CallOpts) error {
+func (t *prestakeMegapoolValidator) assignDeposit(callopts *bind.CallOpts) (bool, error) {
 
 	// Get transactor
 	opts, err := t.w.GetNodeAccountTransactor()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Get the gas limit
 	gasInfo, err := deposit.EstimateAssignDepositsGas(t.rp, big.NewInt(1), opts)
 	if err != nil {
-		t.log.Printlnf("error estimating assignment %w", err)
-		return err
+		t.log.Printlnf("error estimating assignment %s", err.Error())
+		return false, err
 	}
 	gas := big.NewInt(int64(gasInfo.SafeGasLimit))
 	// Get the max fee
@@ -202,13 +207,13 @@ func (t *prestakeMegapoolValidator) assignDeposit(callopts *bind.CallOpts) error
 	if maxFee == nil || maxFee.Uint64() == 0 {
 		maxFee, err = rpgas.GetHeadlessMaxFeeWei(t.cfg)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Print the gas info
 	if !api.PrintAndCheckGasInfo(gasInfo, true, t.gasThreshold, &t.log, maxFee, t.gasLimit) {
-		return nil
+		return false, nil
 	}
 
 	opts.GasFeeCap = maxFee
@@ -218,18 +223,18 @@ func (t *prestakeMegapoolValidator) assignDeposit(callopts *bind.CallOpts) error
 	// Call assign
 	hash, err := deposit.AssignDeposits(t.rp, big.NewInt(1), opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Print TX info and wait for it to be included in a block
 	err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, &t.log)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Log
 	t.log.Println("Successfully assigned ETH to the next megapool validator.")
 
 	// Return
-	return nil
+	return true, nil
 }
@@ -0,0 +1,60 @@
+package node
+
+import (
+	"github.com/urfave/cli"
+
+	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+	"github.com/rocket-pool/smartnode/shared/utils/validator"
+)
+
+// Register Web3Signer keys task
+type registerWeb3SignerKeys struct {
+	c   *cli.Context
+	log log.ColorLogger
+	cfg *config.RocketPoolConfig
+}
+
+// Create register Web3Signer keys task
+func newRegisterWeb3SignerKeys(c *cli.Context, logger log.ColorLogger) (*registerWeb3SignerKeys, error) {
+
+	// Get services
+	cfg, err := services.GetConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return task
+	return &registerWeb3SignerKeys{
+		c:   c,
+		log: logger,
+		cfg: cfg,
+	}, nil
+
+}
+
+// Make sure the VC knows about every key held by Web3Signer.
+// The other clients fetch the keys from the signer themselves, but Lighthouse only signs with remote keys registered through its Keymanager API.
+func (t *registerWeb3SignerKeys) run(state *state.NetworkState) error {
+
+	if t.cfg.Smartnode.GetWeb3SignerUrl() == "" {
+		return nil
+	}
+	client, _ := t.cfg.GetSelectedConsensusClient()
+	if client != cfgtypes.ConsensusClient_Lighthouse {
+		return nil
+	}
+
+	count, err := validator.RegisterWeb3SignerKeys(t.cfg)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		t.log.Printlnf("Registered %d Web3Signer key(s) with the validator client.", count)
+	}
+	return nil
+
+}
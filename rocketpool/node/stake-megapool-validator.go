@@ -1,6 +1,7 @@
 package node
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/docker/docker/client"
@@ -12,13 +13,16 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services"
+	"github.com/rocket-pool/smartnode/shared/services/alerting"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/chainevents"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	rpgas "github.com/rocket-pool/smartnode/shared/services/gas"
 	"github.com/rocket-pool/smartnode/shared/services/state"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	"github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
+	"github.com/rocket-pool/smartnode/shared/utils/validator"
 )
 
 // Stake megapool validator task
@@ -34,10 +38,11 @@ type stakeMegapoolValidator struct {
 	maxFee         *big.Int
 	maxPriorityFee *big.Int
 	gasLimit       uint64
+	chainEvents    *chainevents.Watcher
 }
 
 // Create stake megapool validator task
-func newStakeMegapoolValidator(c *cli.Context, logger log.ColorLogger) (*stakeMegapoolValidator, error) {
+func newStakeMegapoolValidator(c *cli.Context, logger log.ColorLogger, chainEvents *chainevents.Watcher) (*stakeMegapoolValidator, error) {
 
 	// Get services
 	cfg, err := services.GetConfig(c)
@@ -76,7 +81,7 @@ func newStakeMegapoolValidator(c *cli.Context, logger log.ColorLogger) (*stakeMe
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -95,6 +100,7 @@ func newStakeMegapoolValidator(c *cli.Context, logger log.ColorLogger) (*stakeMe
 		maxFee:         maxFee,
 		maxPriorityFee: priorityFee,
 		gasLimit:       0,
+		chainEvents:    chainEvents,
 	}, nil
 
 }
@@ -150,35 +156,54 @@ func (t *stakeMegapoolValidator) run(state *state.NetworkState) error {
 		return err
 	}
 
+	stakedPubkeys := []types.ValidatorPubkey{}
+	waitingCount := 0
 	for i := uint32(0); i < uint32(validatorCount); i++ {
+		// Validators get their index when the Beacon chain processes their deposit at the start of an epoch
+		if validatorInfo[i].InPrestake && validatorInfo[i].BeaconStatus.Index == "" {
+			waitingCount++
+		}
 		if validatorInfo[i].InPrestake && validatorInfo[i].BeaconStatus.Index != "" {
 			// Log
 			t.log.Printlnf("The validator %d needs to be staked", validatorInfo[i].ValidatorId)
 
 			// Call Stake
-			t.stakeValidator(t.rp, mp, validatorInfo[i].ValidatorId, state, types.ValidatorPubkey(validatorInfo[i].PubKey), opts)
+			pubkey := types.ValidatorPubkey(validatorInfo[i].PubKey)
+			success, err := t.stakeValidator(t.rp, mp, validatorInfo[i].ValidatorId, state, pubkey, opts)
+			alerting.AlertMegapoolValidatorStaked(t.cfg, megapoolAddress, validatorInfo[i].ValidatorId, success && err == nil)
+			if err != nil {
+				t.log.Println(fmt.Errorf("Could not stake validator %d: %w", validatorInfo[i].ValidatorId, err))
+			}
+			if success && err == nil {
+				stakedPubkeys = append(stakedPubkeys, pubkey)
+			}
 		}
 	}
 
-	// Return
-	return nil
+	// Check again as soon as the next epoch starts instead of waiting for the regular interval
+	if waitingCount > 0 {
+		t.chainEvents.WakeAtNextEpoch(fmt.Sprintf("a new epoch started while %d megapool validator(s) were waiting for their deposits to be processed", waitingCount))
+	}
+
+	// Make sure the validator client has the keys for the validators that were staked
+	return validator.EnsureValidatorKeysLoaded(t.cfg, t.bc, &t.log, t.d, stakedPubkeys)
 
 }
 
-func (t *stakeMegapoolValidator) stakeValidator(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, callopts *bind.CallOpts) error {
+func (t *stakeMegapoolValidator) stakeValidator(rp *rocketpool.RocketPool, mp megapool.Megapool, validatorId uint32, state *state.NetworkState, validatorPubkey types.ValidatorPubkey, callopts *bind.CallOpts) (bool, error) {
 
 	// Get transactor
 	opts, err := t.w.GetNodeAccountTransactor()
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	t.log.Printlnf("[STARTED] Crafting a proof that the correct credentials were used on the first beacon chain deposit. This process can take several seconds and is CPU and memory intensive. If you don't see a [FINISHED] log entry your system may not have enough resources to perform this operation.")
 
 	proof, err := services.GetValidatorProof(t.c, t.w, state.BeaconConfig, mp.GetAddress(), validatorPubkey)
 	if err != nil {
-		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %w", err)
-		return err
+		t.log.Printlnf("[ERROR] There was an error during the proof creation process: %s", err.Error())
+		return false, err
 	}
 
 	t.log.Printlnf("[FINISHED] The beacon state proof has been successfully created.")
@@ -186,7 +211,7 @@ func (t *stakeMegapoolValidator) stakeValidator(rp *rocketpool.RocketPool, mp me
 	// Get the gas limit
 	gasInfo, err := megapool.EstimateStakeGas(rp, mp.GetAddress(), validatorId, proof, opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 	gas := big.NewInt(int64(gasInfo.SafeGasLimit))
 	// Get the max fee
@@ -194,13 +219,13 @@ func (t *stakeMegapoolValidator) stakeValidator(rp *rocketpool.RocketPool, mp me
 	if maxFee == nil || maxFee.Uint64() == 0 {
 		maxFee, err = rpgas.GetHeadlessMaxFeeWei(t.cfg)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	// Print the gas info
 	if !api.PrintAndCheckGasInfo(gasInfo, true, t.gasThreshold, &t.log, maxFee, t.gasLimit) {
-		return nil
+		return false, nil
 	}
 
 	opts.GasFeeCap = maxFee
@@ -210,18 +235,18 @@ func (t *stakeMegapoolValidator) stakeValidator(rp *rocketpool.RocketPool, mp me
 	// Call stake
 	tx, err := megapool.Stake(rp, mp.GetAddress(), validatorId, proof, opts)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Print TX info and wait for it to be included in a block
 	err = api.PrintAndWaitForTransaction(t.cfg, tx.Hash(), t.rp.Client, &t.log)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Log
 	t.log.Printlnf("Successfully staked validator %d.", validatorId)
 
 	// Return
-	return nil
+	return true, nil
 }
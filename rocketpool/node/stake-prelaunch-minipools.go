@@ -83,7 +83,7 @@ func newStakePrelaunchMinipools(c *cli.Context, logger log.ColorLogger) (*stakeP
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -141,24 +141,27 @@ func (t *stakePrelaunchMinipools) run(state *state.NetworkState) error {
 	t.log.Printlnf("%d minipool(s) are ready for staking...", len(minipools))
 
 	// Stake minipools
-	successCount := 0
+	stakedPubkeys := []rptypes.ValidatorPubkey{}
+	var stakeErr error
 	for _, mpd := range minipools {
 		success, err := t.stakeMinipool(mpd, state, opts)
 		alerting.AlertMinipoolStaked(t.cfg, mpd.MinipoolAddress, success && err == nil)
 		if err != nil {
-			t.log.Println(fmt.Errorf("Could not stake minipool %s: %w", mpd.MinipoolAddress.Hex(), err))
-			return err
+			stakeErr = fmt.Errorf("Could not stake minipool %s: %w", mpd.MinipoolAddress.Hex(), err)
+			t.log.Println(stakeErr)
+			break
 		}
 		if success {
-			successCount++
+			stakedPubkeys = append(stakedPubkeys, mpd.Pubkey)
 		}
 	}
 
-	// Restart validator process if any minipools were staked successfully
-	if successCount > 0 {
-		if err := validator.RestartValidator(t.cfg, t.bc, &t.log, t.d); err != nil {
-			return err
-		}
+	// Make sure the validator client has the keys for any minipools that were staked successfully, even if a later one failed
+	if err := validator.EnsureValidatorKeysLoaded(t.cfg, t.bc, &t.log, t.d, stakedPubkeys); err != nil {
+		return err
+	}
+	if stakeErr != nil {
+		return stakeErr
 	}
 
 	// Return
@@ -301,7 +304,7 @@ func (t *stakePrelaunchMinipools) stakeMinipool(mpd *rpstate.NativeMinipoolDetai
 	}
 
 	// Print TX info and wait for it to be included in a block
-	err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, &t.log)
+	err = api.PrintAndWaitForTrackedTransaction(t.cfg, hash, t.rp.Client, opts, GetAutoTxMaxFeeCap(t.gasThreshold, maxFee), t.maxPriorityFee, &t.log)
 	if err != nil {
 		return false, err
 	}
@@ -7,9 +7,11 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/rocketpool/api"
+	apiserver "github.com/rocket-pool/smartnode/rocketpool/api/server"
 	"github.com/rocket-pool/smartnode/rocketpool/node"
 	"github.com/rocket-pool/smartnode/rocketpool/watchtower"
 	"github.com/rocket-pool/smartnode/shared"
+	"github.com/rocket-pool/smartnode/shared/services"
 	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
 
 	blsversionpin "github.com/herumi/bls-eth-go-binary/bls"
@@ -76,10 +78,15 @@ func main() {
 			Name:  "use-protected-api",
 			Usage: "Set this to true to use the Flashbots Protect RPC instead of your local Execution Client. Useful to ensure your transactions aren't front-run.",
 		},
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Set this to true to export unsigned transactions for signing on an offline machine instead of signing and sending them",
+		},
 	}
 
 	// Register commands
 	api.RegisterCommands(app, "api", []string{"a"})
+	apiserver.RegisterCommands(app, "api-server", []string{"v"})
 	node.RegisterCommands(app, "node", []string{"n"})
 	watchtower.RegisterCommands(app, "watchtower", []string{"w"})
 
@@ -100,4 +107,9 @@ func main() {
 		}
 	}
 
+	// Don't exit until any bundled transactions have been included or resent to the public mempool
+	if commandName == "api" {
+		services.WaitForTransactionFallbacks()
+	}
+
 }
@@ -163,7 +163,7 @@ func (t *dissolveInvalidCredentials) dissolveMegapoolValidator(validator megapoo
 	}
 
 	// Log
-	t.log.Printlnf("Successfully dissolved megapool validator ID: %s from megapool %s. (Invalid credentials)", validator.ValidatorId, validator.MegapoolAddress)
+	t.log.Printlnf("Successfully dissolved megapool validator ID: %d from megapool %s. (Invalid credentials)", validator.ValidatorId, validator.MegapoolAddress)
 
 	// Return
 	return nil
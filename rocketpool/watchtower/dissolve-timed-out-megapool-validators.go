@@ -149,7 +149,7 @@ func (t *dissolveTimedOutMegapoolValidators) dissolveMegapoolValidator(validator
 	}
 
 	// Log
-	t.log.Printlnf("Successfully dissolved megapool validator ID: %s from megapool %s.", validator.ValidatorId, validator.MegapoolAddress)
+	t.log.Printlnf("Successfully dissolved megapool validator ID: %d from megapool %s.", validator.ValidatorId, validator.MegapoolAddress)
 
 	// Return
 	return nil
@@ -50,7 +50,7 @@ func newGenerateRewardsTree(c *cli.Context, logger log.ColorLogger, errorLogger
 	if err != nil {
 		return nil, err
 	}
-	bc, err := services.GetBeaconClient(c)
+	bc, err := services.GetCachingBeaconClient(c)
 	if err != nil {
 		return nil, err
 	}
@@ -245,6 +245,7 @@ func (t *generateRewardsTree) generateRewardsTreeImpl(rp *rocketpool.RocketPool,
 		t.handleError(fmt.Errorf("%s Error creating Merkle tree generator: %w", generationPrefix, err))
 		return
 	}
+	treegen.SetCheckpointFolder(filepath.Join(t.cfg.Smartnode.GetRewardsCheckpointsFolder(true), "generate-rewards-tree"))
 	treeResult, err := treegen.GenerateTree()
 	if err != nil {
 		t.handleError(fmt.Errorf("%s Error generating Merkle tree: %w", generationPrefix, err))
@@ -252,14 +253,14 @@ func (t *generateRewardsTree) generateRewardsTreeImpl(rp *rocketpool.RocketPool,
 	}
 	rewardsFile := treeResult.RewardsFile
 	for address, network := range treeResult.InvalidNetworkNodes {
-		t.log.Printlnf("%s WARNING: Node %s has invalid network %d assigned! Using 0 (mainnet) instead.", generationPrefix, address.Hex(), network)
+		t.log.WithLevel(log.LevelWarn).Printlnf("%s WARNING: Node %s has invalid network %d assigned! Using 0 (mainnet) instead.", generationPrefix, address.Hex(), network)
 	}
 	t.log.Printlnf("%s Finished in %s", generationPrefix, time.Since(start).String())
 
 	// Validate the Merkle root
 	root := rewardsFile.GetMerkleRoot()
 	if root != rewardsEvent.MerkleRoot.Hex() {
-		t.log.Printlnf("%s WARNING: your Merkle tree had a root of %s, but the canonical Merkle tree's root was %s. This file will not be usable for claiming rewards.", generationPrefix, root, rewardsEvent.MerkleRoot.Hex())
+		t.log.WithLevel(log.LevelWarn).Printlnf("%s WARNING: your Merkle tree had a root of %s, but the canonical Merkle tree's root was %s. This file will not be usable for claiming rewards.", generationPrefix, root, rewardsEvent.MerkleRoot.Hex())
 	} else {
 		t.log.Printlnf("%s Your Merkle tree's root of %s matches the canonical root! You will be able to use this file for claiming rewards.", generationPrefix, root)
 	}
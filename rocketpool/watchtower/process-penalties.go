@@ -97,7 +97,7 @@ func newProcessPenalties(c *cli.Context, logger log.ColorLogger, errorLogger log
 	priorityFeeGwei := cfg.Smartnode.PriorityFee.Value.(float64)
 	var priorityFee *big.Int
 	if priorityFeeGwei == 0 {
-		logger.Println("WARNING: priority fee was missing or 0, setting a default of 2.")
+		logger.WithLevel(log.LevelWarn).Println("WARNING: priority fee was missing or 0, setting a default of 2.")
 		priorityFee = eth.GweiToWei(2)
 	} else {
 		priorityFee = eth.GweiToWei(priorityFeeGwei)
@@ -400,7 +400,7 @@ func (t *processPenalties) processBlock(block *beacon.BeaconBlock, smoothingPool
 	}
 	isOptedIn, err := node.GetSmoothingPoolRegistrationState(t.rp, nodeAddress, &opts)
 	if err != nil {
-		t.log.Printlnf("*** WARNING: Couldn't check if node %s was opted into the smoothing pool for slot %d (execution block %d), skipping check... error: %w\n***", nodeAddress.Hex(), block.Slot, block.ExecutionBlockNumber, err)
+		t.log.WithLevel(log.LevelWarn).Printlnf("*** WARNING: Couldn't check if node %s was opted into the smoothing pool for slot %d (execution block %d), skipping check... error: %s\n***", nodeAddress.Hex(), block.Slot, block.ExecutionBlockNumber, err.Error())
 		isOptedIn = false
 	}
 
@@ -423,7 +423,7 @@ func (t *processPenalties) processBlock(block *beacon.BeaconBlock, smoothingPool
 		// Get the opt out time
 		optOutTime, err := node.GetSmoothingPoolRegistrationChanged(t.rp, nodeAddress, &opts)
 		if err != nil {
-			t.log.Printlnf("*** WARNING: Couldn't check when node %s opted out of the smoothing pool for slot %d (execution block %d), skipping check... error: %w\n***", nodeAddress.Hex(), block.Slot, block.ExecutionBlockNumber, err)
+			t.log.WithLevel(log.LevelWarn).Printlnf("*** WARNING: Couldn't check when node %s opted out of the smoothing pool for slot %d (execution block %d), skipping check... error: %s\n***", nodeAddress.Hex(), block.Slot, block.ExecutionBlockNumber, err.Error())
 		} else if optOutTime != time.Unix(0, 0) {
 			// Get the time of the epoch before this one
 			blockEpoch := block.Slot / t.beaconConfig.SlotsPerEpoch
@@ -618,6 +618,7 @@ func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
 	opts.GasFeeCap = maxFee
 	opts.GasTipCap = eth.GweiToWei(utils.GetWatchtowerPrioFee(t.cfg))
 	opts.GasLimit = gasInfo.SafeGasLimit
+	opts.Context = utils.GetWatchtowerSubmissionContext(t.cfg)
 	var hash common.Hash
 	// Submit balances
 	hash, err = network.SubmitBalances(t.rp, balances.Block, balances.SlotTimestamp, totalEth, totalStaking, balances.RETHSupply, opts)
@@ -626,7 +627,7 @@ func (t *submitNetworkBalances) submitBalances(balances networkBalances) error {
 	}
 
 	// Print TX info and wait for it to be included in a block
-	err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+	err = api.PrintAndWaitForTrackedTransaction(t.cfg, hash, t.rp.Client, opts, maxFee, opts.GasTipCap, t.log)
 	if err != nil {
 		return fmt.Errorf("error waiting for transaction: %w", err)
 	}
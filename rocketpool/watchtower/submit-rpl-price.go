@@ -431,22 +431,29 @@ func (t *submitRplPrice) run(state *state.NetworkState) error {
 		t.isRunning = true
 		t.lock.Unlock()
 		logPrefix := "[Price Report]"
-		t.log.Printlnf("%s Starting price report in a separate thread.", logPrefix)
+
+		submissionTimestamp := uint64(nextSubmissionTime.Unix())
+
+		// Include the slot being reported on in the report's log entries
+		reportLog := t.log
+		if submissionTimestamp >= eth2Config.GenesisTime {
+			slotLog := t.log.WithSlot((submissionTimestamp - eth2Config.GenesisTime) / eth2Config.SecondsPerSlot)
+			reportLog = &slotLog
+		}
+		reportLog.Printlnf("%s Starting price report in a separate thread.", logPrefix)
 
 		// Log
-		t.log.Printlnf("Getting RPL price for block %d...", targetBlockNumber)
+		reportLog.Printlnf("Getting RPL price for block %d...", targetBlockNumber)
 
 		// Get RPL price at block
-		rplPrice, err := t.getRplTwap(targetBlockNumber)
+		rplPrice, err := t.getRplTwap(targetBlockNumber, reportLog)
 		if err != nil {
 			t.handleError(fmt.Errorf("%s %w", logPrefix, err))
 			return
 		}
 
 		// Log
-		t.log.Printlnf("RPL price: %.6f ETH", mathutils.RoundDown(eth.WeiToEth(rplPrice), 6))
-
-		submissionTimestamp := uint64(nextSubmissionTime.Unix())
+		reportLog.Printlnf("RPL price: %.6f ETH", mathutils.RoundDown(eth.WeiToEth(rplPrice), 6))
 
 		// Check if we have reported these specific values before
 		hasSubmittedSpecific, err := t.hasSubmittedSpecificBlockPrices(nodeAccount.Address, targetBlockNumber, submissionTimestamp, rplPrice)
@@ -468,20 +475,20 @@ func (t *submitRplPrice) run(state *state.NetworkState) error {
 			return
 		}
 		if hasSubmitted {
-			t.log.Printlnf("Have previously submitted out-of-date prices for block %d, trying again...", targetBlockNumber)
+			reportLog.Printlnf("Have previously submitted out-of-date prices for block %d, trying again...", targetBlockNumber)
 		}
 
 		// Log
-		t.log.Println("Submitting RPL price...")
+		reportLog.Println("Submitting RPL price...")
 
 		// Submit RPL price
-		if err := t.submitRplPrice(targetBlockNumber, submissionTimestamp, rplPrice); err != nil {
+		if err := t.submitRplPrice(targetBlockNumber, submissionTimestamp, rplPrice, reportLog); err != nil {
 			t.handleError(fmt.Errorf("%s could not submit RPL price: %w", logPrefix, err))
 			return
 		}
 
 		// Log and return
-		t.log.Printlnf("%s Price report complete.", logPrefix)
+		reportLog.Printlnf("%s Price report complete.", logPrefix)
 		t.lock.Lock()
 		t.isRunning = false
 		t.lock.Unlock()
@@ -528,7 +535,7 @@ func (t *submitRplPrice) hasSubmittedSpecificBlockPrices(nodeAddress common.Addr
 }
 
 // Get RPL price via TWAP at block
-func (t *submitRplPrice) getRplTwap(blockNumber uint64) (*big.Int, error) {
+func (t *submitRplPrice) getRplTwap(blockNumber uint64, logger *log.ColorLogger) (*big.Int, error) {
 
 	// Initialize call options
 	opts := &bind.CallOpts{
@@ -541,7 +548,7 @@ func (t *submitRplPrice) getRplTwap(blockNumber uint64) (*big.Int, error) {
 	}
 
 	// Get a client with the block number available
-	client, err := eth1.GetBestApiClient(t.rp, t.cfg, t.printMessage, opts.BlockNumber)
+	client, err := eth1.GetBestApiClient(t.rp, t.cfg, func(message string) { logger.Println(message) }, opts.BlockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -593,15 +600,11 @@ func (t *submitRplPrice) getRplTwap(blockNumber uint64) (*big.Int, error) {
 
 }
 
-func (t *submitRplPrice) printMessage(message string) {
-	t.log.Println(message)
-}
-
 // Submit RPL price and total effective RPL stake
-func (t *submitRplPrice) submitRplPrice(blockNumber uint64, slotTimestamp uint64, rplPrice *big.Int) error {
+func (t *submitRplPrice) submitRplPrice(blockNumber uint64, slotTimestamp uint64, rplPrice *big.Int, logger *log.ColorLogger) error {
 
 	// Log
-	t.log.Printlnf("Submitting RPL price for block %d...", blockNumber)
+	logger.Printlnf("Submitting RPL price for block %d...", blockNumber)
 
 	// Get transactor
 	opts, err := t.w.GetNodeAccountTransactor()
@@ -619,7 +622,7 @@ func (t *submitRplPrice) submitRplPrice(blockNumber uint64, slotTimestamp uint64
 
 	// Print the gas info
 	maxFee := eth.GweiToWei(utils.GetWatchtowerMaxFee(t.cfg))
-	if !api.PrintAndCheckGasInfo(gasInfo, false, 0, t.log, maxFee, 0) {
+	if !api.PrintAndCheckGasInfo(gasInfo, false, 0, logger, maxFee, 0) {
 		return nil
 	}
 
@@ -627,6 +630,7 @@ func (t *submitRplPrice) submitRplPrice(blockNumber uint64, slotTimestamp uint64
 	opts.GasFeeCap = maxFee
 	opts.GasTipCap = eth.GweiToWei(utils.GetWatchtowerPrioFee(t.cfg))
 	opts.GasLimit = gasInfo.SafeGasLimit
+	opts.Context = utils.GetWatchtowerSubmissionContext(t.cfg)
 
 	var hash common.Hash
 	// Submit RPL price
@@ -636,13 +640,13 @@ func (t *submitRplPrice) submitRplPrice(blockNumber uint64, slotTimestamp uint64
 	}
 
 	// Print TX info and wait for it to be included in a block
-	err = api.PrintAndWaitForTransaction(t.cfg, hash, t.rp.Client, t.log)
+	err = api.PrintAndWaitForTrackedTransaction(t.cfg, hash, t.rp.Client, opts, maxFee, opts.GasTipCap, logger)
 	if err != nil {
 		return err
 	}
 
 	// Log
-	t.log.Printlnf("Successfully submitted RPL price for block %d.", blockNumber)
+	logger.WithTxHash(hash.Hex()).Printlnf("Successfully submitted RPL price for block %d.", blockNumber)
 
 	// Return
 	return nil
@@ -1013,7 +1017,7 @@ func (t *submitRplPrice) submitArbitrumPrice(priceMessengerAddress string) error
 		opts.GasTipCap = eth.GweiToWei(utils.GetWatchtowerPrioFee(t.cfg))
 		opts.GasLimit = gasInfo.SafeGasLimit
 
-		t.log.Println("Submitting rate to Arbitrum %s...", priceMessengerAddress)
+		t.log.Printlnf("Submitting rate to Arbitrum %s...", priceMessengerAddress)
 
 		// Submit rates
 		tx, err := priceMessenger.Transact(opts, "submitRate", maxSubmissionCost, arbitrumGasLimit, arbitrumMaxFeePerGas)
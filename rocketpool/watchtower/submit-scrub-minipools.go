@@ -507,7 +507,7 @@ func (t *submitScrubMinipools) checkSafetyScrub(state *state.NetworkState) error
 	// Warn if there are any remaining minipools - this should never happen
 	remainingMinipools := len(t.it.minipools)
 	if remainingMinipools > 0 {
-		t.log.Printlnf("WARNING: %d minipools did not have deposit information", remainingMinipools)
+		t.log.WithLevel(log.LevelWarn).Printlnf("WARNING: %d minipools did not have deposit information", remainingMinipools)
 	} else {
 		return nil
 	}
@@ -527,7 +527,7 @@ func (t *submitScrubMinipools) checkSafetyScrub(state *state.NetworkState) error
 
 		// Verify this is actually a prelaunch minipool
 		if mpd.Status != types.Prelaunch {
-			t.log.Printlnf("\tMinipool %s is under review but is in %d status?", minipool.GetAddress().Hex(), types.MinipoolDepositTypes[mpd.Status])
+			t.log.Printlnf("\tMinipool %s is under review but is in %s status?", minipool.GetAddress().Hex(), types.MinipoolDepositTypes[mpd.Status])
 			continue
 		}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/rocket-pool/smartnode/bindings/rocketpool"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/submission"
 )
 
 const (
@@ -32,6 +33,11 @@ func GetWatchtowerPrioFee(cfg *config.RocketPoolConfig) float64 {
 	return max(MinWatchtowerPriorityFee, setting)
 }
 
+// Get the context for submitting the watchtower's price and balance transactions, which selects their submission mode
+func GetWatchtowerSubmissionContext(cfg *config.RocketPoolConfig) context.Context {
+	return submission.WithMode(context.Background(), cfg.Smartnode.GetWatchtowerTxSubmissionMode())
+}
+
 func FindLastBlockWithExecutionPayload(bc beacon.Client, slotNumber uint64) (beacon.BeaconBlock, error) {
 	beaconBlock := beacon.BeaconBlock{}
 	var err error
@@ -1,6 +1,7 @@
 package watchtower
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -18,6 +19,7 @@ import (
 	"github.com/rocket-pool/smartnode/rocketpool/watchtower/collectors"
 	"github.com/rocket-pool/smartnode/shared/services"
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/chainevents"
 	"github.com/rocket-pool/smartnode/shared/services/state"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
 )
@@ -48,6 +50,7 @@ const (
 	CheckSoloMigrationsColor        = color.FgCyan
 	FinalizeProposalsColor          = color.FgMagenta
 	UpdateColor                     = color.FgHiWhite
+	ChainEventsColor                = color.FgBlue
 )
 
 // Register watchtower command
@@ -103,54 +106,56 @@ func run(c *cli.Context) error {
 	bondReductionCollector := collectors.NewBondReductionCollector()
 	soloMigrationCollector := collectors.NewSoloMigrationCollector()
 
-	// Initialize error logger
-	errorLog := log.NewColorLogger(ErrorColor)
-	updateLog := log.NewColorLogger(UpdateColor)
-
-	// Create the state manager
-	m := state.NewNetworkStateManager(rp, cfg.Smartnode.GetStateManagerContracts(), bc, &updateLog)
-
 	// Get the node address
 	nodeAccount, err := w.GetNodeAccount()
 	if err != nil {
 		return fmt.Errorf("error getting node account: %w", err)
 	}
 
+	// Initialize error logger
+	log.SetJsonOutput(cfg.Smartnode.UseJsonLogs())
+	log.SetNodeAddress(nodeAccount.Address.Hex())
+	errorLog := log.NewColorLogger(ErrorColor).WithLevel(log.LevelError)
+	updateLog := log.NewColorLogger(UpdateColor)
+
+	// Create the state manager
+	m := state.NewNetworkStateManager(rp, cfg.Smartnode.GetStateManagerContracts(), bc, &updateLog)
+
 	// Initialize tasks
-	respondChallenges, err := newRespondChallenges(c, log.NewColorLogger(RespondChallengesColor), m)
+	respondChallenges, err := newRespondChallenges(c, log.NewColorLogger(RespondChallengesColor).WithTask("respond-challenges"), m)
 	if err != nil {
 		return fmt.Errorf("error during respond-to-challenges check: %w", err)
 	}
-	submitRplPrice, err := newSubmitRplPrice(c, log.NewColorLogger(SubmitRplPriceColor), errorLog)
+	submitRplPrice, err := newSubmitRplPrice(c, log.NewColorLogger(SubmitRplPriceColor).WithTask("submit-rpl-price"), errorLog.WithTask("submit-rpl-price"))
 	if err != nil {
 		return fmt.Errorf("error during rpl price check: %w", err)
 	}
-	submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewColorLogger(SubmitNetworkBalancesColor), errorLog)
+	submitNetworkBalances, err := newSubmitNetworkBalances(c, log.NewColorLogger(SubmitNetworkBalancesColor).WithTask("submit-network-balances"), errorLog.WithTask("submit-network-balances"))
 	if err != nil {
 		return fmt.Errorf("error during network balances check: %w", err)
 	}
-	dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor))
+	dissolveTimedOutMinipools, err := newDissolveTimedOutMinipools(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor).WithTask("dissolve-timed-out-minipools"))
 	if err != nil {
 		return fmt.Errorf("error during timed-out minipools check: %w", err)
 	}
-	dissolveTimedOutMegapoolValidators, err := newDissolveTimedOutMegapoolValidators(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor))
+	dissolveTimedOutMegapoolValidators, err := newDissolveTimedOutMegapoolValidators(c, log.NewColorLogger(DissolveTimedOutMinipoolsColor).WithTask("dissolve-timed-out-megapool-validators"))
 	if err != nil {
 		return fmt.Errorf("error during timed-out minipools check: %w", err)
 	}
-	challengeValidatorsExiting, err := newChallengeValidatorsExiting(c, log.NewColorLogger(ChallengeValidatorsExitingColor))
+	challengeValidatorsExiting, err := newChallengeValidatorsExiting(c, log.NewColorLogger(ChallengeValidatorsExitingColor).WithTask("challenge-validators-exiting"))
 	if err != nil {
 		return fmt.Errorf("error during flag validators exiting: %w", err)
 	}
-	dissolveInvalidCredentials, err := newDissolveInvalidCredentials(c, log.NewColorLogger(DissolveInvalidCredentialsColor))
+	dissolveInvalidCredentials, err := newDissolveInvalidCredentials(c, log.NewColorLogger(DissolveInvalidCredentialsColor).WithTask("dissolve-invalid-credentials"))
 	if err != nil {
 		return fmt.Errorf("error during invalid credentials check: %w", err)
 	}
-	submitScrubMinipools, err := newSubmitScrubMinipools(c, log.NewColorLogger(SubmitScrubMinipoolsColor), errorLog, scrubCollector)
+	submitScrubMinipools, err := newSubmitScrubMinipools(c, log.NewColorLogger(SubmitScrubMinipoolsColor).WithTask("submit-scrub-minipools"), errorLog.WithTask("submit-scrub-minipools"), scrubCollector)
 	if err != nil {
 		return fmt.Errorf("error during scrub check: %w", err)
 	}
 	var submitRewardsTree_Stateless *submitRewardsTree_Stateless
-	submitRewardsTree_Stateless, err = newSubmitRewardsTree_Stateless(c, log.NewColorLogger(SubmitRewardsTreeColor), errorLog, m)
+	submitRewardsTree_Stateless, err = newSubmitRewardsTree_Stateless(c, log.NewColorLogger(SubmitRewardsTreeColor).WithTask("submit-rewards-tree"), errorLog.WithTask("submit-rewards-tree"), m)
 	if err != nil {
 		return fmt.Errorf("error during stateless rewards tree check: %w", err)
 	}
@@ -158,23 +163,28 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("error during penalties check: %w", err)
 	}*/
-	generateRewardsTree, err := newGenerateRewardsTree(c, log.NewColorLogger(SubmitRewardsTreeColor), errorLog)
+	generateRewardsTree, err := newGenerateRewardsTree(c, log.NewColorLogger(SubmitRewardsTreeColor).WithTask("generate-rewards-tree"), errorLog.WithTask("generate-rewards-tree"))
 	if err != nil {
 		return fmt.Errorf("error during manual tree generation check: %w", err)
 	}
-	cancelBondReductions, err := newCancelBondReductions(c, log.NewColorLogger(CancelBondsColor), errorLog, bondReductionCollector)
+	cancelBondReductions, err := newCancelBondReductions(c, log.NewColorLogger(CancelBondsColor).WithTask("cancel-bond-reductions"), errorLog.WithTask("cancel-bond-reductions"), bondReductionCollector)
 	if err != nil {
 		return fmt.Errorf("error during bond reduction cancel check: %w", err)
 	}
-	checkSoloMigrations, err := newCheckSoloMigrations(c, log.NewColorLogger(CheckSoloMigrationsColor), errorLog, soloMigrationCollector)
+	checkSoloMigrations, err := newCheckSoloMigrations(c, log.NewColorLogger(CheckSoloMigrationsColor).WithTask("check-solo-migrations"), errorLog.WithTask("check-solo-migrations"), soloMigrationCollector)
 	if err != nil {
 		return fmt.Errorf("error during solo migration check: %w", err)
 	}
-	finalizePdaoProposals, err := newFinalizePdaoProposals(c, log.NewColorLogger(FinalizeProposalsColor))
+	finalizePdaoProposals, err := newFinalizePdaoProposals(c, log.NewColorLogger(FinalizeProposalsColor).WithTask("finalize-pdao-proposals"))
 	if err != nil {
 		return fmt.Errorf("error creating finalize-pdao-proposals task: %w", err)
 	}
 
+	// Watch the Beacon chain so the tasks can run as soon as something relevant happens
+	chainEventsLog := log.NewColorLogger(ChainEventsColor).WithTask("chain-events")
+	chainEvents := chainevents.NewWatcher(bc, &chainEventsLog)
+	go chainEvents.Run(context.Background())
+
 	intervalDelta := maxTasksInterval - minTasksInterval
 	secondsDelta := intervalDelta.Seconds()
 
@@ -224,14 +234,14 @@ func run(c *cli.Context) error {
 
 			// Run the manual rewards tree generation
 			if err := generateRewardsTree.run(); err != nil {
-				errorLog.Println(err)
+				errorLog.WithTask("generate-rewards-tree").Println(err)
 			}
 			time.Sleep(taskCooldown)
 
 			if isOnOdao {
 				// Run the challenge check
 				if err := respondChallenges.run(); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("respond-challenges").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
@@ -242,70 +252,71 @@ func run(c *cli.Context) error {
 					time.Sleep(taskCooldown)
 					continue
 				}
+				chainEvents.UpdateState(state)
 
 				// Flag validators that are exiting and didn't notify the exit
 				if err := challengeValidatorsExiting.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("challenge-validators-exiting").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the megapool validator dissolve check
 				if err := dissolveTimedOutMegapoolValidators.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("dissolve-timed-out-megapool-validators").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the invalid credentials dissolve check
 				if err := dissolveInvalidCredentials.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("dissolve-invalid-credentials").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the network balance submission check
 				if err := submitNetworkBalances.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("submit-network-balances").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the rewards tree submission check
 				if err := submitRewardsTree_Stateless.Run(isOnOdao, state, latestBlock.Slot); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("submit-rewards-tree").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the price submission check
 				if err := submitRplPrice.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("submit-rpl-price").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the minipool dissolve check
 				if err := dissolveTimedOutMinipools.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("dissolve-timed-out-minipools").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the finalize proposals check
 				if err := finalizePdaoProposals.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("finalize-pdao-proposals").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the minipool scrub check
 				if err := submitScrubMinipools.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("submit-scrub-minipools").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the bond cancel check
 				if err := cancelBondReductions.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("cancel-bond-reductions").Println(err)
 				}
 				time.Sleep(taskCooldown)
 
 				// Run the solo migration check
 				if err := checkSoloMigrations.run(state); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("check-solo-migrations").Println(err)
 				}
 				/*time.Sleep(taskCooldown)
 
@@ -317,20 +328,21 @@ func run(c *cli.Context) error {
 			} else {
 				// Run the rewards tree submission check
 				if err := submitRewardsTree_Stateless.Run(isOnOdao, nil, latestBlock.Slot); err != nil {
-					errorLog.Println(err)
+					errorLog.WithTask("submit-rewards-tree").Println(err)
 				}
 			}
 
-			time.Sleep(interval)
+			// Wait for the next run, or for a Beacon event that needs the tasks to run sooner
+			chainEvents.Wait(interval)
 		}
 		wg.Done()
 	}()
 
 	// Run metrics loop
 	go func() {
-		err := runMetricsServer(c, log.NewColorLogger(MetricsColor), scrubCollector, bondReductionCollector, soloMigrationCollector)
+		err := runMetricsServer(c, log.NewColorLogger(MetricsColor).WithTask("metrics"), scrubCollector, bondReductionCollector, soloMigrationCollector)
 		if err != nil {
-			errorLog.Println(err)
+			errorLog.WithTask("metrics").Println(err)
 		}
 		wg.Done()
 	}()
@@ -353,8 +365,8 @@ func configureHTTP() {
 // Update the latest network state at each cycle
 func updateNetworkState(m *state.NetworkStateManager, log *log.ColorLogger, block beacon.BeaconBlock) (*state.NetworkState, error) {
 	log.Print("Getting latest network state... ")
-	// Get the state of the network
-	state, err := m.GetStateForSlot(block.Slot)
+	// Get the state of the network, updating the previous cycle's state where possible
+	state, err := m.GetUpdatedStateForSlot(block.Slot)
 	if err != nil {
 		return nil, fmt.Errorf("error getting network state: %w", err)
 	}
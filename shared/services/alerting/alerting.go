@@ -1,12 +1,15 @@
 package alerting
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-openapi/strfmt"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
 	apiclient "github.com/rocket-pool/smartnode/shared/services/alerting/alertmanager/client"
 	apialert "github.com/rocket-pool/smartnode/shared/services/alerting/alertmanager/client/alert"
 	"github.com/rocket-pool/smartnode/shared/services/alerting/alertmanager/models"
@@ -177,6 +180,362 @@ func AlertMinipoolStaked(cfg *config.RocketPoolConfig, minipoolAddress common.Ad
 	return sendAlert(alert, cfg)
 }
 
+// Sends an alert when the node automatically assigned deposit pool ETH to its megapool's next validator or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolValidatorPrestaked(cfg *config.RocketPoolConfig, megapoolAddress common.Address, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolValidatorPrestaked.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolValidatorPrestaked.Value != true {
+		logMessage("alert for MegapoolValidatorPrestaked is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolValidatorPrestaked-%s-%s", succeededOrFailedText, megapoolAddress.Hex()),
+		fmt.Sprintf("Megapool %s prestake %s", megapoolAddress.Hex(), succeededOrFailedText),
+		fmt.Sprintf("The next validator in the megapool with address %s was assigned ETH from the deposit pool with status %s.", megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		map[string]string{
+			"megapool": megapoolAddress.Hex(),
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node automatically staked a megapool validator or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolValidatorStaked(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolValidatorStaked.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolValidatorStaked.Value != true {
+		logMessage("alert for MegapoolValidatorStaked is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolValidatorStaked-%s-%s-%d", succeededOrFailedText, megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d stake %s", megapoolAddress.Hex(), validatorId, succeededOrFailedText),
+		fmt.Sprintf("Validator %d of the megapool with address %s staked with status %s.", validatorId, megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node automatically notified a megapool validator's exit or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolValidatorExiting(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolValidatorExiting.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolValidatorExiting.Value != true {
+		logMessage("alert for MegapoolValidatorExiting is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolValidatorExiting-%s-%s-%d", succeededOrFailedText, megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d exit notification %s", megapoolAddress.Hex(), validatorId, succeededOrFailedText),
+		fmt.Sprintf("Validator %d of the megapool with address %s is exiting, and notifying the megapool of the exit finished with status %s.", validatorId, megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node automatically responded to an exit challenge against a megapool validator or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolChallengeDefended(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolChallengeDefended.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolChallengeDefended.Value != true {
+		logMessage("alert for MegapoolChallengeDefended is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolChallengeDefended-%s-%s-%d", succeededOrFailedText, megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d challenge response %s", megapoolAddress.Hex(), validatorId, succeededOrFailedText),
+		fmt.Sprintf("The response to the exit challenge against validator %d of the megapool with address %s finished with status %s.", validatorId, megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when one of the node's megapool validators was dissolved.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolValidatorDissolved(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolValidatorDissolved.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolValidatorDissolved.Value != true {
+		logMessage("alert for MegapoolValidatorDissolved is disabled, not sending.")
+		return nil
+	}
+
+	alert := createAlert(
+		fmt.Sprintf("MegapoolValidatorDissolved-%s-%d", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d dissolved", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Validator %d of the megapool with address %s was dissolved.", validatorId, megapoolAddress.Hex()),
+		SeverityWarning,
+		getAlertEndsAtForSeverity(SeverityWarning),
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when one of the node's megapool validators was challenged for exiting without notifying the megapool.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolValidatorChallenged(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolValidatorChallenged.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolValidatorChallenged.Value != true {
+		logMessage("alert for MegapoolValidatorChallenged is disabled, not sending.")
+		return nil
+	}
+
+	alert := createAlert(
+		fmt.Sprintf("MegapoolValidatorChallenged-%s-%d", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d exit challenged", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Validator %d of the megapool with address %s was challenged for exiting without notifying the megapool. It's locked until the challenge is answered.", validatorId, megapoolAddress.Hex()),
+		SeverityWarning,
+		getAlertEndsAtForSeverity(SeverityWarning),
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when an exit challenge against one of the node's megapool validators was resolved against the node.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolChallengeLost(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolChallengeLost.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolChallengeLost.Value != true {
+		logMessage("alert for MegapoolChallengeLost is disabled, not sending.")
+		return nil
+	}
+
+	alert := createAlert(
+		fmt.Sprintf("MegapoolChallengeLost-%s-%d", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("Megapool %s validator %d challenge lost", megapoolAddress.Hex(), validatorId),
+		fmt.Sprintf("The exit challenge against validator %d of the megapool with address %s was lost, so the late exit notification fine was added to its debt.", validatorId, megapoolAddress.Hex()),
+		SeverityCritical,
+		getAlertEndsAtForSeverity(SeverityCritical),
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node's megapool incurred debt.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolDebtIncurred(cfg *config.RocketPoolConfig, megapoolAddress common.Address, debt *big.Int) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolDebtIncurred.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolDebtIncurred.Value != true {
+		logMessage("alert for MegapoolDebtIncurred is disabled, not sending.")
+		return nil
+	}
+
+	debtEth := eth.WeiToEth(debt)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolDebtIncurred-%s-%s", megapoolAddress.Hex(), debt.String()),
+		fmt.Sprintf("Megapool %s debt increased to %.6f ETH", megapoolAddress.Hex(), debtEth),
+		fmt.Sprintf("The megapool with address %s now has a debt of %.6f ETH, which will be taken from its rewards until it's repaid.", megapoolAddress.Hex(), debtEth),
+		SeverityWarning,
+		getAlertEndsAtForSeverity(SeverityWarning),
+		map[string]string{
+			"megapool": megapoolAddress.Hex(),
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when one of the node's megapool validators moved in the express queue.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolExpressQueuePositionChanged(cfg *config.RocketPoolConfig, megapoolAddress common.Address, validatorId uint32, position *big.Int) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolExpressQueuePositionChanged.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolExpressQueuePositionChanged.Value != true {
+		logMessage("alert for MegapoolExpressQueuePositionChanged is disabled, not sending.")
+		return nil
+	}
+
+	// Positions are zero-based
+	displayPosition := big.NewInt(0).Add(position, big.NewInt(1))
+	alert := createAlert(
+		fmt.Sprintf("MegapoolExpressQueuePositionChanged-%s-%d-%s", megapoolAddress.Hex(), validatorId, displayPosition.String()),
+		fmt.Sprintf("Megapool %s validator %d is now number %s in the queue", megapoolAddress.Hex(), validatorId, displayPosition.String()),
+		fmt.Sprintf("Validator %d of the megapool with address %s used an express ticket and is now number %s in the deposit queue.", validatorId, megapoolAddress.Hex(), displayPosition.String()),
+		SeverityInfo,
+		getAlertEndsAtForSeverity(SeverityInfo),
+		getMegapoolValidatorLabels(megapoolAddress, validatorId),
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node automatically distributed its megapool's rewards or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolRewardsDistributed(cfg *config.RocketPoolConfig, megapoolAddress common.Address, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolRewardsDistributed.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolRewardsDistributed.Value != true {
+		logMessage("alert for MegapoolRewardsDistributed is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolRewardsDistributed-%s-%s", succeededOrFailedText, megapoolAddress.Hex()),
+		fmt.Sprintf("Megapool %s rewards distribution %s", megapoolAddress.Hex(), succeededOrFailedText),
+		fmt.Sprintf("The megapool with address %s had its rewards distributed with status %s.", megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		map[string]string{
+			"megapool": megapoolAddress.Hex(),
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when the node automatically claimed its megapool's refund or attempted to (success or failure).
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMegapoolRefundClaimed(cfg *config.RocketPoolConfig, megapoolAddress common.Address, succeeded bool) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMegapoolRefundClaimed.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MegapoolRefundClaimed.Value != true {
+		logMessage("alert for MegapoolRefundClaimed is disabled, not sending.")
+		return nil
+	}
+
+	// prepare the alert information:
+	endsAt, severity, succeededOrFailedText := getAlertSettingsForEvent(succeeded)
+	alert := createAlert(
+		fmt.Sprintf("MegapoolRefundClaimed-%s-%s", succeededOrFailedText, megapoolAddress.Hex()),
+		fmt.Sprintf("Megapool %s refund claim %s", megapoolAddress.Hex(), succeededOrFailedText),
+		fmt.Sprintf("The refund of the megapool with address %s was claimed with status %s.", megapoolAddress.Hex(), succeededOrFailedText),
+		severity,
+		endsAt,
+		map[string]string{
+			"megapool": megapoolAddress.Hex(),
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when one of the node's validators missed too many attestations in the recent finalized epochs.
+// The alert stays active for as long as it keeps being sent, so it resolves once the validator is attesting again.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMissedAttestations(cfg *config.RocketPoolConfig, validatorIndex string, missed uint64, epochs uint64) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMissedAttestations.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MissedAttestations.Value != true {
+		logMessage("alert for MissedAttestations is disabled, not sending.")
+		return nil
+	}
+
+	alert := createAlert(
+		fmt.Sprintf("MissedAttestations-%s", validatorIndex),
+		fmt.Sprintf("Validator %s is missing attestations", validatorIndex),
+		fmt.Sprintf("Validator %s missed %d attestations in the last %d finalized epochs.", validatorIndex, missed, epochs),
+		SeverityWarning,
+		strfmt.DateTime(time.Now().Add(DefaultEndsAtDurationForSeverityCritical)),
+		map[string]string{
+			"validator_index": validatorIndex,
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Sends an alert when one of the node's validators missed a block proposal.
+// If alerting/metrics are disabled, this function does nothing.
+func AlertMissedProposal(cfg *config.RocketPoolConfig, validatorIndex string, epoch uint64, missed uint64) error {
+	if !isAlertingEnabled(cfg) {
+		logMessage("alerting is disabled, not sending AlertMissedProposal.")
+		return nil
+	}
+
+	if cfg.Alertmanager.AlertEnabled_MissedProposal.Value != true {
+		logMessage("alert for MissedProposal is disabled, not sending.")
+		return nil
+	}
+
+	alert := createAlert(
+		fmt.Sprintf("MissedProposal-%s-%d", validatorIndex, epoch),
+		fmt.Sprintf("Validator %s missed a block proposal", validatorIndex),
+		fmt.Sprintf("Validator %s missed %d of its block proposals in epoch %d.", validatorIndex, missed, epoch),
+		SeverityCritical,
+		getAlertEndsAtForSeverity(SeverityCritical),
+		map[string]string{
+			"validator_index": validatorIndex,
+		},
+	)
+	return sendAlert(alert, cfg)
+}
+
+// Gets the labels that identify a megapool validator
+func getMegapoolValidatorLabels(megapoolAddress common.Address, validatorId uint32) map[string]string {
+	return map[string]string{
+		"megapool":  megapoolAddress.Hex(),
+		"validator": fmt.Sprint(validatorId),
+	}
+}
+
+// Gets when an alert for something that already happened should end, based on its severity.
+func getAlertEndsAtForSeverity(severity Severity) strfmt.DateTime {
+	if severity == SeverityInfo {
+		return strfmt.DateTime(time.Now().Add(DefaultEndsAtDurationForSeverityInfo))
+	}
+	return strfmt.DateTime(time.Now().Add(DefaultEndsAtDurationForSeverityCritical))
+}
+
 // Gets various settings for an alert based on whether a process succeeded or failed.
 func getAlertSettingsForEvent(succeeded bool) (strfmt.DateTime, Severity, string) {
 	endsAt := strfmt.DateTime(time.Now().Add(DefaultEndsAtDurationForSeverityInfo))
@@ -236,13 +595,22 @@ func alertClientSyncComplete(cfg *config.RocketPoolConfig, client ClientKind) er
 func sendAlert(alert *models.PostableAlert, cfg *config.RocketPoolConfig) error {
 	logMessage("sending alert for %s: %s", alert.Labels["alertname"], alert.Annotations["summary"])
 
-	params := apialert.NewPostAlertsParams().WithDefaults().WithAlerts(models.PostableAlerts{alert})
-	client := createClient(cfg)
-	_, err := client.Alert.PostAlerts(params)
+	errs := []error{}
+	if cfg.Alertmanager.SendToAlertmanager.Value == true {
+		params := apialert.NewPostAlertsParams().WithDefaults().WithAlerts(models.PostableAlerts{alert})
+		client := createClient(cfg)
+		_, err := client.Alert.PostAlerts(params)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error posting alert: %s", err.Error()))
+		}
+	}
+
+	// Deliver the alert directly to any notifiers that are configured
+	err := getNotificationDispatcher(cfg).dispatch(alert)
 	if err != nil {
-		return fmt.Errorf("error posting alert: %s", err.Error())
+		errs = append(errs, err)
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 type Severity string
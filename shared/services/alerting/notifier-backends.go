@@ -0,0 +1,284 @@
+package alerting
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	notifierRequestTimeout = 10 * time.Second
+	defaultTelegramApiUrl  = "https://api.telegram.org"
+)
+
+// Posts the notification as JSON to a generic webhook
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookNotifier(url string) *webhookNotifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: notifierRequestTimeout},
+	}
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(notification *Notification) error {
+	return postJson(n.client, n.url, notification)
+}
+
+// Sends the notification as a message from a Telegram bot
+type telegramNotifier struct {
+	apiUrl string
+	token  string
+	chatID string
+	client *http.Client
+}
+
+func newTelegramNotifier(token string, chatID string) *telegramNotifier {
+	return &telegramNotifier{
+		apiUrl: defaultTelegramApiUrl,
+		token:  token,
+		chatID: chatID,
+		client: &http.Client{Timeout: notifierRequestTimeout},
+	}
+}
+
+func (n *telegramNotifier) Name() string {
+	return "Telegram"
+}
+
+func (n *telegramNotifier) Notify(notification *Notification) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", n.apiUrl, n.token)
+	err := postJson(n.client, url, map[string]string{
+		"chat_id": n.chatID,
+		"text":    getMessageText(notification),
+	})
+	if err != nil {
+		// Don't leak the bot token into the logs
+		return errors.New(strings.ReplaceAll(err.Error(), n.token, "<token>"))
+	}
+	return nil
+}
+
+// Publishes the notification to an ntfy topic
+type ntfyNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newNtfyNotifier(url string, token string) *ntfyNotifier {
+	return &ntfyNotifier{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: notifierRequestTimeout},
+	}
+}
+
+func (n *ntfyNotifier) Name() string {
+	return "ntfy"
+}
+
+func (n *ntfyNotifier) Notify(notification *Notification) error {
+	request, err := http.NewRequest(http.MethodPost, n.url, strings.NewReader(notification.Description))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Set("Title", notification.Title())
+	request.Header.Set("Priority", getNtfyPriority(notification))
+	request.Header.Set("Tags", strings.ToLower(notification.Status()))
+	if n.token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return sendRequest(n.client, request)
+}
+
+// Get the ntfy priority for a notification; resolutions are never urgent
+func getNtfyPriority(notification *Notification) string {
+	if notification.Resolved {
+		return "default"
+	}
+	switch notification.Severity {
+	case SeverityCritical:
+		return "urgent"
+	case SeverityWarning:
+		return "high"
+	default:
+		return "default"
+	}
+}
+
+// Sends the notification to a Slack incoming webhook
+type slackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func newSlackNotifier(url string) *slackNotifier {
+	return &slackNotifier{
+		url:    url,
+		client: &http.Client{Timeout: notifierRequestTimeout},
+	}
+}
+
+func (n *slackNotifier) Name() string {
+	return "Slack"
+}
+
+func (n *slackNotifier) Notify(notification *Notification) error {
+	return postJson(n.client, n.url, map[string]string{
+		"text": getMessageText(notification),
+	})
+}
+
+// Sends the notification by email
+type smtpNotifier struct {
+	host     string
+	port     uint16
+	username string
+	password string
+	from     string
+	to       []string
+	timeout  time.Duration
+}
+
+func newSmtpNotifier(host string, port uint16, username string, password string, from string, to []string) *smtpNotifier {
+	return &smtpNotifier{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+		timeout:  notifierRequestTimeout,
+	}
+}
+
+func (n *smtpNotifier) Name() string {
+	return "SMTP"
+}
+
+func (n *smtpNotifier) Notify(notification *Notification) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", notification.Title())
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(getMessageText(notification), "\n", "\r\n"))
+	message.WriteString("\r\n")
+
+	return n.send(auth, message.Bytes())
+}
+
+// Send an email the way smtp.SendMail does, but with a deadline so an unresponsive server can't block the task that raised the alert
+func (n *smtpNotifier) send(auth smtp.Auth, message []byte) error {
+	address := net.JoinHostPort(n.host, strconv.FormatUint(uint64(n.port), 10))
+	conn, err := net.DialTimeout("tcp", address, n.timeout)
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(n.timeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("the SMTP server doesn't support authentication")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	for _, to := range n.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Get the plain text body of a notification for chat and email backends
+func getMessageText(notification *Notification) string {
+	return fmt.Sprintf("%s\n%s\nSeverity: %s", notification.Title(), notification.Description, notification.Severity)
+}
+
+// Post a value to a URL as JSON
+func postJson(client *http.Client, url string, value any) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error serializing notification: %w", err)
+	}
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return sendRequest(client, request)
+}
+
+// Send a request and make sure it succeeded
+func sendRequest(client *http.Client, request *http.Request) error {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("request failed with status %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rocket-pool/smartnode/shared/services/alerting/alertmanager/models"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+// A backend that delivers alerts directly, without going through Alertmanager
+type Notifier interface {
+	// The name of the backend, used in log messages
+	Name() string
+
+	// Deliver a notification that an alert started firing or was resolved
+	Notify(notification *Notification) error
+}
+
+// An alert as it's delivered to the direct notifiers
+type Notification struct {
+	Name        string            `json:"name"`
+	Summary     string            `json:"summary"`
+	Description string            `json:"description"`
+	Severity    Severity          `json:"severity"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Resolved    bool              `json:"resolved"`
+}
+
+// Get the status of the notification as a short, human-readable tag
+func (n *Notification) Status() string {
+	if n.Resolved {
+		return "RESOLVED"
+	}
+	return "FIRING"
+}
+
+// Get the title of the notification, for backends that show one
+func (n *Notification) Title() string {
+	return fmt.Sprintf("[%s] %s", n.Status(), n.Summary)
+}
+
+// An alert that has been delivered and hasn't been resolved yet
+type activeNotification struct {
+	notification *Notification
+	timer        *time.Timer
+}
+
+// Delivers alerts to the direct notifiers with the same semantics as Alertmanager:
+// an alert is only delivered once while it's active (alerts with the same labels are the same alert),
+// and a resolved notification is delivered once it reaches its end time.
+type notificationDispatcher struct {
+	notifiers []Notifier
+	active    map[string]*activeNotification
+	lock      sync.Mutex
+
+	// Used to get the current time, replaceable for testing
+	now func() time.Time
+}
+
+// The dispatcher shared by all alerts sent from this process
+var (
+	dispatcher     *notificationDispatcher
+	dispatcherLock sync.Mutex
+)
+
+// Creates a dispatcher for the provided notifiers
+func newNotificationDispatcher(notifiers []Notifier) *notificationDispatcher {
+	return &notificationDispatcher{
+		notifiers: notifiers,
+		active:    map[string]*activeNotification{},
+		now:       time.Now,
+	}
+}
+
+// Get the dispatcher for the direct notifiers enabled in the config
+func getNotificationDispatcher(cfg *config.RocketPoolConfig) *notificationDispatcher {
+	dispatcherLock.Lock()
+	defer dispatcherLock.Unlock()
+	if dispatcher == nil {
+		dispatcher = newNotificationDispatcher(createNotifiers(cfg))
+	}
+	return dispatcher
+}
+
+// Deliver an alert to the notifiers unless it's already active
+func (d *notificationDispatcher) dispatch(alert *models.PostableAlert) error {
+	if len(d.notifiers) == 0 {
+		return nil
+	}
+
+	now := d.now()
+	notification := createNotification(alert, now)
+	fingerprint := getFingerprint(notification.Labels)
+
+	// Resolve anything that has ended before checking for duplicates
+	err := d.resolveExpired(now)
+
+	d.lock.Lock()
+	existing, exists := d.active[fingerprint]
+	if exists {
+		// Alertmanager only extends an active alert, so do the same
+		if notification.EndsAt.After(existing.notification.EndsAt) {
+			existing.notification.EndsAt = notification.EndsAt
+			existing.timer.Reset(notification.EndsAt.Sub(now))
+		}
+		d.lock.Unlock()
+		logMessage("alert %s is already active, not sending it again.", notification.Name)
+		return err
+	}
+	d.lock.Unlock()
+
+	// Only mark the alert as active once it was delivered, so it's sent again the next time it's raised if delivery failed
+	notifyErr := d.notify(notification)
+	if notifyErr != nil {
+		return errors.Join(err, notifyErr)
+	}
+	d.lock.Lock()
+	if _, exists := d.active[fingerprint]; !exists {
+		d.active[fingerprint] = &activeNotification{
+			notification: notification,
+			timer: time.AfterFunc(notification.EndsAt.Sub(now), func() {
+				if err := d.resolveExpired(d.now()); err != nil {
+					logMessage("error resolving alerts: %s", err.Error())
+				}
+			}),
+		}
+	}
+	d.lock.Unlock()
+
+	return err
+}
+
+// Deliver resolved notifications for all of the active alerts that have ended
+func (d *notificationDispatcher) resolveExpired(now time.Time) error {
+	d.lock.Lock()
+	resolved := []*Notification{}
+	for fingerprint, active := range d.active {
+		if active.notification.EndsAt.After(now) {
+			continue
+		}
+		active.timer.Stop()
+		delete(d.active, fingerprint)
+		notification := *active.notification
+		notification.Resolved = true
+		resolved = append(resolved, &notification)
+	}
+	d.lock.Unlock()
+
+	errs := []error{}
+	for _, notification := range resolved {
+		errs = append(errs, d.notify(notification))
+	}
+	return errors.Join(errs...)
+}
+
+// Deliver a notification to every notifier, even if some of them fail
+func (d *notificationDispatcher) notify(notification *Notification) error {
+	errs := []error{}
+	for _, notifier := range d.notifiers {
+		logMessage("sending %s notification for %s to %s", strings.ToLower(notification.Status()), notification.Name, notifier.Name())
+		err := notifier.Notify(notification)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error sending notification to %s: %w", notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Creates the notification for an alert
+func createNotification(alert *models.PostableAlert, now time.Time) *Notification {
+	labels := make(map[string]string, len(alert.Labels))
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+
+	endsAt := time.Time(alert.EndsAt)
+	if endsAt.IsZero() {
+		endsAt = now.Add(DefaultEndsAtDurationForSeverityInfo)
+	}
+
+	return &Notification{
+		Name:        labels["alertname"],
+		Summary:     alert.Annotations["summary"],
+		Description: alert.Annotations["description"],
+		Severity:    Severity(labels["severity"]),
+		Labels:      labels,
+		StartsAt:    now,
+		EndsAt:      endsAt,
+	}
+}
+
+// Get a key that identifies an alert by its labels, the way Alertmanager does
+func getFingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&builder, "%s=%q,", k, labels[k])
+	}
+	return builder.String()
+}
+
+// Creates the notifiers that are configured
+func createNotifiers(cfg *config.RocketPoolConfig) []Notifier {
+	notifiers := []Notifier{}
+	amCfg := cfg.Alertmanager
+
+	if url := getStringValue(amCfg.WebhookURL.Value); url != "" {
+		notifiers = append(notifiers, newWebhookNotifier(url))
+	}
+	token := getStringValue(amCfg.TelegramBotToken.Value)
+	chatID := getStringValue(amCfg.TelegramChatID.Value)
+	if token != "" && chatID != "" {
+		notifiers = append(notifiers, newTelegramNotifier(token, chatID))
+	}
+	if url := getStringValue(amCfg.NtfyURL.Value); url != "" {
+		notifiers = append(notifiers, newNtfyNotifier(url, getStringValue(amCfg.NtfyToken.Value)))
+	}
+	if url := getStringValue(amCfg.SlackWebhookURL.Value); url != "" {
+		notifiers = append(notifiers, newSlackNotifier(url))
+	}
+	host := getStringValue(amCfg.SmtpHost.Value)
+	recipients := splitAddresses(getStringValue(amCfg.SmtpTo.Value))
+	if host != "" && len(recipients) > 0 {
+		port, _ := amCfg.SmtpPort.Value.(uint16)
+		notifiers = append(notifiers, newSmtpNotifier(
+			host,
+			port,
+			getStringValue(amCfg.SmtpUsername.Value),
+			getStringValue(amCfg.SmtpPassword.Value),
+			getStringValue(amCfg.SmtpFrom.Value),
+			recipients,
+		))
+	}
+
+	return notifiers
+}
+
+// Get the trimmed value of a string parameter
+func getStringValue(value interface{}) string {
+	str, _ := value.(string)
+	return strings.TrimSpace(str)
+}
+
+// Split a comma-separated list of email addresses
+func splitAddresses(addresses string) []string {
+	result := []string{}
+	for _, address := range strings.Split(addresses, ",") {
+		address = strings.TrimSpace(address)
+		if address != "" {
+			result = append(result, address)
+		}
+	}
+	return result
+}
@@ -0,0 +1,387 @@
+package alerting
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// Records the notifications it receives
+type recordingNotifier struct {
+	notifications []Notification
+}
+
+func (n *recordingNotifier) Name() string {
+	return "recorder"
+}
+
+func (n *recordingNotifier) Notify(notification *Notification) error {
+	n.notifications = append(n.notifications, *notification)
+	return nil
+}
+
+// Fails to deliver notifications until it's told to start working
+type flakyNotifier struct {
+	failing  bool
+	attempts int
+}
+
+func (n *flakyNotifier) Name() string {
+	return "flaky"
+}
+
+func (n *flakyNotifier) Notify(notification *Notification) error {
+	n.attempts++
+	if n.failing {
+		return errors.New("delivery failed")
+	}
+	return nil
+}
+
+func getTestNotification() *Notification {
+	return &Notification{
+		Name:        "MegapoolValidatorDissolved-1",
+		Summary:     "Megapool validator 1 dissolved",
+		Description: "Validator 1 was dissolved.",
+		Severity:    SeverityWarning,
+		Labels: map[string]string{
+			"alertname": "MegapoolValidatorDissolved-1",
+			"severity":  string(SeverityWarning),
+		},
+	}
+}
+
+func TestDispatcherDeduplicatesAndResolves(t *testing.T) {
+	recorder := &recordingNotifier{}
+	d := newNotificationDispatcher([]Notifier{recorder})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	endsAt := now.Add(time.Hour)
+	alert := createAlert("Test", "Test summary", "Test description", SeverityWarning, strfmt.DateTime(endsAt), map[string]string{"megapool": "0x01"})
+	if err := d.dispatch(alert); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same alert again is a duplicate, but it extends the end time
+	now = now.Add(time.Minute)
+	alert = createAlert("Test", "Test summary", "Test description", SeverityWarning, strfmt.DateTime(endsAt.Add(time.Minute)), map[string]string{"megapool": "0x01"})
+	if err := d.dispatch(alert); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.notifications) != 1 {
+		t.Fatalf("expected 1 notification after a duplicate alert, got %d", len(recorder.notifications))
+	}
+
+	// An alert with different labels is a different alert
+	other := createAlert("Test", "Test summary", "Test description", SeverityWarning, strfmt.DateTime(endsAt), map[string]string{"megapool": "0x02"})
+	if err := d.dispatch(other); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.notifications) != 2 {
+		t.Fatalf("expected 2 notifications after a new alert, got %d", len(recorder.notifications))
+	}
+
+	// Only the alert that wasn't extended has ended
+	if err := d.resolveExpired(endsAt); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.notifications) != 3 {
+		t.Fatalf("expected 3 notifications after the first resolution, got %d", len(recorder.notifications))
+	}
+	resolved := recorder.notifications[2]
+	if !resolved.Resolved || resolved.Labels["megapool"] != "0x02" {
+		t.Fatalf("expected the alert for 0x02 to be resolved, got %+v", resolved)
+	}
+
+	if err := d.resolveExpired(endsAt.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.notifications) != 4 || !recorder.notifications[3].Resolved {
+		t.Fatalf("expected the extended alert to be resolved, got %+v", recorder.notifications)
+	}
+
+	// Once resolved, the alert fires again
+	now = endsAt.Add(2 * time.Minute)
+	alert = createAlert("Test", "Test summary", "Test description", SeverityWarning, strfmt.DateTime(now.Add(time.Hour)), map[string]string{"megapool": "0x01"})
+	if err := d.dispatch(alert); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.notifications) != 5 || recorder.notifications[4].Resolved {
+		t.Fatalf("expected the alert to fire again after it was resolved, got %+v", recorder.notifications)
+	}
+}
+
+func TestDispatcherRetriesFailedAlerts(t *testing.T) {
+	notifier := &flakyNotifier{failing: true}
+	d := newNotificationDispatcher([]Notifier{notifier})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	alert := createAlert("Test", "Test summary", "Test description", SeverityWarning, strfmt.DateTime(now.Add(time.Hour)), map[string]string{"megapool": "0x01"})
+	if err := d.dispatch(alert); err == nil {
+		t.Fatal("expected the failed delivery to be reported")
+	}
+
+	// The failed alert isn't active, so it's sent again the next time it's raised
+	notifier.failing = false
+	if err := d.dispatch(alert); err != nil {
+		t.Fatal(err)
+	}
+	if notifier.attempts != 2 {
+		t.Fatalf("expected the alert to be sent again after it failed, got %d attempts", notifier.attempts)
+	}
+
+	// Now that it was delivered it's a duplicate
+	if err := d.dispatch(alert); err != nil {
+		t.Fatal(err)
+	}
+	if notifier.attempts != 2 {
+		t.Fatalf("expected the delivered alert not to be sent again, got %d attempts", notifier.attempts)
+	}
+}
+
+// Starts an HTTP stand-in that records the last request it received
+func startHttpStandIn(t *testing.T, status int) (*httptest.Server, func() (*http.Request, []byte)) {
+	var lock sync.Mutex
+	var lastRequest *http.Request
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		lastRequest = r
+		lastBody = body
+		lock.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (*http.Request, []byte) {
+		lock.Lock()
+		defer lock.Unlock()
+		return lastRequest, lastBody
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, getRequest := startHttpStandIn(t, http.StatusOK)
+	notifier := newWebhookNotifier(server.URL)
+	if err := notifier.Notify(getTestNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	request, body := getRequest()
+	if request.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected content type %s", request.Header.Get("Content-Type"))
+	}
+	var received Notification
+	if err := json.Unmarshal(body, &received); err != nil {
+		t.Fatal(err)
+	}
+	if received.Name != "MegapoolValidatorDissolved-1" || received.Severity != SeverityWarning || received.Resolved {
+		t.Fatalf("unexpected webhook payload %s", string(body))
+	}
+}
+
+func TestWebhookNotifierReportsFailures(t *testing.T) {
+	server, _ := startHttpStandIn(t, http.StatusInternalServerError)
+	notifier := newWebhookNotifier(server.URL)
+	if err := notifier.Notify(getTestNotification()); err == nil {
+		t.Fatal("expected an error when the webhook fails")
+	}
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	server, getRequest := startHttpStandIn(t, http.StatusOK)
+	notifier := newTelegramNotifier("123:abc", "-100")
+	notifier.apiUrl = server.URL
+	if err := notifier.Notify(getTestNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	request, body := getRequest()
+	if request.URL.Path != "/bot123:abc/sendMessage" {
+		t.Fatalf("unexpected path %s", request.URL.Path)
+	}
+	var message map[string]string
+	if err := json.Unmarshal(body, &message); err != nil {
+		t.Fatal(err)
+	}
+	if message["chat_id"] != "-100" || !strings.HasPrefix(message["text"], "[FIRING] Megapool validator 1 dissolved") {
+		t.Fatalf("unexpected Telegram message %s", string(body))
+	}
+}
+
+func TestTelegramNotifierHidesToken(t *testing.T) {
+	server, _ := startHttpStandIn(t, http.StatusUnauthorized)
+	notifier := newTelegramNotifier("123:abc", "-100")
+	notifier.apiUrl = server.URL
+	err := notifier.Notify(getTestNotification())
+	if err == nil {
+		t.Fatal("expected an error when Telegram rejects the message")
+	}
+	if strings.Contains(err.Error(), "123:abc") {
+		t.Fatalf("error leaked the bot token: %s", err.Error())
+	}
+}
+
+func TestNtfyNotifier(t *testing.T) {
+	server, getRequest := startHttpStandIn(t, http.StatusOK)
+	notifier := newNtfyNotifier(server.URL+"/alerts", "tk_test")
+	notification := getTestNotification()
+	notification.Resolved = true
+	if err := notifier.Notify(notification); err != nil {
+		t.Fatal(err)
+	}
+
+	request, body := getRequest()
+	if request.URL.Path != "/alerts" {
+		t.Fatalf("unexpected path %s", request.URL.Path)
+	}
+	if request.Header.Get("Title") != "[RESOLVED] Megapool validator 1 dissolved" {
+		t.Fatalf("unexpected title %s", request.Header.Get("Title"))
+	}
+	if request.Header.Get("Priority") != "default" {
+		t.Fatalf("unexpected priority %s", request.Header.Get("Priority"))
+	}
+	if request.Header.Get("Authorization") != "Bearer tk_test" {
+		t.Fatalf("unexpected authorization %s", request.Header.Get("Authorization"))
+	}
+	if string(body) != "Validator 1 was dissolved." {
+		t.Fatalf("unexpected body %s", string(body))
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, getRequest := startHttpStandIn(t, http.StatusOK)
+	notifier := newSlackNotifier(server.URL)
+	if err := notifier.Notify(getTestNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, body := getRequest()
+	var message map[string]string
+	if err := json.Unmarshal(body, &message); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(message["text"], "Validator 1 was dissolved.") {
+		t.Fatalf("unexpected Slack message %s", string(body))
+	}
+}
+
+// Starts a minimal SMTP stand-in that accepts a single message and sends its data to the channel
+func startSmtpStandIn(t *testing.T) (string, uint16, chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RCPT TO"):
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 Send the message")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), uint16(address.Port), messages
+}
+
+func TestSmtpNotifier(t *testing.T) {
+	host, port, messages := startSmtpStandIn(t)
+	notifier := newSmtpNotifier(host, port, "", "", "node@example.com", []string{"operator@example.com"})
+	if err := notifier.Notify(getTestNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case message := <-messages:
+		if !strings.Contains(message, "Subject: [FIRING] Megapool validator 1 dissolved\r\n") {
+			t.Fatalf("unexpected email subject in %s", message)
+		}
+		if !strings.Contains(message, "To: operator@example.com\r\n") || !strings.Contains(message, "Validator 1 was dissolved.") {
+			t.Fatalf("unexpected email %s", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the email")
+	}
+}
+
+func TestSmtpNotifierTimesOut(t *testing.T) {
+	// A server that accepts connections but never greets the client
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { conn.Close() })
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	notifier := newSmtpNotifier(address.IP.String(), uint16(address.Port), "", "", "node@example.com", []string{"operator@example.com"})
+	notifier.timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if err := notifier.Notify(getTestNotification()); err == nil {
+		t.Fatal("expected the notification to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("the notification took %s to time out", elapsed)
+	}
+}
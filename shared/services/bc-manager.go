@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"strings"
@@ -85,7 +86,7 @@ func NewBeaconClientManager(cfg *config.RocketPoolConfig) (*BeaconClientManager,
 	return &BeaconClientManager{
 		primaryBc:     primaryBc,
 		fallbackBc:    fallbackBc,
-		logger:        log.NewColorLogger(color.FgHiBlue),
+		logger:        log.NewColorLogger(color.FgHiBlue).WithTask("bc-manager"),
 		primaryReady:  true,
 		fallbackReady: fallbackBc != nil,
 	}, nil
@@ -341,6 +342,17 @@ func (m *BeaconClientManager) ChangeWithdrawalCredentials(validatorIndex string,
 	return nil
 }
 
+// Subscribe to the Beacon Node's event stream
+func (m *BeaconClientManager) SubscribeToEvents(ctx context.Context, topics []beacon.EventTopic) (<-chan beacon.Event, error) {
+	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
+		return client.SubscribeToEvents(ctx, topics)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(<-chan beacon.Event), nil
+}
+
 // Get the validator balances for a set of validators at a given slot, with backoff.
 func (m *BeaconClientManager) GetValidatorBalancesSafe(indices []string, opts *beacon.ValidatorStatusOptions) (map[string]*big.Int, error) {
 	result, err := m.runFunction1(func(client beacon.Client) (interface{}, error) {
@@ -438,7 +450,7 @@ func (m *BeaconClientManager) runFunction0(function bcFunction0) error {
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
 				m.primaryReady = false
 				return m.runFunction0(function)
 			}
@@ -455,7 +467,7 @@ func (m *BeaconClientManager) runFunction0(function bcFunction0) error {
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
 				m.fallbackReady = false
 				return fmt.Errorf("all Beacon clients failed")
 			}
@@ -480,7 +492,7 @@ func (m *BeaconClientManager) runFunction1(function bcFunction1) (interface{}, e
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
 				m.primaryReady = false
 				return m.runFunction1(function)
 			}
@@ -497,7 +509,7 @@ func (m *BeaconClientManager) runFunction1(function bcFunction1) (interface{}, e
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
 				m.fallbackReady = false
 				return nil, fmt.Errorf("all Beacon clients failed")
 			}
@@ -522,7 +534,7 @@ func (m *BeaconClientManager) runFunction2(function bcFunction2) (interface{}, i
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Primary Beacon client disconnected (%s), using fallback...", err.Error())
 				m.primaryReady = false
 				return m.runFunction2(function)
 			}
@@ -539,7 +551,7 @@ func (m *BeaconClientManager) runFunction2(function bcFunction2) (interface{}, i
 		if err != nil {
 			if m.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				m.logger.Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
+				m.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Fallback Beacon client disconnected (%s)", err.Error())
 				m.fallbackReady = false
 				return nil, nil, fmt.Errorf("all Beacon clients failed")
 			}
@@ -1,6 +1,7 @@
 package beacon
 
 import (
+	"context"
 	"math/big"
 	"sort"
 
@@ -68,6 +69,37 @@ type BeaconBlockHeader struct {
 	ProposerIndex string
 }
 
+// An event from the Beacon Node's event stream; only the field for the event's topic is set
+type Event struct {
+	Topic               EventTopic
+	Head                *HeadEvent
+	FinalizedCheckpoint *FinalizedCheckpointEvent
+	ChainReorg          *ChainReorgEvent
+	VoluntaryExit       *VoluntaryExitEvent
+}
+type HeadEvent struct {
+	Slot            uint64
+	Block           common.Hash
+	State           common.Hash
+	EpochTransition bool
+}
+type FinalizedCheckpointEvent struct {
+	Epoch uint64
+	Block common.Hash
+	State common.Hash
+}
+type ChainReorgEvent struct {
+	Slot         uint64
+	Depth        uint64
+	OldHeadBlock common.Hash
+	NewHeadBlock common.Hash
+	Epoch        uint64
+}
+type VoluntaryExitEvent struct {
+	ValidatorIndex string
+	Epoch          uint64
+}
+
 // Committees is an interface as an optimization- since committees responses
 // are quite large, there's a decent cpu/memory improvement to removing the
 // translation to an intermediate storage class.
@@ -138,6 +170,16 @@ const (
 	Unknown
 )
 
+// Topics of the Beacon Node's event stream
+type EventTopic string
+
+const (
+	EventTopic_Head                EventTopic = "head"
+	EventTopic_FinalizedCheckpoint EventTopic = "finalized_checkpoint"
+	EventTopic_ChainReorg          EventTopic = "chain_reorg"
+	EventTopic_VoluntaryExit       EventTopic = "voluntary_exit"
+)
+
 type ValidatorState string
 
 const (
@@ -191,6 +233,7 @@ type Client interface {
 	GetEth1DataForEth2Block(blockId string) (Eth1Data, bool, error)
 	GetCommitteesForEpoch(epoch *uint64) (Committees, error)
 	ChangeWithdrawalCredentials(validatorIndex string, fromBlsPubkey types.ValidatorPubkey, toExecutionAddress common.Address, signature types.ValidatorSignature) error
+	SubscribeToEvents(ctx context.Context, topics []EventTopic) (<-chan Event, error)
 
 	GetBeaconStateSSZ(slot uint64) (*BeaconStateSSZ, error)
 	GetBeaconBlockSSZ(slot uint64) (*BeaconBlockSSZ, bool, error)
@@ -0,0 +1,256 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+const (
+	// The version of the cached response format; bump this when it changes so old responses are ignored
+	cachedResponseVersion int = 1
+
+	// How long to wait before asking the Beacon Node for the finalized epoch again when a request is newer than the last one
+	finalityCheckInterval time.Duration = 12 * time.Second
+)
+
+// A cached response, along with whether the requested object existed
+type cachedResponse[DataType any] struct {
+	Exists bool     `json:"exists"`
+	Data   DataType `json:"data"`
+}
+
+// A Beacon client that wraps another one, caching responses for finalized data on disk so they don't have to be downloaded again.
+// Requests that aren't for finalized data (or can't be identified as such, like requests for "head") are always passed through.
+type CachingClient struct {
+	beacon.Client
+	store *responseStore
+
+	// The namespace for this network's responses, and the info needed to know what's finalized
+	prefix            string
+	slotsPerEpoch     uint64
+	finalizedSlot     uint64
+	lastFinalityCheck time.Time
+	finalityLock      sync.Mutex
+}
+
+// Creates a new caching client that saves up to maxSize bytes of responses in the provided folder
+func NewCachingClient(client beacon.Client, folder string, maxSize uint64) (*CachingClient, error) {
+	store, err := newResponseStore(folder, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &CachingClient{
+		Client: client,
+		store:  store,
+	}, nil
+}
+
+// Get the attestations in a block
+func (c *CachingClient) GetAttestations(blockId string) ([]beacon.AttestationInfo, bool, error) {
+	key, cacheable := c.getSlotKey("attestations", blockId)
+	if !cacheable {
+		return c.Client.GetAttestations(blockId)
+	}
+	var response cachedResponse[[]beacon.AttestationInfo]
+	if c.load(key, &response) {
+		return response.Data, response.Exists, nil
+	}
+
+	attestations, exists, err := c.Client.GetAttestations(blockId)
+	if err != nil {
+		return nil, false, err
+	}
+	c.save(key, cachedResponse[[]beacon.AttestationInfo]{Exists: exists, Data: attestations})
+	return attestations, exists, nil
+}
+
+// Get a beacon block
+func (c *CachingClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock, bool, error) {
+	key, cacheable := c.getSlotKey("block", blockId)
+	if !cacheable {
+		return c.Client.GetBeaconBlock(blockId)
+	}
+	var response cachedResponse[beacon.BeaconBlock]
+	if c.load(key, &response) {
+		return response.Data, response.Exists, nil
+	}
+
+	block, exists, err := c.Client.GetBeaconBlock(blockId)
+	if err != nil {
+		return beacon.BeaconBlock{}, false, err
+	}
+	c.save(key, cachedResponse[beacon.BeaconBlock]{Exists: exists, Data: block})
+	return block, exists, nil
+}
+
+// Get the attestation committees for an epoch
+func (c *CachingClient) GetCommitteesForEpoch(epoch *uint64) (beacon.Committees, error) {
+	if epoch == nil || !c.isFinalized(*epoch*c.getSlotsPerEpoch()) {
+		return c.Client.GetCommitteesForEpoch(epoch)
+	}
+	key := c.getKey("committees", strconv.FormatUint(*epoch, 10))
+	var response CommitteesResponse
+	if c.load(key, &response) {
+		return &response, nil
+	}
+
+	committees, err := c.Client.GetCommitteesForEpoch(epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save them in the same format as the Beacon Node's response, so they decode into the pooled validator slices
+	response = CommitteesResponse{
+		Data: make([]Committee, committees.Count()),
+	}
+	for i := range response.Data {
+		response.Data[i] = Committee{
+			Index:      uinteger(committees.Index(i)),
+			Slot:       uinteger(committees.Slot(i)),
+			Validators: committees.Validators(i),
+		}
+	}
+	c.save(key, response)
+	return committees, nil
+}
+
+// Get multiple validators' statuses
+func (c *CachingClient) GetValidatorStatuses(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+	// Only statuses at a specific finalized slot or epoch can be cached
+	var stateId string
+	if opts != nil && opts.Slot != nil && c.isFinalized(*opts.Slot) {
+		stateId = fmt.Sprintf("slot-%d", *opts.Slot)
+	} else if opts != nil && opts.Slot == nil && opts.Epoch != nil && c.isFinalized(*opts.Epoch*c.getSlotsPerEpoch()) {
+		stateId = fmt.Sprintf("epoch-%d", *opts.Epoch)
+	} else {
+		return c.Client.GetValidatorStatuses(pubkeys, opts)
+	}
+
+	// The request is identified by the set of pubkeys, regardless of their order
+	pubkeyStrings := make([]string, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		pubkeyStrings[i] = pubkey.Hex()
+	}
+	sort.Strings(pubkeyStrings)
+	pubkeysHash := sha256.Sum256([]byte(strings.Join(pubkeyStrings, ",")))
+	key := c.getKey("validators", stateId, hex.EncodeToString(pubkeysHash[:]))
+
+	var response cachedResponse[[]beacon.ValidatorStatus]
+	if c.load(key, &response) {
+		statuses := make(map[types.ValidatorPubkey]beacon.ValidatorStatus, len(response.Data))
+		for _, status := range response.Data {
+			statuses[status.Pubkey] = status
+		}
+		return statuses, nil
+	}
+
+	statuses, err := c.Client.GetValidatorStatuses(pubkeys, opts)
+	if err != nil {
+		return nil, err
+	}
+	response = cachedResponse[[]beacon.ValidatorStatus]{
+		Exists: true,
+		Data:   make([]beacon.ValidatorStatus, 0, len(statuses)),
+	}
+	for pubkey, status := range statuses {
+		// The placeholder for the null pubkey has an empty status, so keep each status under the key it was returned with
+		status.Pubkey = pubkey
+		response.Data = append(response.Data, status)
+	}
+	c.save(key, response)
+	return statuses, nil
+}
+
+// Get the cache key for a request on a block ID, if it refers to a finalized slot
+func (c *CachingClient) getSlotKey(kind string, blockId string) (string, bool) {
+	// Only numeric block IDs are stable; "head", "finalized" and the like can change
+	slot, err := strconv.ParseUint(blockId, 10, 64)
+	if err != nil || !c.isFinalized(slot) {
+		return "", false
+	}
+	return c.getKey(kind, blockId), true
+}
+
+// Get the cache key for a request, namespaced by the format version and the network
+func (c *CachingClient) getKey(parts ...string) string {
+	c.finalityLock.Lock()
+	prefix := c.prefix
+	c.finalityLock.Unlock()
+	return fmt.Sprintf("v%d/%s/%s", cachedResponseVersion, prefix, strings.Join(parts, "/"))
+}
+
+// Get the number of slots in an epoch, or 0 if the Beacon Node couldn't provide it
+func (c *CachingClient) getSlotsPerEpoch() uint64 {
+	c.finalityLock.Lock()
+	defer c.finalityLock.Unlock()
+	c.loadNetworkInfo()
+	return c.slotsPerEpoch
+}
+
+// Load the network info from the Beacon Node if it hasn't been loaded yet. Must be called with the finality lock held.
+func (c *CachingClient) loadNetworkInfo() bool {
+	if c.slotsPerEpoch != 0 {
+		return true
+	}
+	eth2Config, err := c.Client.GetEth2Config()
+	if err != nil || eth2Config.SlotsPerEpoch == 0 {
+		return false
+	}
+	c.slotsPerEpoch = eth2Config.SlotsPerEpoch
+	c.prefix = hex.EncodeToString(eth2Config.GenesisValidatorsRoot)
+	return true
+}
+
+// Check if a slot has been finalized. Problems getting the finalized epoch from the Beacon Node are treated as the slot not being
+// finalized, so the request goes straight to the Beacon Node and any errors come from there.
+func (c *CachingClient) isFinalized(slot uint64) bool {
+	c.finalityLock.Lock()
+	defer c.finalityLock.Unlock()
+
+	if !c.loadNetworkInfo() {
+		return false
+	}
+	if !c.lastFinalityCheck.IsZero() && slot <= c.finalizedSlot {
+		return true
+	}
+	if time.Since(c.lastFinalityCheck) < finalityCheckInterval {
+		return false
+	}
+
+	head, err := c.Client.GetBeaconHead()
+	if err != nil {
+		return false
+	}
+	c.lastFinalityCheck = time.Now()
+	c.finalizedSlot = head.FinalizedEpoch * c.slotsPerEpoch
+	return slot <= c.finalizedSlot
+}
+
+// Load a response from the cache into the provided object
+func (c *CachingClient) load(key string, response any) bool {
+	bytes, exists := c.store.get(key)
+	if !exists {
+		return false
+	}
+	err := json.Unmarshal(bytes, response)
+	return err == nil
+}
+
+// Save a response to the cache. Failures are ignored since the response can always be downloaded again.
+func (c *CachingClient) save(key string, response any) {
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	_ = c.store.put(key, bytes)
+}
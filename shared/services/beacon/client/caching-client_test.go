@@ -0,0 +1,308 @@
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+//go:embed testdata/bn-fixtures
+var bnFixtures embed.FS
+
+// A stand-in for a Beacon Node that replays recorded responses, and counts the requests for each path.
+// Fixtures are named after the request path with the slashes replaced by underscores, plus the epoch query parameter if there is one.
+type fixtureBeaconNode struct {
+	server   *httptest.Server
+	requests map[string]int
+	lock     sync.Mutex
+}
+
+func newFixtureBeaconNode(t *testing.T) *fixtureBeaconNode {
+	bn := &fixtureBeaconNode{
+		requests: map[string]int{},
+	}
+	bn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bn.lock.Lock()
+		bn.requests[r.URL.Path]++
+		bn.lock.Unlock()
+
+		name := strings.ReplaceAll(strings.Trim(r.URL.Path, "/"), "/", "_")
+		if epoch := r.URL.Query().Get("epoch"); epoch != "" {
+			name += "_epoch-" + epoch
+		}
+		body, err := bnFixtures.ReadFile("testdata/bn-fixtures/" + name + ".json")
+		if err != nil {
+			http.Error(w, `{"code":404,"message":"NOT_FOUND"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(bn.server.Close)
+	return bn
+}
+
+// Get the number of requests for a path, and reset the count
+func (bn *fixtureBeaconNode) takeRequests(path string) int {
+	bn.lock.Lock()
+	defer bn.lock.Unlock()
+	count := bn.requests[path]
+	delete(bn.requests, path)
+	return count
+}
+
+// The responses for every cacheable request in the fixtures
+type cachedResponses struct {
+	block         beacon.BeaconBlock
+	blockExists   bool
+	missingExists bool
+	attestations  []beacon.AttestationInfo
+	committees    beacon.Committees
+	statuses      map[types.ValidatorPubkey]beacon.ValidatorStatus
+}
+
+var fixturePubkeys = []types.ValidatorPubkey{
+	mustDecodePubkey("b1559beef7b5ba3127485bbbb090362d9f497ba64e177ee2c8e7db74746306efad687f2cf8574e38d70067d40ef136dc"),
+	mustDecodePubkey("afa4c6985aa049fb79dd37010438cfebeb0f2bd42b115b89dd678dab0670c1de38da0c4e9138c9290a398ecd9a0b3110"),
+}
+
+func mustDecodePubkey(value string) types.ValidatorPubkey {
+	pubkey, err := types.HexToValidatorPubkey(value)
+	if err != nil {
+		panic(err)
+	}
+	return pubkey
+}
+
+func getCachedResponses(t *testing.T, client beacon.Client) cachedResponses {
+	var out cachedResponses
+	var err error
+	out.block, out.blockExists, err = client.GetBeaconBlock("100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, out.missingExists, err = client.GetBeaconBlock("101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.attestations, _, err = client.GetAttestations("100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch := uint64(3)
+	out.committees, err = client.GetCommitteesForEpoch(&epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := uint64(96)
+	out.statuses, err = client.GetValidatorStatuses(fixturePubkeys, &beacon.ValidatorStatusOptions{Slot: &slot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func requireSameResponses(t *testing.T, expected cachedResponses, actual cachedResponses) {
+	t.Helper()
+	expectedBlock, _ := json.Marshal(expected.block)
+	actualBlock, _ := json.Marshal(actual.block)
+	if !bytes.Equal(expectedBlock, actualBlock) || expected.blockExists != actual.blockExists {
+		t.Fatalf("block mismatch:\nexpected %s\nactual   %s", expectedBlock, actualBlock)
+	}
+	if expected.missingExists || actual.missingExists {
+		t.Fatal("expected the missing block to be reported as missing")
+	}
+	if !reflect.DeepEqual(expected.attestations, actual.attestations) {
+		t.Fatalf("attestations mismatch: expected %v, got %v", expected.attestations, actual.attestations)
+	}
+	if expected.committees.Count() != actual.committees.Count() {
+		t.Fatalf("expected %d committees, got %d", expected.committees.Count(), actual.committees.Count())
+	}
+	for i := 0; i < expected.committees.Count(); i++ {
+		if expected.committees.Index(i) != actual.committees.Index(i) ||
+			expected.committees.Slot(i) != actual.committees.Slot(i) ||
+			!reflect.DeepEqual(expected.committees.Validators(i), actual.committees.Validators(i)) {
+			t.Fatalf("committee %d mismatch", i)
+		}
+	}
+	if !reflect.DeepEqual(expected.statuses, actual.statuses) {
+		t.Fatalf("validator statuses mismatch: expected %v, got %v", expected.statuses, actual.statuses)
+	}
+}
+
+func TestCachingClient(t *testing.T) {
+	bn := newFixtureBeaconNode(t)
+	folder := t.TempDir()
+
+	// Responses straight from the BN
+	direct := NewStandardHttpClient(bn.server.URL)
+	expected := getCachedResponses(t, direct)
+	if !expected.blockExists || len(expected.statuses) != len(fixturePubkeys)+1 {
+		t.Fatalf("fixtures weren't loaded properly: %+v", expected)
+	}
+
+	// The first pass through the cache should go to the BN
+	cachingClient, err := NewCachingClient(direct, folder, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireSameResponses(t, expected, getCachedResponses(t, cachingClient))
+
+	// A fresh client on the same folder shouldn't need the BN for any of it
+	dataPaths := []string{
+		"/eth/v2/beacon/blocks/100",
+		"/eth/v2/beacon/blocks/101",
+		"/eth/v1/beacon/blocks/100/attestations",
+		"/eth/v1/beacon/states/head/committees",
+		"/eth/v1/beacon/states/96/validators",
+	}
+	for _, path := range dataPaths {
+		bn.takeRequests(path)
+	}
+	cachingClient, err = NewCachingClient(direct, folder, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireSameResponses(t, expected, getCachedResponses(t, cachingClient))
+	for _, path := range dataPaths {
+		if count := bn.takeRequests(path); count != 0 {
+			t.Fatalf("expected %s to be served from the cache, but the BN got %d requests", path, count)
+		}
+	}
+
+	// Anything that isn't finalized (the fixtures finalize epoch 10) must always come from the BN
+	for i := 0; i < 2; i++ {
+		_, _, err = cachingClient.GetBeaconBlock("400")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = cachingClient.GetBeaconBlock("head")
+		if err != nil {
+			t.Fatal(err)
+		}
+		epoch := uint64(20)
+		committees, err := cachingClient.GetCommitteesForEpoch(&epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		committees.Release()
+	}
+	if count := bn.takeRequests("/eth/v2/beacon/blocks/400"); count != 2 {
+		t.Fatalf("expected 2 requests for an unfinalized block, got %d", count)
+	}
+	if count := bn.takeRequests("/eth/v2/beacon/blocks/head"); count != 2 {
+		t.Fatalf("expected 2 requests for the head block, got %d", count)
+	}
+	if count := bn.takeRequests("/eth/v1/beacon/states/head/committees"); count != 2 {
+		t.Fatalf("expected 2 requests for unfinalized committees, got %d", count)
+	}
+}
+
+func TestResponseStoreEviction(t *testing.T) {
+	folder := t.TempDir()
+
+	// Random data doesn't compress, so each response takes about 1000 bytes and only two fit
+	store, err := newResponseStore(folder, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := map[string][]byte{}
+	for _, key := range []string{"a", "b", "c"} {
+		response := make([]byte, 1000)
+		_, _ = rand.Read(response)
+		responses[key] = response
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if err := store.put(key, responses[key]); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Using "a" makes "b" the least recently used one
+	if _, exists := store.get("a"); !exists {
+		t.Fatal("expected a to be cached")
+	}
+	time.Sleep(time.Millisecond)
+	if err := store.put("c", responses["c"]); err != nil {
+		t.Fatal(err)
+	}
+	if store.size > store.maxSize {
+		t.Fatalf("store is %d bytes, over its limit of %d", store.size, store.maxSize)
+	}
+	for key, shouldExist := range map[string]bool{"a": true, "b": false, "c": true} {
+		response, exists := store.get(key)
+		if exists != shouldExist {
+			t.Fatalf("expected %s to be cached: %t, but it was: %t", key, shouldExist, exists)
+		}
+		if exists && !bytes.Equal(response, responses[key]) {
+			t.Fatalf("cached response for %s doesn't match", key)
+		}
+	}
+
+	// Reopening the store should pick up what's on disk, and corrupt files should be thrown out
+	_, path := store.getPath("c")
+	if err := os.WriteFile(path, []byte("not zstd"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err = newResponseStore(folder, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := store.get("a"); !exists {
+		t.Fatal("expected a to survive reopening the store")
+	}
+	if _, exists := store.get("c"); exists {
+		t.Fatal("expected the corrupt response to be discarded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("expected the corrupt response to be deleted")
+	}
+}
+
+func TestResponseStoreReopenOrder(t *testing.T) {
+	folder := t.TempDir()
+	store, err := newResponseStore(folder, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		response := make([]byte, 1000)
+		_, _ = rand.Read(response)
+		if err := store.put(key, response); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Make "b" the oldest on disk, then reopen the store with room for only two responses
+	oldTime := time.Now().Add(-time.Hour)
+	_, path := store.getPath("b")
+	if err := os.Chtimes(path, oldTime, oldTime); err != nil {
+		t.Fatal(err)
+	}
+	store, err = newResponseStore(folder, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.lru.Len() != 2 || len(store.entries) != 2 {
+		t.Fatalf("expected 2 responses after reopening, got %d", store.lru.Len())
+	}
+	for key, shouldExist := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, exists := store.get(key); exists != shouldExist {
+			t.Fatalf("expected %s to be cached: %t, but it was: %t", key, shouldExist, exists)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+// The largest line the event stream parser will accept
+const maxEventLineSize int = 1024 * 1024
+
+// Subscribe to the Beacon Node's event stream for the provided topics.
+// Events are sent on the returned channel, which is closed when the context is cancelled or the stream ends.
+func (c *StandardHttpClient) SubscribeToEvents(ctx context.Context, topics []beacon.EventTopic) (<-chan beacon.Event, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no event topics were provided")
+	}
+	topicNames := make([]string, len(topics))
+	for i, topic := range topics {
+		topicNames[i] = string(topic)
+	}
+
+	// Open the stream
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(RequestUrlFormat, c.providerAddress, fmt.Sprintf(RequestEventsPath, strings.Join(topicNames, ","))), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", RequestEventStreamContentType)
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("Could not subscribe to events: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("Could not subscribe to events: HTTP status %d; response body: '%s'", response.StatusCode, string(responseBody))
+	}
+
+	// Relay the events until the stream ends
+	events := make(chan beacon.Event)
+	go func() {
+		defer close(events)
+		defer func() {
+			_ = response.Body.Close()
+		}()
+		_ = readEventStream(response.Body, func(topic string, data []byte) bool {
+			event, err := parseEvent(topic, data)
+			if err != nil {
+				// Skip events that can't be parsed rather than dropping the subscription
+				return true
+			}
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return events, nil
+}
+
+// Read server-sent events from a stream, calling the handler for each one until it returns false or the stream ends
+func readEventStream(reader io.Reader, handler func(topic string, data []byte) bool) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 4096), maxEventLineSize)
+
+	var topic string
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// A blank line dispatches the event
+		if line == "" {
+			if data.Len() > 0 {
+				if !handler(topic, data.Bytes()) {
+					return nil
+				}
+			}
+			topic = ""
+			data.Reset()
+			continue
+		}
+
+		// Lines starting with a colon are comments, which are used as keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			topic = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	return scanner.Err()
+}
+
+// Convert an event from the stream into its Beacon client type
+func parseEvent(topic string, data []byte) (beacon.Event, error) {
+	event := beacon.Event{
+		Topic: beacon.EventTopic(topic),
+	}
+	switch event.Topic {
+	case beacon.EventTopic_Head:
+		var head HeadEventData
+		if err := json.Unmarshal(data, &head); err != nil {
+			return beacon.Event{}, fmt.Errorf("error decoding head event: %w", err)
+		}
+		event.Head = &beacon.HeadEvent{
+			Slot:            uint64(head.Slot),
+			Block:           common.BytesToHash(head.Block),
+			State:           common.BytesToHash(head.State),
+			EpochTransition: head.EpochTransition,
+		}
+
+	case beacon.EventTopic_FinalizedCheckpoint:
+		var checkpoint FinalizedCheckpointEventData
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return beacon.Event{}, fmt.Errorf("error decoding finalized checkpoint event: %w", err)
+		}
+		event.FinalizedCheckpoint = &beacon.FinalizedCheckpointEvent{
+			Epoch: uint64(checkpoint.Epoch),
+			Block: common.BytesToHash(checkpoint.Block),
+			State: common.BytesToHash(checkpoint.State),
+		}
+
+	case beacon.EventTopic_ChainReorg:
+		var reorg ChainReorgEventData
+		if err := json.Unmarshal(data, &reorg); err != nil {
+			return beacon.Event{}, fmt.Errorf("error decoding chain reorg event: %w", err)
+		}
+		event.ChainReorg = &beacon.ChainReorgEvent{
+			Slot:         uint64(reorg.Slot),
+			Depth:        uint64(reorg.Depth),
+			OldHeadBlock: common.BytesToHash(reorg.OldHeadBlock),
+			NewHeadBlock: common.BytesToHash(reorg.NewHeadBlock),
+			Epoch:        uint64(reorg.Epoch),
+		}
+
+	case beacon.EventTopic_VoluntaryExit:
+		var exit VoluntaryExitEventData
+		if err := json.Unmarshal(data, &exit); err != nil {
+			return beacon.Event{}, fmt.Errorf("error decoding voluntary exit event: %w", err)
+		}
+		event.VoluntaryExit = &beacon.VoluntaryExitEvent{
+			ValidatorIndex: exit.Message.ValidatorIndex,
+			Epoch:          uint64(exit.Message.Epoch),
+		}
+
+	default:
+		return beacon.Event{}, fmt.Errorf("unsupported event topic: %s", topic)
+	}
+	return event, nil
+}
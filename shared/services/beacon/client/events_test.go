@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+// The events the test Beacon Node streams, as a Beacon Node would send them
+const testEventStream = `: keep-alive
+
+event: head
+data: {"slot":"10","block":"0x1111111111111111111111111111111111111111111111111111111111111111","state":"0x2222222222222222222222222222222222222222222222222222222222222222","epoch_transition":false,"execution_optimistic":false}
+
+event: head
+data: {"slot":"32","block":"0x3333333333333333333333333333333333333333333333333333333333333333","state":"0x4444444444444444444444444444444444444444444444444444444444444444","epoch_transition":true,"execution_optimistic":false}
+
+event: block
+data: {"slot":"32","block":"0x3333333333333333333333333333333333333333333333333333333333333333"}
+
+event: finalized_checkpoint
+data: {"block":"0x5555555555555555555555555555555555555555555555555555555555555555","state":"0x6666666666666666666666666666666666666666666666666666666666666666","epoch":"2","execution_optimistic":false}
+
+event: chain_reorg
+data: {"slot":"33","depth":"2","old_head_block":"0x7777777777777777777777777777777777777777777777777777777777777777","new_head_block":"0x8888888888888888888888888888888888888888888888888888888888888888","old_head_state":"0x9999999999999999999999999999999999999999999999999999999999999999","new_head_state":"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","epoch":"1","execution_optimistic":false}
+
+event: voluntary_exit
+data: {"message":{"epoch":"1","validator_index":"123"},
+data: "signature":"0x01"}
+
+`
+
+// Start a Beacon Node that streams the test events, then holds the stream open until the client disconnects
+func newEventBeaconNode(t *testing.T, topics chan<- string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eth/v1/events" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept") != RequestEventStreamContentType {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		topics <- r.URL.Query().Get("topics")
+
+		w.Header().Set("Content-Type", RequestEventStreamContentType)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, testEventStream)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func receiveEvent(t *testing.T, events <-chan beacon.Event) beacon.Event {
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("event stream closed early")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return beacon.Event{}
+}
+
+func TestSubscribeToEvents(t *testing.T) {
+	topics := make(chan string, 1)
+	server := newEventBeaconNode(t, topics)
+	client := NewStandardHttpClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.SubscribeToEvents(ctx, []beacon.EventTopic{
+		beacon.EventTopic_Head,
+		beacon.EventTopic_FinalizedCheckpoint,
+		beacon.EventTopic_ChainReorg,
+		beacon.EventTopic_VoluntaryExit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested := <-topics; requested != "head,finalized_checkpoint,chain_reorg,voluntary_exit" {
+		t.Fatalf("unexpected topics requested: %s", requested)
+	}
+
+	event := receiveEvent(t, events)
+	if event.Topic != beacon.EventTopic_Head || event.Head.Slot != 10 || event.Head.EpochTransition {
+		t.Fatalf("unexpected first head event: %+v", event.Head)
+	}
+	event = receiveEvent(t, events)
+	if event.Topic != beacon.EventTopic_Head || event.Head.Slot != 32 || !event.Head.EpochTransition {
+		t.Fatalf("unexpected second head event: %+v", event.Head)
+	}
+	if event.Head.Block != common.HexToHash(strings.Repeat("33", 32)) {
+		t.Fatalf("unexpected head block: %s", event.Head.Block.Hex())
+	}
+
+	// The block event isn't supported, so it's skipped
+	event = receiveEvent(t, events)
+	if event.Topic != beacon.EventTopic_FinalizedCheckpoint || event.FinalizedCheckpoint.Epoch != 2 {
+		t.Fatalf("unexpected finalized checkpoint event: %+v", event)
+	}
+
+	event = receiveEvent(t, events)
+	if event.Topic != beacon.EventTopic_ChainReorg {
+		t.Fatalf("expected a chain reorg event, got %s", event.Topic)
+	}
+	reorg := event.ChainReorg
+	if reorg.Slot != 33 || reorg.Depth != 2 || reorg.Epoch != 1 || reorg.NewHeadBlock != common.HexToHash(strings.Repeat("88", 32)) {
+		t.Fatalf("unexpected chain reorg event: %+v", reorg)
+	}
+
+	// The exit's data is split over two lines
+	event = receiveEvent(t, events)
+	if event.Topic != beacon.EventTopic_VoluntaryExit || event.VoluntaryExit.ValidatorIndex != "123" || event.VoluntaryExit.Epoch != 1 {
+		t.Fatalf("unexpected voluntary exit event: %+v", event)
+	}
+
+	// Cancelling the context closes the stream
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("expected the event stream to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event stream to close")
+	}
+}
+
+func TestSubscribeToEventsErrors(t *testing.T) {
+	server := newEventBeaconNode(t, make(chan string, 1))
+	client := NewStandardHttpClient(server.URL)
+
+	if _, err := client.SubscribeToEvents(context.Background(), nil); err == nil {
+		t.Fatal("expected an error subscribing without topics")
+	}
+
+	// A Beacon Node without the events endpoint can't be subscribed to
+	client = NewStandardHttpClient(server.URL + "/missing")
+	if _, err := client.SubscribeToEvents(context.Background(), []beacon.EventTopic{beacon.EventTopic_Head}); err == nil {
+		t.Fatal("expected an error subscribing to a Beacon Node without events")
+	}
+}
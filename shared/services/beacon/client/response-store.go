@@ -0,0 +1,241 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Cached responses are saved with this extension
+const responseStoreExtension string = ".zst"
+
+// A single response in the store
+type responseStoreEntry struct {
+	hash string
+	path string
+	size uint64
+}
+
+// A content-addressed store of Beacon Node responses on disk. Each response is compressed and saved in a file named after
+// the SHA-256 hash of its request key. When the store grows beyond its size limit, the least recently used responses are evicted.
+type responseStore struct {
+	folder       string
+	maxSize      uint64
+	size         uint64
+	entries      map[string]*list.Element
+	lru          *list.List // Most recently used at the front
+	compressor   *zstd.Encoder
+	decompressor *zstd.Decoder
+	lock         sync.Mutex
+}
+
+// Open the store in the provided folder, creating it if it doesn't exist yet
+func newResponseStore(folder string, maxSize uint64) (*responseStore, error) {
+	// Create the zstd compressor and decompressor
+	compressor, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating zstd compressor: %w", err)
+	}
+	decompressor, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating zstd decompressor: %w", err)
+	}
+
+	err = os.MkdirAll(folder, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Beacon cache folder %s: %w", folder, err)
+	}
+
+	s := &responseStore{
+		folder:       folder,
+		maxSize:      maxSize,
+		entries:      map[string]*list.Element{},
+		lru:          list.New(),
+		compressor:   compressor,
+		decompressor: decompressor,
+	}
+
+	// Index the responses that are already on disk, using their modification times as the last time they were used
+	type indexedEntry struct {
+		entry    *responseStoreEntry
+		lastUsed time.Time
+	}
+	indexedEntries := []indexedEntry{}
+	err = filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if filepath.Ext(path) == ".tmp" {
+			// Left over from a write that was interrupted
+			return os.Remove(path)
+		}
+		if filepath.Ext(path) != responseStoreExtension {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		indexedEntries = append(indexedEntries, indexedEntry{
+			entry: &responseStoreEntry{
+				hash: filepath.Base(path[:len(path)-len(responseStoreExtension)]),
+				path: path,
+				size: uint64(info.Size()),
+			},
+			lastUsed: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading Beacon cache folder %s: %w", folder, err)
+	}
+	sort.Slice(indexedEntries, func(i, j int) bool {
+		return indexedEntries[i].lastUsed.After(indexedEntries[j].lastUsed)
+	})
+	for _, indexed := range indexedEntries {
+		s.entries[indexed.entry.hash] = s.lru.PushBack(indexed.entry)
+		s.size += indexed.entry.size
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err = s.evict()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get the hash and path of the file for a request key
+func (s *responseStore) getPath(key string) (string, string) {
+	hashBytes := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(hashBytes[:])
+	return hash, filepath.Join(s.folder, hash[:2], hash+responseStoreExtension)
+}
+
+// Get the response for a request key, if it's in the store
+func (s *responseStore) get(key string) ([]byte, bool) {
+	hash, path := s.getPath(key)
+
+	s.lock.Lock()
+	element, exists := s.entries[hash]
+	if exists {
+		s.lru.MoveToFront(element)
+	}
+	s.lock.Unlock()
+	if !exists {
+		return nil, false
+	}
+
+	compressedBytes, err := os.ReadFile(path)
+	if err != nil {
+		s.remove(hash)
+		return nil, false
+	}
+	bytes, err := s.decompressor.DecodeAll(compressedBytes, nil)
+	if err != nil {
+		// The file is corrupt, so throw it out and fetch the response again
+		s.remove(hash)
+		return nil, false
+	}
+
+	// Keep the modification time current so the eviction order survives restarts; this is best-effort
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return bytes, true
+}
+
+// Save the response for a request key in the store, evicting old responses if it's now too large
+func (s *responseStore) put(key string, bytes []byte) error {
+	hash, path := s.getPath(key)
+	compressedBytes := s.compressor.EncodeAll(bytes, make([]byte, 0, len(bytes)/4))
+	size := uint64(len(compressedBytes))
+	if size > s.maxSize {
+		// Don't bother caching something that would evict everything else
+		return nil
+	}
+
+	// Write to a temporary file first so readers never see a partial response
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating Beacon cache folder %s: %w", filepath.Dir(path), err)
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), hash+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating Beacon cache file: %w", err)
+	}
+	_, err = tempFile.Write(compressedBytes)
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return fmt.Errorf("error saving Beacon cache file %s: %w", path, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if element, exists := s.entries[hash]; exists {
+		entry := element.Value.(*responseStoreEntry)
+		s.size -= entry.size
+		entry.size = size
+		s.lru.MoveToFront(element)
+	} else {
+		s.entries[hash] = s.lru.PushFront(&responseStoreEntry{
+			hash: hash,
+			path: path,
+			size: size,
+		})
+	}
+	s.size += size
+	return s.evict()
+}
+
+// Remove a response from the store
+func (s *responseStore) remove(hash string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	element, exists := s.entries[hash]
+	if !exists {
+		return
+	}
+	entry := s.lru.Remove(element).(*responseStoreEntry)
+	_ = os.Remove(entry.path)
+	delete(s.entries, hash)
+	s.size -= entry.size
+}
+
+// Delete the least recently used responses until the store fits within its size limit. Must be called with the lock held.
+func (s *responseStore) evict() error {
+	for s.size > s.maxSize {
+		element := s.lru.Back()
+		if element == nil {
+			break
+		}
+		entry := element.Value.(*responseStoreEntry)
+		err := os.Remove(entry.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error evicting Beacon cache file %s: %w", entry.path, err)
+		}
+		s.lru.Remove(element)
+		delete(s.entries, entry.hash)
+		s.size -= entry.size
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/rocket-pool/smartnode/bindings/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/eth2"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/deneb"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/electra"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
+)
+
+// The epoch used by the spec for events that haven't been scheduled yet
+const farFutureEpoch uint64 = math.MaxUint64
+
+// Returned when an object can't be retrieved as SSZ, so it has to be requested as JSON instead
+var errSSZUnsupported = errors.New("SSZ response not available")
+
+// Get an SSZ-encoded object from the Beacon Node, along with the fork it belongs to.
+// Beacon Nodes that don't respond with SSZ are remembered so they aren't asked again.
+func (c *StandardHttpClient) getSSZ(requestPath string) ([]byte, string, bool, error) {
+	if c.sszUnsupported.Load() {
+		return nil, "", false, errSSZUnsupported
+	}
+
+	response, err := c.sszRequest(requestPath)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, "", false, nil
+	case http.StatusNotAcceptable, http.StatusUnsupportedMediaType:
+		c.sszUnsupported.Store(true)
+		return nil, "", false, errSSZUnsupported
+	default:
+		return nil, "", false, fmt.Errorf("HTTP status %d", response.StatusCode)
+	}
+
+	// Some Beacon Nodes ignore the Accept header and respond with JSON anyway
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != RequestSSZContentType {
+		c.sszUnsupported.Store(true)
+		return nil, "", false, errSSZUnsupported
+	}
+
+	// Objects from forks without SSZ types can still be retrieved as JSON
+	fork := strings.ToLower(response.Header.Get(ResponseConsensusVersionHeader))
+	if !eth2.IsSupportedFork(fork) {
+		return nil, "", false, errSSZUnsupported
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return body, fork, true, nil
+}
+
+// Check if an object at a block or state ID could be provided as SSZ.
+// Slots from before the first fork with SSZ types are requested as JSON straight away instead of taking a second request.
+func (c *StandardHttpClient) isSSZAvailable(id string) bool {
+	if c.sszUnsupported.Load() {
+		return false
+	}
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return true
+	}
+
+	c.sszForkSlotLock.Lock()
+	defer c.sszForkSlotLock.Unlock()
+	if !c.sszForkSlotLoaded {
+		// If the config can't be loaded, try SSZ anyway and fall back to JSON if needed
+		eth2Config, err := c.getEth2Config()
+		if err == nil {
+			forkEpoch := uint64(eth2Config.Data.DenebForkEpoch)
+			slotsPerEpoch := uint64(eth2Config.Data.SlotsPerEpoch)
+			if slotsPerEpoch != 0 && forkEpoch > math.MaxUint64/slotsPerEpoch {
+				c.sszForkSlot = math.MaxUint64
+			} else {
+				c.sszForkSlot = forkEpoch * slotsPerEpoch
+			}
+		}
+		c.sszForkSlotLoaded = true
+	}
+	return slot >= c.sszForkSlot
+}
+
+// Get a beacon block, requesting it as SSZ
+func (c *StandardHttpClient) getBeaconBlockSSZ(blockId string) (beacon.BeaconBlock, bool, error) {
+	if !c.isSSZAvailable(blockId) {
+		return beacon.BeaconBlock{}, false, errSSZUnsupported
+	}
+
+	data, fork, exists, err := c.getSSZ(fmt.Sprintf(RequestBeaconBlockPath, blockId))
+	if err != nil {
+		return beacon.BeaconBlock{}, false, err
+	}
+	if !exists {
+		return beacon.BeaconBlock{}, false, nil
+	}
+
+	signedBlock, err := eth2.NewSignedBeaconBlock(data, fork)
+	if err != nil {
+		return beacon.BeaconBlock{}, false, fmt.Errorf("Could not decode beacon block data: %w", err)
+	}
+
+	beaconBlock := beacon.BeaconBlock{}
+	var payload *generic.ExecutionPayload
+	switch block := signedBlock.(type) {
+	case *deneb.SignedBeaconBlock:
+		beaconBlock.Slot = block.Block.Slot
+		beaconBlock.ProposerIndex = strconv.FormatUint(block.Block.ProposerIndex, 10)
+		if block.Block.Body.ExecutionPayload != nil {
+			payload = &generic.ExecutionPayload{
+				FeeRecipient: block.Block.Body.ExecutionPayload.FeeRecipient,
+				BlockNumber:  block.Block.Body.ExecutionPayload.BlockNumber,
+				Withdrawals:  block.Block.Body.ExecutionPayload.Withdrawals,
+			}
+		}
+
+		// Attestations before Electra are for a single committee
+		beaconBlock.Attestations = make([]beacon.AttestationInfo, len(block.Block.Body.Attestations))
+		for i, attestation := range block.Block.Body.Attestations {
+			beaconBlock.Attestations[i] = beacon.AttestationInfo{
+				AggregationBits: attestation.AggregationBits,
+				SlotIndex:       attestation.Data.Slot,
+				Committees:      bitfield.NewBitvector64(),
+			}
+			beaconBlock.Attestations[i].Committees.SetBitAt(attestation.Data.Index, true)
+		}
+
+	case *electra.SignedBeaconBlock:
+		beaconBlock.Slot = block.Block.Slot
+		beaconBlock.ProposerIndex = strconv.FormatUint(block.Block.ProposerIndex, 10)
+		payload = block.Block.Body.ExecutionPayload
+
+		beaconBlock.Attestations = make([]beacon.AttestationInfo, len(block.Block.Body.Attestations))
+		for i, attestation := range block.Block.Body.Attestations {
+			beaconBlock.Attestations[i] = beacon.AttestationInfo{
+				AggregationBits: attestation.AggregationBits,
+				SlotIndex:       attestation.Data.Slot,
+				Committees:      bitfield.Bitvector64(attestation.CommitteeBits),
+			}
+		}
+
+	default:
+		return beacon.BeaconBlock{}, false, errSSZUnsupported
+	}
+
+	// Execution payload only exists after the merge, so check for its existence
+	beaconBlock.Withdrawals = []beacon.WithdrawalInfo{}
+	if payload != nil {
+		beaconBlock.HasExecutionPayload = true
+		beaconBlock.FeeRecipient = common.Address(payload.FeeRecipient)
+		beaconBlock.ExecutionBlockNumber = payload.BlockNumber
+
+		beaconBlock.Withdrawals = make([]beacon.WithdrawalInfo, len(payload.Withdrawals))
+		for i, withdrawal := range payload.Withdrawals {
+			// amount is in Gwei, but we want wei
+			amount := new(big.Int).SetUint64(withdrawal.Amount)
+			amount.Mul(amount, big.NewInt(1e9))
+			beaconBlock.Withdrawals[i] = beacon.WithdrawalInfo{
+				ValidatorIndex: strconv.FormatUint(withdrawal.ValidatorIndex, 10),
+				Address:        common.Address(withdrawal.Address),
+				Amount:         amount,
+			}
+		}
+	}
+
+	return beaconBlock, true, nil
+}
+
+// Get the statuses of all of the validators on a state, requesting the state as SSZ.
+// This is only worthwhile when a lot of validators are needed, since the state is much larger than a filtered validator list.
+func (c *StandardHttpClient) getValidatorStatusesSSZ(stateId string) ([]beacon.ValidatorStatus, error) {
+	if !c.isSSZAvailable(stateId) {
+		return nil, errSSZUnsupported
+	}
+	eth2Config, err := c.getEth2Config()
+	if err != nil {
+		return nil, err
+	}
+
+	data, fork, exists, err := c.getSSZ(fmt.Sprintf(RequestBeaconStatePath, stateId))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("state %s not found", stateId)
+	}
+
+	state, err := eth2.NewBeaconState(data, fork)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decode beacon state data: %w", err)
+	}
+	return getValidatorStatusesFromState(state, uint64(eth2Config.Data.SlotsPerEpoch))
+}
+
+// Get the statuses of all of the validators in a Beacon state
+func getValidatorStatusesFromState(state eth2.BeaconState, slotsPerEpoch uint64) ([]beacon.ValidatorStatus, error) {
+	validators := state.GetValidators()
+	balances := state.GetBalances()
+	if len(validators) != len(balances) {
+		return nil, fmt.Errorf("state has %d validators but %d balances", len(validators), len(balances))
+	}
+	if slotsPerEpoch == 0 {
+		return nil, fmt.Errorf("slots per epoch can't be 0")
+	}
+	epoch := state.GetSlot() / slotsPerEpoch
+
+	statuses := make([]beacon.ValidatorStatus, len(validators))
+	for i, validator := range validators {
+		statuses[i] = beacon.ValidatorStatus{
+			Pubkey:                     types.BytesToValidatorPubkey(validator.Pubkey),
+			Index:                      strconv.Itoa(i),
+			WithdrawalCredentials:      common.BytesToHash(validator.WithdrawalCredentials),
+			Balance:                    balances[i],
+			EffectiveBalance:           validator.EffectiveBalance,
+			Status:                     getValidatorState(validator, balances[i], epoch),
+			Slashed:                    validator.Slashed,
+			ActivationEligibilityEpoch: validator.ActivationEligibilityEpoch,
+			ActivationEpoch:            validator.ActivationEpoch,
+			ExitEpoch:                  validator.ExitEpoch,
+			WithdrawableEpoch:          validator.WithdrawableEpoch,
+			Exists:                     true,
+		}
+	}
+	return statuses, nil
+}
+
+// Get a validator's status at an epoch, following the rules the Beacon API uses for the validators route
+func getValidatorState(validator *generic.Validator, balance uint64, epoch uint64) beacon.ValidatorState {
+	if validator.ActivationEpoch > epoch {
+		if validator.ActivationEligibilityEpoch == farFutureEpoch {
+			return beacon.ValidatorState_PendingInitialized
+		}
+		return beacon.ValidatorState_PendingQueued
+	}
+	if epoch < validator.ExitEpoch {
+		if validator.ExitEpoch == farFutureEpoch {
+			return beacon.ValidatorState_ActiveOngoing
+		}
+		if validator.Slashed {
+			return beacon.ValidatorState_ActiveSlashed
+		}
+		return beacon.ValidatorState_ActiveExiting
+	}
+	if epoch < validator.WithdrawableEpoch {
+		if validator.Slashed {
+			return beacon.ValidatorState_ExitedSlashed
+		}
+		return beacon.ValidatorState_ExitedUnslashed
+	}
+	if balance != 0 {
+		return beacon.ValidatorState_WithdrawalPossible
+	}
+	return beacon.ValidatorState_WithdrawalDone
+}
@@ -0,0 +1,819 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/types/eth2"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/deneb"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/electra"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
+	hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+// How the test Beacon Node responds to requests for SSZ
+type sszSupport int
+
+const (
+	sszSupported sszSupport = iota
+	sszIgnored
+	sszNotAcceptable
+)
+
+// A block the test Beacon Node can serve, in both encodings
+type blockFixture struct {
+	slot             string
+	fork             string
+	ssz              []byte
+	json             []byte
+	attestationsJson []byte
+}
+
+// The Deneb fork slot the test Beacon Node reports, along with a slot before it
+const (
+	testDenebForkEpoch uint64 = 269568
+	testSlotsPerEpoch  uint64 = 32
+	testPreDenebSlot   string = "8000000"
+)
+
+var blockFixtures = []struct {
+	slot string
+	fork string
+}{
+	{slot: "11544444", fork: "deneb"},
+	{slot: "11900001", fork: "electra"},
+}
+
+// A stand-in for a Beacon Node that serves blocks as SSZ or JSON depending on the Accept header
+type blockBeaconNode struct {
+	server   *httptest.Server
+	blocks   map[string]blockFixture
+	support  sszSupport
+	requests int
+	lock     sync.Mutex
+}
+
+func loadBlockFixture(tb testing.TB, slot string, fork string) blockFixture {
+	data, err := os.ReadFile(fmt.Sprintf("../../../types/eth2/testdata/block_%s.ssz", slot))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	block, err := eth2.NewSignedBeaconBlock(data, fork)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	blockJson := getBlockJSON(tb, block)
+	jsonData, err := json.Marshal(blockJson)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	attestations := blockJson["data"].(map[string]any)["message"].(map[string]any)["body"].(map[string]any)["attestations"]
+	attestationsJson, err := json.Marshal(map[string]any{"data": attestations})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return blockFixture{
+		slot:             slot,
+		fork:             fork,
+		ssz:              data,
+		json:             jsonData,
+		attestationsJson: attestationsJson,
+	}
+}
+
+// Build the Beacon API's JSON response for a block, including the transactions since they make up most of the response
+func getBlockJSON(tb testing.TB, signedBlock eth2.SignedBeaconBlock) map[string]any {
+	var slot, proposerIndex uint64
+	var feeRecipient [20]byte
+	var blockNumber uint64
+	var transactions [][]byte
+	var withdrawals []*generic.Withdrawal
+	attestations := []map[string]any{}
+	switch block := signedBlock.(type) {
+	case *deneb.SignedBeaconBlock:
+		slot = block.Block.Slot
+		proposerIndex = block.Block.ProposerIndex
+		feeRecipient = block.Block.Body.ExecutionPayload.FeeRecipient
+		blockNumber = block.Block.Body.ExecutionPayload.BlockNumber
+		transactions = block.Block.Body.ExecutionPayload.Transactions
+		withdrawals = block.Block.Body.ExecutionPayload.Withdrawals
+		for _, attestation := range block.Block.Body.Attestations {
+			attestations = append(attestations, map[string]any{
+				"aggregation_bits": "0x" + hex.EncodeToString(attestation.AggregationBits),
+				"data": map[string]any{
+					"slot":              strconv.FormatUint(attestation.Data.Slot, 10),
+					"index":             strconv.FormatUint(attestation.Data.Index, 10),
+					"beacon_block_root": "0x" + hex.EncodeToString(attestation.Data.BeaconBlockHash[:]),
+				},
+				"signature": "0x" + hex.EncodeToString(attestation.Signature[:]),
+			})
+		}
+	case *electra.SignedBeaconBlock:
+		slot = block.Block.Slot
+		proposerIndex = block.Block.ProposerIndex
+		feeRecipient = block.Block.Body.ExecutionPayload.FeeRecipient
+		blockNumber = block.Block.Body.ExecutionPayload.BlockNumber
+		transactions = block.Block.Body.ExecutionPayload.Transactions
+		withdrawals = block.Block.Body.ExecutionPayload.Withdrawals
+		for _, attestation := range block.Block.Body.Attestations {
+			attestations = append(attestations, map[string]any{
+				"aggregation_bits": "0x" + hex.EncodeToString(attestation.AggregationBits),
+				"data": map[string]any{
+					"slot":              strconv.FormatUint(attestation.Data.Slot, 10),
+					"index":             strconv.FormatUint(attestation.Data.Index, 10),
+					"beacon_block_root": "0x" + hex.EncodeToString(attestation.Data.BeaconBlockHash[:]),
+				},
+				"signature":      "0x" + hex.EncodeToString(attestation.Signature[:]),
+				"committee_bits": "0x" + hex.EncodeToString(attestation.CommitteeBits),
+			})
+		}
+	default:
+		tb.Fatalf("unexpected block type %T", signedBlock)
+	}
+
+	transactionStrings := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		transactionStrings[i] = "0x" + hex.EncodeToString(transaction)
+	}
+	withdrawalObjects := make([]map[string]any, len(withdrawals))
+	for i, withdrawal := range withdrawals {
+		withdrawalObjects[i] = map[string]any{
+			"index":           strconv.FormatUint(withdrawal.Index, 10),
+			"validator_index": strconv.FormatUint(withdrawal.ValidatorIndex, 10),
+			"address":         "0x" + hex.EncodeToString(withdrawal.Address[:]),
+			"amount":          strconv.FormatUint(withdrawal.Amount, 10),
+		}
+	}
+
+	return map[string]any{
+		"data": map[string]any{
+			"message": map[string]any{
+				"slot":           strconv.FormatUint(slot, 10),
+				"proposer_index": strconv.FormatUint(proposerIndex, 10),
+				"body": map[string]any{
+					"attestations": attestations,
+					"execution_payload": map[string]any{
+						"fee_recipient": "0x" + hex.EncodeToString(feeRecipient[:]),
+						"block_number":  strconv.FormatUint(blockNumber, 10),
+						"transactions":  transactionStrings,
+						"withdrawals":   withdrawalObjects,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newBlockBeaconNode(tb testing.TB, support sszSupport) *blockBeaconNode {
+	bn := &blockBeaconNode{
+		blocks:  map[string]blockFixture{},
+		support: support,
+	}
+	for _, fixture := range blockFixtures {
+		bn.blocks[fixture.slot] = loadBlockFixture(tb, fixture.slot, fixture.fork)
+	}
+
+	// Serve a copy of the Deneb block as a block from before Deneb, which is only decoded as JSON
+	preDenebBlock := bn.blocks[blockFixtures[0].slot]
+	preDenebBlock.slot = testPreDenebSlot
+	preDenebBlock.fork = "capella"
+	bn.blocks[testPreDenebSlot] = preDenebBlock
+
+	bn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == RequestEth2ConfigPath {
+			_, _ = fmt.Fprintf(w, `{"data":{"SLOTS_PER_EPOCH":"%d","DENEB_FORK_EPOCH":"%d"}}`, testSlotsPerEpoch, testDenebForkEpoch)
+			return
+		}
+
+		bn.lock.Lock()
+		bn.requests++
+		bn.lock.Unlock()
+
+		// Attestations are only served as JSON
+		if blockId, isAttestations := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/eth/v1/beacon/blocks/"), "/attestations"); isAttestations {
+			block, exists := bn.blocks[blockId]
+			if !exists {
+				http.Error(w, `{"code":404,"message":"NOT_FOUND"}`, http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", RequestJsonContentType)
+			_, _ = w.Write(block.attestationsJson)
+			return
+		}
+
+		block, exists := bn.blocks[strings.TrimPrefix(r.URL.Path, "/eth/v2/beacon/blocks/")]
+		if !exists {
+			http.Error(w, `{"code":404,"message":"NOT_FOUND"}`, http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Accept") == RequestSSZContentType {
+			switch bn.support {
+			case sszSupported:
+				w.Header().Set("Content-Type", RequestSSZContentType)
+				w.Header().Set(ResponseConsensusVersionHeader, block.fork)
+				_, _ = w.Write(block.ssz)
+				return
+			case sszNotAcceptable:
+				http.Error(w, `{"code":406,"message":"NOT_ACCEPTABLE"}`, http.StatusNotAcceptable)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", RequestJsonContentType)
+		w.Header().Set(ResponseConsensusVersionHeader, block.fork)
+		_, _ = w.Write(block.json)
+	}))
+	tb.Cleanup(bn.server.Close)
+	return bn
+}
+
+// Get the number of requests the Beacon Node has served, and reset the count
+func (bn *blockBeaconNode) takeRequests() int {
+	bn.lock.Lock()
+	defer bn.lock.Unlock()
+	count := bn.requests
+	bn.requests = 0
+	return count
+}
+
+func requireSameBlock(t *testing.T, expected beacon.BeaconBlock, actual beacon.BeaconBlock) {
+	t.Helper()
+	expectedBytes, _ := json.Marshal(expected)
+	actualBytes, _ := json.Marshal(actual)
+	if string(expectedBytes) != string(actualBytes) {
+		t.Fatalf("block mismatch:\nexpected %s\nactual   %s", expectedBytes, actualBytes)
+	}
+}
+
+func TestBeaconBlockSSZMatchesJSON(t *testing.T) {
+	bn := newBlockBeaconNode(t, sszSupported)
+	for _, fixture := range blockFixtures {
+		t.Run(fixture.fork, func(t *testing.T) {
+			client := NewStandardHttpClient(bn.server.URL)
+			block, exists, err := client.GetBeaconBlock(fixture.slot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !exists {
+				t.Fatal("expected the block to exist")
+			}
+			if client.sszUnsupported.Load() {
+				t.Fatal("expected the block to be retrieved as SSZ")
+			}
+			if strconv.FormatUint(block.Slot, 10) != fixture.slot || !block.HasExecutionPayload || len(block.Attestations) == 0 {
+				t.Fatalf("block wasn't decoded properly: %+v", block)
+			}
+
+			// Get the same block through the JSON route
+			jsonClient := NewStandardHttpClient(bn.server.URL)
+			jsonClient.sszUnsupported.Store(true)
+			jsonBlock, exists, err := jsonClient.GetBeaconBlock(fixture.slot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !exists {
+				t.Fatal("expected the JSON block to exist")
+			}
+			requireSameBlock(t, jsonBlock, block)
+		})
+	}
+
+	// Missing blocks shouldn't fall back to JSON
+	bn.takeRequests()
+	client := NewStandardHttpClient(bn.server.URL)
+	_, exists, err := client.GetBeaconBlock("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("expected the block to be missing")
+	}
+	if count := bn.takeRequests(); count != 1 {
+		t.Fatalf("expected 1 request for a missing block, got %d", count)
+	}
+}
+
+func TestBeaconBlockSSZFallback(t *testing.T) {
+	expected := map[string]beacon.BeaconBlock{}
+	bn := newBlockBeaconNode(t, sszSupported)
+	client := NewStandardHttpClient(bn.server.URL)
+	for _, fixture := range blockFixtures {
+		block, _, err := client.GetBeaconBlock(fixture.slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected[fixture.slot] = block
+	}
+
+	for name, support := range map[string]sszSupport{"ignored": sszIgnored, "not-acceptable": sszNotAcceptable} {
+		t.Run(name, func(t *testing.T) {
+			bn := newBlockBeaconNode(t, support)
+			client := NewStandardHttpClient(bn.server.URL)
+			for i, fixture := range blockFixtures {
+				block, exists, err := client.GetBeaconBlock(fixture.slot)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !exists {
+					t.Fatal("expected the block to exist")
+				}
+				requireSameBlock(t, expected[fixture.slot], block)
+
+				// Only the first request should try SSZ
+				expectedRequests := 1
+				if i == 0 {
+					expectedRequests = 2
+				}
+				if count := bn.takeRequests(); count != expectedRequests {
+					t.Fatalf("expected %d requests for block %s, got %d", expectedRequests, fixture.slot, count)
+				}
+			}
+			if !client.sszUnsupported.Load() {
+				t.Fatal("expected the client to stop requesting SSZ")
+			}
+		})
+	}
+}
+
+func TestBeaconBlockSSZSkipsPreDeneb(t *testing.T) {
+	bn := newBlockBeaconNode(t, sszSupported)
+	client := NewStandardHttpClient(bn.server.URL)
+
+	// Blocks before Deneb should be requested as JSON straight away
+	block, exists, err := client.GetBeaconBlock(testPreDenebSlot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || !block.HasExecutionPayload {
+		t.Fatalf("block wasn't decoded properly: %+v", block)
+	}
+	if count := bn.takeRequests(); count != 1 {
+		t.Fatalf("expected 1 request for a block before Deneb, got %d", count)
+	}
+	if client.sszUnsupported.Load() {
+		t.Fatal("a block before Deneb shouldn't stop the client from requesting SSZ")
+	}
+
+	// Later blocks should still be requested as SSZ
+	for _, fixture := range blockFixtures {
+		if _, _, err := client.GetBeaconBlock(fixture.slot); err != nil {
+			t.Fatal(err)
+		}
+		if count := bn.takeRequests(); count != 1 {
+			t.Fatalf("expected 1 request for block %s, got %d", fixture.slot, count)
+		}
+	}
+	if client.sszUnsupported.Load() {
+		t.Fatal("expected the blocks to be retrieved as SSZ")
+	}
+}
+
+func TestGetValidatorState(t *testing.T) {
+	const epoch uint64 = 100
+	tests := []struct {
+		name      string
+		validator generic.Validator
+		balance   uint64
+		expected  beacon.ValidatorState
+	}{
+		{
+			name:      "pending initialized",
+			validator: generic.Validator{ActivationEligibilityEpoch: farFutureEpoch, ActivationEpoch: farFutureEpoch, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_PendingInitialized,
+		},
+		{
+			name:      "pending queued",
+			validator: generic.Validator{ActivationEligibilityEpoch: 90, ActivationEpoch: 101, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_PendingQueued,
+		},
+		{
+			name:      "active ongoing",
+			validator: generic.Validator{ActivationEligibilityEpoch: 90, ActivationEpoch: 100, ExitEpoch: farFutureEpoch, WithdrawableEpoch: farFutureEpoch},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_ActiveOngoing,
+		},
+		{
+			name:      "active exiting",
+			validator: generic.Validator{ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 101, WithdrawableEpoch: 357},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_ActiveExiting,
+		},
+		{
+			name:      "active slashed",
+			validator: generic.Validator{Slashed: true, ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 101, WithdrawableEpoch: 8293},
+			balance:   31e9,
+			expected:  beacon.ValidatorState_ActiveSlashed,
+		},
+		{
+			name:      "exited unslashed",
+			validator: generic.Validator{ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 100, WithdrawableEpoch: 356},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_ExitedUnslashed,
+		},
+		{
+			name:      "exited slashed",
+			validator: generic.Validator{Slashed: true, ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 50, WithdrawableEpoch: 8242},
+			balance:   31e9,
+			expected:  beacon.ValidatorState_ExitedSlashed,
+		},
+		{
+			name:      "withdrawal possible",
+			validator: generic.Validator{ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 30, WithdrawableEpoch: 100},
+			balance:   32e9,
+			expected:  beacon.ValidatorState_WithdrawalPossible,
+		},
+		{
+			name:      "withdrawal done",
+			validator: generic.Validator{ActivationEligibilityEpoch: 10, ActivationEpoch: 20, ExitEpoch: 30, WithdrawableEpoch: 50},
+			balance:   0,
+			expected:  beacon.ValidatorState_WithdrawalDone,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := getValidatorState(&test.validator, test.balance, epoch)
+			if state != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, state)
+			}
+		})
+	}
+}
+
+func TestValidatorStatusesFromState(t *testing.T) {
+	pubkeys := fixturePubkeys
+	state := &deneb.BeaconState{
+		Slot: 3205,
+		Validators: []*generic.Validator{
+			{
+				Pubkey:                     pubkeys[0].Bytes(),
+				WithdrawalCredentials:      make([]byte, 32),
+				EffectiveBalance:           32e9,
+				ActivationEligibilityEpoch: 1,
+				ActivationEpoch:            5,
+				ExitEpoch:                  farFutureEpoch,
+				WithdrawableEpoch:          farFutureEpoch,
+			},
+			{
+				Pubkey:                     pubkeys[1].Bytes(),
+				WithdrawalCredentials:      make([]byte, 32),
+				EffectiveBalance:           32e9,
+				ActivationEligibilityEpoch: 1,
+				ActivationEpoch:            5,
+				ExitEpoch:                  50,
+				WithdrawableEpoch:          306,
+			},
+		},
+		Balances: []uint64{32001000000, 32000500000},
+	}
+
+	statuses, err := getValidatorStatusesFromState(state, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+	expectedStates := []beacon.ValidatorState{beacon.ValidatorState_ActiveOngoing, beacon.ValidatorState_ExitedUnslashed}
+	for i, status := range statuses {
+		if status.Pubkey != pubkeys[i] || status.Index != strconv.Itoa(i) || !status.Exists {
+			t.Fatalf("status %d has the wrong identity: %+v", i, status)
+		}
+		if status.Balance != state.Balances[i] || status.EffectiveBalance != 32e9 {
+			t.Fatalf("status %d has the wrong balances: %+v", i, status)
+		}
+		if status.Status != expectedStates[i] {
+			t.Fatalf("expected status %d to be %s, got %s", i, expectedStates[i], status.Status)
+		}
+	}
+
+	// A state with mismatched lists is invalid
+	state.Balances = state.Balances[:1]
+	if _, err := getValidatorStatusesFromState(state, 32); err == nil {
+		t.Fatal("expected an error for a state with a missing balance")
+	}
+}
+
+// A stand-in for a Beacon Node that serves a head state's validators as an SSZ state or a JSON validator list
+type validatorBeaconNode struct {
+	server        *httptest.Server
+	state         []byte
+	validators    map[string]Validator
+	support       sszSupport
+	stateRequests int
+	listRequests  int
+	lock          sync.Mutex
+}
+
+// Build a Deneb state with validators in a mix of states, returning it with their pubkeys
+func newValidatorTestState(tb testing.TB, count int) (*deneb.BeaconState, []types.ValidatorPubkey) {
+	newRoot := func(value byte) []byte {
+		return bytes.Repeat([]byte{value}, 32)
+	}
+	newSyncCommittee := func() *generic.SyncCommittee {
+		committee := &generic.SyncCommittee{PubKeys: make([][]byte, 512)}
+		for i := range committee.PubKeys {
+			committee.PubKeys[i] = make([]byte, 48)
+		}
+		return committee
+	}
+
+	epoch := testDenebForkEpoch + 10
+	state := &deneb.BeaconState{
+		GenesisValidatorsRoot:        newRoot(1),
+		Slot:                         epoch * testSlotsPerEpoch,
+		Fork:                         &generic.Fork{PreviousVersion: []byte{3, 0, 0, 0}, CurrentVersion: []byte{4, 0, 0, 0}, Epoch: testDenebForkEpoch},
+		LatestBlockHeader:            &generic.BeaconBlockHeader{ParentRoot: newRoot(2), StateRoot: newRoot(3), BodyRoot: newRoot(4)},
+		Eth1Data:                     &generic.Eth1Data{DepositRoot: newRoot(5), BlockHash: newRoot(6)},
+		RandaoMixes:                  make([][]byte, 65536),
+		Slashings:                    make([]uint64, 8192),
+		PreviousJustifiedCheckpoint:  &generic.Checkpoint{Root: newRoot(7)},
+		CurrentJustifiedCheckpoint:   &generic.Checkpoint{Root: newRoot(8)},
+		FinalizedCheckpoint:          &generic.Checkpoint{Root: newRoot(9)},
+		CurrentSyncCommittee:         newSyncCommittee(),
+		NextSyncCommittee:            newSyncCommittee(),
+		LatestExecutionPayloadHeader: &generic.ExecutionPayloadHeader{},
+	}
+	for i := range state.RandaoMixes {
+		state.RandaoMixes[i] = make([]byte, 32)
+	}
+
+	pubkeys := make([]types.ValidatorPubkey, count)
+	for i := range pubkeys {
+		key, err := bls.RandKey()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubkeys[i] = types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+		validator := &generic.Validator{
+			Pubkey:                     pubkeys[i].Bytes(),
+			WithdrawalCredentials:      newRoot(byte(i)),
+			EffectiveBalance:           32e9,
+			ActivationEligibilityEpoch: 1,
+			ActivationEpoch:            5,
+			ExitEpoch:                  farFutureEpoch,
+			WithdrawableEpoch:          farFutureEpoch,
+		}
+		switch i % 3 {
+		case 1:
+			validator.ExitEpoch = epoch + 5
+			validator.WithdrawableEpoch = epoch + 261
+		case 2:
+			validator.Slashed = true
+			validator.ExitEpoch = epoch - 5
+			validator.WithdrawableEpoch = epoch + 8187
+		}
+		state.Validators = append(state.Validators, validator)
+		state.Balances = append(state.Balances, 32e9+uint64(i))
+		state.PreviousEpochParticipation = append(state.PreviousEpochParticipation, 7)
+		state.CurrentEpochParticipation = append(state.CurrentEpochParticipation, 7)
+		state.InactivityScores = append(state.InactivityScores, 0)
+	}
+	return state, pubkeys
+}
+
+func newValidatorBeaconNode(tb testing.TB, state *deneb.BeaconState, support sszSupport) *validatorBeaconNode {
+	data, err := state.MarshalSSZ()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	statuses, err := getValidatorStatusesFromState(state, testSlotsPerEpoch)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	bn := &validatorBeaconNode{
+		state:      data,
+		validators: map[string]Validator{},
+		support:    support,
+	}
+	for _, status := range statuses {
+		var validator Validator
+		validator.Index = status.Index
+		validator.Balance = uinteger(status.Balance)
+		validator.Status = string(status.Status)
+		validator.Validator.Pubkey = status.Pubkey.Bytes()
+		validator.Validator.WithdrawalCredentials = status.WithdrawalCredentials.Bytes()
+		validator.Validator.EffectiveBalance = uinteger(status.EffectiveBalance)
+		validator.Validator.Slashed = status.Slashed
+		validator.Validator.ActivationEligibilityEpoch = uinteger(status.ActivationEligibilityEpoch)
+		validator.Validator.ActivationEpoch = uinteger(status.ActivationEpoch)
+		validator.Validator.ExitEpoch = uinteger(status.ExitEpoch)
+		validator.Validator.WithdrawableEpoch = uinteger(status.WithdrawableEpoch)
+		bn.validators[hexutil.AddPrefix(status.Pubkey.Hex())] = validator
+	}
+
+	bn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RequestEth2ConfigPath:
+			_, _ = fmt.Fprintf(w, `{"data":{"SLOTS_PER_EPOCH":"%d","DENEB_FORK_EPOCH":"%d"}}`, testSlotsPerEpoch, testDenebForkEpoch)
+
+		case fmt.Sprintf(RequestBeaconStatePath, "head"):
+			bn.lock.Lock()
+			bn.stateRequests++
+			bn.lock.Unlock()
+			if r.Header.Get("Accept") != RequestSSZContentType || bn.support != sszSupported {
+				http.Error(w, `{"code":406,"message":"NOT_ACCEPTABLE"}`, http.StatusNotAcceptable)
+				return
+			}
+			w.Header().Set("Content-Type", RequestSSZContentType)
+			w.Header().Set(ResponseConsensusVersionHeader, "deneb")
+			_, _ = w.Write(bn.state)
+
+		case fmt.Sprintf(RequestValidatorsPath, "head"):
+			bn.lock.Lock()
+			bn.listRequests++
+			bn.lock.Unlock()
+			response := ValidatorsResponse{Data: []Validator{}}
+			for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+				if validator, exists := bn.validators[id]; exists {
+					response.Data = append(response.Data, validator)
+				}
+			}
+			w.Header().Set("Content-Type", RequestJsonContentType)
+			_ = json.NewEncoder(w).Encode(response)
+
+		default:
+			http.Error(w, `{"code":404,"message":"NOT_FOUND"}`, http.StatusNotFound)
+		}
+	}))
+	tb.Cleanup(bn.server.Close)
+	return bn
+}
+
+// Get the number of state and validator list requests the Beacon Node has served, and reset the counts
+func (bn *validatorBeaconNode) takeRequests() (int, int) {
+	bn.lock.Lock()
+	defer bn.lock.Unlock()
+	stateRequests, listRequests := bn.stateRequests, bn.listRequests
+	bn.stateRequests, bn.listRequests = 0, 0
+	return stateRequests, listRequests
+}
+
+func TestValidatorStatusesSSZMatchesJSON(t *testing.T) {
+	defaultCount := minSSZValidatorCount
+	defer func() { minSSZValidatorCount = defaultCount }()
+
+	state, pubkeys := newValidatorTestState(t, 30)
+	bn := newValidatorBeaconNode(t, state, sszSupported)
+
+	// Include a validator that isn't on the chain yet
+	key, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := append(pubkeys[:20:20], types.BytesToValidatorPubkey(key.PublicKey().Marshal()))
+
+	// Small requests use the JSON validator list
+	expected, err := NewStandardHttpClient(bn.server.URL).GetValidatorStatuses(request, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stateRequests, listRequests := bn.takeRequests(); stateRequests != 0 || listRequests != 1 {
+		t.Fatalf("expected 1 validator list request, got %d state and %d list requests", stateRequests, listRequests)
+	}
+	if len(expected) != 21 {
+		t.Fatalf("expected 20 statuses and the null status, got %d", len(expected))
+	}
+
+	// Large requests pick the same statuses out of the SSZ state
+	minSSZValidatorCount = 10
+	client := NewStandardHttpClient(bn.server.URL)
+	actual, err := client.GetValidatorStatuses(request, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stateRequests, listRequests := bn.takeRequests(); stateRequests != 1 || listRequests != 0 {
+		t.Fatalf("expected 1 state request, got %d state and %d list requests", stateRequests, listRequests)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("statuses from the SSZ state don't match the JSON list:\nexpected %+v\nactual   %+v", expected, actual)
+	}
+}
+
+func TestValidatorStatusesSSZFallback(t *testing.T) {
+	defaultCount := minSSZValidatorCount
+	defer func() { minSSZValidatorCount = defaultCount }()
+	minSSZValidatorCount = 1
+
+	state, pubkeys := newValidatorTestState(t, 3)
+	bn := newValidatorBeaconNode(t, state, sszNotAcceptable)
+	client := NewStandardHttpClient(bn.server.URL)
+
+	// Beacon Nodes that can't provide the state as SSZ should still get the JSON list
+	statuses, err := client.GetValidatorStatuses(pubkeys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 4 || statuses[pubkeys[1]].Status != beacon.ValidatorState_ActiveExiting {
+		t.Fatalf("statuses weren't retrieved properly: %+v", statuses)
+	}
+	if stateRequests, listRequests := bn.takeRequests(); stateRequests != 1 || listRequests != 1 {
+		t.Fatalf("expected 1 state and 1 list request, got %d and %d", stateRequests, listRequests)
+	}
+	if !client.sszUnsupported.Load() {
+		t.Fatal("expected the client to stop requesting SSZ")
+	}
+}
+
+// Compare getting a block's attestations from the JSON attestations route with getting them from the SSZ block
+func BenchmarkGetAttestations(b *testing.B) {
+	bn := newBlockBeaconNode(b, sszSupported)
+	for _, fixture := range blockFixtures {
+		block := bn.blocks[fixture.slot]
+		b.Run(fmt.Sprintf("%s/json", fixture.fork), func(b *testing.B) {
+			client := NewStandardHttpClient(bn.server.URL)
+			b.SetBytes(int64(len(block.attestationsJson)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, err := client.GetAttestations(fixture.slot)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%s/ssz-block", fixture.fork), func(b *testing.B) {
+			client := NewStandardHttpClient(bn.server.URL)
+			b.SetBytes(int64(len(block.ssz)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, err := client.GetBeaconBlock(fixture.slot)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetBeaconBlock(b *testing.B) {
+	bn := newBlockBeaconNode(b, sszSupported)
+	for _, fixture := range blockFixtures {
+		block := bn.blocks[fixture.slot]
+		for _, encoding := range []string{"json", "ssz"} {
+			b.Run(fmt.Sprintf("%s/%s", fixture.fork, encoding), func(b *testing.B) {
+				client := NewStandardHttpClient(bn.server.URL)
+				if encoding == "json" {
+					client.sszUnsupported.Store(true)
+					b.SetBytes(int64(len(block.json)))
+				} else {
+					b.SetBytes(int64(len(block.ssz)))
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, _, err := client.GetBeaconBlock(fixture.slot)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Compare getting validator statuses from the JSON validator list with picking them out of the SSZ state
+func BenchmarkGetValidatorStatuses(b *testing.B) {
+	defaultCount := minSSZValidatorCount
+	defer func() { minSSZValidatorCount = defaultCount }()
+
+	state, pubkeys := newValidatorTestState(b, 20000)
+	bn := newValidatorBeaconNode(b, state, sszSupported)
+	for _, count := range []int{MaxRequestValidatorsCount, len(pubkeys)} {
+		request := pubkeys[:count]
+		for _, encoding := range []string{"json", "ssz-state"} {
+			b.Run(fmt.Sprintf("%d/%s", count, encoding), func(b *testing.B) {
+				client := NewStandardHttpClient(bn.server.URL)
+				if encoding == "json" {
+					minSSZValidatorCount = math.MaxInt
+				} else {
+					minSSZValidatorCount = 0
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, err := client.GetValidatorStatuses(request, nil)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
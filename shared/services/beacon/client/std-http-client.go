@@ -32,6 +32,7 @@ const (
 	RequestUrlFormat               = "%s%s"
 	RequestJsonContentType         = "application/json"
 	RequestSSZContentType          = "application/octet-stream"
+	RequestEventStreamContentType  = "text/event-stream"
 	ResponseConsensusVersionHeader = "Eth-Consensus-Version"
 
 	RequestSyncStatusPath                  = "/eth/v1/node/syncing"
@@ -47,24 +48,39 @@ const (
 	RequestAttestationsPath                = "/eth/v1/beacon/blocks/%s/attestations"
 	RequestBeaconBlockPath                 = "/eth/v2/beacon/blocks/%s"
 	RequestBeaconBlockHeaderPath           = "/eth/v1/beacon/headers/%s"
-	RequestBeaconStatePath                 = "/eth/v2/debug/beacon/states/%d"
+	RequestBeaconStatePath                 = "/eth/v2/debug/beacon/states/%s"
 	RequestValidatorSyncDuties             = "/eth/v1/validator/duties/sync/%s"
 	RequestValidatorProposerDuties         = "/eth/v1/validator/duties/proposer/%s"
 	RequestWithdrawalCredentialsChangePath = "/eth/v1/beacon/pool/bls_to_execution_changes"
+	RequestEventsPath                      = "/eth/v1/events?topics=%s"
 
 	MaxRequestValidatorsCount     = 600
 	threadLimit               int = 12
 )
 
+// Requests for at least this many validators read them from the SSZ state instead of the JSON validator list.
+// The state holds every validator on the chain (around a million on Mainnet), so it's only worth downloading when a request covers a large share of them.
+var minSSZValidatorCount int = 50000
+
 // Beacon client using the standard Beacon HTTP REST API (https://ethereum.github.io/beacon-APIs/)
 type StandardHttpClient struct {
 	providerAddress string
+	client          *http.Client
+
+	// Set when the Beacon Node doesn't provide SSZ responses, so only JSON is requested from then on
+	sszUnsupported atomic.Bool
+
+	// The first slot of the earliest fork with SSZ types, loaded from the Beacon Node's config when it's first needed
+	sszForkSlot       uint64
+	sszForkSlotLoaded bool
+	sszForkSlotLock   sync.Mutex
 }
 
 // Create a new client instance
 func NewStandardHttpClient(providerAddress string) *StandardHttpClient {
 	return &StandardHttpClient{
 		providerAddress: providerAddress,
+		client:          &http.Client{},
 	}
 }
 
@@ -395,6 +411,14 @@ func (c *StandardHttpClient) GetValidatorStatuses(pubkeys []types.ValidatorPubke
 		pubkeysHex[vi] = hexutil.AddPrefix(realPubkeys[vi].Hex())
 	}
 
+	// Large lists are faster to pick out of the SSZ state than to request as JSON in batches
+	if len(realPubkeys) >= minSSZValidatorCount {
+		statuses, err := c.getValidatorStatusesFromSSZState(realPubkeys, opts)
+		if err == nil {
+			return statuses, nil
+		}
+	}
+
 	// Get validators
 	validators, err := c.getValidatorsByOpts(pubkeysHex, opts)
 	if err != nil {
@@ -439,6 +463,34 @@ func (c *StandardHttpClient) GetValidatorStatuses(pubkeys []types.ValidatorPubke
 
 }
 
+// Get multiple validators' statuses from the SSZ state
+func (c *StandardHttpClient) getValidatorStatusesFromSSZState(pubkeys []types.ValidatorPubkey, opts *beacon.ValidatorStatusOptions) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+	stateId, err := c.getStateId(opts)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := c.getValidatorStatusesSSZ(stateId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pick out the requested validators; ones that aren't on the state are left out, like the JSON route does
+	requested := make(map[types.ValidatorPubkey]bool, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		requested[pubkey] = true
+	}
+	statuses := make(map[types.ValidatorPubkey]beacon.ValidatorStatus, len(pubkeys)+1)
+	for _, validator := range validators {
+		if requested[validator.Pubkey] {
+			statuses[validator.Pubkey] = validator
+		}
+	}
+
+	// Put an empty status in for null pubkeys
+	statuses[types.ValidatorPubkey{}] = beacon.ValidatorStatus{}
+	return statuses, nil
+}
+
 // Get whether validators have sync duties to perform at given epoch
 func (c *StandardHttpClient) GetValidatorSyncDuties(indices []string, epoch uint64) (map[string]bool, error) {
 	// Return if there are not validators to check
@@ -607,6 +659,7 @@ func (c *StandardHttpClient) GetEth1DataForEth2Block(blockId string) (beacon.Eth
 }
 
 func (c *StandardHttpClient) GetAttestations(blockId string) ([]beacon.AttestationInfo, bool, error) {
+	// This stays on JSON: the SSZ block includes the execution payload, so it's slower than the attestations alone (see BenchmarkGetAttestations)
 	attestations, exists, err := c.getAttestations(blockId)
 	if err != nil {
 		return nil, false, err
@@ -639,6 +692,12 @@ func (c *StandardHttpClient) GetAttestations(blockId string) ([]beacon.Attestati
 }
 
 func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock, bool, error) {
+	// Blocks are much smaller and faster to decode as SSZ, so try that first
+	beaconBlock, exists, err := c.getBeaconBlockSSZ(blockId)
+	if err == nil {
+		return beaconBlock, exists, nil
+	}
+
 	block, exists, err := c.getBeaconBlock(blockId)
 	if err != nil {
 		return beacon.BeaconBlock{}, false, err
@@ -647,7 +706,7 @@ func (c *StandardHttpClient) GetBeaconBlock(blockId string) (beacon.BeaconBlock,
 		return beacon.BeaconBlock{}, false, nil
 	}
 
-	beaconBlock := beacon.BeaconBlock{
+	beaconBlock = beacon.BeaconBlock{
 		Slot:          uint64(block.Data.Message.Slot),
 		ProposerIndex: block.Data.Message.ProposerIndex,
 	}
@@ -878,12 +937,18 @@ func (c *StandardHttpClient) getValidators(stateId string, pubkeys []string) (Va
 }
 
 func (c *StandardHttpClient) GetAllValidators() ([]beacon.ValidatorStatus, error) {
+	// The full validator list is enormous as JSON, so get it from the SSZ state if possible
+	validators, err := c.getValidatorStatusesSSZ("finalized")
+	if err == nil {
+		return validators, nil
+	}
+
 	response, err := c.getValidators("finalized", []string{})
 	if err != nil {
 		return []beacon.ValidatorStatus{}, fmt.Errorf("Could not get all validators: %w", err)
 	}
 
-	validators := make([]beacon.ValidatorStatus, len(response.Data))
+	validators = make([]beacon.ValidatorStatus, len(response.Data))
 	for i, validator := range response.Data {
 
 		// Add status
@@ -907,29 +972,37 @@ func (c *StandardHttpClient) GetAllValidators() ([]beacon.ValidatorStatus, error
 
 }
 
-// Get validators by pubkeys and status options
-func (c *StandardHttpClient) getValidatorsByOpts(pubkeysOrIndices []string, opts *beacon.ValidatorStatusOptions) (ValidatorsResponse, error) {
-
-	// Get state ID
-	var stateId string
+// Get the state ID for status options
+func (c *StandardHttpClient) getStateId(opts *beacon.ValidatorStatusOptions) (string, error) {
 	if opts == nil {
-		stateId = "head"
-	} else if opts.Slot != nil {
-		stateId = strconv.FormatInt(int64(*opts.Slot), 10)
-	} else if opts.Epoch != nil {
+		return "head", nil
+	}
+	if opts.Slot != nil {
+		return strconv.FormatInt(int64(*opts.Slot), 10), nil
+	}
+	if opts.Epoch != nil {
 
 		// Get eth2 config
 		eth2Config, err := c.getEth2Config()
 		if err != nil {
-			return ValidatorsResponse{}, err
+			return "", err
 		}
 
 		// Get slot number
 		slot := *opts.Epoch * uint64(eth2Config.Data.SlotsPerEpoch)
-		stateId = strconv.FormatInt(int64(slot), 10)
+		return strconv.FormatInt(int64(slot), 10), nil
 
-	} else {
-		return ValidatorsResponse{}, fmt.Errorf("must specify a slot or epoch when calling getValidatorsByOpts")
+	}
+	return "", fmt.Errorf("must specify a slot or epoch when calling getValidatorsByOpts")
+}
+
+// Get validators by pubkeys and status options
+func (c *StandardHttpClient) getValidatorsByOpts(pubkeysOrIndices []string, opts *beacon.ValidatorStatusOptions) (ValidatorsResponse, error) {
+
+	// Get state ID
+	stateId, err := c.getStateId(opts)
+	if err != nil {
+		return ValidatorsResponse{}, err
 	}
 
 	count := len(pubkeysOrIndices)
@@ -1026,7 +1099,7 @@ func (c *StandardHttpClient) getBeaconBlock(blockId string) (BeaconBlockResponse
 
 // Get the Beacon state for a slot
 func (c *StandardHttpClient) GetBeaconStateSSZ(slot uint64) (*beacon.BeaconStateSSZ, error) {
-	response, err := c.sszRequest(fmt.Sprintf(RequestBeaconStatePath, slot))
+	response, err := c.sszRequest(fmt.Sprintf(RequestBeaconStatePath, strconv.FormatUint(slot, 10)))
 	if err != nil {
 		return nil, fmt.Errorf("Could not get beacon state data: %w", err)
 	}
@@ -1193,9 +1266,7 @@ func (c *StandardHttpClient) getRequestReaderWithContentType(requestPath string,
 	}
 	request.Header.Set("Accept", contentType)
 
-	client := http.Client{}
-
-	response, err := client.Do(request)
+	response, err := c.client.Do(request)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1236,7 +1307,7 @@ func (c *StandardHttpClient) sszRequest(requestPath string) (*http.Response, err
 		return nil, err
 	}
 	request.Header.Set("Accept", RequestSSZContentType)
-	return http.DefaultClient.Do(request)
+	return c.client.Do(request)
 }
 
 // Make a POST request to the beacon node
@@ -1250,7 +1321,7 @@ func (c *StandardHttpClient) postRequest(requestPath string, requestBody interfa
 	requestBodyReader := bytes.NewReader(requestBodyBytes)
 
 	// Send request
-	response, err := http.Post(fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), RequestJsonContentType, requestBodyReader)
+	response, err := c.client.Post(fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), RequestJsonContentType, requestBodyReader)
 	if err != nil {
 		return []byte{}, 0, err
 	}
@@ -45,6 +45,7 @@ type Eth2ConfigResponse struct {
 		SlotsPerEpoch                uinteger  `json:"SLOTS_PER_EPOCH"`
 		CapellaForkVersion           byteArray `json:"CAPELLA_FORK_VERSION"`
 		EpochsPerSyncCommitteePeriod uinteger  `json:"EPOCHS_PER_SYNC_COMMITTEE_PERIOD"`
+		DenebForkEpoch               uinteger  `json:"DENEB_FORK_EPOCH"`
 	} `json:"data"`
 }
 type Eth2DepositContractResponse struct {
@@ -176,6 +177,30 @@ type Withdrawal struct {
 	Amount         string    `json:"amount"`
 }
 
+// Event stream types
+type HeadEventData struct {
+	Slot            uinteger  `json:"slot"`
+	Block           byteArray `json:"block"`
+	State           byteArray `json:"state"`
+	EpochTransition bool      `json:"epoch_transition"`
+}
+type FinalizedCheckpointEventData struct {
+	Block byteArray `json:"block"`
+	State byteArray `json:"state"`
+	Epoch uinteger  `json:"epoch"`
+}
+type ChainReorgEventData struct {
+	Slot         uinteger  `json:"slot"`
+	Depth        uinteger  `json:"depth"`
+	OldHeadBlock byteArray `json:"old_head_block"`
+	NewHeadBlock byteArray `json:"new_head_block"`
+	Epoch        uinteger  `json:"epoch"`
+}
+type VoluntaryExitEventData struct {
+	Message   VoluntaryExitMessage `json:"message"`
+	Signature byteArray            `json:"signature"`
+}
+
 // Unsigned integer type
 type uinteger uint64
 
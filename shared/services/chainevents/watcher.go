@@ -0,0 +1,173 @@
+package chainevents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Config
+var streamRetryInterval, _ = time.ParseDuration("30s")
+
+// The Beacon events that can wake a task loop early
+var topics = []beacon.EventTopic{
+	beacon.EventTopic_Head,
+	beacon.EventTopic_ChainReorg,
+	beacon.EventTopic_VoluntaryExit,
+}
+
+// Watches the Beacon Node's event stream so a task loop can run as soon as something relevant to its latest network state happens.
+// The loop still runs on its regular interval, so tasks keep running if the stream isn't available.
+type Watcher struct {
+	bc  beacon.Client
+	log *log.ColorLogger
+
+	// Holds the reason for the next early run; it only holds one, so events that arrive while the tasks are running are coalesced
+	wake chan string
+
+	// The validators in the latest network state, so exits of other validators can be ignored
+	validatorIndices map[string]bool
+
+	// The slot the latest network state was built at, so reorgs that don't replace it can be ignored
+	stateSlot uint64
+	hasState  bool
+
+	// Set when a task is waiting for the next epoch, with the reason to log when it starts
+	epochWakeReason string
+
+	lock sync.Mutex
+}
+
+// Create a new chain event watcher
+func NewWatcher(bc beacon.Client, logger *log.ColorLogger) *Watcher {
+	return &Watcher{
+		bc:               bc,
+		log:              logger,
+		wake:             make(chan string, 1),
+		validatorIndices: map[string]bool{},
+	}
+}
+
+// Keep the event subscription open until the context is cancelled, resubscribing whenever the stream drops
+func (w *Watcher) Run(ctx context.Context) {
+	subscribed := true
+	for {
+		events, err := w.bc.SubscribeToEvents(ctx, topics)
+		if err != nil {
+			// Only log when the stream first becomes unavailable
+			if subscribed {
+				w.log.WithLevel(log.LevelWarn).Printlnf("WARNING: Couldn't subscribe to Beacon events (%s), tasks will only run on their regular interval until it's available.", err.Error())
+			}
+			subscribed = false
+		} else {
+			if !subscribed {
+				w.log.Println("Subscribed to Beacon events.")
+			}
+			subscribed = true
+			for event := range events {
+				w.handleEvent(event)
+			}
+			if ctx.Err() == nil {
+				w.log.Println("Beacon event stream ended, resubscribing...")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamRetryInterval):
+		}
+	}
+}
+
+// Update the network state that events are checked against
+func (w *Watcher) UpdateState(state *state.NetworkState) {
+	indices := map[string]bool{}
+	for _, validator := range state.MinipoolValidatorDetails {
+		if validator.Exists {
+			indices[validator.Index] = true
+		}
+	}
+	for _, validator := range state.MegapoolValidatorDetails {
+		if validator.Exists {
+			indices[validator.Index] = true
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.validatorIndices = indices
+	w.stateSlot = state.BeaconSlotNumber
+	w.hasState = true
+}
+
+// Wake the task loop when the next epoch starts, since the Beacon chain applies things like new deposits during the epoch transition.
+// The reason is logged when the loop is woken; it's only used for one epoch, so tasks need to call this again on each run while they're waiting.
+func (w *Watcher) WakeAtNextEpoch(reason string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.epochWakeReason = reason
+}
+
+// Wait until a relevant event arrives or the timeout passes, whichever comes first
+func (w *Watcher) Wait(timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case reason := <-w.wake:
+		w.log.Printlnf("Running tasks early: %s.", reason)
+	case <-timer.C:
+	}
+}
+
+// Wake the task loop if an event makes its latest network state out of date
+func (w *Watcher) handleEvent(event beacon.Event) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// Nothing is relevant until the loop has built a state
+	if !w.hasState {
+		return
+	}
+
+	var reason string
+	switch event.Topic {
+	case beacon.EventTopic_Head:
+		// Head events arrive every slot, so only the first one of an epoch is relevant, and only if a task is waiting for it
+		if !event.Head.EpochTransition || w.epochWakeReason == "" {
+			return
+		}
+		reason = w.epochWakeReason
+		w.epochWakeReason = ""
+
+	case beacon.EventTopic_ChainReorg:
+		// Only reorgs that forked the chain before the slot the state was built at can have replaced its block
+		reorg := event.ChainReorg
+		forkSlot := reorg.Slot - min(reorg.Depth, reorg.Slot)
+		if forkSlot >= w.stateSlot {
+			return
+		}
+		reason = fmt.Sprintf("chain reorg of depth %d at slot %d replaced the state's block at slot %d", reorg.Depth, reorg.Slot, w.stateSlot)
+
+	case beacon.EventTopic_VoluntaryExit:
+		if !w.validatorIndices[event.VoluntaryExit.ValidatorIndex] {
+			return
+		}
+		reason = fmt.Sprintf("validator %s submitted an exit", event.VoluntaryExit.ValidatorIndex)
+
+	default:
+		return
+	}
+
+	// Don't block if a run is already pending
+	select {
+	case w.wake <- reason:
+	default:
+	}
+}
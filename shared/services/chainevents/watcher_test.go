@@ -0,0 +1,148 @@
+package chainevents
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/state"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+func newTestWatcher() *Watcher {
+	logger := log.NewColorLogger(color.FgWhite)
+	return NewWatcher(nil, &logger)
+}
+
+// Check if the watcher woke the loop, consuming the pending wake-up if it did
+func woke(w *Watcher) bool {
+	select {
+	case <-w.wake:
+		return true
+	default:
+		return false
+	}
+}
+
+func exitEvent(index string) beacon.Event {
+	return beacon.Event{
+		Topic:         beacon.EventTopic_VoluntaryExit,
+		VoluntaryExit: &beacon.VoluntaryExitEvent{ValidatorIndex: index},
+	}
+}
+
+func reorgEvent(slot uint64, depth uint64) beacon.Event {
+	return beacon.Event{
+		Topic:      beacon.EventTopic_ChainReorg,
+		ChainReorg: &beacon.ChainReorgEvent{Slot: slot, Depth: depth},
+	}
+}
+
+func headEvent(slot uint64, epochTransition bool) beacon.Event {
+	return beacon.Event{
+		Topic: beacon.EventTopic_Head,
+		Head:  &beacon.HeadEvent{Slot: slot, EpochTransition: epochTransition},
+	}
+}
+
+func TestEventsIgnoredWithoutState(t *testing.T) {
+	w := newTestWatcher()
+	w.handleEvent(exitEvent("1"))
+	w.handleEvent(reorgEvent(100, 50))
+	if woke(w) {
+		t.Fatal("woke the loop before it built a state")
+	}
+}
+
+func TestVoluntaryExits(t *testing.T) {
+	w := newTestWatcher()
+	w.UpdateState(&state.NetworkState{
+		BeaconSlotNumber: 100,
+		MinipoolValidatorDetails: state.ValidatorDetailsMap{
+			{0x01}: {Index: "1", Exists: true},
+			{0x02}: {Index: "2", Exists: false},
+		},
+		MegapoolValidatorDetails: state.ValidatorDetailsMap{
+			{0x03}: {Index: "3", Exists: true},
+		},
+	})
+
+	w.handleEvent(exitEvent("4"))
+	if woke(w) {
+		t.Fatal("woke the loop for a validator that isn't in the state")
+	}
+	w.handleEvent(exitEvent("2"))
+	if woke(w) {
+		t.Fatal("woke the loop for a validator that doesn't exist on the Beacon chain")
+	}
+	w.handleEvent(exitEvent("1"))
+	if !woke(w) {
+		t.Fatal("didn't wake the loop for a minipool validator's exit")
+	}
+	w.handleEvent(exitEvent("3"))
+	if !woke(w) {
+		t.Fatal("didn't wake the loop for a megapool validator's exit")
+	}
+}
+
+func TestReorgs(t *testing.T) {
+	w := newTestWatcher()
+	w.UpdateState(&state.NetworkState{BeaconSlotNumber: 100})
+
+	// Reorgs after the state's slot don't affect it
+	w.handleEvent(reorgEvent(105, 1))
+	w.handleEvent(reorgEvent(105, 5))
+	if woke(w) {
+		t.Fatal("woke the loop for a reorg that didn't replace the state's block")
+	}
+
+	// Reorgs that forked before the state's slot do
+	w.handleEvent(reorgEvent(105, 6))
+	if !woke(w) {
+		t.Fatal("didn't wake the loop for a reorg that replaced the state's block")
+	}
+}
+
+func TestWakeAtNextEpoch(t *testing.T) {
+	w := newTestWatcher()
+	w.UpdateState(&state.NetworkState{BeaconSlotNumber: 100})
+
+	// New epochs don't wake the loop unless a task is waiting for one
+	w.handleEvent(headEvent(128, true))
+	if woke(w) {
+		t.Fatal("woke the loop for an epoch nothing was waiting for")
+	}
+
+	// Only the first slot of the epoch wakes the loop
+	w.WakeAtNextEpoch("a validator is waiting for its deposit to be processed")
+	w.handleEvent(headEvent(129, false))
+	if woke(w) {
+		t.Fatal("woke the loop for a slot in the middle of an epoch")
+	}
+	w.handleEvent(headEvent(160, true))
+	if !woke(w) {
+		t.Fatal("didn't wake the loop when the epoch a task was waiting for started")
+	}
+
+	// The request only applies to one epoch
+	w.handleEvent(headEvent(192, true))
+	if woke(w) {
+		t.Fatal("woke the loop again without a new request")
+	}
+}
+
+func TestWakeCoalesced(t *testing.T) {
+	w := newTestWatcher()
+	w.UpdateState(&state.NetworkState{BeaconSlotNumber: 100})
+
+	// Events that arrive while a run is pending shouldn't block
+	w.handleEvent(reorgEvent(100, 10))
+	w.handleEvent(reorgEvent(100, 20))
+	if !woke(w) {
+		t.Fatal("didn't wake the loop")
+	}
+	if woke(w) {
+		t.Fatal("queued more than one run")
+	}
+}
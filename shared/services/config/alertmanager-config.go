@@ -25,6 +25,9 @@ const defaultAlertmanagerPort uint16 = 9093
 const defaultAlertmanagerHost string = "localhost"
 const defaultAlertmanagerOpenPort config.RPCMode = config.RPC_Closed
 const defaultLowETHBalanceThreshold float64 = 0.05
+const defaultSmtpPort uint16 = 587
+const defaultMissedAttestationsThreshold uint64 = 3
+const defaultMissedAttestationsWindow uint64 = 10
 
 // Configuration for Alertmanager
 type AlertmanagerConfig struct {
@@ -59,6 +62,31 @@ type AlertmanagerConfig struct {
 	// The Pushover User Key for alert notifications
 	PushoverUserKey config.Parameter `yaml:"pushoverUserKey,omitempty"`
 
+	// Whether alerts are posted to Alertmanager; the direct notifiers below work without it
+	SendToAlertmanager config.Parameter `yaml:"sendToAlertmanager,omitempty"`
+
+	// The URL that alerts are posted to as JSON
+	WebhookURL config.Parameter `yaml:"webhookURL,omitempty"`
+
+	// The Telegram bot token and chat for alert notifications
+	TelegramBotToken config.Parameter `yaml:"telegramBotToken,omitempty"`
+	TelegramChatID   config.Parameter `yaml:"telegramChatID,omitempty"`
+
+	// The ntfy topic URL and access token for alert notifications
+	NtfyURL   config.Parameter `yaml:"ntfyURL,omitempty"`
+	NtfyToken config.Parameter `yaml:"ntfyToken,omitempty"`
+
+	// The Slack incoming webhook URL for alert notifications
+	SlackWebhookURL config.Parameter `yaml:"slackWebhookURL,omitempty"`
+
+	// The SMTP server and addresses for email alert notifications
+	SmtpHost     config.Parameter `yaml:"smtpHost,omitempty"`
+	SmtpPort     config.Parameter `yaml:"smtpPort,omitempty"`
+	SmtpUsername config.Parameter `yaml:"smtpUsername,omitempty"`
+	SmtpPassword config.Parameter `yaml:"smtpPassword,omitempty"`
+	SmtpFrom     config.Parameter `yaml:"smtpFrom,omitempty"`
+	SmtpTo       config.Parameter `yaml:"smtpTo,omitempty"`
+
 	// Alerts configured in prometheus rule configuration file:
 	AlertEnabled_ClientSyncStatusBeacon    config.Parameter `yaml:"alertEnabled_ClientSyncStatusBeacon,omitempty"`
 	AlertEnabled_ClientSyncStatusExecution config.Parameter `yaml:"alertEnabled_ClientSyncStatusExecution,omitempty"`
@@ -73,13 +101,28 @@ type AlertmanagerConfig struct {
 	AlertEnabled_LowETHBalance             config.Parameter `yaml:"alertEnabled_LowETHBalance,omitempty"`
 	LowETHBalanceThreshold                 config.Parameter `yaml:"lowETHBalanceThreshold,omitempty"`
 	// Alerts manually sent in alerting.go:
-	AlertEnabled_FeeRecipientChanged         config.Parameter `yaml:"alertEnabled_FeeRecipientChanged,omitempty"`
-	AlertEnabled_MinipoolBondReduced         config.Parameter `yaml:"alertEnabled_MinipoolBondReduced,omitempty"`
-	AlertEnabled_MinipoolBalanceDistributed  config.Parameter `yaml:"alertEnabled_MinipoolBalanceDistributed,omitempty"`
-	AlertEnabled_MinipoolPromoted            config.Parameter `yaml:"alertEnabled_MinipoolPromoted,omitempty"`
-	AlertEnabled_MinipoolStaked              config.Parameter `yaml:"alertEnabled_MinipoolStaked,omitempty"`
-	AlertEnabled_ExecutionClientSyncComplete config.Parameter `yaml:"alertEnabled_ExecutionClientSyncComplete,omitempty"`
-	AlertEnabled_BeaconClientSyncComplete    config.Parameter `yaml:"alertEnabled_BeaconClientSyncComplete,omitempty"`
+	AlertEnabled_FeeRecipientChanged                 config.Parameter `yaml:"alertEnabled_FeeRecipientChanged,omitempty"`
+	AlertEnabled_MinipoolBondReduced                 config.Parameter `yaml:"alertEnabled_MinipoolBondReduced,omitempty"`
+	AlertEnabled_MinipoolBalanceDistributed          config.Parameter `yaml:"alertEnabled_MinipoolBalanceDistributed,omitempty"`
+	AlertEnabled_MinipoolPromoted                    config.Parameter `yaml:"alertEnabled_MinipoolPromoted,omitempty"`
+	AlertEnabled_MinipoolStaked                      config.Parameter `yaml:"alertEnabled_MinipoolStaked,omitempty"`
+	AlertEnabled_ExecutionClientSyncComplete         config.Parameter `yaml:"alertEnabled_ExecutionClientSyncComplete,omitempty"`
+	AlertEnabled_BeaconClientSyncComplete            config.Parameter `yaml:"alertEnabled_BeaconClientSyncComplete,omitempty"`
+	AlertEnabled_MegapoolValidatorPrestaked          config.Parameter `yaml:"alertEnabled_MegapoolValidatorPrestaked,omitempty"`
+	AlertEnabled_MegapoolValidatorStaked             config.Parameter `yaml:"alertEnabled_MegapoolValidatorStaked,omitempty"`
+	AlertEnabled_MegapoolValidatorDissolved          config.Parameter `yaml:"alertEnabled_MegapoolValidatorDissolved,omitempty"`
+	AlertEnabled_MegapoolValidatorExiting            config.Parameter `yaml:"alertEnabled_MegapoolValidatorExiting,omitempty"`
+	AlertEnabled_MegapoolValidatorChallenged         config.Parameter `yaml:"alertEnabled_MegapoolValidatorChallenged,omitempty"`
+	AlertEnabled_MegapoolChallengeDefended           config.Parameter `yaml:"alertEnabled_MegapoolChallengeDefended,omitempty"`
+	AlertEnabled_MegapoolChallengeLost               config.Parameter `yaml:"alertEnabled_MegapoolChallengeLost,omitempty"`
+	AlertEnabled_MegapoolDebtIncurred                config.Parameter `yaml:"alertEnabled_MegapoolDebtIncurred,omitempty"`
+	AlertEnabled_MegapoolExpressQueuePositionChanged config.Parameter `yaml:"alertEnabled_MegapoolExpressQueuePositionChanged,omitempty"`
+	AlertEnabled_MegapoolRewardsDistributed          config.Parameter `yaml:"alertEnabled_MegapoolRewardsDistributed,omitempty"`
+	AlertEnabled_MegapoolRefundClaimed               config.Parameter `yaml:"alertEnabled_MegapoolRefundClaimed,omitempty"`
+	AlertEnabled_MissedAttestations                  config.Parameter `yaml:"alertEnabled_MissedAttestations,omitempty"`
+	AlertEnabled_MissedProposal                      config.Parameter `yaml:"alertEnabled_MissedProposal,omitempty"`
+	MissedAttestationsThreshold                      config.Parameter `yaml:"missedAttestationsThreshold,omitempty"`
+	MissedAttestationsWindow                         config.Parameter `yaml:"missedAttestationsWindow,omitempty"`
 }
 
 func NewAlertmanagerConfig(cfg *RocketPoolConfig) *AlertmanagerConfig {
@@ -189,6 +232,149 @@ func NewAlertmanagerConfig(cfg *RocketPoolConfig) *AlertmanagerConfig {
 			OverwriteOnUpgrade: false,
 		},
 
+		SendToAlertmanager: config.Parameter{
+			ID:                 "sendToAlertmanager",
+			Name:               "Send Alerts to Alertmanager",
+			Description:        "Post the Smartnode's alerts to Alertmanager, which forwards them to the Discord and Pushover receivers above. Disable this if you don't run Alertmanager and only want to use the direct notification settings below.",
+			Type:               config.ParameterType_Bool,
+			Default:            map[config.Network]interface{}{config.Network_All: true},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		WebhookURL: config.Parameter{
+			ID:                 "webhookURL",
+			Name:               "Webhook URL",
+			Description:        "If set, the Smartnode will POST each alert to this URL as a JSON object, and again when the alert is resolved.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		TelegramBotToken: config.Parameter{
+			ID:                 "telegramBotToken",
+			Name:               "Telegram Bot Token",
+			Description:        "The token of the Telegram bot that sends alert notifications. Create a bot with @BotFather to get one.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		TelegramChatID: config.Parameter{
+			ID:                 "telegramChatID",
+			Name:               "Telegram Chat ID",
+			Description:        "The ID of the Telegram chat, group or channel the bot should send alert notifications to.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		NtfyURL: config.Parameter{
+			ID:                 "ntfyURL",
+			Name:               "ntfy Topic URL",
+			Description:        "The full URL of the ntfy topic to publish alert notifications to, such as https://ntfy.sh/my-node-alerts.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		NtfyToken: config.Parameter{
+			ID:                 "ntfyToken",
+			Name:               "ntfy Access Token",
+			Description:        "The access token for the ntfy topic, if it requires authentication.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SlackWebhookURL: config.Parameter{
+			ID:                 "slackWebhookURL",
+			Name:               "Slack Webhook URL",
+			Description:        "The Slack incoming webhook URL to send alert notifications to. See https://api.slack.com/messaging/webhooks to learn how to create one.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpHost: config.Parameter{
+			ID:                 "smtpHost",
+			Name:               "SMTP Host",
+			Description:        "The hostname of the SMTP server used to send alert notifications by email.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpPort: config.Parameter{
+			ID:                 "smtpPort",
+			Name:               "SMTP Port",
+			Description:        "The port of the SMTP server used to send alert notifications by email.",
+			Type:               config.ParameterType_Uint16,
+			Default:            map[config.Network]interface{}{config.Network_All: defaultSmtpPort},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpUsername: config.Parameter{
+			ID:                 "smtpUsername",
+			Name:               "SMTP Username",
+			Description:        "The username to log into the SMTP server with. Leave this blank if the server doesn't require authentication.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpPassword: config.Parameter{
+			ID:                 "smtpPassword",
+			Name:               "SMTP Password",
+			Description:        "The password to log into the SMTP server with.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpFrom: config.Parameter{
+			ID:                 "smtpFrom",
+			Name:               "Email Sender Address",
+			Description:        "The address alert emails are sent from.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		SmtpTo: config.Parameter{
+			ID:                 "smtpTo",
+			Name:               "Email Recipient Addresses",
+			Description:        "The addresses alert emails are sent to, separated by commas.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
 		AlertEnabled_ClientSyncStatusBeacon: createParameterForAlertEnablement(
 			"ClientSyncStatusBeacon",
 			"beacon client is not synced"),
@@ -260,6 +446,80 @@ func NewAlertmanagerConfig(cfg *RocketPoolConfig) *AlertmanagerConfig {
 			"LowETHBalance",
 			"Low ETH Balance"),
 
+		AlertEnabled_MegapoolValidatorPrestaked: createParameterForAlertEnablement(
+			"MegapoolValidatorPrestaked",
+			"Megapool Validator Prestaked"),
+
+		AlertEnabled_MegapoolValidatorStaked: createParameterForAlertEnablement(
+			"MegapoolValidatorStaked",
+			"Megapool Validator Staked"),
+
+		AlertEnabled_MegapoolValidatorDissolved: createParameterForAlertEnablement(
+			"MegapoolValidatorDissolved",
+			"Megapool Validator Dissolved"),
+
+		AlertEnabled_MegapoolValidatorExiting: createParameterForAlertEnablement(
+			"MegapoolValidatorExiting",
+			"Megapool Validator Exiting"),
+
+		AlertEnabled_MegapoolValidatorChallenged: createParameterForAlertEnablement(
+			"MegapoolValidatorChallenged",
+			"Megapool Validator Exit Challenged"),
+
+		AlertEnabled_MegapoolChallengeDefended: createParameterForAlertEnablement(
+			"MegapoolChallengeDefended",
+			"Megapool Exit Challenge Defended"),
+
+		AlertEnabled_MegapoolChallengeLost: createParameterForAlertEnablement(
+			"MegapoolChallengeLost",
+			"Megapool Exit Challenge Lost"),
+
+		AlertEnabled_MegapoolDebtIncurred: createParameterForAlertEnablement(
+			"MegapoolDebtIncurred",
+			"Megapool Debt Incurred"),
+
+		AlertEnabled_MegapoolExpressQueuePositionChanged: createParameterForAlertEnablement(
+			"MegapoolExpressQueuePositionChanged",
+			"Megapool Express Queue Position Changed"),
+
+		AlertEnabled_MegapoolRewardsDistributed: createParameterForAlertEnablement(
+			"MegapoolRewardsDistributed",
+			"Megapool Rewards Distributed"),
+
+		AlertEnabled_MegapoolRefundClaimed: createParameterForAlertEnablement(
+			"MegapoolRefundClaimed",
+			"Megapool Refund Claimed"),
+
+		AlertEnabled_MissedAttestations: createParameterForAlertEnablement(
+			"MissedAttestations",
+			"Missed Attestations"),
+
+		AlertEnabled_MissedProposal: createParameterForAlertEnablement(
+			"MissedProposal",
+			"Missed Proposal"),
+
+		MissedAttestationsThreshold: config.Parameter{
+			ID:                 "missedAttestationsThreshold",
+			Name:               "Missed Attestations Threshold",
+			Description:        "The number of attestations a validator can miss within the window below before the missed attestations alert is sent.",
+			Type:               config.ParameterType_Uint,
+			Default:            map[config.Network]interface{}{config.Network_All: defaultMissedAttestationsThreshold},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		MissedAttestationsWindow: config.Parameter{
+			ID:                 "missedAttestationsWindow",
+			Name:               "Missed Attestations Window",
+			Description:        "The number of recent finalized epochs to count a validator's missed attestations over.",
+			Type:               config.ParameterType_Uint,
+			Default:            map[config.Network]interface{}{config.Network_All: defaultMissedAttestationsWindow},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
 		LowETHBalanceThreshold: config.Parameter{
 			ID:                 "lowETHBalanceThreshold",
 			Name:               "Low ETH Balance Threshold",
@@ -297,6 +557,19 @@ func (cfg *AlertmanagerConfig) GetParameters() []*config.Parameter {
 		&cfg.DiscordWebhookURL,
 		&cfg.PushoverToken,
 		&cfg.PushoverUserKey,
+		&cfg.SendToAlertmanager,
+		&cfg.WebhookURL,
+		&cfg.TelegramBotToken,
+		&cfg.TelegramChatID,
+		&cfg.NtfyURL,
+		&cfg.NtfyToken,
+		&cfg.SlackWebhookURL,
+		&cfg.SmtpHost,
+		&cfg.SmtpPort,
+		&cfg.SmtpUsername,
+		&cfg.SmtpPassword,
+		&cfg.SmtpFrom,
+		&cfg.SmtpTo,
 		&cfg.ContainerTag,
 		&cfg.AlertEnabled_ClientSyncStatusBeacon,
 		&cfg.AlertEnabled_ClientSyncStatusExecution,
@@ -316,6 +589,21 @@ func (cfg *AlertmanagerConfig) GetParameters() []*config.Parameter {
 		&cfg.AlertEnabled_ExecutionClientSyncComplete,
 		&cfg.AlertEnabled_BeaconClientSyncComplete,
 		&cfg.AlertEnabled_LowETHBalance,
+		&cfg.AlertEnabled_MegapoolValidatorPrestaked,
+		&cfg.AlertEnabled_MegapoolValidatorStaked,
+		&cfg.AlertEnabled_MegapoolValidatorDissolved,
+		&cfg.AlertEnabled_MegapoolValidatorExiting,
+		&cfg.AlertEnabled_MegapoolValidatorChallenged,
+		&cfg.AlertEnabled_MegapoolChallengeDefended,
+		&cfg.AlertEnabled_MegapoolChallengeLost,
+		&cfg.AlertEnabled_MegapoolDebtIncurred,
+		&cfg.AlertEnabled_MegapoolExpressQueuePositionChanged,
+		&cfg.AlertEnabled_MegapoolRewardsDistributed,
+		&cfg.AlertEnabled_MegapoolRefundClaimed,
+		&cfg.AlertEnabled_MissedAttestations,
+		&cfg.AlertEnabled_MissedProposal,
+		&cfg.MissedAttestationsThreshold,
+		&cfg.MissedAttestationsWindow,
 		&cfg.LowETHBalanceThreshold,
 	}
 }
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/alessio/shellescape"
+	"github.com/ethereum/go-ethereum/common"
 	externalip "github.com/glendc/go-external-ip"
 	"github.com/pbnjay/memory"
 	"github.com/rocket-pool/smartnode/addons"
@@ -1150,6 +1151,16 @@ func (cfg *RocketPoolConfig) FeeRecipientFile() string {
 	return FeeRecipientFilename
 }
 
+// Used by text/template to format validator.yml
+func (cfg *RocketPoolConfig) KeymanagerTokenFile() string {
+	return KeymanagerTokenFilename
+}
+
+// Used by text/template to format validator.yml
+func (cfg *RocketPoolConfig) Web3SignerUrl() string {
+	return cfg.Smartnode.GetWeb3SignerUrl()
+}
+
 // Used by text/template to format validator.yml
 func (cfg *RocketPoolConfig) MevBoostUrl() string {
 	if !cfg.EnableMevBoost.Value.(bool) {
@@ -1415,6 +1426,21 @@ func (cfg *RocketPoolConfig) GetMevBoostOpenPorts() string {
 	return fmt.Sprintf("\"%s\"", portMode.DockerPortMapping(port))
 }
 
+// Used by text/template to format api.yml
+func (cfg *RocketPoolConfig) IsApiServerEnabled() bool {
+	return cfg.Smartnode.ApiServerMode.Value.(config.RPCMode).Open()
+}
+
+// Used by text/template to format api.yml
+func (cfg *RocketPoolConfig) GetApiServerOpenPorts() string {
+	portMode := cfg.Smartnode.ApiServerMode.Value.(config.RPCMode)
+	if !portMode.Open() {
+		return ""
+	}
+	port := cfg.Smartnode.ApiServerPort.Value.(uint16)
+	return fmt.Sprintf("\"%s\"", portMode.DockerPortMapping(port))
+}
+
 // TODO: remove this code on the next Prysm release - so users can still rollback from 6.0.4
 // Used by text/template to select an entrypoint based on which consensus client is used.
 func (cfg *RocketPoolConfig) GetEth2Entrypoint() string {
@@ -1542,6 +1568,28 @@ func (cfg *RocketPoolConfig) Validate() []string {
 		errors = append(errors, "You are using an externally-managed Execution client and a locally-managed Consensus client.\nThis configuration is not compatible with The Merge; please select either locally-managed or externally-managed for both the EC and CC.")
 	}
 
+	// Web3Signer needs a URL
+	if cfg.Smartnode.UseWeb3Signer.Value == true && cfg.Smartnode.Web3SignerUrl.Value.(string) == "" {
+		errors = append(errors, "You have Web3Signer enabled but don't have a URL set. Please enter the Web3Signer URL to use it.")
+	}
+
+	// The external signer account has to be an address
+	if account := cfg.Smartnode.ExternalSignerAccount.Value.(string); account != "" && !common.IsHexAddress(account) {
+		errors = append(errors, fmt.Sprintf("The external signer account [%s] is not a valid address.", account))
+	}
+
+	// Transaction submission modes need their endpoints
+	for _, mode := range []config.TxSubmissionMode{cfg.Smartnode.TxSubmissionMode.Value.(config.TxSubmissionMode), cfg.Smartnode.GetWatchtowerTxSubmissionMode()} {
+		if mode == config.TxSubmissionMode_PrivateRpc && cfg.Smartnode.GetPrivateRpcUrl() == "" {
+			errors = append(errors, "You have the Private RPC transaction submission mode selected but don't have a private RPC URL set. Please enter the URL to use it.")
+			break
+		}
+		if mode == config.TxSubmissionMode_Bundle && cfg.Smartnode.BundleRelayUrl.Value.(string) == "" {
+			errors = append(errors, "You have the Bundle transaction submission mode selected but don't have a bundle relay URL set. Please enter the URL to use it.")
+			break
+		}
+	}
+
 	if !cfg.IsNativeMode && cfg.EnableMevBoost.Value == true {
 		switch cfg.MevBoost.Mode.Value.(config.Mode) {
 		case config.Mode_Local:
@@ -1598,6 +1646,8 @@ func (cfg *RocketPoolConfig) Validate() []string {
 	portMap, errors = addAndCheckForDuplicate(portMap, cfg.MevBoost.Port, errors)
 	portMap, errors = addAndCheckForDuplicate(portMap, cfg.Prometheus.Port, errors)
 	portMap, errors = addAndCheckForDuplicate(portMap, cfg.Alertmanager.Port, errors)
+	portMap, errors = addAndCheckForDuplicate(portMap, cfg.Smartnode.ApiServerPort, errors)
+	portMap, errors = addAndCheckForDuplicate(portMap, cfg.Smartnode.KeymanagerApiPort, errors)
 	_, errors = addAndCheckForDuplicate(portMap, cfg.Lighthouse.P2pQuicPort, errors)
 
 	return errors
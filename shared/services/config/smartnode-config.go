@@ -31,11 +31,27 @@ const (
 	GithubRewardsFileUrl               string = "https://github.com/rocket-pool/rewards-trees/raw/main/%s/%s"
 	FeeRecipientFilename               string = "rp-fee-recipient.txt"
 	NativeFeeRecipientFilename         string = "rp-fee-recipient-env.txt"
+	ApiTokenFilename                   string = "api-token"
+	ApiServerCertFilename              string = "api-server.crt"
+	ApiServerKeyFilename               string = "api-server.key"
+	KeymanagerTokenFilename            string = "keymanager-api-token.txt"
+	SlashingProtectionFilename         string = "slashing_protection.json"
+	SlashingProtectionBackupFilename   string = "deleted-keys-slashing-protection.json"
+	PendingTransactionsFilename        string = "pending-transactions.json"
+	OfflineTransactionsFolder          string = "offline-transactions"
+	RewardsHistoryFilename             string = "rewards-history.json"
+	DutyRecordsFolder                  string = "duty-records"
+	RewardsCheckpointsFolder           string = "rewards-checkpoints"
+	BeaconCacheFolder                  string = "beacon-cache"
+	AttestationRecordsFolder           string = "attestation-records"
+	attestationRecordFilenameFormat    string = "rp-attestations-%s-%d.json"
 )
 
 // Defaults
 const (
 	defaultProjectName       string = "rocketpool"
+	defaultApiServerPort     uint16 = 8280
+	defaultKeymanagerApiPort uint16 = 5062
 	WatchtowerMaxFeeDefault  uint64 = 50
 	WatchtowerPrioFeeDefault uint64 = 3
 )
@@ -88,6 +104,12 @@ type SmartnodeConfig struct {
 	// The amount of ETH in a minipool's balance before auto-distribute kicks in
 	DistributeThreshold config.Parameter `yaml:"distributeThreshold,omitempty"`
 
+	// The amount of pending rewards in the megapool before auto-distribute kicks in
+	MegapoolDistributeThreshold config.Parameter `yaml:"megapoolDistributeThreshold,omitempty"`
+
+	// The amount of ETH the megapool owes the node before its refund is claimed automatically
+	MegapoolClaimRefundThreshold config.Parameter `yaml:"megapoolClaimRefundThreshold,omitempty"`
+
 	// Mode for acquiring Merkle rewards trees
 	RewardsTreeMode config.Parameter `yaml:"rewardsTreeMode,omitempty"`
 
@@ -100,18 +122,72 @@ type SmartnodeConfig struct {
 	// URL for an EC with archive mode, for manual rewards tree generation
 	ArchiveECUrl config.Parameter `yaml:"archiveEcUrl,omitempty"`
 
+	// Toggle for keeping attestation duty records on disk during rewards tree generation
+	LowMemoryTreeGeneration config.Parameter `yaml:"lowMemoryTreeGeneration,omitempty"`
+
+	// The size of the on-disk cache of finalized Beacon Node responses used for rewards tree generation, in MB
+	BeaconResponseCacheSize config.Parameter `yaml:"beaconResponseCacheSize,omitempty"`
+
 	// Manual override for the watchtower's max fee
 	WatchtowerMaxFeeOverride config.Parameter `yaml:"watchtowerMaxFeeOverride,omitempty"`
 
 	// Manual override for the watchtower's priority fee
 	WatchtowerPrioFeeOverride config.Parameter `yaml:"watchtowerPrioFeeOverride,omitempty"`
 
+	// The default way to submit transactions to the network
+	TxSubmissionMode config.Parameter `yaml:"txSubmissionMode,omitempty"`
+
+	// The private RPC endpoint to submit transactions to
+	PrivateRpcUrl config.Parameter `yaml:"privateRpcUrl,omitempty"`
+
+	// The relay to submit transaction bundles to
+	BundleRelayUrl config.Parameter `yaml:"bundleRelayUrl,omitempty"`
+
+	// The way to submit the watchtower's price and balance transactions
+	WatchtowerTxSubmissionMode config.Parameter `yaml:"watchtowerTxSubmissionMode,omitempty"`
+
+	// The way to submit rewards claim transactions
+	RewardsClaimTxSubmissionMode config.Parameter `yaml:"rewardsClaimTxSubmissionMode,omitempty"`
+
+	// The number of blocks to wait before re-broadcasting a stuck transaction with higher fees
+	TxFeeBumpBlocks config.Parameter `yaml:"txFeeBumpBlocks,omitempty"`
+
+	// The format of the daemon logs
+	LogFormat config.Parameter `yaml:"logFormat,omitempty"`
+
 	// The toggle for enabling pDAO proposal verification duties
 	VerifyProposals config.Parameter `yaml:"verifyProposals,omitempty"`
 
 	// Delay for automatic queue assignment
 	AutoAssignmentDelay config.Parameter `yaml:"autoAssignmentDelay,omitempty"`
 
+	// Toggle for exposing the HTTP API server
+	ApiServerMode config.Parameter `yaml:"apiServerMode,omitempty"`
+
+	// The port for the HTTP API server
+	ApiServerPort config.Parameter `yaml:"apiServerPort,omitempty"`
+
+	// Toggle for allowing the HTTP API server to serve commands that return the node's secrets
+	ApiServerAllowSecretExport config.Parameter `yaml:"apiServerAllowSecretExport,omitempty"`
+
+	// Toggle for managing the Validator Client through its Keymanager API
+	EnableKeymanagerApi config.Parameter `yaml:"enableKeymanagerApi,omitempty"`
+
+	// The port for the Validator Client's Keymanager API
+	KeymanagerApiPort config.Parameter `yaml:"keymanagerApiPort,omitempty"`
+
+	// Toggle for storing validator keys in Web3Signer instead of the local keystores
+	UseWeb3Signer config.Parameter `yaml:"useWeb3Signer,omitempty"`
+
+	// The URL of the Web3Signer instance
+	Web3SignerUrl config.Parameter `yaml:"web3SignerUrl,omitempty"`
+
+	// The URL of an external signer (e.g. Clef) that holds the node account key
+	ExternalSignerUrl config.Parameter `yaml:"externalSignerUrl,omitempty"`
+
+	// The node account to use on the external signer
+	ExternalSignerAccount config.Parameter `yaml:"externalSignerAccount,omitempty"`
+
 	///////////////////////////
 	// Non-editable settings //
 	///////////////////////////
@@ -314,6 +390,28 @@ func NewSmartnodeConfig(cfg *RocketPoolConfig) *SmartnodeConfig {
 			OverwriteOnUpgrade: false,
 		},
 
+		MegapoolDistributeThreshold: config.Parameter{
+			ID:                 "megapoolDistributeThreshold",
+			Name:               "Megapool Auto-Distribute Threshold",
+			Description:        "The Smartnode will regularly check the pending rewards of your megapool.\nIf they are greater than this threshold (in ETH), the Smartnode will automatically distribute them. This moves your share of the rewards into the megapool's refund, which can then be claimed.\n\nSet this to 0 to disable automatic megapool distributes.",
+			Type:               config.ParameterType_Float,
+			Default:            map[config.Network]interface{}{config.Network_All: float64(0)},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		MegapoolClaimRefundThreshold: config.Parameter{
+			ID:                 "megapoolClaimRefundThreshold",
+			Name:               "Megapool Auto-Claim Refund Threshold",
+			Description:        "The Smartnode will regularly check the refund your megapool owes you, such as your share of distributed rewards.\nIf it is greater than this threshold (in ETH), the Smartnode will automatically claim it. This will send the refund to your withdrawal address.\n\nSet this to 0 to disable automatic refund claims.",
+			Type:               config.ParameterType_Float,
+			Default:            map[config.Network]interface{}{config.Network_All: float64(0)},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
 		VerifyProposals: config.Parameter{
 			ID:                 "verifyProposals",
 			Name:               "Enable PDAO Proposal Checker",
@@ -336,6 +434,106 @@ func NewSmartnodeConfig(cfg *RocketPoolConfig) *SmartnodeConfig {
 			OverwriteOnUpgrade: false,
 		},
 
+		ApiServerMode: config.Parameter{
+			ID:                 "apiServerMode",
+			Name:               "Expose API Server",
+			Description:        "The Smartnode can run a long-lived HTTP server that serves its API, so the `rocketpool` CLI (and your own dashboards or scripts) can talk to it directly instead of using `docker exec` for every command.\n\nRequests must provide the token stored in the `api-token` file in your data folder as a Bearer token. Select Closed to disable the server; the CLI will fall back to running each command inside the API container.\n\nTo manage this node from another machine, put a TLS certificate and key named `api-server.crt` and `api-server.key` in your data folder; the CLI only connects to remote API servers over HTTPS, and the server won't start when it's open to external hosts without them.",
+			Type:               config.ParameterType_Choice,
+			Default:            map[config.Network]interface{}{config.Network_All: config.RPC_Closed},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+			Options:            config.PortModes("Allow connections from external hosts. Anyone with your API token will be able to control your node, so only use this if you trust your local network."),
+		},
+
+		ApiServerPort: config.Parameter{
+			ID:                 "apiServerPort",
+			Name:               "API Server Port",
+			Description:        "The port the Smartnode's HTTP API server should listen on.",
+			Type:               config.ParameterType_Uint16,
+			Default:            map[config.Network]interface{}{config.Network_All: defaultApiServerPort},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		ApiServerAllowSecretExport: config.Parameter{
+			ID:                 "apiServerAllowSecretExport",
+			Name:               "Allow Secret Export over API Server",
+			Description:        "Allow the HTTP API server to serve commands that return your node wallet's secrets, such as `wallet export` (which includes your mnemonic password and node private key) and `wallet init` (which returns the new mnemonic).\n\nWhen this is disabled, the CLI on this machine still runs those commands inside the API container, but nothing can retrieve your secrets over HTTP.",
+			Type:               config.ParameterType_Bool,
+			Default:            map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		EnableKeymanagerApi: config.Parameter{
+			ID:                 "enableKeymanagerApi",
+			Name:               "Enable Keymanager API",
+			Description:        "Enable the Validator Client's standard Keymanager API so the Smartnode can change your fee recipient and load new validator keys without restarting it. This avoids missing attestations while the Validator Client restarts.\n\nNew validator keys are imported through the API instead of being written to the Validator Client's keystore folder, so the Validator Client must be running when you create or recover them. A copy of each key is kept in the `validators/keymanager` folder so it can be imported again if needed.\n\nThe API is only reachable from inside the Rocket Pool network, and requests must provide the token stored in your `validators` folder. If it's disabled, the Smartnode will restart the Validator Client instead.",
+			Type:               config.ParameterType_Bool,
+			Default:            map[config.Network]interface{}{config.Network_All: true},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Validator, config.ContainerID_Node, config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		KeymanagerApiPort: config.Parameter{
+			ID:                 "keymanagerApiPort",
+			Name:               "Keymanager API Port",
+			Description:        "The port the Validator Client's Keymanager API should listen on.",
+			Type:               config.ParameterType_Uint16,
+			Default:            map[config.Network]interface{}{config.Network_All: defaultKeymanagerApiPort},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Validator, config.ContainerID_Node, config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		UseWeb3Signer: config.Parameter{
+			ID:                 "useWeb3Signer",
+			Name:               "Use Web3Signer",
+			Description:        "Store new validator keys in a Web3Signer instance instead of writing them to your Validator Client's keystore folders. Your Validator Client will request signatures from Web3Signer rather than holding the keys itself.\n\nWeb3Signer must be running with its Keymanager API enabled. Keys are still derived from your node wallet, so they can be recovered from your mnemonic.",
+			Type:               config.ParameterType_Bool,
+			Default:            map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Validator, config.ContainerID_Node, config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		Web3SignerUrl: config.Parameter{
+			ID:                 "web3SignerUrl",
+			Name:               "Web3Signer URL",
+			Description:        "The URL of your Web3Signer instance, including the port (e.g. `http://web3signer:9000`). It must be reachable from both the Smartnode and your Validator Client.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Validator, config.ContainerID_Node, config.ContainerID_Api},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		ExternalSignerUrl: config.Parameter{
+			ID:                 "externalSignerUrl",
+			Name:               "External Signer URL",
+			Description:        "The URL of an external signer that holds your node account's key, such as Clef connected to a hardware wallet (e.g. `http://clef:8550`). It must support Clef's external API (`account_list`, `account_signTransaction` and `account_signData`).\n\nWhen this is set, transactions you make with the `rocketpool` CLI will be sent to the signer for approval. The node and watchtower daemons will not sign anything, so automatic transactions such as staking prelaunch minipools or distributing balances must be done manually.\n\nLeave this blank to use the node wallet stored on this machine.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		ExternalSignerAccount: config.Parameter{
+			ID:                 "externalSignerAccount",
+			Name:               "External Signer Account",
+			Description:        "The address of the external signer's account to use as your node account. Leave this blank to use the first account the signer provides.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
 		RewardsTreeMode: config.Parameter{
 			ID:                 "rewardsTreeMode",
 			Name:               "Rewards Tree Mode",
@@ -389,6 +587,28 @@ func NewSmartnodeConfig(cfg *RocketPoolConfig) *SmartnodeConfig {
 			OverwriteOnUpgrade: false,
 		},
 
+		LowMemoryTreeGeneration: config.Parameter{
+			ID:                 "lowMemoryTreeGeneration",
+			Name:               "Low-Memory Tree Generation",
+			Description:        "Enable this to keep the attestation duty records on disk instead of in memory while generating a rewards tree. Generation will take longer, but it needs far less RAM when many validators are opted into the Smoothing Pool. The generated tree is identical either way.",
+			Type:               config.ParameterType_Bool,
+			Default:            map[config.Network]interface{}{config.Network_All: false},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		BeaconResponseCacheSize: config.Parameter{
+			ID:                 "beaconResponseCacheSize",
+			Name:               "Beacon Response Cache Size",
+			Description:        "The maximum size, in MB, of the on-disk cache of finalized Beacon Node responses (committees, blocks, attestations and validator statuses) used when generating rewards trees. Regenerating an interval will reuse these instead of downloading them again. Set this to 0 to disable the cache.",
+			Type:               config.ParameterType_Uint,
+			Default:            map[config.Network]interface{}{config.Network_All: uint64(0)},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
 		WatchtowerMaxFeeOverride: config.Parameter{
 			ID:                 "watchtowerMaxFeeOverride",
 			Name:               "Watchtower Max Fee Override",
@@ -411,6 +631,95 @@ func NewSmartnodeConfig(cfg *RocketPoolConfig) *SmartnodeConfig {
 			OverwriteOnUpgrade: true,
 		},
 
+		TxSubmissionMode: config.Parameter{
+			ID:                 "txSubmissionMode",
+			Name:               "Transaction Submission Mode",
+			Description:        "Select how the Smartnode should submit its transactions to the network.",
+			Type:               config.ParameterType_Choice,
+			Default:            map[config.Network]interface{}{config.Network_All: config.TxSubmissionMode_Public},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+			Options:            getTxSubmissionModeOptions(false),
+		},
+
+		PrivateRpcUrl: config.Parameter{
+			ID:                 "privateRpcUrl",
+			Name:               "Private RPC URL",
+			Description:        "The URL of the private RPC endpoint to send transactions to when using the Private RPC submission mode.\n\nLeave this blank to use Flashbots Protect.",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		BundleRelayUrl: config.Parameter{
+			ID:                 "bundleRelayUrl",
+			Name:               "Bundle Relay URL",
+			Description:        "The URL of the relay to send transaction bundles to with `eth_sendBundle` when using the Bundle submission mode (e.g. `https://relay.flashbots.net`).",
+			Type:               config.ParameterType_String,
+			Default:            map[config.Network]interface{}{config.Network_All: ""},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         true,
+			OverwriteOnUpgrade: false,
+		},
+
+		WatchtowerTxSubmissionMode: config.Parameter{
+			ID:                 "watchtowerTxSubmissionMode",
+			Name:               "Watchtower Submission Mode",
+			Description:        "[orange]**For Oracle DAO members only.**\n\n[white]Select how the watchtower should submit its price and balance transactions to the network.",
+			Type:               config.ParameterType_Choice,
+			Default:            map[config.Network]interface{}{config.Network_All: config.TxSubmissionMode_Default},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+			Options:            getTxSubmissionModeOptions(true),
+		},
+
+		RewardsClaimTxSubmissionMode: config.Parameter{
+			ID:                 "rewardsClaimTxSubmissionMode",
+			Name:               "Rewards Claim Submission Mode",
+			Description:        "Select how the Smartnode should submit transactions that claim your node's rewards. Use this to keep claims out of the public mempool without changing how your other transactions are submitted.",
+			Type:               config.ParameterType_Choice,
+			Default:            map[config.Network]interface{}{config.Network_All: config.TxSubmissionMode_Default},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+			Options:            getTxSubmissionModeOptions(true),
+		},
+
+		TxFeeBumpBlocks: config.Parameter{
+			ID:                 "txFeeBumpBlocks",
+			Name:               "Fee Bump Delay",
+			Description:        "The number of blocks the Smartnode daemons will wait for one of their automatic transactions to be included before re-broadcasting it with a higher max fee and priority fee. The fees will never be raised above the limits that apply to the transaction (such as the Automatic TX Gas Threshold and Priority Fee, or the Watchtower Max Fee and Priority Fee for Oracle DAO members). The mempool only accepts a replacement if both fees go up, so transactions that already use the full Priority Fee won't be re-broadcast.\n\nUse 0 to disable fee bumping.",
+			Type:               config.ParameterType_Uint,
+			Default:            map[config.Network]interface{}{config.Network_All: uint64(10)},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+
+		LogFormat: config.Parameter{
+			ID:                 "logFormat",
+			Name:               "Log Format",
+			Description:        "Select how the node, watchtower, and API daemons format their logs.",
+			Type:               config.ParameterType_Choice,
+			Default:            map[config.Network]interface{}{config.Network_All: config.LogFormat_Color},
+			AffectsContainers:  []config.ContainerID{config.ContainerID_Api, config.ContainerID_Node, config.ContainerID_Watchtower},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+			Options: []config.ParameterOption{{
+				Name:        "Color",
+				Description: "Write colored, human-readable lines.",
+				Value:       config.LogFormat_Color,
+			}, {
+				Name:        "JSON",
+				Description: "Write one JSON object per line with the level, task, node address, and (where relevant) transaction hash and slot of each message. Use this if you collect your logs with a tool like Loki.",
+				Value:       config.LogFormat_Json,
+			}},
+		},
+
 		txWatchUrl: map[config.Network]string{
 			config.Network_Mainnet: "https://etherscan.io/tx",
 			config.Network_Devnet:  "https://hoodi.etherscan.io/tx",
@@ -625,14 +934,34 @@ func (cfg *SmartnodeConfig) GetParameters() []*config.Parameter {
 		&cfg.PriorityFee,
 		&cfg.AutoTxGasThreshold,
 		&cfg.DistributeThreshold,
+		&cfg.MegapoolDistributeThreshold,
+		&cfg.MegapoolClaimRefundThreshold,
 		&cfg.VerifyProposals,
 		&cfg.AutoAssignmentDelay,
+		&cfg.ApiServerMode,
+		&cfg.ApiServerPort,
+		&cfg.ApiServerAllowSecretExport,
+		&cfg.EnableKeymanagerApi,
+		&cfg.KeymanagerApiPort,
+		&cfg.UseWeb3Signer,
+		&cfg.Web3SignerUrl,
+		&cfg.ExternalSignerUrl,
+		&cfg.ExternalSignerAccount,
 		&cfg.RewardsTreeMode,
 		&cfg.PriceBalanceSubmissionReferenceTimestamp,
 		&cfg.RewardsTreeCustomUrl,
 		&cfg.ArchiveECUrl,
+		&cfg.LowMemoryTreeGeneration,
+		&cfg.BeaconResponseCacheSize,
 		&cfg.WatchtowerMaxFeeOverride,
 		&cfg.WatchtowerPrioFeeOverride,
+		&cfg.TxSubmissionMode,
+		&cfg.PrivateRpcUrl,
+		&cfg.BundleRelayUrl,
+		&cfg.WatchtowerTxSubmissionMode,
+		&cfg.RewardsClaimTxSubmissionMode,
+		&cfg.TxFeeBumpBlocks,
+		&cfg.LogFormat,
 	}
 }
 
@@ -698,6 +1027,83 @@ func (cfg *SmartnodeConfig) GetVotingPath() string {
 	return filepath.Join(DaemonDataPath, "voting", string(cfg.Network.Value.(config.Network)))
 }
 
+func (cfg *SmartnodeConfig) GetApiTokenPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), ApiTokenFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, ApiTokenFilename)
+}
+
+func (cfg *SmartnodeConfig) GetApiServerCertPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), ApiServerCertFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, ApiServerCertFilename)
+}
+
+func (cfg *SmartnodeConfig) GetApiServerKeyPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), ApiServerKeyFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, ApiServerKeyFilename)
+}
+
+func (cfg *SmartnodeConfig) GetSlashingProtectionBackupPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), SlashingProtectionBackupFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, SlashingProtectionBackupFilename)
+}
+
+func (cfg *SmartnodeConfig) GetPendingTransactionsPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), PendingTransactionsFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, PendingTransactionsFilename)
+}
+
+func (cfg *SmartnodeConfig) GetOfflineTransactionsPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), OfflineTransactionsFolder)
+	}
+
+	return filepath.Join(DaemonDataPath, OfflineTransactionsFolder)
+}
+
+func (cfg *SmartnodeConfig) GetRewardsHistoryPath() string {
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), RewardsHistoryFilename)
+	}
+
+	return filepath.Join(DaemonDataPath, RewardsHistoryFilename)
+}
+
+func (cfg *SmartnodeConfig) GetAttestationRecordPath(interval uint64) string {
+	filename := fmt.Sprintf(attestationRecordFilenameFormat, string(cfg.Network.Value.(config.Network)), interval)
+	if cfg.parent.IsNativeMode {
+		return filepath.Join(cfg.DataPath.Value.(string), AttestationRecordsFolder, filename)
+	}
+
+	return filepath.Join(DaemonDataPath, AttestationRecordsFolder, filename)
+}
+
+func (cfg *SmartnodeConfig) GetOfflineTransactionsPathInCLI() string {
+	return filepath.Join(cfg.DataPath.Value.(string), OfflineTransactionsFolder)
+}
+
+func (cfg *SmartnodeConfig) GetApiTokenPathInCLI() string {
+	return filepath.Join(cfg.DataPath.Value.(string), ApiTokenFilename)
+}
+
+func (cfg *SmartnodeConfig) GetSlashingProtectionBackupPathInCLI() string {
+	return filepath.Join(cfg.DataPath.Value.(string), SlashingProtectionBackupFilename)
+}
+
 func (cfg *SmartnodeConfig) GetWalletPathInCLI() string {
 	return filepath.Join(cfg.DataPath.Value.(string), "wallet")
 }
@@ -825,6 +1231,14 @@ func (cfg *SmartnodeConfig) GetRegenerateRewardsTreeRequestPath(interval uint64,
 	return filepath.Join(cfg.DataPath.Value.(string), WatchtowerFolder, fmt.Sprintf(RegenerateRewardsTreeRequestFormat, interval))
 }
 
+func (cfg *SmartnodeConfig) GetDataFolder(daemon bool) string {
+	if daemon && !cfg.parent.IsNativeMode {
+		return DaemonDataPath
+	}
+
+	return cfg.DataPath.Value.(string)
+}
+
 func (cfg *SmartnodeConfig) GetWatchtowerFolder(daemon bool) string {
 	if daemon && !cfg.parent.IsNativeMode {
 		return filepath.Join(DaemonDataPath, WatchtowerFolder)
@@ -833,6 +1247,18 @@ func (cfg *SmartnodeConfig) GetWatchtowerFolder(daemon bool) string {
 	return filepath.Join(cfg.DataPath.Value.(string), WatchtowerFolder)
 }
 
+func (cfg *SmartnodeConfig) GetDutyRecordsFolder(daemon bool) string {
+	return filepath.Join(cfg.GetWatchtowerFolder(daemon), DutyRecordsFolder)
+}
+
+func (cfg *SmartnodeConfig) GetRewardsCheckpointsFolder(daemon bool) string {
+	return filepath.Join(cfg.GetWatchtowerFolder(daemon), RewardsCheckpointsFolder)
+}
+
+func (cfg *SmartnodeConfig) GetBeaconCacheFolder(daemon bool) string {
+	return filepath.Join(cfg.GetWatchtowerFolder(daemon), BeaconCacheFolder)
+}
+
 func (cfg *SmartnodeConfig) GetFeeRecipientFilePath() string {
 	if !cfg.parent.IsNativeMode {
 		return filepath.Join(DaemonDataPath, "validators", FeeRecipientFilename)
@@ -841,6 +1267,41 @@ func (cfg *SmartnodeConfig) GetFeeRecipientFilePath() string {
 	return filepath.Join(cfg.DataPath.Value.(string), "validators", NativeFeeRecipientFilename)
 }
 
+func (cfg *SmartnodeConfig) GetKeymanagerTokenPath() string {
+	if !cfg.parent.IsNativeMode {
+		return filepath.Join(DaemonDataPath, "validators", KeymanagerTokenFilename)
+	}
+
+	return filepath.Join(cfg.DataPath.Value.(string), "validators", KeymanagerTokenFilename)
+}
+
+func (cfg *SmartnodeConfig) GetKeymanagerApiUrl() string {
+	port := cfg.KeymanagerApiPort.Value.(uint16)
+	if !cfg.parent.IsNativeMode {
+		return fmt.Sprintf("http://%s:%d", ValidatorContainerName, port)
+	}
+
+	return fmt.Sprintf("http://127.0.0.1:%d", port)
+}
+
+// Get the URL of the Web3Signer instance, or an empty string if validator keys are stored locally
+func (cfg *SmartnodeConfig) GetWeb3SignerUrl() string {
+	if !cfg.UseWeb3Signer.Value.(bool) {
+		return ""
+	}
+	return strings.TrimSuffix(cfg.Web3SignerUrl.Value.(string), "/")
+}
+
+// Check if the daemons should write structured JSON logs
+func (cfg *SmartnodeConfig) UseJsonLogs() bool {
+	return cfg.LogFormat.Value.(config.LogFormat) == config.LogFormat_Json
+}
+
+// Get the URL of the external signer for the node account, or an empty string if the local node wallet is used
+func (cfg *SmartnodeConfig) GetExternalSignerUrl() string {
+	return strings.TrimSuffix(cfg.ExternalSignerUrl.Value.(string), "/")
+}
+
 func (cfg *SmartnodeConfig) GetV100RewardsPoolAddress() common.Address {
 	return common.HexToAddress(cfg.v1_0_0_RewardsPoolAddress[cfg.Network.Value.(config.Network)])
 }
@@ -949,6 +1410,32 @@ func (cfg *SmartnodeConfig) GetFlashbotsProtectUrl() string {
 	return cfg.flashbotsProtectUrl[cfg.Network.Value.(config.Network)]
 }
 
+// Get the private RPC endpoint to submit transactions to, defaulting to Flashbots Protect
+func (cfg *SmartnodeConfig) GetPrivateRpcUrl() string {
+	url := cfg.PrivateRpcUrl.Value.(string)
+	if url == "" {
+		return cfg.GetFlashbotsProtectUrl()
+	}
+	return url
+}
+
+// Get the submission mode for the watchtower's price and balance transactions
+func (cfg *SmartnodeConfig) GetWatchtowerTxSubmissionMode() config.TxSubmissionMode {
+	mode := cfg.WatchtowerTxSubmissionMode.Value.(config.TxSubmissionMode)
+	if mode == config.TxSubmissionMode_Default {
+		return cfg.TxSubmissionMode.Value.(config.TxSubmissionMode)
+	}
+	return mode
+}
+
+func (cfg *SmartnodeConfig) GetRewardsClaimTxSubmissionMode() config.TxSubmissionMode {
+	mode := cfg.RewardsClaimTxSubmissionMode.Value.(config.TxSubmissionMode)
+	if mode == config.TxSubmissionMode_Default {
+		return cfg.TxSubmissionMode.Value.(config.TxSubmissionMode)
+	}
+	return mode
+}
+
 func getNetworkOptions() []config.ParameterOption {
 	options := []config.ParameterOption{
 		{
@@ -972,3 +1459,29 @@ func getNetworkOptions() []config.ParameterOption {
 
 	return options
 }
+
+// Get the options for the transaction submission modes
+func getTxSubmissionModeOptions(includeDefault bool) []config.ParameterOption {
+	options := []config.ParameterOption{}
+	if includeDefault {
+		options = append(options, config.ParameterOption{
+			Name:        "Use Default",
+			Description: "Use the Transaction Submission Mode selected for the rest of the Smartnode.",
+			Value:       config.TxSubmissionMode_Default,
+		})
+	}
+	options = append(options, config.ParameterOption{
+		Name:        "Public",
+		Description: "Send transactions to your Execution client so they're broadcast to the public mempool.",
+		Value:       config.TxSubmissionMode_Public,
+	}, config.ParameterOption{
+		Name:        "Private RPC",
+		Description: "Send transactions to a private RPC endpoint (such as Flashbots Protect) so they aren't visible in the public mempool before they're included in a block.",
+		Value:       config.TxSubmissionMode_PrivateRpc,
+	}, config.ParameterOption{
+		Name:        "Bundle",
+		Description: "Send transactions to a block builder relay as single-transaction bundles with `eth_sendBundle`. They're only included if a builder using the relay wins one of the next few blocks; if none does, the Smartnode daemons resubmit them to the public mempool.",
+		Value:       config.TxSubmissionMode_Bundle,
+	})
+	return options
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/fatih/color"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/submission"
 	"github.com/rocket-pool/smartnode/shared/types/api"
 	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
@@ -29,6 +31,9 @@ type ExecutionClientManager struct {
 	primaryReady    bool
 	fallbackReady   bool
 	ignoreSyncCheck bool
+	submissionMode  cfgtypes.TxSubmissionMode
+	submitters      map[cfgtypes.TxSubmissionMode]submission.Submitter
+	fallbacks       sync.WaitGroup
 }
 
 // This is a signature for a wrapped ethclient.Client function
@@ -78,16 +83,30 @@ func NewExecutionClientManager(cfg *config.RocketPoolConfig) (*ExecutionClientMa
 	}
 
 	out := &ExecutionClientManager{
-		primaryEcUrl:  primaryEcUrl,
-		fallbackEcUrl: fallbackEcUrl,
-		primaryEc:     &ethClient{primaryEc},
-		logger:        log.NewColorLogger(color.FgYellow),
-		primaryReady:  true,
-		fallbackReady: fallbackEc != nil,
+		primaryEcUrl:   primaryEcUrl,
+		fallbackEcUrl:  fallbackEcUrl,
+		primaryEc:      &ethClient{primaryEc},
+		logger:         log.NewColorLogger(color.FgYellow).WithTask("ec-manager"),
+		primaryReady:   true,
+		fallbackReady:  fallbackEc != nil,
+		submissionMode: cfg.Smartnode.TxSubmissionMode.Value.(cfgtypes.TxSubmissionMode),
+		submitters:     map[cfgtypes.TxSubmissionMode]submission.Submitter{},
 	}
 	if fallbackEc != nil {
 		out.fallbackEc = &ethClient{fallbackEc}
 	}
+
+	// Set up the alternative transaction submitters
+	if privateRpcUrl := cfg.Smartnode.GetPrivateRpcUrl(); privateRpcUrl != "" {
+		out.submitters[cfgtypes.TxSubmissionMode_PrivateRpc] = submission.NewPrivateRpcSubmitter(privateRpcUrl)
+	}
+	if bundleRelayUrl := cfg.Smartnode.BundleRelayUrl.Value.(string); bundleRelayUrl != "" {
+		bundleSubmitter, err := submission.NewBundleSubmitter(bundleRelayUrl, out, submission.DefaultBundleBlockCount)
+		if err != nil {
+			return nil, err
+		}
+		out.submitters[cfgtypes.TxSubmissionMode_Bundle] = bundleSubmitter
+	}
 	return out, nil
 
 }
@@ -209,7 +228,56 @@ func (p *ExecutionClientManager) EstimateGas(ctx context.Context, call ethereum.
 }
 
 // SendTransaction injects the transaction into the pending pool for execution.
+// The transaction is submitted with the configured submission mode, unless the context overrides it (see submission.WithMode).
 func (p *ExecutionClientManager) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	mode := p.submissionMode
+	if override, ok := submission.GetMode(ctx); ok {
+		mode = override
+	}
+
+	if mode != cfgtypes.TxSubmissionMode_Public {
+		submitter, exists := p.submitters[mode]
+		if !exists {
+			return fmt.Errorf("transaction submission mode [%s] is selected but isn't configured", mode)
+		}
+		p.logger.Printlnf("Submitting transaction %s with the [%s] submission mode.", tx.Hash().Hex(), mode)
+		expiringSubmitter, ok := submitter.(submission.ExpiringSubmitter)
+		if !ok {
+			return submitter.SendTransaction(ctx, tx)
+		}
+
+		// Fall back to the public mempool if the transaction isn't included by the last block it was submitted for
+		lastBlock, err := expiringSubmitter.SendExpiringTransaction(ctx, tx)
+		if err != nil {
+			return err
+		}
+		p.fallbacks.Add(1)
+		go func() {
+			defer p.fallbacks.Done()
+			sent, err := submission.FallBackAfterBlock(p, tx, lastBlock, submission.FallbackPollInterval, func(tx *types.Transaction) error {
+				return p.sendPublicTransaction(context.Background(), tx)
+			})
+			if err != nil {
+				p.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: couldn't fall back to the public mempool for transaction %s: %s", tx.Hash().Hex(), err.Error())
+			} else if sent {
+				p.logger.Printlnf("Transaction %s wasn't included by block %d, so it was resubmitted to the public mempool.", tx.Hash().Hex(), lastBlock)
+			}
+		}()
+		return nil
+	}
+
+	return p.sendPublicTransaction(ctx, tx)
+}
+
+// Wait until every transaction submitted with an expiring submission mode has either been included or fallen back to the public mempool.
+// Short-lived processes like API calls must call this before they exit, or an expired transaction will never be resent.
+func (p *ExecutionClientManager) WaitForFallbacks() {
+	p.fallbacks.Wait()
+}
+
+// Submit a signed transaction to the public mempool
+func (p *ExecutionClientManager) sendPublicTransaction(ctx context.Context, tx *types.Transaction) error {
+	p.logger.Printlnf("Submitting transaction %s to the public mempool.", tx.Hash().Hex())
 	_, err := p.runFunction(func(client *ethClient) (interface{}, error) {
 		return nil, client.SendTransaction(ctx, tx)
 	})
@@ -489,7 +557,7 @@ func (p *ExecutionClientManager) runFunction(function ecFunction) (interface{},
 		if err != nil {
 			if p.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				p.logger.Printlnf("WARNING: Primary Execution client disconnected (%s), using fallback...", err.Error())
+				p.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Primary Execution client disconnected (%s), using fallback...", err.Error())
 				p.primaryReady = false
 				return p.runFunction(function)
 			}
@@ -508,7 +576,7 @@ func (p *ExecutionClientManager) runFunction(function ecFunction) (interface{},
 		if err != nil {
 			if p.isDisconnected(err) {
 				// If it's disconnected, log it and try the fallback
-				p.logger.Printlnf("WARNING: Fallback Execution client disconnected (%s)", err.Error())
+				p.logger.WithLevel(log.LevelWarn).Printlnf("WARNING: Fallback Execution client disconnected (%s)", err.Error())
 				p.fallbackReady = false
 				return nil, fmt.Errorf("all Execution clients failed")
 			}
@@ -0,0 +1,292 @@
+package keymanager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+
+	hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+// Config
+const (
+	RequestUrlFormat       = "%s%s"
+	RequestJsonContentType = "application/json"
+
+	RequestKeystoresPath    = "/eth/v1/keystores"
+	RequestRemoteKeysPath   = "/eth/v1/remotekeys"
+	RequestFeeRecipientPath = "/eth/v1/validator/%s/feerecipient"
+
+	requestTimeout = 30 * time.Second
+)
+
+// Client for a validator client's Keymanager API (https://ethereum.github.io/keymanager-APIs/)
+type Client struct {
+	providerAddress string
+	token           string
+	tokenPath       string
+	client          http.Client
+}
+
+// Create a new client instance.
+// The token can be left blank for servers that don't require authentication (e.g. Web3Signer).
+func NewClient(providerAddress string, token string) *Client {
+	return &Client{
+		providerAddress: providerAddress,
+		token:           token,
+		client: http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+// Create a new client instance that authenticates with the token in the provided file.
+// The file is read on each request, since the validator client creates it when it starts and may replace it.
+func NewClientFromTokenFile(providerAddress string, tokenPath string) *Client {
+	client := NewClient(providerAddress, "")
+	client.tokenPath = tokenPath
+	return client
+}
+
+// Get the local keystores the validator client is using
+func (c *Client) ListKeystores() ([]Keystore, error) {
+	responseBody, status, err := c.request(http.MethodGet, RequestKeystoresPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not list keystores: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not list keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response ListKeystoresResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode keystore list: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Import EIP-2335 keystores into the validator client, with optional EIP-3076 slashing protection data
+func (c *Client) ImportKeystores(keystores []string, passwords []string, slashingProtection string) ([]KeyStatusResult, error) {
+	if len(keystores) != len(passwords) {
+		return nil, fmt.Errorf("Could not import keystores: got %d keystores but %d passwords", len(keystores), len(passwords))
+	}
+	request := ImportKeystoresRequest{
+		Keystores:          keystores,
+		Passwords:          passwords,
+		SlashingProtection: slashingProtection,
+	}
+	responseBody, status, err := c.request(http.MethodPost, RequestKeystoresPath, request)
+	if err != nil {
+		return nil, fmt.Errorf("Could not import keystores: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not import keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response KeyStatusResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode keystore import response: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Remove local keystores from the validator client.
+// Returns the EIP-3076 slashing protection data of the removed keys, which should be kept in case they're used to validate again.
+func (c *Client) DeleteKeystores(pubkeys []types.ValidatorPubkey) ([]DeleteKeyResult, string, error) {
+	request := DeleteKeysRequest{
+		Pubkeys: formatPubkeys(pubkeys),
+	}
+	responseBody, status, err := c.request(http.MethodDelete, RequestKeystoresPath, request)
+	if err != nil {
+		return nil, "", fmt.Errorf("Could not delete keystores: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, "", fmt.Errorf("Could not delete keystores: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response DeleteKeystoresResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, "", fmt.Errorf("Could not decode keystore delete response: %w", err)
+	}
+	return response.Data, response.SlashingProtection, nil
+}
+
+// Get the remote signer keys the validator client is using
+func (c *Client) ListRemoteKeys() ([]RemoteKey, error) {
+	responseBody, status, err := c.request(http.MethodGet, RequestRemoteKeysPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not list remote keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not list remote keys: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response ListRemoteKeysResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode remote key list: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Register keys held by a remote signer with the validator client
+func (c *Client) ImportRemoteKeys(pubkeys []types.ValidatorPubkey, url string) ([]KeyStatusResult, error) {
+	request := ImportRemoteKeysRequest{
+		RemoteKeys: make([]RemoteKey, len(pubkeys)),
+	}
+	for i, pubkey := range formatPubkeys(pubkeys) {
+		request.RemoteKeys[i] = RemoteKey{
+			Pubkey: pubkey,
+			Url:    url,
+		}
+	}
+	responseBody, status, err := c.request(http.MethodPost, RequestRemoteKeysPath, request)
+	if err != nil {
+		return nil, fmt.Errorf("Could not import remote keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not import remote keys: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response KeyStatusResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode remote key import response: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Remove keys held by a remote signer from the validator client
+func (c *Client) DeleteRemoteKeys(pubkeys []types.ValidatorPubkey) ([]DeleteKeyResult, error) {
+	request := DeleteKeysRequest{
+		Pubkeys: formatPubkeys(pubkeys),
+	}
+	responseBody, status, err := c.request(http.MethodDelete, RequestRemoteKeysPath, request)
+	if err != nil {
+		return nil, fmt.Errorf("Could not delete remote keys: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Could not delete remote keys: HTTP status %d; response body: '%s'", status, string(responseBody))
+	}
+	var response DeleteRemoteKeysResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("Could not decode remote key delete response: %w", err)
+	}
+	return response.Data, nil
+}
+
+// Get the fee recipient the validator client is using for a key
+func (c *Client) GetFeeRecipient(pubkey types.ValidatorPubkey) (common.Address, error) {
+	responseBody, status, err := c.request(http.MethodGet, fmt.Sprintf(RequestFeeRecipientPath, hexutil.AddPrefix(pubkey.Hex())), nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("Could not get fee recipient for validator %s: %w", pubkey.Hex(), err)
+	}
+	if status != http.StatusOK {
+		return common.Address{}, fmt.Errorf("Could not get fee recipient for validator %s: HTTP status %d; response body: '%s'", pubkey.Hex(), status, string(responseBody))
+	}
+	var response FeeRecipientResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return common.Address{}, fmt.Errorf("Could not decode fee recipient for validator %s: %w", pubkey.Hex(), err)
+	}
+	return response.Data.EthAddress, nil
+}
+
+// Set the fee recipient the validator client should use for a key
+func (c *Client) SetFeeRecipient(pubkey types.ValidatorPubkey, feeRecipient common.Address) error {
+	request := SetFeeRecipientRequest{
+		EthAddress: feeRecipient,
+	}
+	responseBody, status, err := c.request(http.MethodPost, fmt.Sprintf(RequestFeeRecipientPath, hexutil.AddPrefix(pubkey.Hex())), request)
+	if err != nil {
+		return fmt.Errorf("Could not set fee recipient for validator %s: %w", pubkey.Hex(), err)
+	}
+	if status != http.StatusAccepted && status != http.StatusOK {
+		return fmt.Errorf("Could not set fee recipient for validator %s: HTTP status %d; response body: '%s'", pubkey.Hex(), status, string(responseBody))
+	}
+	return nil
+}
+
+// Parse a pubkey returned by the Keymanager API
+func ParsePubkey(pubkey string) (types.ValidatorPubkey, error) {
+	return types.HexToValidatorPubkey(hexutil.RemovePrefix(pubkey))
+}
+
+// Format pubkeys as the 0x-prefixed hex strings the Keymanager API requires
+func formatPubkeys(pubkeys []types.ValidatorPubkey) []string {
+	formatted := make([]string, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		formatted[i] = hexutil.AddPrefix(pubkey.Hex())
+	}
+	return formatted
+}
+
+// Make an authenticated request to the Keymanager API and read the body of the response
+func (c *Client) request(method string, requestPath string, requestBody interface{}) ([]byte, int, error) {
+
+	// Get request body
+	var requestBodyReader io.Reader
+	if requestBody != nil {
+		requestBodyBytes, err := json.Marshal(requestBody)
+		if err != nil {
+			return []byte{}, 0, err
+		}
+		requestBodyReader = bytes.NewReader(requestBodyBytes)
+	}
+
+	// Get the token
+	token := c.token
+	if c.tokenPath != "" {
+		var err error
+		token, err = readTokenFile(c.tokenPath)
+		if err != nil {
+			return []byte{}, 0, err
+		}
+	}
+
+	// Send request
+	request, err := http.NewRequest(method, fmt.Sprintf(RequestUrlFormat, c.providerAddress, requestPath), requestBodyReader)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	request.Header.Set("Accept", RequestJsonContentType)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	if requestBody != nil {
+		request.Header.Set("Content-Type", RequestJsonContentType)
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	// Get response
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return []byte{}, 0, err
+	}
+
+	// Return
+	return body, response.StatusCode, nil
+
+}
+
+// Read the API token from a validator client's token file
+func readTokenFile(tokenPath string) (string, error) {
+	bytes, err := os.ReadFile(tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("could not read Keymanager API token file [%s]: %w", tokenPath, err)
+	}
+
+	// Some clients (e.g. Prysm) rewrite the token file with the API URL on the first line, so the token is always the last line
+	lines := strings.Split(strings.TrimSpace(string(bytes)), "\n")
+	token := strings.TrimSpace(lines[len(lines)-1])
+	if token == "" {
+		return "", fmt.Errorf("Keymanager API token file [%s] is empty", tokenPath)
+	}
+	return token, nil
+}
@@ -0,0 +1,89 @@
+package keymanager
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The status of an individual key after an import request
+type KeyStatus string
+
+const (
+	KeyStatus_Imported  KeyStatus = "imported"
+	KeyStatus_Duplicate KeyStatus = "duplicate"
+	KeyStatus_Error     KeyStatus = "error"
+)
+
+// The result of an import for a single key
+type KeyStatusResult struct {
+	Status  KeyStatus `json:"status"`
+	Message string    `json:"message,omitempty"`
+}
+
+// The status of an individual key after a delete request
+type DeleteKeyStatus string
+
+const (
+	DeleteKeyStatus_Deleted   DeleteKeyStatus = "deleted"
+	DeleteKeyStatus_NotActive DeleteKeyStatus = "not_active"
+	DeleteKeyStatus_NotFound  DeleteKeyStatus = "not_found"
+	DeleteKeyStatus_Error     DeleteKeyStatus = "error"
+)
+
+// The result of a delete for a single key
+type DeleteKeyResult struct {
+	Status  DeleteKeyStatus `json:"status"`
+	Message string          `json:"message,omitempty"`
+}
+
+// A local keystore that the validator client is currently using.
+// Pubkeys use 0x-prefixed hex strings since that's what the Keymanager API requires.
+type Keystore struct {
+	Pubkey         string `json:"validating_pubkey"`
+	DerivationPath string `json:"derivation_path,omitempty"`
+	ReadOnly       bool   `json:"readonly,omitempty"`
+}
+
+// A key that the validator client is currently using through a remote signer
+type RemoteKey struct {
+	Pubkey   string `json:"pubkey"`
+	Url      string `json:"url,omitempty"`
+	ReadOnly bool   `json:"readonly,omitempty"`
+}
+
+// Request / response types
+type ListKeystoresResponse struct {
+	Data []Keystore `json:"data"`
+}
+type ImportKeystoresRequest struct {
+	Keystores          []string `json:"keystores"`
+	Passwords          []string `json:"passwords"`
+	SlashingProtection string   `json:"slashing_protection,omitempty"`
+}
+type ListRemoteKeysResponse struct {
+	Data []RemoteKey `json:"data"`
+}
+type ImportRemoteKeysRequest struct {
+	RemoteKeys []RemoteKey `json:"remote_keys"`
+}
+type KeyStatusResponse struct {
+	Data []KeyStatusResult `json:"data"`
+}
+type DeleteKeysRequest struct {
+	Pubkeys []string `json:"pubkeys"`
+}
+type DeleteKeystoresResponse struct {
+	Data               []DeleteKeyResult `json:"data"`
+	SlashingProtection string            `json:"slashing_protection"`
+}
+type DeleteRemoteKeysResponse struct {
+	Data []DeleteKeyResult `json:"data"`
+}
+type FeeRecipientResponse struct {
+	Data struct {
+		Pubkey     string         `json:"pubkey"`
+		EthAddress common.Address `json:"ethaddress"`
+	} `json:"data"`
+}
+type SetFeeRecipientRequest struct {
+	EthAddress common.Address `json:"ethaddress"`
+}
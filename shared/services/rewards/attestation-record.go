@@ -0,0 +1,204 @@
+package rewards
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+)
+
+// A range of epochs, inclusive on both ends
+type EpochRange struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// The attestation duties the node daemon checked for one of its validators
+type RecordedValidatorDuties struct {
+	CheckedEpochs []EpochRange `json:"checkedEpochs"`
+	MissedSlots   []uint64     `json:"missedSlots"`
+}
+
+// The node daemon's own record of its validators' attestations during a rewards interval, built from the blocks it saw.
+// Attestations count as missed under the same rule the rewards tree uses (not included within an epoch of their slot),
+// so the record can be used to check the attestation performance a rewards tree was built from.
+type AttestationRecord struct {
+	Interval      uint64                              `json:"interval"`
+	SlotsPerEpoch uint64                              `json:"slotsPerEpoch"`
+	Validators    map[string]*RecordedValidatorDuties `json:"validators"`
+}
+
+// How a validator's attestation performance in a minipool performance file compares with the node's own record of it
+type AttestationRecordComparison struct {
+	Address common.Address        `json:"address"`
+	Pubkey  types.ValidatorPubkey `json:"pubkey"`
+
+	// The number of the interval's epochs the node's record covers
+	CheckedEpochs uint64 `json:"checkedEpochs"`
+
+	// Slots the performance file says were missed, but the record shows were attested in time
+	DisputedSlots []uint64 `json:"disputedSlots"`
+
+	// Slots the record shows were missed, but the performance file doesn't penalize.
+	// These are expected for duties the validator had while it wasn't eligible for Smoothing Pool rewards.
+	UnpenalizedSlots []uint64 `json:"unpenalizedSlots"`
+}
+
+// Create a new, empty attestation record for an interval
+func NewAttestationRecord(interval uint64, slotsPerEpoch uint64) *AttestationRecord {
+	return &AttestationRecord{
+		Interval:      interval,
+		SlotsPerEpoch: slotsPerEpoch,
+		Validators:    map[string]*RecordedValidatorDuties{},
+	}
+}
+
+// Load an attestation record from disk. Returns nil if there isn't one.
+func LoadAttestationRecord(path string) (*AttestationRecord, error) {
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading attestation record %s: %w", path, err)
+	}
+	record := new(AttestationRecord)
+	err = json.Unmarshal(bytes, record)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing attestation record %s: %w", path, err)
+	}
+	if record.Validators == nil {
+		record.Validators = map[string]*RecordedValidatorDuties{}
+	}
+	return record, nil
+}
+
+// Save the record to disk, replacing the previous one atomically so a crash never leaves a partial record behind
+func (r *AttestationRecord) Save(path string) error {
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("error serializing attestation record: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating attestation record folder: %w", err)
+	}
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing attestation record to %s: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error moving attestation record to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Record the result of a validator's attestation duty in an epoch; missedSlot is nil if it was attested in time.
+// Epochs that were already recorded for the validator are ignored.
+func (r *AttestationRecord) AddDuty(pubkey types.ValidatorPubkey, epoch uint64, missedSlot *uint64) {
+	duties, exists := r.Validators[pubkey.Hex()]
+	if !exists {
+		duties = &RecordedValidatorDuties{
+			CheckedEpochs: []EpochRange{},
+			MissedSlots:   []uint64{},
+		}
+		r.Validators[pubkey.Hex()] = duties
+	}
+
+	for _, epochs := range duties.CheckedEpochs {
+		if epoch >= epochs.Start && epoch <= epochs.End {
+			return
+		}
+	}
+	last := len(duties.CheckedEpochs) - 1
+	if last >= 0 && duties.CheckedEpochs[last].End+1 == epoch {
+		duties.CheckedEpochs[last].End = epoch
+	} else {
+		duties.CheckedEpochs = append(duties.CheckedEpochs, EpochRange{Start: epoch, End: epoch})
+	}
+	if missedSlot != nil {
+		duties.MissedSlots = append(duties.MissedSlots, *missedSlot)
+	}
+}
+
+// Compare the attestation performance of the provided validators in a minipool performance file with the node's own records of it.
+// Records for the intervals on either side can be included, since duties near an interval's boundary may have been recorded in either one;
+// only the slots within the rewards file's consensus range are compared.
+func CompareAttestationRecords(rewardsFile IRewardsFile, performanceFile IMinipoolPerformanceFile, validators []common.Address, records []*AttestationRecord) ([]AttestationRecordComparison, error) {
+	startSlot := rewardsFile.GetConsensusStartBlock()
+	endSlot := rewardsFile.GetConsensusEndBlock()
+
+	comparisons := []AttestationRecordComparison{}
+	for _, validator := range validators {
+		performance, exists := performanceFile.GetSmoothingPoolPerformance(validator)
+		if !exists {
+			continue
+		}
+		pubkey, err := performance.GetPubkey()
+		if err != nil {
+			return nil, fmt.Errorf("error getting pubkey for validator %s: %w", validator.Hex(), err)
+		}
+
+		// Merge the records for the validator
+		checked := map[uint64]bool{}
+		missed := map[uint64]bool{}
+		for _, record := range records {
+			duties, exists := record.Validators[pubkey.Hex()]
+			if !exists {
+				continue
+			}
+			for _, epochs := range duties.CheckedEpochs {
+				for epoch := epochs.Start; epoch <= epochs.End; epoch++ {
+					slot := epoch * record.SlotsPerEpoch
+					if slot >= startSlot && slot <= endSlot {
+						checked[slot] = true
+					}
+				}
+			}
+			for _, slot := range duties.MissedSlots {
+				if slot >= startSlot && slot <= endSlot {
+					missed[slot] = true
+				}
+			}
+		}
+		if len(checked) == 0 {
+			continue
+		}
+		slotsPerEpoch := records[0].SlotsPerEpoch
+
+		comparison := AttestationRecordComparison{
+			Address:          validator,
+			Pubkey:           pubkey,
+			CheckedEpochs:    uint64(len(checked)),
+			DisputedSlots:    []uint64{},
+			UnpenalizedSlots: []uint64{},
+		}
+		fileMissed := map[uint64]bool{}
+		for _, slot := range performance.GetMissingAttestationSlots() {
+			fileMissed[slot] = true
+			epochStart := slot - slot%slotsPerEpoch
+			if checked[epochStart] && !missed[slot] {
+				comparison.DisputedSlots = append(comparison.DisputedSlots, slot)
+			}
+		}
+		for slot := range missed {
+			if !fileMissed[slot] {
+				comparison.UnpenalizedSlots = append(comparison.UnpenalizedSlots, slot)
+			}
+		}
+		sort.Slice(comparison.DisputedSlots, func(i, j int) bool {
+			return comparison.DisputedSlots[i] < comparison.DisputedSlots[j]
+		})
+		sort.Slice(comparison.UnpenalizedSlots, func(i, j int) bool {
+			return comparison.UnpenalizedSlots[i] < comparison.UnpenalizedSlots[j]
+		})
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons, nil
+}
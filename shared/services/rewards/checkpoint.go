@@ -0,0 +1,130 @@
+package rewards
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+)
+
+const (
+	// The version of the generation checkpoint format; bump this to invalidate old checkpoints when it changes
+	generationCheckpointVersion uint64 = 1
+
+	// The default number of epochs to process between checkpoints
+	defaultCheckpointEpochInterval uint64 = 100
+)
+
+// Identifies the interval and snapshot a checkpoint was taken for; a checkpoint is only valid if all of these match
+type generationCheckpointHeader struct {
+	Version             uint64 `json:"version"`
+	Index               uint64 `json:"index"`
+	RulesetVersion      uint64 `json:"rulesetVersion"`
+	SnapshotEndSlot     uint64 `json:"snapshotEndSlot"`
+	ConsensusStartBlock uint64 `json:"consensusStartBlock"`
+	ConsensusEndBlock   uint64 `json:"consensusEndBlock"`
+}
+
+// A minipool's attestation tallies at the time of a checkpoint
+type minipoolCheckpoint struct {
+	GoodAttestations        uint64        `json:"goodAttestations,omitempty"`
+	AttestationScore        *QuotedBigInt `json:"attestationScore,omitempty"`
+	MissingAttestationSlots []uint64      `json:"missingAttestationSlots,omitempty"`
+}
+
+// The attestation duties for a slot that could still be fulfilled at the time of a checkpoint
+type pendingDutiesCheckpoint struct {
+	Slot           uint64                    `json:"slot"`
+	CommitteeSizes map[uint64]int            `json:"committeeSizes"`
+	Committees     map[uint64]map[int]string `json:"committees"`
+}
+
+// A snapshot of a tree generator's progress through an interval's attestation processing
+type generationCheckpoint struct {
+	generationCheckpointHeader
+
+	// The last epoch that was fully processed
+	LastProcessedEpoch uint64 `json:"lastProcessedEpoch"`
+
+	// Running totals
+	TotalAttestationScore  *QuotedBigInt `json:"totalAttestationScore"`
+	SuccessfulAttestations uint64        `json:"successfulAttestations"`
+
+	// Per-minipool tallies, keyed by validator index
+	Minipools map[string]*minipoolCheckpoint `json:"minipools"`
+
+	// Duties that haven't been resolved yet
+	PendingDuties []pendingDutiesCheckpoint `json:"pendingDuties"`
+
+	// Eligible withdrawals made by each minipool's validator
+	MinipoolWithdrawals map[common.Address]*QuotedBigInt `json:"minipoolWithdrawals"`
+
+	// The on-disk duty records and how many of them were written at the time of the checkpoint, if spilling is enabled
+	DutyRecordsPath  string `json:"dutyRecordsPath,omitempty"`
+	DutyRecordsCount uint64 `json:"dutyRecordsCount,omitempty"`
+}
+
+// Get the path of the checkpoint for an interval and ruleset within the checkpoint folder
+func getGenerationCheckpointPath(folder string, index uint64, rulesetVersion uint64) string {
+	return filepath.Join(folder, fmt.Sprintf("checkpoint-%d-v%d.json", index, rulesetVersion))
+}
+
+// Load a checkpoint from disk. Returns nil if there isn't one; checkpoints taken for a different snapshot are deleted.
+func loadGenerationCheckpoint(path string, header generationCheckpointHeader) (*generationCheckpoint, error) {
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint %s: %w", path, err)
+	}
+
+	var checkpoint generationCheckpoint
+	err = json.Unmarshal(bytes, &checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing checkpoint %s: %w", path, err)
+	}
+	if checkpoint.generationCheckpointHeader != header {
+		return nil, checkpoint.delete(path)
+	}
+	return &checkpoint, nil
+}
+
+// Delete the checkpoint and the duty records it refers to
+func (c *generationCheckpoint) delete(path string) error {
+	if c.DutyRecordsPath != "" {
+		err := os.Remove(c.DutyRecordsPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error deleting duty records %s: %w", c.DutyRecordsPath, err)
+		}
+	}
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting checkpoint %s: %w", path, err)
+	}
+	return nil
+}
+
+// Save the checkpoint to disk, replacing the previous one atomically so a crash never leaves a partial checkpoint behind
+func (c *generationCheckpoint) save(path string) error {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error serializing checkpoint: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating checkpoint folder: %w", err)
+	}
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing checkpoint to %s: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("error moving checkpoint to %s: %w", path, err)
+	}
+	return nil
+}
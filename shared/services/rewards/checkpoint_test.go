@@ -0,0 +1,145 @@
+package rewards
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/rewards/test"
+)
+
+// A beacon client that fails the first time the committees for a chosen epoch are requested, and counts how many epochs were requested
+type flakyBeaconClient struct {
+	*test.MockBeaconClient
+	failEpoch uint64
+	failed    bool
+	requested uint64
+	lock      sync.Mutex
+}
+
+func (bc *flakyBeaconClient) GetCommitteesForEpoch(epoch *uint64) (beacon.Committees, error) {
+	bc.lock.Lock()
+	bc.requested++
+	if epoch != nil && *epoch == bc.failEpoch && !bc.failed {
+		bc.failed = true
+		bc.lock.Unlock()
+		return nil, errors.New("beacon node unavailable")
+	}
+	bc.lock.Unlock()
+	return bc.MockBeaconClient.GetCommitteesForEpoch(epoch)
+}
+
+// Make sure a generation that fails partway through resumes from its last checkpoint and produces the same files as an uninterrupted one
+func TestResumeFromCheckpoint(t *testing.T) {
+	for _, rulesetVersion := range []uint64{9, 10} {
+		for _, spill := range []bool{false, true} {
+			t.Run(fmt.Sprintf("v%d-spill=%t", rulesetVersion, spill), func(t *testing.T) {
+				checkpointFolder := t.TempDir()
+				spillFolder := ""
+				if spill {
+					spillFolder = t.TempDir()
+				}
+
+				// Uninterrupted run to compare against
+				baseline := generateMockTree(t, rulesetVersion, spillFolder)
+				startEpoch := baseline.RewardsFile.GetConsensusStartBlock() / 32
+				endEpoch := baseline.RewardsFile.GetConsensusEndBlock() / 32
+
+				// Fail partway through the interval
+				v8, generator := newMockTreeGenerator(t, rulesetVersion)
+				generator.setDutySpillFolder(spillFolder)
+				generator.setCheckpointFolder(checkpointFolder)
+				generator.checkpointEpochInterval = 10
+				bc := &flakyBeaconClient{
+					MockBeaconClient: v8.bc,
+					failEpoch:        startEpoch + 35,
+				}
+				_, err := generator.generateTree(v8.rp, "mainnet", make([]common.Address, 0), bc)
+				if err == nil {
+					t.Fatal("expected the first generation to fail")
+				}
+				checkpointPath := getGenerationCheckpointPath(checkpointFolder, generator.rewardsFile.Index, rulesetVersion)
+				if _, err := os.Stat(checkpointPath); err != nil {
+					t.Fatalf("expected a checkpoint to be saved: %s", err.Error())
+				}
+
+				// Resume with a fresh generator
+				v8, generator = newMockTreeGenerator(t, rulesetVersion)
+				generator.setDutySpillFolder(spillFolder)
+				generator.setCheckpointFolder(checkpointFolder)
+				generator.checkpointEpochInterval = 10
+				bc = &flakyBeaconClient{MockBeaconClient: v8.bc}
+				resumed, err := generator.generateTree(v8.rp, "mainnet", make([]common.Address, 0), bc)
+				v8.failIf(err)
+
+				// The last checkpoint before the failure was taken after the 30th epoch
+				expectedRequests := endEpoch + 1 - (startEpoch + 30)
+				if bc.requested != expectedRequests {
+					t.Fatalf("expected committees for %d epochs after resuming, got %d", expectedRequests, bc.requested)
+				}
+				requireIdenticalFiles(t, baseline, resumed)
+
+				// Everything should be cleaned up after a successful run
+				for _, folder := range []string{checkpointFolder, spillFolder} {
+					if folder == "" {
+						continue
+					}
+					entries, err := os.ReadDir(folder)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(entries) != 0 {
+						t.Fatalf("expected %s to be empty, found %d files", folder, len(entries))
+					}
+				}
+			})
+		}
+	}
+}
+
+// Make sure checkpoints are discarded once the snapshot they were taken for changes
+func TestCheckpointInvalidatedBySnapshotChange(t *testing.T) {
+	folder := t.TempDir()
+	path := getGenerationCheckpointPath(folder, 5, 10)
+	header := generationCheckpointHeader{
+		Version:             generationCheckpointVersion,
+		Index:               5,
+		RulesetVersion:      10,
+		SnapshotEndSlot:     1000,
+		ConsensusStartBlock: 100,
+		ConsensusEndBlock:   1000,
+	}
+	checkpoint := &generationCheckpoint{
+		generationCheckpointHeader: header,
+		LastProcessedEpoch:         20,
+		TotalAttestationScore:      NewQuotedBigInt(12),
+		SuccessfulAttestations:     3,
+	}
+	if err := checkpoint.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadGenerationCheckpoint(path, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded == nil || loaded.LastProcessedEpoch != 20 || loaded.TotalAttestationScore.Uint64() != 12 {
+		t.Fatalf("expected the checkpoint to be loaded, got %+v", loaded)
+	}
+
+	header.SnapshotEndSlot = 1032
+	loaded, err = loadGenerationCheckpoint(path, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != nil {
+		t.Fatal("expected the checkpoint to be invalidated by the new snapshot end slot")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("expected the stale checkpoint to be deleted")
+	}
+}
@@ -0,0 +1,170 @@
+package rewards
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The size of a single serialized duty record: slot (8 bytes), validator index (8 bytes), outcome (1 byte)
+const dutyRecordSize int = 17
+
+// The outcome of a single attestation duty
+type dutyOutcome uint8
+
+const (
+	dutyOutcome_Attested dutyOutcome = 1
+	dutyOutcome_Missed   dutyOutcome = 2
+)
+
+// The final outcome of a minipool's attestation duty for a slot
+type dutyRecord struct {
+	Slot           uint64
+	ValidatorIndex uint64
+	Outcome        dutyOutcome
+}
+
+// An append-only log of attestation duty outcomes kept in a temporary file, so the per-minipool duty records of an interval
+// don't have to be held in memory during tree generation. Records are streamed back in the order they were written.
+type dutyStore struct {
+	file   *os.File
+	writer *bufio.Writer
+	count  uint64
+
+	// Keep the file when the store is closed so it can be reopened from a checkpoint
+	keep bool
+}
+
+// Create a new duty store in a temporary file within the provided folder
+func newDutyStore(folder string, index uint64, rulesetVersion uint64) (*dutyStore, error) {
+	err := os.MkdirAll(folder, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating duty records folder %s: %w", folder, err)
+	}
+	file, err := os.CreateTemp(folder, fmt.Sprintf("duties-%d-v%d-*.bin", index, rulesetVersion))
+	if err != nil {
+		return nil, fmt.Errorf("error creating duty records file in %s: %w", folder, err)
+	}
+	return &dutyStore{
+		file:   file,
+		writer: bufio.NewWriter(file),
+	}, nil
+}
+
+// Reopen an existing duty store, discarding any records written after the first count of them
+func openDutyStore(path string, count uint64) (*dutyStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening duty records file %s: %w", path, err)
+	}
+	err = file.Truncate(int64(count) * int64(dutyRecordSize))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error truncating duty records file %s: %w", path, err)
+	}
+	_, err = file.Seek(0, io.SeekEnd)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error seeking to the end of duty records file %s: %w", path, err)
+	}
+	return &dutyStore{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		count:  count,
+	}, nil
+}
+
+// Get the path of the store's file
+func (s *dutyStore) path() string {
+	return s.file.Name()
+}
+
+// Write any buffered records to disk
+func (s *dutyStore) flush() error {
+	err := s.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("error flushing duty records to %s: %w", s.file.Name(), err)
+	}
+	return nil
+}
+
+// Append a record to the store
+func (s *dutyStore) write(record dutyRecord) error {
+	var buffer [dutyRecordSize]byte
+	binary.BigEndian.PutUint64(buffer[0:8], record.Slot)
+	binary.BigEndian.PutUint64(buffer[8:16], record.ValidatorIndex)
+	buffer[16] = byte(record.Outcome)
+	_, err := s.writer.Write(buffer[:])
+	if err != nil {
+		return fmt.Errorf("error writing duty record to %s: %w", s.file.Name(), err)
+	}
+	s.count++
+	return nil
+}
+
+// Stream every record in the store back through the provided callback, in the order they were written
+func (s *dutyStore) forEach(callback func(record dutyRecord) error) error {
+	err := s.flush()
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("error rewinding duty records file %s: %w", s.file.Name(), err)
+	}
+
+	reader := bufio.NewReader(s.file)
+	var buffer [dutyRecordSize]byte
+	for {
+		_, err := io.ReadFull(reader, buffer[:])
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading duty records from %s: %w", s.file.Name(), err)
+		}
+		record := dutyRecord{
+			Slot:           binary.BigEndian.Uint64(buffer[0:8]),
+			ValidatorIndex: binary.BigEndian.Uint64(buffer[8:16]),
+			Outcome:        dutyOutcome(buffer[16]),
+		}
+		err = callback(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Go back to the end so new records are appended
+	_, err = s.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("error seeking to the end of duty records file %s: %w", s.file.Name(), err)
+	}
+	return nil
+}
+
+// Close the store and delete its file, unless it's being kept for a checkpoint
+func (s *dutyStore) close() error {
+	path := s.file.Name()
+	if s.keep {
+		err := s.flush()
+		if err != nil {
+			s.file.Close()
+			return err
+		}
+	}
+	err := s.file.Close()
+	if err != nil {
+		return fmt.Errorf("error closing duty records file %s: %w", path, err)
+	}
+	if s.keep {
+		return nil
+	}
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("error deleting duty records file %s: %w", path, err)
+	}
+	return nil
+}
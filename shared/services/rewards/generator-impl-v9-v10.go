@@ -3,8 +3,11 @@ package rewards
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
+	"os"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -43,6 +46,10 @@ type treeGeneratorImpl_v9_v10 struct {
 	nodeDetails                  []*NodeSmoothingDetails
 	smoothingPoolBalance         *big.Int
 	intervalDutiesInfo           *IntervalDutiesInfo
+	dutySpillFolder              string
+	dutyStore                    *dutyStore
+	checkpointFolder             string
+	checkpointEpochInterval      uint64
 	slotsPerEpoch                uint64
 	validatorIndexMap            map[string]*MinipoolInfo
 	elStartTime                  time.Time
@@ -109,6 +116,17 @@ func (r *treeGeneratorImpl_v9_v10) getRulesetVersion() uint64 {
 	return r.rewardsFile.RulesetVersion
 }
 
+// Spill attestation duty records to the provided folder during generation instead of keeping them in memory
+func (r *treeGeneratorImpl_v9_v10) setDutySpillFolder(folder string) {
+	r.dutySpillFolder = folder
+}
+
+// Save checkpoints of the attestation processing to the provided folder, and resume from them
+func (r *treeGeneratorImpl_v9_v10) setCheckpointFolder(folder string) {
+	r.checkpointFolder = folder
+	r.checkpointEpochInterval = defaultCheckpointEpochInterval
+}
+
 func (r *treeGeneratorImpl_v9_v10) generateTree(rp RewardsExecutionClient, networkName string, previousRewardsPoolAddresses []common.Address, bc RewardsBeaconClient) (*GenerateTreeResult, error) {
 
 	r.log.Printlnf("%s Generating tree using Ruleset v%d.", r.logPrefix, r.rewardsFile.RulesetVersion)
@@ -501,6 +519,9 @@ func (r *treeGeneratorImpl_v9_v10) calculateEthRewards(checkBeaconPerformance bo
 		if err != nil {
 			return err
 		}
+		if r.dutyStore != nil {
+			defer r.closeDutyStore(false)
+		}
 	} else {
 		// Attestation processing is disabled, just give each minipool 1 good attestation and complete slot activity so they're all scored the same
 		// Used for approximating rETH's share during balances calculation
@@ -511,7 +532,7 @@ func (r *treeGeneratorImpl_v9_v10) calculateEthRewards(checkBeaconPerformance bo
 				eligibleBorrowedEth := nodeInfo.EligibleBorrowedEth
 				_, percentOfBorrowedEth := r.networkState.GetStakedRplValueInEthAndPercentOfBorrowedEth(eligibleBorrowedEth, nodeInfo.RplStake)
 				for _, minipool := range nodeInfo.Minipools {
-					minipool.CompletedAttestations = map[uint64]bool{0: true}
+					minipool.GoodAttestations = 1
 
 					// Make up an attestation
 					details := r.networkState.MinipoolDetailsByAddress[minipool.Address]
@@ -568,8 +589,8 @@ func (r *treeGeneratorImpl_v9_v10) calculateEthRewards(checkBeaconPerformance bo
 
 			// Add minipool rewards to the JSON
 			for _, minipoolInfo := range nodeInfo.Minipools {
-				successfulAttestations := uint64(len(minipoolInfo.CompletedAttestations))
-				missingAttestations := uint64(len(minipoolInfo.MissingAttestationSlots))
+				successfulAttestations := minipoolInfo.GoodAttestations
+				missingAttestations := uint64(len(minipoolInfo.MissingAttestationSlots)) + minipoolInfo.MissedAttestations
 				performance := &SmoothingPoolMinipoolPerformance_v2{
 					Pubkey:                  minipoolInfo.ValidatorPubkey.Hex(),
 					SuccessfulAttestations:  successfulAttestations,
@@ -601,6 +622,14 @@ func (r *treeGeneratorImpl_v9_v10) calculateEthRewards(checkBeaconPerformance bo
 		}
 	}
 
+	// Write the spilled missed attestations straight into the performance file
+	if r.dutyStore != nil {
+		err = r.writeSpilledMissedSlots()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Set the totals
 	r.rewardsFile.TotalRewards.PoolStakerSmoothingPoolEth.Set(poolStakerETH)
 	r.rewardsFile.TotalRewards.NodeOperatorSmoothingPoolEth.Set(nodeOpEth)
@@ -710,7 +739,7 @@ func (r *treeGeneratorImpl_v9_v10) calculateNodeRewards() (*big.Int, *big.Int, *
 			continue
 		}
 		for _, minipool := range nodeInfo.Minipools {
-			if len(minipool.CompletedAttestations)+len(minipool.MissingAttestationSlots) == 0 || !minipool.WasActive {
+			if minipool.GoodAttestations+uint64(len(minipool.MissingAttestationSlots))+minipool.MissedAttestations == 0 || !minipool.WasActive {
 				// Ignore minipools that weren't active for the interval
 				minipool.WasActive = false
 				minipool.MinipoolShare = big.NewInt(0)
@@ -781,24 +810,60 @@ func (r *treeGeneratorImpl_v9_v10) calculateNodeRewards() (*big.Int, *big.Int, *
 }
 
 // Get all of the duties for a range of epochs
-func (r *treeGeneratorImpl_v9_v10) processAttestationsBalancesAndWithdrawalsForInterval() error {
+func (r *treeGeneratorImpl_v9_v10) processAttestationsBalancesAndWithdrawalsForInterval() (err error) {
 
 	startEpoch := r.rewardsFile.ConsensusStartBlock / r.beaconConfig.SlotsPerEpoch
 	endEpoch := r.rewardsFile.ConsensusEndBlock / r.beaconConfig.SlotsPerEpoch
 
 	// Determine the validator indices of each minipool
-	err := r.createMinipoolIndexMap()
+	err = r.createMinipoolIndexMap()
 	if err != nil {
 		return err
 	}
 
+	// Pick up where a previous attempt left off if there's a checkpoint for this snapshot
+	firstEpoch := startEpoch
+	checkpoint, checkpointPath := r.resumeFromCheckpoint()
+	checkpointSaved := checkpoint != nil
+	if checkpoint != nil {
+		firstEpoch = checkpoint.LastProcessedEpoch + 1
+	}
+
+	// Set up the on-disk duty records if spilling is enabled
+	if r.dutySpillFolder != "" {
+		if checkpoint != nil {
+			r.dutyStore, err = openDutyStore(checkpoint.DutyRecordsPath, checkpoint.DutyRecordsCount)
+			if err != nil {
+				// The checkpoint has already been applied, so it can't be used without its records
+				deleteErr := checkpoint.delete(checkpointPath)
+				if deleteErr != nil {
+					r.log.Printlnf("%s WARNING: %s", r.logPrefix, deleteErr.Error())
+				}
+				return fmt.Errorf("error resuming from checkpoint: %w", err)
+			}
+		} else {
+			r.dutyStore, err = newDutyStore(r.dutySpillFolder, r.rewardsFile.Index, r.rewardsFile.RulesetVersion)
+			if err != nil {
+				return err
+			}
+		}
+		defer func() {
+			// Keep the records around for the next attempt if this one fails and a checkpoint refers to them.
+			// If it succeeds, they're still needed to write out the missed attestations, so they're closed once that's done.
+			if err != nil {
+				r.closeDutyStore(checkpointSaved)
+			}
+		}()
+		r.log.Printlnf("%s Spilling attestation duty records to %s", r.logPrefix, r.dutySpillFolder)
+	}
+
 	// Check all of the attestations for each epoch
-	r.log.Printlnf("%s Checking participation of %d minipools for epochs %d to %d", r.logPrefix, len(r.validatorIndexMap), startEpoch, endEpoch)
+	r.log.Printlnf("%s Checking participation of %d minipools for epochs %d to %d", r.logPrefix, len(r.validatorIndexMap), firstEpoch, endEpoch)
 	r.log.Printlnf("%s NOTE: this will take a long time, progress is reported every 100 epochs", r.logPrefix)
 
 	epochsDone := 0
 	reportStartTime := time.Now()
-	for epoch := startEpoch; epoch < endEpoch+1; epoch++ {
+	for epoch := firstEpoch; epoch < endEpoch+1; epoch++ {
 		if epochsDone == 100 {
 			timeTaken := time.Since(reportStartTime)
 			r.log.Printlnf("%s On Epoch %d of %d (%.2f%%)... (%s so far)", r.logPrefix, epoch, endEpoch, float64(epoch-startEpoch)/float64(endEpoch-startEpoch)*100.0, timeTaken)
@@ -810,6 +875,16 @@ func (r *treeGeneratorImpl_v9_v10) processAttestationsBalancesAndWithdrawalsForI
 			return err
 		}
 
+		// Save a checkpoint periodically so a failure doesn't mean starting over
+		if checkpointPath != "" && (epoch-startEpoch+1)%r.checkpointEpochInterval == 0 && epoch < endEpoch {
+			err = r.saveCheckpoint(checkpointPath, epoch)
+			if err != nil {
+				r.log.Printlnf("%s WARNING: error saving checkpoint at epoch %d: %s", r.logPrefix, epoch, err.Error())
+			} else {
+				checkpointSaved = true
+			}
+		}
+
 		epochsDone++
 	}
 
@@ -820,11 +895,202 @@ func (r *treeGeneratorImpl_v9_v10) processAttestationsBalancesAndWithdrawalsForI
 		return err
 	}
 
+	// Score the spilled duty records now that every attestation has been seen
+	if r.dutyStore != nil {
+		err = r.spillDuties(math.MaxUint64)
+		if err != nil {
+			return err
+		}
+		err = r.scoreSpilledDuties()
+		if err != nil {
+			return err
+		}
+	}
+
+	// The checkpoint isn't needed anymore
+	if checkpointPath != "" {
+		err = os.Remove(checkpointPath)
+		if err != nil && !os.IsNotExist(err) {
+			r.log.Printlnf("%s WARNING: error deleting checkpoint %s: %s", r.logPrefix, checkpointPath, err.Error())
+		}
+	}
+
 	r.log.Printlnf("%s Finished participation check (total time = %s)", r.logPrefix, time.Since(reportStartTime))
 	return nil
 
 }
 
+// Get the details that a checkpoint must match to be used for this generator's snapshot
+func (r *treeGeneratorImpl_v9_v10) getCheckpointHeader() generationCheckpointHeader {
+	return generationCheckpointHeader{
+		Version:             generationCheckpointVersion,
+		Index:               r.rewardsFile.Index,
+		RulesetVersion:      r.rewardsFile.RulesetVersion,
+		SnapshotEndSlot:     r.snapshotEnd.Slot,
+		ConsensusStartBlock: r.rewardsFile.ConsensusStartBlock,
+		ConsensusEndBlock:   r.rewardsFile.ConsensusEndBlock,
+	}
+}
+
+// Loads and applies the checkpoint for this snapshot if checkpoints are enabled and one exists.
+// Returns the applied checkpoint (or nil if starting from scratch) and the path to save new checkpoints to (or an empty string if they're disabled).
+func (r *treeGeneratorImpl_v9_v10) resumeFromCheckpoint() (*generationCheckpoint, string) {
+	if r.checkpointFolder == "" {
+		return nil, ""
+	}
+	checkpointPath := getGenerationCheckpointPath(r.checkpointFolder, r.rewardsFile.Index, r.rewardsFile.RulesetVersion)
+	checkpoint, err := loadGenerationCheckpoint(checkpointPath, r.getCheckpointHeader())
+	if err != nil {
+		r.log.Printlnf("%s WARNING: %s; starting from the beginning of the interval.", r.logPrefix, err.Error())
+		return nil, checkpointPath
+	}
+	if checkpoint == nil {
+		return nil, checkpointPath
+	}
+
+	// Checkpoints can only be used with the same duty record mode they were taken with
+	if (checkpoint.DutyRecordsPath != "") != (r.dutySpillFolder != "") {
+		err = fmt.Errorf("checkpoint %s was taken with a different duty records mode", checkpointPath)
+	} else {
+		err = r.restoreCheckpoint(checkpoint)
+	}
+	if err != nil {
+		r.log.Printlnf("%s WARNING: %s; starting from the beginning of the interval.", r.logPrefix, err.Error())
+		deleteErr := checkpoint.delete(checkpointPath)
+		if deleteErr != nil {
+			r.log.Printlnf("%s WARNING: %s", r.logPrefix, deleteErr.Error())
+		}
+		return nil, checkpointPath
+	}
+
+	r.log.Printlnf("%s Resuming from the checkpoint at epoch %d.", r.logPrefix, checkpoint.LastProcessedEpoch)
+	return checkpoint, checkpointPath
+}
+
+// Saves the progress of the attestation processing up to and including the provided epoch
+func (r *treeGeneratorImpl_v9_v10) saveCheckpoint(path string, epoch uint64) error {
+	checkpoint := &generationCheckpoint{
+		generationCheckpointHeader: r.getCheckpointHeader(),
+		LastProcessedEpoch:         epoch,
+		TotalAttestationScore:      QuotedBigIntFromBigInt(r.totalAttestationScore),
+		SuccessfulAttestations:     r.successfulAttestations,
+		Minipools:                  map[string]*minipoolCheckpoint{},
+		PendingDuties:              []pendingDutiesCheckpoint{},
+		MinipoolWithdrawals:        map[common.Address]*QuotedBigInt{},
+	}
+
+	// Minipool tallies
+	for validatorIndex, minipoolInfo := range r.validatorIndexMap {
+		if minipoolInfo.GoodAttestations == 0 && minipoolInfo.AttestationScore.Sign() == 0 && len(minipoolInfo.MissingAttestationSlots) == 0 {
+			continue
+		}
+		minipoolCheckpoint := &minipoolCheckpoint{
+			GoodAttestations: minipoolInfo.GoodAttestations,
+			AttestationScore: QuotedBigIntFromBigInt(&minipoolInfo.AttestationScore.Int),
+		}
+		for slot := range minipoolInfo.MissingAttestationSlots {
+			minipoolCheckpoint.MissingAttestationSlots = append(minipoolCheckpoint.MissingAttestationSlots, slot)
+		}
+		checkpoint.Minipools[validatorIndex] = minipoolCheckpoint
+	}
+
+	// Duties that can still be attested; anything before this epoch is already final
+	firstOpenSlot := epoch * r.slotsPerEpoch
+	for slotIndex, slotInfo := range r.intervalDutiesInfo.Slots {
+		if slotIndex < firstOpenSlot {
+			continue
+		}
+		pendingDuties := pendingDutiesCheckpoint{
+			Slot:           slotIndex,
+			CommitteeSizes: slotInfo.CommitteeSizes,
+			Committees:     map[uint64]map[int]string{},
+		}
+		for committeeIndex, committee := range slotInfo.Committees {
+			positions := map[int]string{}
+			for position, validator := range committee.Positions {
+				positions[position] = validator.ValidatorIndex
+			}
+			pendingDuties.Committees[committeeIndex] = positions
+		}
+		checkpoint.PendingDuties = append(checkpoint.PendingDuties, pendingDuties)
+	}
+
+	// Withdrawals
+	for address, amount := range r.minipoolWithdrawals {
+		checkpoint.MinipoolWithdrawals[address] = QuotedBigIntFromBigInt(amount)
+	}
+
+	// Duty records
+	if r.dutyStore != nil {
+		err := r.dutyStore.flush()
+		if err != nil {
+			return err
+		}
+		checkpoint.DutyRecordsPath = r.dutyStore.path()
+		checkpoint.DutyRecordsCount = r.dutyStore.count
+	}
+
+	return checkpoint.save(path)
+}
+
+// Applies a checkpoint's progress to the generator. Nothing is changed if the checkpoint doesn't match the generator's minipools.
+func (r *treeGeneratorImpl_v9_v10) restoreCheckpoint(checkpoint *generationCheckpoint) error {
+	// Rebuild the pending duties and make sure every minipool is known before changing anything
+	slots := map[uint64]*SlotInfo{}
+	for _, pendingDuties := range checkpoint.PendingDuties {
+		slotInfo := &SlotInfo{
+			Index:          pendingDuties.Slot,
+			Committees:     map[uint64]*CommitteeInfo{},
+			CommitteeSizes: pendingDuties.CommitteeSizes,
+		}
+		if slotInfo.CommitteeSizes == nil {
+			slotInfo.CommitteeSizes = map[uint64]int{}
+		}
+		for committeeIndex, positions := range pendingDuties.Committees {
+			committee := &CommitteeInfo{
+				Index:     committeeIndex,
+				Positions: map[int]*MinipoolInfo{},
+			}
+			for position, validatorIndex := range positions {
+				minipoolInfo, exists := r.validatorIndexMap[validatorIndex]
+				if !exists {
+					return fmt.Errorf("checkpoint has a duty for unknown validator %s", validatorIndex)
+				}
+				committee.Positions[position] = minipoolInfo
+			}
+			slotInfo.Committees[committeeIndex] = committee
+		}
+		slots[pendingDuties.Slot] = slotInfo
+	}
+	for validatorIndex := range checkpoint.Minipools {
+		if _, exists := r.validatorIndexMap[validatorIndex]; !exists {
+			return fmt.Errorf("checkpoint has tallies for unknown validator %s", validatorIndex)
+		}
+	}
+	if checkpoint.TotalAttestationScore == nil {
+		return fmt.Errorf("checkpoint is missing the total attestation score")
+	}
+
+	// Apply it
+	r.intervalDutiesInfo.Slots = slots
+	for validatorIndex, tallies := range checkpoint.Minipools {
+		minipoolInfo := r.validatorIndexMap[validatorIndex]
+		minipoolInfo.GoodAttestations = tallies.GoodAttestations
+		if tallies.AttestationScore != nil {
+			minipoolInfo.AttestationScore.Set(&tallies.AttestationScore.Int)
+		}
+		for _, slot := range tallies.MissingAttestationSlots {
+			minipoolInfo.MissingAttestationSlots[slot] = true
+		}
+	}
+	r.totalAttestationScore.Set(&checkpoint.TotalAttestationScore.Int)
+	r.successfulAttestations = checkpoint.SuccessfulAttestations
+	for address, amount := range checkpoint.MinipoolWithdrawals {
+		r.minipoolWithdrawals[address] = big.NewInt(0).Set(&amount.Int)
+	}
+	return nil
+}
+
 // Process an epoch, optionally getting the duties for all eligible minipools in it and checking each one's attestation performance
 func (r *treeGeneratorImpl_v9_v10) processEpoch(duringInterval bool, epoch uint64) error {
 
@@ -927,7 +1193,18 @@ func (r *treeGeneratorImpl_v9_v10) processEpoch(duringInterval bool, epoch uint6
 		inclusionSlot := epoch*r.slotsPerEpoch + i
 		attestations := attestationsPerSlot[i]
 		if len(attestations) > 0 {
-			r.checkAttestations(attestations, inclusionSlot)
+			err = r.checkAttestations(attestations, inclusionSlot)
+			if err != nil {
+				return fmt.Errorf("error checking attestations in slot %d: %w", inclusionSlot, err)
+			}
+		}
+	}
+
+	// Attestations can't be included more than an epoch after their slot, so duties before this epoch are final
+	if r.dutyStore != nil {
+		err = r.spillDuties(epoch * r.slotsPerEpoch)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -970,44 +1247,141 @@ func (r *treeGeneratorImpl_v9_v10) checkAttestations(attestations []beacon.Attes
 				if len(slotInfo.Committees) == 0 {
 					delete(r.intervalDutiesInfo.Slots, attestation.SlotIndex)
 				}
-				delete(validator.MissingAttestationSlots, attestation.SlotIndex)
 
-				// Check if this minipool was opted into the SP for this block
-				nodeDetails := r.nodeDetails[validator.NodeIndex]
-				if blockTime.Before(nodeDetails.OptInTime) || blockTime.After(nodeDetails.OptOutTime) {
-					// Not opted in
+				// Record it for scoring once the interval is done if spilling is enabled
+				if r.dutyStore != nil {
+					err := r.writeDutyRecord(validator, attestation.SlotIndex, dutyOutcome_Attested)
+					if err != nil {
+						return err
+					}
 					continue
 				}
 
-				eligibleBorrowedEth := nodeDetails.EligibleBorrowedEth
-				_, percentOfBorrowedEth := r.networkState.GetStakedRplValueInEthAndPercentOfBorrowedEth(eligibleBorrowedEth, nodeDetails.RplStake)
+				delete(validator.MissingAttestationSlots, attestation.SlotIndex)
+				r.scoreAttestation(validator, blockTime)
+			}
+		}
+	}
 
-				// Mark this duty as completed
-				validator.CompletedAttestations[attestation.SlotIndex] = true
+	return nil
 
-				// Get the pseudoscore for this attestation
-				details := r.networkState.MinipoolDetailsByAddress[validator.Address]
-				bond, fee := details.GetMinipoolBondAndNodeFee(blockTime)
+}
 
-				if r.rewardsFile.RulesetVersion >= 10 {
-					fee = fees.GetMinipoolFeeWithBonus(bond, fee, percentOfBorrowedEth)
-				}
+// Adds a successful attestation to a minipool's score if its node was opted into the Smoothing Pool at the time
+func (r *treeGeneratorImpl_v9_v10) scoreAttestation(validator *MinipoolInfo, blockTime time.Time) {
+	// Check if this minipool was opted into the SP for this block
+	nodeDetails := r.nodeDetails[validator.NodeIndex]
+	if blockTime.Before(nodeDetails.OptInTime) || blockTime.After(nodeDetails.OptOutTime) {
+		// Not opted in
+		return
+	}
+
+	eligibleBorrowedEth := nodeDetails.EligibleBorrowedEth
+	_, percentOfBorrowedEth := r.networkState.GetStakedRplValueInEthAndPercentOfBorrowedEth(eligibleBorrowedEth, nodeDetails.RplStake)
+
+	// Mark this duty as completed
+	validator.GoodAttestations++
+
+	// Get the pseudoscore for this attestation
+	details := r.networkState.MinipoolDetailsByAddress[validator.Address]
+	bond, fee := details.GetMinipoolBondAndNodeFee(blockTime)
+
+	if r.rewardsFile.RulesetVersion >= 10 {
+		fee = fees.GetMinipoolFeeWithBonus(bond, fee, percentOfBorrowedEth)
+	}
+
+	minipoolScore := big.NewInt(0).Sub(oneEth, fee) // 1 - fee
+	minipoolScore.Mul(minipoolScore, bond)          // Multiply by bond
+	minipoolScore.Div(minipoolScore, thirtyTwoEth)  // Divide by 32 to get the bond as a fraction of a total validator
+	minipoolScore.Add(minipoolScore, fee)           // Total = fee + (bond/32)(1 - fee)
+
+	// Add it to the minipool's score and the total score
+	validator.AttestationScore.Add(&validator.AttestationScore.Int, minipoolScore)
+	r.totalAttestationScore.Add(r.totalAttestationScore, minipoolScore)
+	r.successfulAttestations++
+}
 
-				minipoolScore := big.NewInt(0).Sub(oneEth, fee) // 1 - fee
-				minipoolScore.Mul(minipoolScore, bond)          // Multiply by bond
-				minipoolScore.Div(minipoolScore, thirtyTwoEth)  // Divide by 32 to get the bond as a fraction of a total validator
-				minipoolScore.Add(minipoolScore, fee)           // Total = fee + (bond/32)(1 - fee)
+// Writes the outcome of a minipool's duty to the on-disk duty records
+func (r *treeGeneratorImpl_v9_v10) writeDutyRecord(validator *MinipoolInfo, slot uint64, outcome dutyOutcome) error {
+	validatorIndex, err := strconv.ParseUint(validator.ValidatorIndex, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing validator index %s of minipool %s: %w", validator.ValidatorIndex, validator.Address.Hex(), err)
+	}
+	return r.dutyStore.write(dutyRecord{
+		Slot:           slot,
+		ValidatorIndex: validatorIndex,
+		Outcome:        outcome,
+	})
+}
 
-				// Add it to the minipool's score and the total score
-				validator.AttestationScore.Add(&validator.AttestationScore.Int, minipoolScore)
-				r.totalAttestationScore.Add(r.totalAttestationScore, minipoolScore)
-				r.successfulAttestations++
+// Moves the duties for every slot before the provided one out of memory and into the on-disk duty records as missed attestations
+func (r *treeGeneratorImpl_v9_v10) spillDuties(beforeSlot uint64) error {
+	for slotIndex, slotInfo := range r.intervalDutiesInfo.Slots {
+		if slotIndex >= beforeSlot {
+			continue
+		}
+		for _, committee := range slotInfo.Committees {
+			for _, validator := range committee.Positions {
+				err := r.writeDutyRecord(validator, slotIndex, dutyOutcome_Missed)
+				if err != nil {
+					return err
+				}
 			}
 		}
+		delete(r.intervalDutiesInfo.Slots, slotIndex)
 	}
-
 	return nil
+}
 
+// Streams the on-disk duty records back and scores them.
+// Missed duties are only counted here; their slots are written straight to the performance file by writeSpilledMissedSlots once it's built.
+func (r *treeGeneratorImpl_v9_v10) scoreSpilledDuties() error {
+	r.log.Printlnf("%s Scoring %d spilled duty records...", r.logPrefix, r.dutyStore.count)
+	return r.dutyStore.forEach(func(record dutyRecord) error {
+		validator, exists := r.validatorIndexMap[strconv.FormatUint(record.ValidatorIndex, 10)]
+		if !exists {
+			return fmt.Errorf("duty record for slot %d refers to unknown validator %d", record.Slot, record.ValidatorIndex)
+		}
+		switch record.Outcome {
+		case dutyOutcome_Attested:
+			blockTime := r.genesisTime.Add(time.Second * time.Duration(r.networkState.BeaconConfig.SecondsPerSlot*record.Slot))
+			r.scoreAttestation(validator, blockTime)
+		case dutyOutcome_Missed:
+			validator.MissedAttestations++
+		default:
+			return fmt.Errorf("duty record for slot %d has unknown outcome %d", record.Slot, record.Outcome)
+		}
+		return nil
+	})
+}
+
+// Streams the on-disk duty records back and adds each missed duty to its minipool's entry in the performance file
+func (r *treeGeneratorImpl_v9_v10) writeSpilledMissedSlots() error {
+	return r.dutyStore.forEach(func(record dutyRecord) error {
+		if record.Outcome != dutyOutcome_Missed {
+			return nil
+		}
+		validator, exists := r.validatorIndexMap[strconv.FormatUint(record.ValidatorIndex, 10)]
+		if !exists {
+			return fmt.Errorf("duty record for slot %d refers to unknown validator %d", record.Slot, record.ValidatorIndex)
+		}
+		performance, exists := r.minipoolPerformanceFile.MinipoolPerformance[validator.Address]
+		if !exists {
+			return nil
+		}
+		performance.MissingAttestationSlots = append(performance.MissingAttestationSlots, record.Slot)
+		return nil
+	})
+}
+
+// Closes the on-disk duty records, keeping the file if a checkpoint refers to it
+func (r *treeGeneratorImpl_v9_v10) closeDutyStore(keep bool) {
+	r.dutyStore.keep = keep
+	err := r.dutyStore.close()
+	if err != nil {
+		r.log.Printlnf("%s WARNING: %s", r.logPrefix, err.Error())
+	}
+	r.dutyStore = nil
 }
 
 // Maps out the attestaion duties for the given epoch
@@ -1061,8 +1435,11 @@ func (r *treeGeneratorImpl_v9_v10) getDutiesForEpoch(committees beacon.Committee
 			}
 
 			// This was a legal RP validator opted into the SP during this slot so add it
+			// If spilling is enabled, unattested duties are recorded as missed once they're final instead
 			rpValidators[position] = minipoolInfo
-			minipoolInfo.MissingAttestationSlots[slotIndex] = true
+			if r.dutyStore == nil {
+				minipoolInfo.MissingAttestationSlots[slotIndex] = true
+			}
 		}
 
 		// If there are some RP validators, add this committee to the map
@@ -1198,7 +1575,6 @@ func (r *treeGeneratorImpl_v9_v10) getSmoothingPoolNodeDetails() error {
 							//MissedAttestations:      0,
 							//GoodAttestations:        0,
 							MissingAttestationSlots: map[uint64]bool{},
-							CompletedAttestations:   map[uint64]bool{},
 							WasActive:               true,
 							AttestationScore:        NewQuotedBigInt(0),
 							NodeOperatorBond:        nativeMinipoolDetails.NodeDepositBalance,
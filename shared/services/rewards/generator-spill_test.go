@@ -0,0 +1,192 @@
+package rewards
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/fatih/color"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/rewards/test"
+	"github.com/rocket-pool/smartnode/shared/services/rewards/test/assets"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Creates a generator for the default mock history, with a few missed attestations and some Smoothing Pool withdrawals
+func newMockTreeGenerator(tt *testing.T, rulesetVersion uint64) (*v8Test, *treeGeneratorImpl_v9_v10) {
+	history := test.NewDefaultMockHistory()
+	state := history.GetEndNetworkState()
+
+	t := newV8Test(tt, state.NetworkDetails.RewardIndex)
+	t.bc.SetState(state)
+
+	consensusStartBlock := history.GetConsensusStartBlock()
+	executionStartBlock := history.GetExecutionStartBlock()
+	consensusEndBlock := history.GetConsensusEndBlock()
+	executionEndBlock := history.GetExecutionEndBlock()
+
+	t.rp.SetRewardSnapshotEvent(history.GetPreviousRewardSnapshotEvent())
+	t.bc.SetBeaconBlock(fmt.Sprint(consensusStartBlock-1), beacon.BeaconBlock{ExecutionBlockNumber: executionStartBlock - 1})
+	t.bc.SetBeaconBlock(fmt.Sprint(consensusStartBlock), beacon.BeaconBlock{ExecutionBlockNumber: executionStartBlock})
+	t.rp.SetHeaderByNumber(big.NewInt(int64(executionStartBlock)), &types.Header{Time: uint64(history.GetStartTime().Unix())})
+
+	// Have some of the validators miss a few attestations so both outcomes end up in the duty records
+	for _, validator := range state.MinipoolValidatorDetails {
+		index, err := strconv.ParseUint(validator.Index, 10, 64)
+		t.failIf(err)
+		missedSlots := []uint64{}
+		if index%3 == 0 {
+			for slot := consensusStartBlock + index%32; slot <= consensusEndBlock; slot += 32 * 50 {
+				missedSlots = append(missedSlots, slot)
+			}
+		}
+		t.bc.SetMinipoolPerformance(validator.Index, missedSlots)
+	}
+
+	nodeSummary := history.GetNodeSummary()
+	for _, node := range nodeSummary["single_eight_eth_opted_in_quarter"] {
+		node.Minipools[0].SPWithdrawals = eth.EthToWei(0.75)
+	}
+	for _, node := range nodeSummary["single_bond_reduction"] {
+		node.Minipools[0].SPWithdrawals = eth.EthToWei(0.5)
+	}
+	history.SetWithdrawals(t.bc)
+
+	logger := log.NewColorLogger(color.Faint)
+	generator := newTreeGeneratorImpl_v9_v10(
+		rulesetVersion,
+		&logger,
+		t.Name(),
+		state.NetworkDetails.RewardIndex,
+		&SnapshotEnd{
+			Slot:           consensusEndBlock,
+			ConsensusBlock: consensusEndBlock,
+			ExecutionBlock: executionEndBlock,
+		},
+		&types.Header{
+			Number: big.NewInt(int64(executionEndBlock)),
+			Time:   assets.Mainnet20ELHeaderTime,
+		},
+		/* intervalsPassed= */ 1,
+		state,
+	)
+	return t, generator
+}
+
+// Generates a tree for the default mock history, optionally spilling the duty records to the provided folder
+func generateMockTree(tt *testing.T, rulesetVersion uint64, spillFolder string) *GenerateTreeResult {
+	t, generator := newMockTreeGenerator(tt, rulesetVersion)
+	generator.setDutySpillFolder(spillFolder)
+
+	result, err := generator.generateTree(
+		t.rp,
+		"mainnet",
+		make([]common.Address, 0),
+		t.bc,
+	)
+	t.failIf(err)
+	return result
+}
+
+// Fails the test if the rewards or performance files of two generation results aren't byte-for-byte identical
+func requireIdenticalFiles(t *testing.T, expected *GenerateTreeResult, actual *GenerateTreeResult) {
+	t.Helper()
+	serializers := []struct {
+		name      string
+		serialize func(result *GenerateTreeResult) ([]byte, error)
+	}{
+		{"rewards JSON", func(result *GenerateTreeResult) ([]byte, error) { return result.RewardsFile.Serialize() }},
+		{"rewards SSZ", func(result *GenerateTreeResult) ([]byte, error) { return result.RewardsFile.SerializeSSZ() }},
+		{"minipool performance", func(result *GenerateTreeResult) ([]byte, error) { return result.MinipoolPerformanceFile.Serialize() }},
+	}
+	for _, serializer := range serializers {
+		expectedBytes, err := serializer.serialize(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actualBytes, err := serializer.serialize(actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expectedBytes, actualBytes) {
+			t.Fatalf("%s differs from the expected output", serializer.name)
+		}
+	}
+}
+
+// Make sure spilling the duty records to disk produces exactly the same files as keeping them in memory
+func TestSpilledDutiesMatchInMemory(t *testing.T) {
+	for _, rulesetVersion := range []uint64{9, 10} {
+		t.Run(fmt.Sprintf("v%d", rulesetVersion), func(t *testing.T) {
+			spillFolder := t.TempDir()
+			inMemory := generateMockTree(t, rulesetVersion, "")
+			spilled := generateMockTree(t, rulesetVersion, spillFolder)
+
+			if len(inMemory.MinipoolPerformanceFile.GetMinipoolAddresses()) == 0 {
+				t.Fatal("expected the mock history to produce minipool performance")
+			}
+
+			requireIdenticalFiles(t, inMemory, spilled)
+
+			// The duty records should be cleaned up afterwards
+			entries, err := os.ReadDir(spillFolder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Fatalf("expected the duty records to be deleted, found %d files", len(entries))
+			}
+		})
+	}
+}
+
+func TestDutyStore(t *testing.T) {
+	store, err := newDutyStore(t.TempDir(), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.close()
+
+	records := []dutyRecord{
+		{Slot: 100, ValidatorIndex: 7, Outcome: dutyOutcome_Attested},
+		{Slot: 101, ValidatorIndex: 1 << 40, Outcome: dutyOutcome_Missed},
+		{Slot: 1 << 50, ValidatorIndex: 0, Outcome: dutyOutcome_Attested},
+	}
+	for _, record := range records[:2] {
+		if err := store.write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reading shouldn't stop later records from being appended
+	read := []dutyRecord{}
+	collect := func(record dutyRecord) error {
+		read = append(read, record)
+		return nil
+	}
+	if err := store.forEach(collect); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.write(records[2]); err != nil {
+		t.Fatal(err)
+	}
+	read = read[:0]
+	if err := store.forEach(collect); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != len(records) {
+		t.Fatalf("expected %d records, got %d", len(records), len(read))
+	}
+	for i, record := range records {
+		if read[i] != record {
+			t.Fatalf("record %d: expected %+v, got %+v", i, record, read[i])
+		}
+	}
+}
@@ -113,6 +113,15 @@ type treeGeneratorImpl interface {
 	saveFiles(smartnode *config.SmartnodeConfig, treeResult *GenerateTreeResult, nodeTrusted bool) (cid.Cid, map[string]cid.Cid, error)
 }
 
+// Implemented by tree generators that can keep their attestation duty records on disk instead of in memory
+type dutySpiller interface {
+	setDutySpillFolder(folder string)
+}
+
+type checkpointer interface {
+	setCheckpointFolder(folder string)
+}
+
 func NewTreeGenerator(logger *log.ColorLogger, logPrefix string, rp RewardsExecutionClient, cfg *config.RocketPoolConfig, bc beacon.Client, index uint64, startTime time.Time, endTime time.Time, snapshotEnd *SnapshotEnd, elSnapshotHeader *types.Header, intervalsPassed uint64, state *state.NetworkState) (*TreeGenerator, error) {
 	t := &TreeGenerator{
 		logger:           logger,
@@ -211,9 +220,37 @@ func NewTreeGenerator(logger *log.ColorLogger, logPrefix string, rp RewardsExecu
 		return nil, fmt.Errorf("No treegen implementation could be found for interval %d", t.index)
 	}
 
+	// Keep the duty records on disk if low-memory generation is enabled
+	if t.cfg.Smartnode.LowMemoryTreeGeneration.Value.(bool) {
+		t.SetDutySpillFolder(t.cfg.Smartnode.GetDutyRecordsFolder(true))
+	}
+
 	return t, nil
 }
 
+// Spill the attestation duty records for the interval to temporary files in the provided folder while generating, and stream them back
+// when scoring, instead of holding them all in memory. The generated files are identical either way. Rulesets that don't support this
+// will still keep their records in memory. An empty folder disables spilling.
+func (t *TreeGenerator) SetDutySpillFolder(folder string) {
+	for _, info := range t.rewardsIntervalInfos {
+		if spiller, ok := info.generator.(dutySpiller); ok {
+			spiller.setDutySpillFolder(folder)
+		}
+	}
+}
+
+// Periodically save the progress of the attestation processing to the provided folder while generating, so a generation that fails
+// partway through (e.g. because the Beacon Node went down) can resume from the last checkpoint instead of starting over. Checkpoints
+// are discarded if the snapshot they were taken for changes. Rulesets that don't support this will always start from the beginning.
+// An empty folder disables checkpoints.
+func (t *TreeGenerator) SetCheckpointFolder(folder string) {
+	for _, info := range t.rewardsIntervalInfos {
+		if checkpointer, ok := info.generator.(checkpointer); ok {
+			checkpointer.setCheckpointFolder(folder)
+		}
+	}
+}
+
 type GenerateTreeResult struct {
 	RewardsFile             IRewardsFile
 	MinipoolPerformanceFile IMinipoolPerformanceFile
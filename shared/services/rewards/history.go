@@ -0,0 +1,615 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/minipool"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/storage"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+)
+
+// The version of the rewards history cache; bump this to force a rebuild when the format changes
+const rewardsLedgerVersion uint64 = 2
+
+// The most blocks scanned for events in a single update, so the first update doesn't have to scan the whole chain at once
+const RewardsLedgerScanLimit uint64 = 250000
+
+// Minipool distributions with a balance below this are skimmed consensus rewards rather than withdrawals
+var skimThreshold = eth.EthToWei(8)
+
+// The kind of income or event a ledger entry records
+type RewardsLedgerEntryType string
+
+const (
+	LedgerEntry_RplInflation         RewardsLedgerEntryType = "rpl-inflation"
+	LedgerEntry_OracleDaoRpl         RewardsLedgerEntryType = "odao-rpl"
+	LedgerEntry_SmoothingPoolEth     RewardsLedgerEntryType = "smoothing-pool-eth"
+	LedgerEntry_ConsensusSkim        RewardsLedgerEntryType = "consensus-skim"
+	LedgerEntry_MinipoolDistribution RewardsLedgerEntryType = "minipool-distribution"
+	LedgerEntry_MegapoolDistribution RewardsLedgerEntryType = "megapool-distribution"
+	LedgerEntry_Claim                RewardsLedgerEntryType = "claim"
+)
+
+// A single line in a node's rewards history
+type RewardsLedgerEntry struct {
+	Type      RewardsLedgerEntryType `json:"type"`
+	Interval  *uint64                `json:"interval,omitempty"`
+	Time      time.Time              `json:"time"`
+	Block     uint64                 `json:"block"`
+	TxHash    *common.Hash           `json:"txHash,omitempty"`
+	Validator *common.Address        `json:"validator,omitempty"`
+	Pubkey    *types.ValidatorPubkey `json:"pubkey,omitempty"`
+	RplAmount *QuotedBigInt          `json:"rplAmount,omitempty"`
+	EthAmount *QuotedBigInt          `json:"ethAmount,omitempty"`
+	RplPrice  *QuotedBigInt          `json:"rplPrice,omitempty"`
+}
+
+// An RPL price reported by the Oracle DAO
+type RplPriceUpdate struct {
+	Block uint64        `json:"block"`
+	Price *QuotedBigInt `json:"price"`
+}
+
+// A node's rewards history, cached on disk so only new intervals and blocks need to be processed on each update
+type RewardsLedger struct {
+	Version          uint64               `json:"version"`
+	NodeAddress      common.Address       `json:"nodeAddress"`
+	LastScannedBlock uint64               `json:"lastScannedBlock"`
+	Minipools        []common.Address     `json:"minipools"`
+	Intervals        []uint64             `json:"intervals"`
+	PriceUpdates     []RplPriceUpdate     `json:"priceUpdates"`
+	Entries          []RewardsLedgerEntry `json:"entries"`
+
+	// Intervals the node may have rewards in but whose tree files aren't available locally
+	MissingIntervals []uint64 `json:"-"`
+
+	// The latest block, and whether the ledger has been scanned up to it
+	LatestBlock uint64 `json:"-"`
+	Complete    bool   `json:"-"`
+}
+
+// Get the value of an entry in ETH, using the RPL price at the time.
+// Claims only move rewards that were already recorded when they were earned, so they have no value of their own.
+// Returns nil if the entry has RPL but there's no price to value it with.
+func (e *RewardsLedgerEntry) GetEthValue() *big.Int {
+	if e.Type == LedgerEntry_Claim {
+		return nil
+	}
+	value := big.NewInt(0)
+	if e.EthAmount != nil {
+		value.Add(value, &e.EthAmount.Int)
+	}
+	if e.RplAmount != nil {
+		if e.RplPrice == nil {
+			return nil
+		}
+		rplValue := big.NewInt(0).Mul(&e.RplAmount.Int, &e.RplPrice.Int)
+		rplValue.Quo(rplValue, big.NewInt(1e18))
+		value.Add(value, rplValue)
+	}
+	return value
+}
+
+// Loads the node's cached rewards history and brings it up to date with the local rewards trees and the chain.
+// At most RewardsLedgerScanLimit blocks are scanned per call; the ledger isn't complete until a call scans up to the latest block,
+// so callers should keep calling this until it is.
+func UpdateRewardsLedger(rp *rocketpool.RocketPool, cfg *config.RocketPoolConfig, nodeAddress common.Address, intervalSize *big.Int) (*RewardsLedger, error) {
+	path := cfg.Smartnode.GetRewardsHistoryPath()
+	ledger, err := loadRewardsLedger(path, nodeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the next range of blocks to scan
+	currentBlock, err := rp.Client.BlockNumber(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error getting the latest block: %w", err)
+	}
+	deployBlock := uint64(0)
+	if ledger.LastScannedBlock == 0 {
+		block, err := storage.GetDeployBlock(rp)
+		if err != nil {
+			return nil, err
+		}
+		deployBlock = block.Uint64()
+	}
+	fromBlock, toBlock, complete := getLedgerScanRange(ledger.LastScannedBlock, deployBlock, currentBlock, RewardsLedgerScanLimit)
+	ledger.LatestBlock = currentBlock
+	ledger.Complete = complete
+
+	// Scan it for new minipools, price updates, claims and distributions
+	if fromBlock <= toBlock {
+		var megapoolAddress *common.Address
+		deployed, err := megapool.GetMegapoolDeployed(rp, nodeAddress, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error checking if the node's megapool is deployed: %w", err)
+		}
+		if deployed {
+			address, err := megapool.GetMegapoolExpectedAddress(rp, nodeAddress, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error getting the node's megapool address: %w", err)
+			}
+			megapoolAddress = &address
+		}
+
+		scanner := &ledgerScanner{
+			rp:           rp,
+			ledger:       ledger,
+			nodeAddress:  nodeAddress,
+			intervalSize: intervalSize,
+			fromBlock:    big.NewInt(0).SetUint64(fromBlock),
+			toBlock:      big.NewInt(0).SetUint64(toBlock),
+			blockTimes:   map[uint64]time.Time{},
+		}
+		if err := scanner.scanMinipools(); err != nil {
+			return nil, err
+		}
+		if err := scanner.scanPriceUpdates(); err != nil {
+			return nil, err
+		}
+		if err := scanner.scanClaims(); err != nil {
+			return nil, err
+		}
+		if err := scanner.scanMinipoolDistributions(ledger.Minipools); err != nil {
+			return nil, err
+		}
+		if megapoolAddress != nil {
+			if err := scanner.scanMegapoolDistributions(*megapoolAddress); err != nil {
+				return nil, err
+			}
+		}
+		ledger.LastScannedBlock = toBlock
+	}
+
+	// The interval rewards and RPL prices can't be filled in until every price update has been scanned
+	if ledger.Complete {
+		currentIndex, err := rp.GetRewardIndex(nil)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the current rewards interval: %w", err)
+		}
+		for interval := uint64(0); interval < currentIndex.Uint64(); interval++ {
+			if slices.Contains(ledger.Intervals, interval) {
+				continue
+			}
+			treePath := cfg.Smartnode.GetRewardsTreePath(interval, true, config.RewardsExtensionJSON)
+			if _, err := os.Stat(treePath); os.IsNotExist(err) {
+				ledger.MissingIntervals = append(ledger.MissingIntervals, interval)
+				continue
+			}
+			localRewardsFile, err := ReadLocalRewardsFile(treePath)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", treePath, err)
+			}
+			var performanceFile IMinipoolPerformanceFile
+			performancePath := cfg.Smartnode.GetMinipoolPerformancePath(interval, true)
+			if _, err := os.Stat(performancePath); err == nil {
+				localPerformanceFile, err := ReadLocalMinipoolPerformanceFile(performancePath)
+				if err != nil {
+					return nil, fmt.Errorf("error reading %s: %w", performancePath, err)
+				}
+				performanceFile = localPerformanceFile.Impl()
+			}
+			if err := ledger.addInterval(interval, localRewardsFile.Impl(), performanceFile); err != nil {
+				return nil, err
+			}
+			ledger.Intervals = append(ledger.Intervals, interval)
+		}
+
+		// Attach the RPL price at the time of each entry
+		ledger.setPrices()
+	}
+
+	// Save the result
+	sort.SliceStable(ledger.Entries, func(i, j int) bool {
+		return ledger.Entries[i].Block < ledger.Entries[j].Block
+	})
+	if err := ledger.save(path); err != nil {
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// Get the range of blocks the next update should scan, and whether it reaches the latest block.
+// The deploy block is used as the start if nothing has been scanned yet.
+func getLedgerScanRange(lastScannedBlock uint64, deployBlock uint64, currentBlock uint64, limit uint64) (uint64, uint64, bool) {
+	fromBlock := lastScannedBlock + 1
+	if lastScannedBlock == 0 {
+		fromBlock = deployBlock
+	}
+	toBlock := currentBlock
+	if fromBlock+limit-1 < toBlock {
+		toBlock = fromBlock + limit - 1
+	}
+	return fromBlock, toBlock, toBlock == currentBlock || fromBlock > currentBlock
+}
+
+// Load the cached rewards history, starting from scratch if it doesn't exist or belongs to a different node
+func loadRewardsLedger(path string, nodeAddress common.Address) (*RewardsLedger, error) {
+	ledger := &RewardsLedger{
+		Version:     rewardsLedgerVersion,
+		NodeAddress: nodeAddress,
+	}
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading rewards history from %s: %w", path, err)
+	}
+
+	var cached RewardsLedger
+	if err := json.Unmarshal(bytes, &cached); err != nil {
+		return nil, fmt.Errorf("error parsing rewards history from %s: %w", path, err)
+	}
+	if cached.Version != rewardsLedgerVersion || cached.NodeAddress != nodeAddress {
+		return ledger, nil
+	}
+	return &cached, nil
+}
+
+// Save the rewards history to disk
+func (l *RewardsLedger) save(path string) error {
+	bytes, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("error serializing rewards history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating rewards history folder: %w", err)
+	}
+	if err := os.WriteFile(path, bytes, 0644); err != nil {
+		return fmt.Errorf("error writing rewards history to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Add the node's rewards from an interval's tree, splitting the Smoothing Pool rewards by validator if the performance file is available
+func (l *RewardsLedger) addInterval(interval uint64, rewardsFile IRewardsFile, performanceFile IMinipoolPerformanceFile) error {
+	if !rewardsFile.HasRewardsFor(l.NodeAddress) {
+		return nil
+	}
+
+	newEntry := func(entryType RewardsLedgerEntryType) RewardsLedgerEntry {
+		index := interval
+		return RewardsLedgerEntry{
+			Type:     entryType,
+			Interval: &index,
+			Time:     rewardsFile.GetEndTime(),
+			Block:    rewardsFile.GetExecutionEndBlock(),
+		}
+	}
+
+	// RPL rewards are only tracked per node
+	if amount := rewardsFile.GetNodeCollateralRpl(l.NodeAddress); amount.Sign() > 0 {
+		entry := newEntry(LedgerEntry_RplInflation)
+		entry.RplAmount = QuotedBigIntFromBigInt(amount)
+		l.Entries = append(l.Entries, entry)
+	}
+	if amount := rewardsFile.GetNodeOracleDaoRpl(l.NodeAddress); amount.Sign() > 0 {
+		entry := newEntry(LedgerEntry_OracleDaoRpl)
+		entry.RplAmount = QuotedBigIntFromBigInt(amount)
+		l.Entries = append(l.Entries, entry)
+	}
+
+	nodeEth := rewardsFile.GetNodeSmoothingPoolEth(l.NodeAddress)
+	if nodeEth.Sign() <= 0 {
+		return nil
+	}
+
+	// Break the Smoothing Pool rewards down by validator, including minipools that have since been closed
+	validatorEntries := []RewardsLedgerEntry{}
+	validatorTotal := big.NewInt(0)
+	if performanceFile != nil {
+		for _, validator := range l.Minipools {
+			performance, exists := performanceFile.GetSmoothingPoolPerformance(validator)
+			if !exists {
+				continue
+			}
+			amount := big.NewInt(0).Set(performance.GetEthEarned())
+			if bonus := performance.GetBonusEthEarned(); bonus != nil {
+				amount.Add(amount, bonus)
+			}
+			if amount.Sign() <= 0 {
+				continue
+			}
+			pubkey, err := performance.GetPubkey()
+			if err != nil {
+				return fmt.Errorf("error getting pubkey for minipool %s in interval %d: %w", validator.Hex(), interval, err)
+			}
+			address := validator
+			entry := newEntry(LedgerEntry_SmoothingPoolEth)
+			entry.Validator = &address
+			entry.Pubkey = &pubkey
+			entry.EthAmount = QuotedBigIntFromBigInt(amount)
+			validatorEntries = append(validatorEntries, entry)
+			validatorTotal.Add(validatorTotal, amount)
+		}
+	}
+
+	// Anything that can't be attributed to a validator is recorded against the node
+	if validatorTotal.Cmp(nodeEth) > 0 {
+		validatorEntries = nil
+		validatorTotal.SetUint64(0)
+	}
+	l.Entries = append(l.Entries, validatorEntries...)
+	if remainder := big.NewInt(0).Sub(nodeEth, validatorTotal); remainder.Sign() > 0 {
+		entry := newEntry(LedgerEntry_SmoothingPoolEth)
+		entry.EthAmount = QuotedBigIntFromBigInt(remainder)
+		l.Entries = append(l.Entries, entry)
+	}
+	return nil
+}
+
+// Set the RPL price on every entry to the latest price the Oracle DAO reported at or before its block
+func (l *RewardsLedger) setPrices() {
+	sort.Slice(l.PriceUpdates, func(i, j int) bool {
+		return l.PriceUpdates[i].Block < l.PriceUpdates[j].Block
+	})
+	for i := range l.Entries {
+		entry := &l.Entries[i]
+		if entry.RplPrice != nil {
+			continue
+		}
+		index := sort.Search(len(l.PriceUpdates), func(j int) bool {
+			return l.PriceUpdates[j].Block > entry.Block
+		})
+		if index > 0 {
+			entry.RplPrice = l.PriceUpdates[index-1].Price
+		}
+	}
+}
+
+// Scans a range of blocks for the events that go into a node's rewards history
+type ledgerScanner struct {
+	rp           *rocketpool.RocketPool
+	ledger       *RewardsLedger
+	nodeAddress  common.Address
+	intervalSize *big.Int
+	fromBlock    *big.Int
+	toBlock      *big.Int
+	blockTimes   map[uint64]time.Time
+}
+
+// Record every minipool created by the node, so the ones that have since exited and been closed are still included
+func (s *ledgerScanner) scanMinipools() error {
+	rocketMinipoolManager, err := s.rp.GetContract("rocketMinipoolManager", nil)
+	if err != nil {
+		return err
+	}
+	event := rocketMinipoolManager.ABI.Events["MinipoolCreated"]
+	logs, err := eth.FilterContractLogs(s.rp, "rocketMinipoolManager", eth.FilterQuery{
+		FromBlock: s.fromBlock,
+		ToBlock:   s.toBlock,
+		Topics:    [][]common.Hash{{event.ID}, nil, {common.BytesToHash(s.nodeAddress.Bytes())}},
+	}, s.intervalSize, nil)
+	if err != nil {
+		return fmt.Errorf("error getting the node's minipools: %w", err)
+	}
+
+	for _, log := range logs {
+		if len(log.Topics) < 2 {
+			continue
+		}
+		address := common.BytesToAddress(log.Topics[1].Bytes())
+		if !slices.Contains(s.ledger.Minipools, address) {
+			s.ledger.Minipools = append(s.ledger.Minipools, address)
+		}
+	}
+	return nil
+}
+
+// Record the RPL prices reported by the Oracle DAO
+func (s *ledgerScanner) scanPriceUpdates() error {
+	rocketNetworkPrices, err := s.rp.GetContract("rocketNetworkPrices", nil)
+	if err != nil {
+		return err
+	}
+	event := rocketNetworkPrices.ABI.Events["PricesUpdated"]
+	logs, err := eth.FilterContractLogs(s.rp, "rocketNetworkPrices", eth.FilterQuery{
+		FromBlock: s.fromBlock,
+		ToBlock:   s.toBlock,
+		Topics:    [][]common.Hash{{event.ID}},
+	}, s.intervalSize, nil)
+	if err != nil {
+		return fmt.Errorf("error getting RPL price updates: %w", err)
+	}
+
+	for _, log := range logs {
+		values, err := unpackLedgerEvent(event, log)
+		if err != nil {
+			return err
+		}
+		price, ok := values["rplPrice"].(*big.Int)
+		if !ok {
+			continue
+		}
+		s.ledger.PriceUpdates = append(s.ledger.PriceUpdates, RplPriceUpdate{
+			Block: log.BlockNumber,
+			Price: QuotedBigIntFromBigInt(price),
+		})
+	}
+	return nil
+}
+
+// Record the node's rewards claims
+func (s *ledgerScanner) scanClaims() error {
+	rocketMerkleDistributorMainnet, err := s.rp.GetContract("rocketMerkleDistributorMainnet", nil)
+	if err != nil {
+		return err
+	}
+	event := rocketMerkleDistributorMainnet.ABI.Events["RewardsClaimed"]
+	logs, err := eth.FilterContractLogs(s.rp, "rocketMerkleDistributorMainnet", eth.FilterQuery{
+		FromBlock: s.fromBlock,
+		ToBlock:   s.toBlock,
+		Topics:    [][]common.Hash{{event.ID}, {common.BytesToHash(s.nodeAddress.Bytes())}},
+	}, s.intervalSize, nil)
+	if err != nil {
+		return fmt.Errorf("error getting rewards claims: %w", err)
+	}
+
+	for _, log := range logs {
+		values, err := unpackLedgerEvent(event, log)
+		if err != nil {
+			return err
+		}
+		indices, _ := values["rewardIndex"].([]*big.Int)
+		rplAmounts, _ := values["amountRPL"].([]*big.Int)
+		ethAmounts, _ := values["amountETH"].([]*big.Int)
+		blockTime, err := s.getBlockTime(log.BlockNumber)
+		if err != nil {
+			return err
+		}
+		for i, index := range indices {
+			interval := index.Uint64()
+			txHash := log.TxHash
+			entry := RewardsLedgerEntry{
+				Type:     LedgerEntry_Claim,
+				Interval: &interval,
+				Time:     blockTime,
+				Block:    log.BlockNumber,
+				TxHash:   &txHash,
+			}
+			if i < len(rplAmounts) {
+				entry.RplAmount = QuotedBigIntFromBigInt(rplAmounts[i])
+			}
+			if i < len(ethAmounts) {
+				entry.EthAmount = QuotedBigIntFromBigInt(ethAmounts[i])
+			}
+			s.ledger.Entries = append(s.ledger.Entries, entry)
+		}
+	}
+	return nil
+}
+
+// Record the node's share of each minipool balance distribution
+func (s *ledgerScanner) scanMinipoolDistributions(minipoolAddresses []common.Address) error {
+	if len(minipoolAddresses) == 0 {
+		return nil
+	}
+	// Only the event ABI is needed, so don't query the minipool; it may have been closed already
+	mp, err := minipool.NewMinipoolFromVersion(s.rp, minipoolAddresses[0], 3, nil)
+	if err != nil {
+		return err
+	}
+	event, exists := mp.GetContract().ABI.Events["EtherWithdrawalProcessed"]
+	if !exists {
+		return nil
+	}
+	logs, err := eth.GetLogs(s.rp, minipoolAddresses, [][]common.Hash{{event.ID}}, s.intervalSize, s.fromBlock, s.toBlock, nil)
+	if err != nil {
+		return fmt.Errorf("error getting minipool distributions: %w", err)
+	}
+
+	for _, log := range logs {
+		values, err := unpackLedgerEvent(event, log)
+		if err != nil {
+			return err
+		}
+		nodeAmount, ok := values["nodeAmount"].(*big.Int)
+		if !ok {
+			continue
+		}
+		entryType := LedgerEntry_MinipoolDistribution
+		if totalBalance, ok := values["totalBalance"].(*big.Int); ok && totalBalance.Cmp(skimThreshold) < 0 {
+			entryType = LedgerEntry_ConsensusSkim
+		}
+		if err := s.addDistribution(entryType, log, values, nodeAmount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record the node's share of each megapool rewards distribution
+func (s *ledgerScanner) scanMegapoolDistributions(megapoolAddress common.Address) error {
+	mp, err := megapool.NewMegaPoolV1(s.rp, megapoolAddress, nil)
+	if err != nil {
+		return err
+	}
+	event, exists := mp.GetContract().ABI.Events["RewardsDistributed"]
+	if !exists {
+		return nil
+	}
+	logs, err := eth.GetLogs(s.rp, []common.Address{megapoolAddress}, [][]common.Hash{{event.ID}}, s.intervalSize, s.fromBlock, s.toBlock, nil)
+	if err != nil {
+		return fmt.Errorf("error getting megapool distributions: %w", err)
+	}
+
+	for _, log := range logs {
+		values, err := unpackLedgerEvent(event, log)
+		if err != nil {
+			return err
+		}
+		nodeAmount, ok := values["nodeAmount"].(*big.Int)
+		if !ok {
+			continue
+		}
+		if err := s.addDistribution(LedgerEntry_MegapoolDistribution, log, values, nodeAmount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add a distribution to the ledger, using the time from the event if it has one
+func (s *ledgerScanner) addDistribution(entryType RewardsLedgerEntryType, log ethtypes.Log, values map[string]interface{}, nodeAmount *big.Int) error {
+	var blockTime time.Time
+	if eventTime, ok := values["time"].(*big.Int); ok {
+		blockTime = time.Unix(eventTime.Int64(), 0)
+	} else {
+		var err error
+		blockTime, err = s.getBlockTime(log.BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	txHash := log.TxHash
+	validator := log.Address
+	s.ledger.Entries = append(s.ledger.Entries, RewardsLedgerEntry{
+		Type:      entryType,
+		Time:      blockTime,
+		Block:     log.BlockNumber,
+		TxHash:    &txHash,
+		Validator: &validator,
+		EthAmount: QuotedBigIntFromBigInt(nodeAmount),
+	})
+	return nil
+}
+
+// Get the timestamp of a block
+func (s *ledgerScanner) getBlockTime(block uint64) (time.Time, error) {
+	if blockTime, exists := s.blockTimes[block]; exists {
+		return blockTime, nil
+	}
+	header, err := s.rp.Client.HeaderByNumber(context.Background(), big.NewInt(0).SetUint64(block))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting header for block %d: %w", block, err)
+	}
+	blockTime := time.Unix(int64(header.Time), 0)
+	s.blockTimes[block] = blockTime
+	return blockTime, nil
+}
+
+// Decode the non-indexed values of an event log
+func unpackLedgerEvent(event abi.Event, log ethtypes.Log) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if err := event.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+		return nil, fmt.Errorf("error unpacking %s event in tx %s: %w", event.Name, log.TxHash.Hex(), err)
+	}
+	return values, nil
+}
@@ -0,0 +1,193 @@
+package rewards
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/bindings/types"
+)
+
+func TestRewardsLedgerPrices(t *testing.T) {
+	ledger := &RewardsLedger{
+		PriceUpdates: []RplPriceUpdate{
+			{Block: 200, Price: NewQuotedBigInt(20)},
+			{Block: 100, Price: NewQuotedBigInt(10)},
+		},
+		Entries: []RewardsLedgerEntry{
+			{Type: LedgerEntry_RplInflation, Block: 50},
+			{Type: LedgerEntry_RplInflation, Block: 100},
+			{Type: LedgerEntry_RplInflation, Block: 199},
+			{Type: LedgerEntry_RplInflation, Block: 500},
+		},
+	}
+	ledger.setPrices()
+
+	if ledger.Entries[0].RplPrice != nil {
+		t.Fatalf("expected no price before the first update, got %s", ledger.Entries[0].RplPrice.String())
+	}
+	expected := []int64{10, 10, 20}
+	for i, price := range expected {
+		entry := ledger.Entries[i+1]
+		if entry.RplPrice == nil || entry.RplPrice.Int64() != price {
+			t.Fatalf("expected price %d for block %d, got %v", price, entry.Block, entry.RplPrice)
+		}
+	}
+}
+
+func TestRewardsLedgerCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rewards-history.json")
+	nodeAddress := common.HexToAddress("0x1234")
+	interval := uint64(3)
+
+	ledger, err := loadRewardsLedger(path, nodeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger.LastScannedBlock = 1000
+	ledger.Intervals = []uint64{interval}
+	ledger.Entries = append(ledger.Entries, RewardsLedgerEntry{
+		Type:      LedgerEntry_SmoothingPoolEth,
+		Interval:  &interval,
+		Block:     900,
+		EthAmount: NewQuotedBigInt(12345),
+	})
+	if err := ledger.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same node should pick up where it left off
+	cached, err := loadRewardsLedger(path, nodeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.LastScannedBlock != 1000 || len(cached.Intervals) != 1 || len(cached.Entries) != 1 {
+		t.Fatalf("cached ledger wasn't restored: %+v", cached)
+	}
+	if cached.Entries[0].EthAmount.Int64() != 12345 || *cached.Entries[0].Interval != interval {
+		t.Fatalf("cached entry wasn't restored: %+v", cached.Entries[0])
+	}
+
+	// A different node should start from scratch
+	other, err := loadRewardsLedger(path, common.HexToAddress("0x5678"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.LastScannedBlock != 0 || len(other.Entries) != 0 {
+		t.Fatalf("ledger for a different node should be empty: %+v", other)
+	}
+}
+
+func TestRewardsLedgerInterval(t *testing.T) {
+	node := common.HexToAddress("0x01")
+	active := common.HexToAddress("0x10")
+	closed := common.HexToAddress("0x11")
+	other := common.HexToAddress("0x12")
+	activePubkey := types.ValidatorPubkey{0x01}
+	closedPubkey := types.ValidatorPubkey{0x02}
+
+	// The node earned 200 wei from the Smoothing Pool, 150 of which can be attributed to its minipools
+	rewardsFile := newVerifyTestFile(t, map[common.Address]int64{node: 100})
+	performanceFile := &MinipoolPerformanceFile_v2{
+		MinipoolPerformance: map[common.Address]*SmoothingPoolMinipoolPerformance_v2{
+			active: {Pubkey: activePubkey.Hex(), EthEarned: NewQuotedBigInt(100)},
+			closed: {Pubkey: closedPubkey.Hex(), EthEarned: NewQuotedBigInt(40), BonusEthEarned: NewQuotedBigInt(10)},
+			other:  {Pubkey: types.ValidatorPubkey{0x03}.Hex(), EthEarned: NewQuotedBigInt(1000)},
+		},
+	}
+	ledger := &RewardsLedger{
+		NodeAddress: node,
+		Minipools:   []common.Address{active, closed},
+	}
+	if err := ledger.addInterval(7, rewardsFile, performanceFile); err != nil {
+		t.Fatal(err)
+	}
+
+	smoothingPool := map[types.ValidatorPubkey]int64{}
+	var unattributed int64
+	for _, entry := range ledger.Entries {
+		switch entry.Type {
+		case LedgerEntry_RplInflation:
+			if entry.RplAmount.Int64() != 100 {
+				t.Fatalf("expected 100 RPL of inflation, got %s", entry.RplAmount.String())
+			}
+		case LedgerEntry_SmoothingPoolEth:
+			if entry.Pubkey == nil {
+				unattributed += entry.EthAmount.Int64()
+				continue
+			}
+			smoothingPool[*entry.Pubkey] = entry.EthAmount.Int64()
+		default:
+			t.Fatalf("unexpected entry type %s", entry.Type)
+		}
+	}
+	if len(smoothingPool) != 2 || smoothingPool[activePubkey] != 100 || smoothingPool[closedPubkey] != 50 {
+		t.Fatalf("expected the Smoothing Pool rewards to be split by validator, got %v", smoothingPool)
+	}
+	if unattributed != 50 {
+		t.Fatalf("expected 50 wei to be recorded against the node, got %d", unattributed)
+	}
+
+	// Without a performance file, everything is recorded against the node
+	ledger = &RewardsLedger{
+		NodeAddress: node,
+		Minipools:   []common.Address{active, closed},
+	}
+	if err := ledger.addInterval(7, rewardsFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Entries) != 2 || ledger.Entries[1].Pubkey != nil || ledger.Entries[1].EthAmount.Int64() != 200 {
+		t.Fatalf("expected a single node-level Smoothing Pool entry, got %+v", ledger.Entries)
+	}
+}
+
+func TestRewardsLedgerEntryValue(t *testing.T) {
+	price := NewQuotedBigInt(0)
+	price.SetString("20000000000000000", 10) // 0.02 ETH per RPL
+	rpl := NewQuotedBigInt(0)
+	rpl.SetString("1000000000000000000", 10)
+
+	inflation := RewardsLedgerEntry{Type: LedgerEntry_RplInflation, RplAmount: rpl, RplPrice: price}
+	if value := inflation.GetEthValue(); value == nil || value.String() != "20000000000000000" {
+		t.Fatalf("expected 1 RPL to be worth 0.02 ETH, got %v", value)
+	}
+
+	distribution := RewardsLedgerEntry{Type: LedgerEntry_MinipoolDistribution, EthAmount: NewQuotedBigInt(5)}
+	if value := distribution.GetEthValue(); value == nil || value.Int64() != 5 {
+		t.Fatalf("expected a distribution to be worth its ETH, got %v", value)
+	}
+
+	unpriced := RewardsLedgerEntry{Type: LedgerEntry_OracleDaoRpl, RplAmount: rpl}
+	if value := unpriced.GetEthValue(); value != nil {
+		t.Fatalf("expected RPL without a price to have no value, got %s", value.String())
+	}
+
+	// Claims move rewards that were already counted, so they shouldn't be counted again
+	claim := RewardsLedgerEntry{Type: LedgerEntry_Claim, RplAmount: rpl, EthAmount: NewQuotedBigInt(5), RplPrice: price}
+	if value := claim.GetEthValue(); value != nil {
+		t.Fatalf("expected a claim to have no value, got %s", value.String())
+	}
+}
+
+func TestRewardsLedgerScanRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastScanned  uint64
+		currentBlock uint64
+		from         uint64
+		to           uint64
+		complete     bool
+	}{
+		{"first page", 0, 10000, 1000, 1999, false},
+		{"next page", 1999, 10000, 2000, 2999, false},
+		{"last page", 9499, 10000, 9500, 10000, true},
+		{"exact page", 8999, 10000, 9000, 9999, false},
+		{"up to date", 10000, 10000, 10001, 10000, true},
+	}
+	for _, test := range tests {
+		from, to, complete := getLedgerScanRange(test.lastScanned, 1000, test.currentBlock, 1000)
+		if from != test.from || to != test.to || complete != test.complete {
+			t.Errorf("%s: expected %d-%d (complete: %t), got %d-%d (complete: %t)", test.name, test.from, test.to, test.complete, from, to, complete)
+		}
+	}
+}
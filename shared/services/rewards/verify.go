@@ -0,0 +1,161 @@
+package rewards
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The result of rebuilding a rewards file's Merkle tree
+type TreeVerification struct {
+	FileMerkleRoot     common.Hash `json:"fileMerkleRoot"`
+	ComputedMerkleRoot common.Hash `json:"computedMerkleRoot"`
+	NodeIncluded       bool        `json:"nodeIncluded"`
+	NodeProofValid     bool        `json:"nodeProofValid"`
+}
+
+// A validator's Smoothing Pool performance as recorded in a minipool performance file
+type ValidatorPerformance struct {
+	Address                common.Address `json:"address"`
+	SuccessfulAttestations uint64         `json:"successfulAttestations"`
+	MissedAttestations     uint64         `json:"missedAttestations"`
+	EthEarned              *big.Int       `json:"ethEarned"`
+}
+
+// A field that differs between two rewards files
+type RewardsFileDifference struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Rebuilds the Merkle tree from the rewards in the file and checks that the root and the node's proof match the ones the file came with.
+// NOTE: this replaces the file's Merkle root and proofs with the recomputed ones.
+func VerifyRewardsFile(rewardsFile IRewardsFile, nodeAddress common.Address) (TreeVerification, error) {
+	verification := TreeVerification{
+		FileMerkleRoot: common.HexToHash(rewardsFile.GetMerkleRoot()),
+		NodeIncluded:   rewardsFile.HasRewardsFor(nodeAddress),
+	}
+
+	// Get the node's proof before it's regenerated
+	var fileProof []common.Hash
+	if verification.NodeIncluded {
+		var err error
+		fileProof, err = rewardsFile.GetMerkleProof(nodeAddress)
+		if err != nil {
+			return verification, fmt.Errorf("error reading Merkle proof for node %s: %w", nodeAddress.Hex(), err)
+		}
+	}
+
+	// Rebuild the tree
+	if err := rewardsFile.GenerateMerkleTree(); err != nil {
+		return verification, err
+	}
+	verification.ComputedMerkleRoot = common.HexToHash(rewardsFile.GetMerkleRoot())
+
+	// Compare the node's proof
+	if verification.NodeIncluded {
+		computedProof, err := rewardsFile.GetMerkleProof(nodeAddress)
+		if err != nil {
+			return verification, fmt.Errorf("error generating Merkle proof for node %s: %w", nodeAddress.Hex(), err)
+		}
+		verification.NodeProofValid = slices.Equal(fileProof, computedProof)
+	}
+	return verification, nil
+}
+
+// Gets the Smoothing Pool performance of the provided validators from a minipool performance file, along with the total ETH they earned
+func GetValidatorPerformance(performanceFile IMinipoolPerformanceFile, validators []common.Address) ([]ValidatorPerformance, *big.Int) {
+	performance := []ValidatorPerformance{}
+	total := big.NewInt(0)
+	for _, validator := range validators {
+		validatorPerformance, exists := performanceFile.GetSmoothingPoolPerformance(validator)
+		if !exists {
+			continue
+		}
+		ethEarned := big.NewInt(0).Set(validatorPerformance.GetEthEarned())
+		if bonus := validatorPerformance.GetBonusEthEarned(); bonus != nil {
+			ethEarned.Add(ethEarned, bonus)
+		}
+		performance = append(performance, ValidatorPerformance{
+			Address:                validator,
+			SuccessfulAttestations: validatorPerformance.GetSuccessfulAttestationCount(),
+			MissedAttestations:     validatorPerformance.GetMissedAttestationCount(),
+			EthEarned:              ethEarned,
+		})
+		total.Add(total, ethEarned)
+	}
+	return performance, total
+}
+
+// Compares two rewards files for the same interval field by field, returning every field where actual differs from expected.
+// Node rewards are compared for every node in either file.
+func DiffRewardsFiles(expected IRewardsFile, actual IRewardsFile) []RewardsFileDifference {
+	differences := []RewardsFileDifference{}
+	compare := func(field string, expected any, actual any) {
+		expectedString := fmt.Sprint(expected)
+		actualString := fmt.Sprint(actual)
+		if expectedString != actualString {
+			differences = append(differences, RewardsFileDifference{
+				Field:    field,
+				Expected: expectedString,
+				Actual:   actualString,
+			})
+		}
+	}
+
+	// Header
+	compare("index", expected.GetIndex(), actual.GetIndex())
+	compare("intervalsPassed", expected.GetIntervalsPassed(), actual.GetIntervalsPassed())
+	compare("startTime", expected.GetStartTime().UTC(), actual.GetStartTime().UTC())
+	compare("endTime", expected.GetEndTime().UTC(), actual.GetEndTime().UTC())
+	compare("executionStartBlock", expected.GetExecutionStartBlock(), actual.GetExecutionStartBlock())
+	compare("executionEndBlock", expected.GetExecutionEndBlock(), actual.GetExecutionEndBlock())
+	compare("consensusStartBlock", expected.GetConsensusStartBlock(), actual.GetConsensusStartBlock())
+	compare("consensusEndBlock", expected.GetConsensusEndBlock(), actual.GetConsensusEndBlock())
+	compare("merkleRoot", expected.GetMerkleRoot(), actual.GetMerkleRoot())
+
+	// Totals
+	compare("totalRewards.protocolDaoRpl", expected.GetTotalProtocolDaoRpl(), actual.GetTotalProtocolDaoRpl())
+	compare("totalRewards.totalOracleDaoRpl", expected.GetTotalOracleDaoRpl(), actual.GetTotalOracleDaoRpl())
+	compare("totalRewards.totalCollateralRpl", expected.GetTotalCollateralRpl(), actual.GetTotalCollateralRpl())
+	compare("totalRewards.nodeOperatorSmoothingPoolEth", expected.GetTotalNodeOperatorSmoothingPoolEth(), actual.GetTotalNodeOperatorSmoothingPoolEth())
+	compare("totalRewards.poolStakerSmoothingPoolEth", expected.GetTotalPoolStakerSmoothingPoolEth(), actual.GetTotalPoolStakerSmoothingPoolEth())
+	compare("totalRewards.totalNodeWeight", expected.GetTotalNodeWeight(), actual.GetTotalNodeWeight())
+
+	// Networks
+	for network := uint64(0); expected.HasRewardsForNetwork(network) || actual.HasRewardsForNetwork(network); network++ {
+		prefix := fmt.Sprintf("networkRewards[%d]", network)
+		compare(prefix+".collateralRpl", expected.GetNetworkCollateralRpl(network), actual.GetNetworkCollateralRpl(network))
+		compare(prefix+".oracleDaoRpl", expected.GetNetworkOracleDaoRpl(network), actual.GetNetworkOracleDaoRpl(network))
+		compare(prefix+".smoothingPoolEth", expected.GetNetworkSmoothingPoolEth(network), actual.GetNetworkSmoothingPoolEth(network))
+	}
+
+	// Nodes
+	nodes := map[common.Address]bool{}
+	for _, address := range expected.GetNodeAddresses() {
+		nodes[address] = true
+	}
+	for _, address := range actual.GetNodeAddresses() {
+		nodes[address] = true
+	}
+	addresses := make([]common.Address, 0, len(nodes))
+	for address := range nodes {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Cmp(addresses[j]) < 0
+	})
+	for _, address := range addresses {
+		prefix := fmt.Sprintf("nodeRewards[%s]", address.Hex())
+		compare(prefix+".included", expected.HasRewardsFor(address), actual.HasRewardsFor(address))
+		compare(prefix+".collateralRpl", expected.GetNodeCollateralRpl(address), actual.GetNodeCollateralRpl(address))
+		compare(prefix+".oracleDaoRpl", expected.GetNodeOracleDaoRpl(address), actual.GetNodeOracleDaoRpl(address))
+		compare(prefix+".smoothingPoolEth", expected.GetNodeSmoothingPoolEth(address), actual.GetNodeSmoothingPoolEth(address))
+	}
+
+	return differences
+}
@@ -0,0 +1,174 @@
+package rewards
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/bindings/types"
+)
+
+// Make a small rewards file with a Merkle tree for a few nodes
+func newVerifyTestFile(t *testing.T, nodes map[common.Address]int64) *RewardsFile_v3 {
+	file := &RewardsFile_v3{
+		RewardsFileHeader: &RewardsFileHeader{
+			RewardsFileVersion: 3,
+			Index:              7,
+			TotalRewards: &TotalRewards{
+				ProtocolDaoRpl:               NewQuotedBigInt(0),
+				TotalCollateralRpl:           NewQuotedBigInt(0),
+				TotalOracleDaoRpl:            NewQuotedBigInt(0),
+				TotalSmoothingPoolEth:        NewQuotedBigInt(0),
+				PoolStakerSmoothingPoolEth:   NewQuotedBigInt(0),
+				NodeOperatorSmoothingPoolEth: NewQuotedBigInt(0),
+				TotalNodeWeight:              NewQuotedBigInt(0),
+			},
+			NetworkRewards: map[uint64]*NetworkRewardsInfo{},
+		},
+		NodeRewards: map[common.Address]*NodeRewardsInfo_v2{},
+	}
+	for address, amount := range nodes {
+		file.NodeRewards[address] = &NodeRewardsInfo_v2{
+			CollateralRpl:    NewQuotedBigInt(amount),
+			OracleDaoRpl:     NewQuotedBigInt(0),
+			SmoothingPoolEth: NewQuotedBigInt(amount * 2),
+		}
+	}
+	if err := file.GenerateMerkleTree(); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestVerifyRewardsFile(t *testing.T) {
+	node := common.HexToAddress("0x01")
+	nodes := map[common.Address]int64{
+		node:                        100,
+		common.HexToAddress("0x02"): 200,
+		common.HexToAddress("0x03"): 300,
+	}
+
+	// An untouched file should verify
+	file := newVerifyTestFile(t, nodes)
+	verification, err := VerifyRewardsFile(file, node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.ComputedMerkleRoot != verification.FileMerkleRoot || !verification.NodeIncluded || !verification.NodeProofValid {
+		t.Fatalf("expected an untouched file to verify: %+v", verification)
+	}
+
+	// Tampering with another node's rewards should change the root and our proof
+	file = newVerifyTestFile(t, nodes)
+	file.NodeRewards[common.HexToAddress("0x02")].CollateralRpl = NewQuotedBigInt(201)
+	verification, err = VerifyRewardsFile(file, node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.ComputedMerkleRoot == verification.FileMerkleRoot {
+		t.Fatal("expected a tampered file to produce a different root")
+	}
+	if verification.NodeProofValid {
+		t.Fatal("expected the node's proof to change after tampering")
+	}
+}
+
+func TestDiffRewardsFiles(t *testing.T) {
+	node := common.HexToAddress("0x01")
+	nodes := map[common.Address]int64{
+		node:                        100,
+		common.HexToAddress("0x02"): 200,
+	}
+	expected := newVerifyTestFile(t, nodes)
+	actual := newVerifyTestFile(t, nodes)
+	if differences := DiffRewardsFiles(expected, actual); len(differences) != 0 {
+		t.Fatalf("expected identical files to have no differences, got %v", differences)
+	}
+
+	actual.NodeRewards[node].SmoothingPoolEth = NewQuotedBigInt(1)
+	actual.ExecutionEndBlock = 12345
+	differences := DiffRewardsFiles(expected, actual)
+	fields := map[string]RewardsFileDifference{}
+	for _, difference := range differences {
+		fields[difference.Field] = difference
+	}
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 differences, got %v", differences)
+	}
+	if difference, exists := fields["executionEndBlock"]; !exists || difference.Actual != "12345" {
+		t.Fatalf("expected the execution end block to differ, got %v", differences)
+	}
+	for field, difference := range fields {
+		if strings.HasPrefix(field, "nodeRewards[") {
+			if !strings.Contains(field, node.Hex()) || difference.Expected != "200" || difference.Actual != "1" {
+				t.Fatalf("unexpected node difference %+v", difference)
+			}
+		}
+	}
+}
+
+func TestCompareAttestationRecords(t *testing.T) {
+	const slotsPerEpoch = 32
+	minipool := common.HexToAddress("0x10")
+	pubkey := types.ValidatorPubkey{0x01}
+
+	// The interval covers epochs 10 through 19
+	rewardsFile := newVerifyTestFile(t, map[common.Address]int64{common.HexToAddress("0x01"): 100})
+	rewardsFile.ConsensusStartBlock = 10 * slotsPerEpoch
+	rewardsFile.ConsensusEndBlock = 20*slotsPerEpoch - 1
+
+	// The tree says the validator missed slots in epochs 11, 12 and 18
+	performanceFile := &MinipoolPerformanceFile_v2{
+		MinipoolPerformance: map[common.Address]*SmoothingPoolMinipoolPerformance_v2{
+			minipool: {
+				Pubkey:                  pubkey.Hex(),
+				MissingAttestationSlots: []uint64{11*slotsPerEpoch + 3, 12*slotsPerEpoch + 5, 18*slotsPerEpoch + 1},
+			},
+		},
+	}
+
+	// The node recorded epochs 9 through 14 in one interval and 15 through 16 in the next, and saw misses in epochs 11 and 13
+	current := NewAttestationRecord(7, slotsPerEpoch)
+	next := NewAttestationRecord(8, slotsPerEpoch)
+	for epoch := uint64(9); epoch <= 14; epoch++ {
+		var missedSlot *uint64
+		switch epoch {
+		case 11:
+			slot := uint64(11*slotsPerEpoch + 3)
+			missedSlot = &slot
+		case 13:
+			slot := uint64(13*slotsPerEpoch + 7)
+			missedSlot = &slot
+		}
+		current.AddDuty(pubkey, epoch, missedSlot)
+	}
+	current.AddDuty(pubkey, 12, nil)
+	for epoch := uint64(15); epoch <= 16; epoch++ {
+		next.AddDuty(pubkey, epoch, nil)
+	}
+	if ranges := current.Validators[pubkey.Hex()].CheckedEpochs; len(ranges) != 1 || ranges[0].Start != 9 || ranges[0].End != 14 {
+		t.Fatalf("expected contiguous epochs to be merged into one range, got %v", ranges)
+	}
+
+	comparisons, err := CompareAttestationRecords(rewardsFile, performanceFile, []common.Address{minipool, common.HexToAddress("0x11")}, []*AttestationRecord{current, next})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comparisons) != 1 {
+		t.Fatalf("expected 1 comparison, got %d", len(comparisons))
+	}
+	comparison := comparisons[0]
+
+	// Epoch 9 is before the interval
+	if comparison.CheckedEpochs != 7 {
+		t.Fatalf("expected 7 checked epochs, got %d", comparison.CheckedEpochs)
+	}
+
+	// Epoch 12 was attested according to the node, epoch 18 wasn't checked
+	if len(comparison.DisputedSlots) != 1 || comparison.DisputedSlots[0] != 12*slotsPerEpoch+5 {
+		t.Fatalf("expected the miss in epoch 12 to be disputed, got %v", comparison.DisputedSlots)
+	}
+	if len(comparison.UnpenalizedSlots) != 1 || comparison.UnpenalizedSlots[0] != 13*slotsPerEpoch+7 {
+		t.Fatalf("expected the miss in epoch 13 to be unpenalized, got %v", comparison.UnpenalizedSlots)
+	}
+}
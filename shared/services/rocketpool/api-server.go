@@ -0,0 +1,189 @@
+package rocketpool
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/types/api"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
+)
+
+// Config
+const (
+	apiServerVersion string = "v1"
+	apiServerHost    string = "127.0.0.1"
+)
+
+// Call the Rocket Pool API through the HTTP API server if it's enabled and reachable.
+// Returns false if the server couldn't be used and the caller should fall back to running the API command directly.
+func (c *Client) callAPIServer(args string, otherArgs ...string) ([]byte, bool, error) {
+
+	// Get the route and arguments
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return nil, false, nil
+	}
+	group := fields[0]
+	command := fields[1]
+	commandArgs := append(fields[2:], otherArgs...)
+
+	// Remote nodes can only be reached through their API server, so there's nothing to fall back to
+	remote := c.apiUrl != ""
+	if remote {
+		if err := ValidateApiUrl(c.apiUrl); err != nil {
+			return nil, true, err
+		}
+		if api.IsSecretServerRoute(group, command) {
+			return nil, true, fmt.Errorf("the `%s %s` command returns the node's secrets, so it can't be run on a remote node; please run it on the node itself", group, command)
+		}
+	}
+
+	// Make sure the server is enabled
+	cfg, isNew, err := c.LoadConfig()
+	if !remote && (err != nil || isNew || !cfg.IsApiServerEnabled()) {
+		return nil, false, nil
+	}
+
+	// Get the auth token
+	token, err := c.getApiServerToken(cfg)
+	if err != nil {
+		if remote {
+			return nil, true, err
+		}
+		if c.debugPrint {
+			fmt.Printf("Not using the API server: %s\n", err.Error())
+		}
+		return nil, false, nil
+	}
+
+	// Build the request
+	request := api.ServerRequest{
+		Args:            commandArgs,
+		MaxFee:          c.maxFee,
+		MaxPrioFee:      c.maxPrioFee,
+		GasLimit:        c.gasLimit,
+		IgnoreSyncCheck: c.ignoreSyncCheck,
+		ForceFallbacks:  c.forceFallbacks,
+		Offline:         c.offline,
+	}
+	if c.customNonce != nil {
+		request.Nonce = c.customNonce.String()
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, true, fmt.Errorf("error serializing API server request: %w", err)
+	}
+	serverUrl := c.apiUrl
+	if !remote {
+		serverUrl = fmt.Sprintf("http://%s:%d", apiServerHost, cfg.Smartnode.ApiServerPort.Value.(uint16))
+	}
+	url := fmt.Sprintf("%s/api/%s/%s/%s", serverUrl, apiServerVersion, group, command)
+	httpRequest, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, true, fmt.Errorf("error creating API server request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Authorization", "Bearer "+token)
+
+	if c.debugPrint {
+		fmt.Println("To API Server:")
+		fmt.Printf("%s %s\n", url, string(body))
+	}
+
+	// Send it
+	httpClient, err := c.getApiServerHttpClient()
+	if err != nil {
+		return nil, true, err
+	}
+	response, err := httpClient.Do(httpRequest)
+	if err != nil {
+		// Only fall back if the server couldn't be reached at all, since otherwise the command may have already run
+		var opErr *net.OpError
+		if !remote && errors.As(err, &opErr) && opErr.Op == "dial" {
+			if c.debugPrint {
+				fmt.Printf("API server unavailable, falling back: %s\n", err.Error())
+			}
+			return nil, false, nil
+		}
+		return nil, true, fmt.Errorf("error calling API server: %w", err)
+	}
+	defer response.Body.Close()
+	output, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("error reading API server response: %w", err)
+	}
+
+	if c.debugPrint {
+		fmt.Println("API Server Out:")
+		fmt.Println(string(output))
+	}
+
+	// Unauthorized, forbidden (e.g. secret exports) or unknown routes (e.g. a daemon on an older version) are handled by the regular API path
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusNotFound {
+		if remote {
+			return nil, true, fmt.Errorf("API server at %s returned %s", c.apiUrl, response.Status)
+		}
+		return nil, false, nil
+	}
+
+	// Reset the gas settings after the call
+	c.maxFee = c.originalMaxFee
+	c.maxPrioFee = c.originalMaxPrioFee
+	c.gasLimit = c.originalGasLimit
+
+	return output, true, nil
+
+}
+
+// Get the API server's auth token
+func (c *Client) getApiServerToken(cfg *config.RocketPoolConfig) (string, error) {
+	path := c.apiTokenPath
+	if path == "" {
+		if cfg == nil {
+			return "", fmt.Errorf("no API token path is set and the config couldn't be loaded")
+		}
+		path = cfg.Smartnode.GetApiTokenPathInCLI()
+	}
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return "", fmt.Errorf("error expanding API token path: %w", err)
+	}
+	return apiutils.ReadApiToken(path)
+}
+
+// Get the HTTP client for the API server, trusting the profile's CA certificate if it has one (e.g. for a self-signed server certificate)
+func (c *Client) getApiServerHttpClient() (*http.Client, error) {
+	if c.apiCaCertPath == "" {
+		return http.DefaultClient, nil
+	}
+	path, err := homedir.Expand(c.apiCaCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding API server CA certificate path: %w", err)
+	}
+	cert, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading API server CA certificate [%s]: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(cert) {
+		return nil, fmt.Errorf("API server CA certificate [%s] doesn't contain any PEM certificates", path)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -1,6 +1,7 @@
 package rocketpool
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -8,8 +9,14 @@ import (
 	"github.com/rocket-pool/smartnode/shared/types/api"
 )
 
+// Returned when waiting for a transaction that was exported for offline signing, since it won't be mined until it's signed and broadcast separately
+var ErrTransactionNotSubmitted = errors.New("the transaction was exported for offline signing and hasn't been submitted")
+
 // Wait for a transaction
 func (c *Client) WaitForTransaction(txHash common.Hash) (api.APIResponse, error) {
+	if c.offline {
+		return api.APIResponse{}, ErrTransactionNotSubmitted
+	}
 	responseBytes, err := c.callAPI(fmt.Sprintf("wait %s", txHash.String()))
 	if err != nil {
 		return api.APIResponse{}, fmt.Errorf("Error waiting for tx: %w", err)
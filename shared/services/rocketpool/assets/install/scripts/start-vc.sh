@@ -61,6 +61,18 @@ if [ "$CC_CLIENT" = "lighthouse" ]; then
         CMD="$CMD --builder-proposals --prefer-builder-proposals"
     fi
 
+    if [ "$ENABLE_KEYMANAGER_API" = "true" ]; then
+        CMD="$CMD --http --http-address 0.0.0.0 --http-port $KEYMANAGER_API_PORT --unencrypted-http-transport --http-token-path /validators/$KEYMANAGER_TOKEN_FILE"
+    fi
+
+    # Lighthouse can't fetch keys from Web3Signer itself; the node process registers them as remote keys through the Keymanager API
+    if [ ! -z "$WEB3SIGNER_URL" ]; then
+        if [ "$ENABLE_KEYMANAGER_API" != "true" ]; then
+            echo "Lighthouse needs the Keymanager API to use keys held by Web3Signer, please enable it with 'rocketpool service config'."
+            exit 1
+        fi
+    fi
+
     if [ "$ENABLE_METRICS" = "true" ]; then
         CMD="$CMD --metrics --metrics-address 0.0.0.0 --metrics-port $VC_METRICS_PORT"
     fi
@@ -112,6 +124,14 @@ if [ ! -z "$VC_SUGGESTED_BLOCK_GAS_LIMIT" ]; then
         CMD="$CMD --builder"
     fi
 
+    if [ "$ENABLE_KEYMANAGER_API" = "true" ]; then
+        CMD="$CMD --keymanager --keymanager.address 0.0.0.0 --keymanager.port $KEYMANAGER_API_PORT --keymanager.tokenFile /validators/$KEYMANAGER_TOKEN_FILE"
+    fi
+
+    if [ ! -z "$WEB3SIGNER_URL" ]; then
+        CMD="$CMD --externalSigner.url $WEB3SIGNER_URL --externalSigner.fetch"
+    fi
+
     if [ "$ENABLE_METRICS" = "true" ]; then
         CMD="$CMD --metrics --metrics.address 0.0.0.0 --metrics.port $VC_METRICS_PORT"
     fi
@@ -152,6 +172,14 @@ if [ "$CC_CLIENT" = "nimbus" ]; then
         CMD="$CMD --payload-builder"
     fi
 
+    if [ "$ENABLE_KEYMANAGER_API" = "true" ]; then
+        CMD="$CMD --keymanager --keymanager-address=0.0.0.0 --keymanager-port=$KEYMANAGER_API_PORT --keymanager-token-file=/validators/$KEYMANAGER_TOKEN_FILE"
+    fi
+
+    if [ ! -z "$WEB3SIGNER_URL" ]; then
+        CMD="$CMD --web3-signer-url=$WEB3SIGNER_URL"
+    fi
+
     if [ "$ENABLE_METRICS" = "true" ]; then
         CMD="$CMD --metrics --metrics-address=0.0.0.0 --metrics-port=$VC_METRICS_PORT"
     fi
@@ -192,6 +220,14 @@ if [ "$CC_CLIENT" = "prysm" ]; then
         CMD="$CMD --enable-builder"
     fi
 
+    if [ "$ENABLE_KEYMANAGER_API" = "true" ]; then
+        CMD="$CMD --rpc --http-host 0.0.0.0 --http-port $KEYMANAGER_API_PORT --keymanager-token-file /validators/$KEYMANAGER_TOKEN_FILE"
+    fi
+
+    if [ ! -z "$WEB3SIGNER_URL" ]; then
+        CMD="$CMD --validators-external-signer-url $WEB3SIGNER_URL --validators-external-signer-public-keys $WEB3SIGNER_URL/api/v1/eth2/publicKeys"
+    fi
+
     if [ "$DOPPELGANGER_DETECTION" = "true" ]; then
         CMD="$CMD --enable-doppelganger"
     fi
@@ -253,6 +289,14 @@ if [ "$CC_CLIENT" = "teku" ]; then
         CMD="$CMD --shut-down-when-validator-slashed-enabled=true"
     fi
 
+    if [ "$ENABLE_KEYMANAGER_API" = "true" ]; then
+        CMD="$CMD --validator-api-enabled=true --validator-api-interface=0.0.0.0 --validator-api-port=$KEYMANAGER_API_PORT --validator-api-host-allowlist=* --validator-api-bearer-file=/validators/$KEYMANAGER_TOKEN_FILE --Xvalidator-api-ssl-enabled=false"
+    fi
+
+    if [ ! -z "$WEB3SIGNER_URL" ]; then
+        CMD="$CMD --validators-external-signer-url=$WEB3SIGNER_URL --validators-external-signer-public-keys=external-signer"
+    fi
+
     if [ "$ENABLE_METRICS" = "true" ]; then
         CMD="$CMD --metrics-enabled=true --metrics-interface=0.0.0.0 --metrics-port=$VC_METRICS_PORT --metrics-host-allowlist=*"
     fi
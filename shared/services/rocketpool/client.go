@@ -29,6 +29,7 @@ import (
 	"github.com/rocket-pool/smartnode/shared/services/rocketpool/template"
 	"github.com/rocket-pool/smartnode/shared/types/api"
 	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+	apiutils "github.com/rocket-pool/smartnode/shared/utils/api"
 	"github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
@@ -77,6 +78,10 @@ type Client struct {
 	originalMaxPrioFee float64
 	originalGasLimit   uint64
 	debugPrint         bool
+	offline            bool
+	apiUrl             string
+	apiTokenPath       string
+	apiCaCertPath      string
 	ignoreSyncCheck    bool
 	forceFallbacks     bool
 }
@@ -153,6 +158,7 @@ func NewClientFromCtx(c *cli.Context) *Client {
 		originalMaxPrioFee: c.GlobalFloat64("maxPrioFee"),
 		originalGasLimit:   c.GlobalUint64("gasLimit"),
 		debugPrint:         c.GlobalBool("debug"),
+		offline:            c.GlobalBool("offline"),
 		forceFallbacks:     false,
 		ignoreSyncCheck:    false,
 	}
@@ -161,9 +167,34 @@ func NewClientFromCtx(c *cli.Context) *Client {
 		client.customNonce = nonce.(*big.Int)
 	}
 
+	// Use the node selected with the --node flag
+	if profile, ok := c.App.Metadata["node-profile"]; ok {
+		client.applyProfile(profile.(NodeProfile))
+	}
+
+	return client
+}
+
+// Create new Rocket Pool client for a node profile from CLI context without checking for sync status
+func NewClientFromProfile(c *cli.Context, profile NodeProfile) *Client {
+	client := NewClientFromCtx(c)
+	client.applyProfile(profile)
 	return client
 }
 
+// Point the client at the node in a profile
+func (c *Client) applyProfile(profile NodeProfile) {
+	if profile.ConfigPath != "" {
+		c.configPath = os.ExpandEnv(profile.ConfigPath)
+	}
+	if profile.DaemonPath != "" {
+		c.daemonPath = os.ExpandEnv(profile.DaemonPath)
+	}
+	c.apiUrl = strings.TrimSuffix(profile.ApiUrl, "/")
+	c.apiTokenPath = os.ExpandEnv(profile.ApiTokenPath)
+	c.apiCaCertPath = os.ExpandEnv(profile.ApiCaCertPath)
+}
+
 // Check the status of a newly created client and return it
 // Only use this function from commands that may work without the clients being synced-
 // most users should use WithReady instead
@@ -1202,6 +1233,17 @@ func (c *Client) deployTemplates(cfg *config.RocketPoolConfig, rocketpoolDir str
 		fmt.Printf("%sWARNING: Couldn't create the custom validator key directory (%s). You will not be able to recover any minipool keys you created outside of the Smart Node until you create the folder [%s] manually.%s\n", colorYellow, err.Error(), customKeyDir, colorReset)
 	}
 
+	// Create the API server token so it's owned by the user instead of the API container
+	if cfg.IsApiServerEnabled() {
+		tokenPath, err := homedir.Expand(cfg.Smartnode.GetApiTokenPathInCLI())
+		if err == nil {
+			_, err = apiutils.LoadOrCreateApiToken(tokenPath)
+		}
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't create the API server token (%s). The API container will create one when it starts, but the CLI may not be able to read it.%s\n", colorYellow, err.Error(), colorReset)
+		}
+	}
+
 	// Create the rewards file dir
 	rewardsFileDir, err := homedir.Expand(cfg.Smartnode.GetRewardsTreeDirectory(false))
 	if err != nil {
@@ -1248,6 +1290,14 @@ func (c *Client) composeAddons(cfg *config.RocketPoolConfig, rocketpoolDir strin
 
 // Call the Rocket Pool API
 func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
+	// Use the API server if it's available
+	if output, ok, err := c.callAPIServer(args, otherArgs...); ok {
+		return output, err
+	}
+	if c.apiUrl != "" {
+		return []byte{}, fmt.Errorf("the API server at %s doesn't support the `%s` command", c.apiUrl, args)
+	}
+
 	// Sanitize and parse the args
 	ignoreSyncCheckFlag, forceFallbackECFlag, args := c.getApiCallArgs(args, otherArgs...)
 
@@ -1258,13 +1308,14 @@ func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
 		if err != nil {
 			return []byte{}, err
 		}
-		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s api %s", shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), args)
+		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s api %s", shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getOfflineFlag(), c.getGasOpts(), c.getCustomNonce(), args)
 	} else {
-		cmd = fmt.Sprintf("%s --settings %s %s %s %s %s api %s",
+		cmd = fmt.Sprintf("%s --settings %s %s %s %s %s %s api %s",
 			c.daemonPath,
 			shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, SettingsFile)),
 			ignoreSyncCheckFlag,
 			forceFallbackECFlag,
+			c.getOfflineFlag(),
 			c.getGasOpts(),
 			c.getCustomNonce(),
 			args)
@@ -1276,6 +1327,11 @@ func (c *Client) callAPI(args string, otherArgs ...string) ([]byte, error) {
 
 // Call the Rocket Pool API with some custom environment variables
 func (c *Client) callAPIWithEnvVars(envVars map[string]string, args string, otherArgs ...string) ([]byte, error) {
+	// Environment variables can't be passed to remote nodes
+	if c.apiUrl != "" {
+		return []byte{}, fmt.Errorf("this command can't be run on a remote node; please run it on the node itself")
+	}
+
 	// Sanitize and parse the args
 	ignoreSyncCheckFlag, forceFallbackECFlag, args := c.getApiCallArgs(args, otherArgs...)
 
@@ -1291,18 +1347,19 @@ func (c *Client) callAPIWithEnvVars(envVars map[string]string, args string, othe
 		if err != nil {
 			return []byte{}, err
 		}
-		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s api %s", envArgs, shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getGasOpts(), c.getCustomNonce(), args)
+		cmd = fmt.Sprintf("docker exec %s %s %s %s %s %s %s %s api %s", envArgs, shellescape.Quote(containerName), shellescape.Quote(APIBinPath), ignoreSyncCheckFlag, forceFallbackECFlag, c.getOfflineFlag(), c.getGasOpts(), c.getCustomNonce(), args)
 	} else {
 		envArgs := ""
 		for key, value := range envVars {
 			envArgs += fmt.Sprintf("%s=%s ", key, shellescape.Quote(value))
 		}
-		cmd = fmt.Sprintf("%s %s --settings %s %s %s %s %s api %s",
+		cmd = fmt.Sprintf("%s %s --settings %s %s %s %s %s %s api %s",
 			envArgs,
 			c.daemonPath,
 			shellescape.Quote(fmt.Sprintf("%s/%s", c.configPath, SettingsFile)),
 			ignoreSyncCheckFlag,
 			forceFallbackECFlag,
+			c.getOfflineFlag(),
 			c.getGasOpts(),
 			c.getCustomNonce(),
 			args)
@@ -1387,6 +1444,19 @@ func (c *Client) getGasOpts() string {
 	return opts
 }
 
+// Check if transactions are being exported for offline signing instead of being sent
+func (c *Client) IsOffline() bool {
+	return c.offline
+}
+
+// Get the offline signing flag
+func (c *Client) getOfflineFlag() string {
+	if c.offline {
+		return "--offline"
+	}
+	return ""
+}
+
 func (c *Client) getCustomNonce() string {
 	// Set the custom nonce
 	nonce := ""
@@ -139,6 +139,38 @@ func (c *Client) DownloadRewardsFile(interval uint64) (api.DownloadRewardsFileRe
 	return response, nil
 }
 
+// Rebuild the local rewards tree for an interval and check it against the canonical Merkle root
+func (c *Client) VerifyRewardsTree(interval uint64) (api.NetworkVerifyRewardsTreeResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("network verify-rewards-tree %d", interval))
+	if err != nil {
+		return api.NetworkVerifyRewardsTreeResponse{}, fmt.Errorf("could not verify rewards tree: %w", err)
+	}
+	var response api.NetworkVerifyRewardsTreeResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NetworkVerifyRewardsTreeResponse{}, fmt.Errorf("could not decode verify-rewards-tree response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NetworkVerifyRewardsTreeResponse{}, fmt.Errorf("could not verify rewards tree: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Compare the local rewards tree for an interval with one regenerated independently, given its path relative to the Smartnode data folder
+func (c *Client) CompareRewardsTree(interval uint64, path string) (api.NetworkCompareRewardsTreeResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("network compare-rewards-tree %d", interval), path)
+	if err != nil {
+		return api.NetworkCompareRewardsTreeResponse{}, fmt.Errorf("could not compare rewards trees: %w", err)
+	}
+	var response api.NetworkCompareRewardsTreeResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NetworkCompareRewardsTreeResponse{}, fmt.Errorf("could not decode compare-rewards-tree response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NetworkCompareRewardsTreeResponse{}, fmt.Errorf("could not compare rewards trees: %s", response.Error)
+	}
+	return response, nil
+}
+
 // Check if Saturn 1.4 has been deployed yet
 func (c *Client) IsSaturnDeployed() (api.IsSaturnDeployedResponse, error) {
 	responseBytes, err := c.callAPI("network is-saturn-deployed")
@@ -857,6 +857,22 @@ func (c *Client) NodeRewards() (api.NodeRewardsResponse, error) {
 	return response, nil
 }
 
+// Get the node's rewards history
+func (c *Client) NodeRewardsHistory() (api.NodeRewardsHistoryResponse, error) {
+	responseBytes, err := c.callAPI("node rewards-history")
+	if err != nil {
+		return api.NodeRewardsHistoryResponse{}, fmt.Errorf("Could not get node rewards history: %w", err)
+	}
+	var response api.NodeRewardsHistoryResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodeRewardsHistoryResponse{}, fmt.Errorf("Could not decode node rewards history response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodeRewardsHistoryResponse{}, fmt.Errorf("Could not get node rewards history: %s", response.Error)
+	}
+	return response, nil
+}
+
 // Get the deposit contract info for Rocket Pool and the Beacon Client
 func (c *Client) DepositContractInfo() (api.DepositContractInfoResponse, error) {
 	responseBytes, err := c.callAPI("node deposit-contract-info")
@@ -1241,6 +1257,54 @@ func (c *Client) SendMessage(address common.Address, message []byte) (api.NodeSe
 	return response, nil
 }
 
+// Get the node's pending transactions
+func (c *Client) PendingTransactions() (api.NodePendingTransactionsResponse, error) {
+	responseBytes, err := c.callAPI("node pending-transactions")
+	if err != nil {
+		return api.NodePendingTransactionsResponse{}, fmt.Errorf("Could not get pending transactions: %w", err)
+	}
+	var response api.NodePendingTransactionsResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodePendingTransactionsResponse{}, fmt.Errorf("Could not decode pending transactions response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodePendingTransactionsResponse{}, fmt.Errorf("Could not get pending transactions: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Check whether the node's pending transaction with the given nonce can be cancelled
+func (c *Client) CanCancelTransaction(nonce uint64) (api.CanNodeCancelTransactionResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("node can-cancel-transaction %d", nonce))
+	if err != nil {
+		return api.CanNodeCancelTransactionResponse{}, fmt.Errorf("Could not get can-cancel-transaction response: %w", err)
+	}
+	var response api.CanNodeCancelTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.CanNodeCancelTransactionResponse{}, fmt.Errorf("Could not decode can-cancel-transaction response: %w", err)
+	}
+	if response.Error != "" {
+		return api.CanNodeCancelTransactionResponse{}, fmt.Errorf("Could not get can-cancel-transaction response: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Cancel the node's pending transaction with the given nonce
+func (c *Client) CancelTransaction(nonce uint64) (api.NodeCancelTransactionResponse, error) {
+	responseBytes, err := c.callAPI(fmt.Sprintf("node cancel-transaction %d", nonce))
+	if err != nil {
+		return api.NodeCancelTransactionResponse{}, fmt.Errorf("Could not cancel transaction: %w", err)
+	}
+	var response api.NodeCancelTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.NodeCancelTransactionResponse{}, fmt.Errorf("Could not decode cancel-transaction response: %w", err)
+	}
+	if response.Error != "" {
+		return api.NodeCancelTransactionResponse{}, fmt.Errorf("Could not cancel transaction: %s", response.Error)
+	}
+	return response, nil
+}
+
 // Check if the node can deploy a megapool
 func (c *Client) CanDeployMegapool() (api.CanDeployMegapoolResponse, error) {
 	responseBytes, err := c.callAPI("megapool can-deploy-megapool")
@@ -0,0 +1,124 @@
+package rocketpool
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+// Config
+const NodeProfilesFile string = "nodes.yml"
+
+// A named node that the CLI can manage with the --node flag.
+// Local nodes are reached through their config folder; remote nodes are reached through their HTTP API server.
+type NodeProfile struct {
+	ConfigPath    string `yaml:"configPath,omitempty"`
+	DaemonPath    string `yaml:"daemonPath,omitempty"`
+	ApiUrl        string `yaml:"apiUrl,omitempty"`
+	ApiTokenPath  string `yaml:"apiTokenPath,omitempty"`
+	ApiCaCertPath string `yaml:"apiCaCertPath,omitempty"`
+}
+
+// The node profiles known to the CLI
+type NodeProfiles struct {
+	Nodes map[string]NodeProfile `yaml:"nodes"`
+}
+
+// Get the path of the node profiles file, which lives in the default config folder
+func GetNodeProfilesPath(configPath string) (string, error) {
+	path, err := homedir.Expand(filepath.Join(configPath, NodeProfilesFile))
+	if err != nil {
+		return "", fmt.Errorf("error expanding node profiles path: %w", err)
+	}
+	return path, nil
+}
+
+// Load the node profiles from disk; a missing file means there aren't any yet
+func LoadNodeProfiles(path string) (*NodeProfiles, error) {
+	profiles := &NodeProfiles{
+		Nodes: map[string]NodeProfile{},
+	}
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading node profiles from [%s]: %w", path, err)
+	}
+	if err := yaml.Unmarshal(bytes, profiles); err != nil {
+		return nil, fmt.Errorf("error parsing node profiles from [%s]: %w", path, err)
+	}
+	if profiles.Nodes == nil {
+		profiles.Nodes = map[string]NodeProfile{}
+	}
+	return profiles, nil
+}
+
+// Save the node profiles to disk
+func (p *NodeProfiles) Save(path string) error {
+	bytes, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("error serializing node profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating node profiles folder: %w", err)
+	}
+	if err := os.WriteFile(path, bytes, 0600); err != nil {
+		return fmt.Errorf("error writing node profiles to [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Get a node profile by name
+func (p *NodeProfiles) Get(name string) (NodeProfile, error) {
+	profile, exists := p.Nodes[name]
+	if !exists {
+		return NodeProfile{}, fmt.Errorf("there is no node named [%s]; use `rocketpool fleet add` to add it", name)
+	}
+	return profile, nil
+}
+
+// Get the names of the node profiles in alphabetical order
+func (p *NodeProfiles) Names() []string {
+	names := make([]string, 0, len(p.Nodes))
+	for name := range p.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check that a remote node's API server URL is valid.
+// The API token and the node's responses would be readable by anyone on the network over plain HTTP, so HTTPS is required unless the server is on this machine.
+func ValidateApiUrl(apiUrl string) error {
+	parsed, err := url.ParseRequestURI(apiUrl)
+	if err != nil {
+		return fmt.Errorf("invalid API server URL [%s]: %w", apiUrl, err)
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if isLoopbackHost(parsed.Hostname()) {
+			return nil
+		}
+		return fmt.Errorf("the API server URL [%s] must use https, since it isn't on this machine", apiUrl)
+	default:
+		return fmt.Errorf("invalid API server URL [%s]: the scheme must be http or https", apiUrl)
+	}
+}
+
+// Check if a host name refers to this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,123 @@
+package rocketpool
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+)
+
+// Config
+const (
+	slashingProtectionContainerSuffix string = "_slashing_protection"
+	slashingProtectionFile            string = "/validators/" + config.SlashingProtectionFilename
+)
+
+// Get the consensus client that a validator container was created for
+func (c *Client) GetValidatorContainerClient(container string) (cfgtypes.ConsensusClient, error) {
+
+	cmd := fmt.Sprintf("docker container inspect --format='{{range .Config.Env}}{{println .}}{{end}}' %s", container)
+	output, err := c.readOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if client, found := strings.CutPrefix(strings.TrimSpace(line), "CC_CLIENT="); found {
+			return cfgtypes.ConsensusClient(client), nil
+		}
+	}
+	return "", fmt.Errorf("container [%s] doesn't have a consensus client set", container)
+
+}
+
+// Export the slashing protection database of a validator client into the validators folder using the EIP-3076 interchange format.
+// The validator client must not be running.
+func (c *Client) ExportSlashingProtection(cfg *config.RocketPoolConfig, client cfgtypes.ConsensusClient, image string) error {
+	return c.runSlashingProtectionCommand(cfg, client, image, true)
+}
+
+// Import the EIP-3076 slashing protection file in the validators folder into a validator client's database.
+// The validator client must not be running.
+func (c *Client) ImportSlashingProtection(cfg *config.RocketPoolConfig, client cfgtypes.ConsensusClient, image string) error {
+	return c.runSlashingProtectionCommand(cfg, client, image, false)
+}
+
+// Run a validator client's slashing protection tool in a temporary container
+func (c *Client) runSlashingProtectionCommand(cfg *config.RocketPoolConfig, client cfgtypes.ConsensusClient, image string, export bool) error {
+
+	// Use the same network names as start-vc.sh
+	network := "hoodi"
+	if cfg.Smartnode.Network.Value.(cfgtypes.Network) == cfgtypes.Network_Mainnet {
+		network = "mainnet"
+	}
+
+	var command string
+	switch client {
+	case cfgtypes.ConsensusClient_Lighthouse:
+		action := "import"
+		if export {
+			action = "export"
+		}
+		command = fmt.Sprintf("/usr/local/bin/lighthouse account validator slashing-protection %s %s --network %s --datadir /validators/lighthouse", action, slashingProtectionFile, network)
+
+	case cfgtypes.ConsensusClient_Lodestar:
+		ccUrl, err := cfg.ConsensusClientApiUrl()
+		if err != nil {
+			return err
+		}
+		action := "import"
+		if export {
+			action = "export"
+		}
+		command = fmt.Sprintf("/usr/app/node_modules/.bin/lodestar validator slashing-protection %s --network %s --dataDir /validators/lodestar --beaconNodes %s --file %s", action, network, ccUrl, slashingProtectionFile)
+
+	case cfgtypes.ConsensusClient_Prysm:
+		if export {
+			// Prysm always names the exported file slashing_protection.json
+			command = fmt.Sprintf("/app/cmd/validator/validator slashing-protection-history export --accept-terms-of-use --%s --datadir /validators/prysm-non-hd/direct --slashing-protection-export-dir /validators", network)
+		} else {
+			command = fmt.Sprintf("/app/cmd/validator/validator slashing-protection-history import --accept-terms-of-use --%s --datadir /validators/prysm-non-hd/direct --slashing-protection-json-file %s", network, slashingProtectionFile)
+		}
+
+	case cfgtypes.ConsensusClient_Teku:
+		if export {
+			command = fmt.Sprintf("/opt/teku/bin/teku slashing-protection export --data-path=/validators/teku --to=%s", slashingProtectionFile)
+		} else {
+			command = fmt.Sprintf("/opt/teku/bin/teku slashing-protection import --data-path=/validators/teku --from=%s", slashingProtectionFile)
+		}
+
+	case cfgtypes.ConsensusClient_Nimbus:
+		// Nimbus keeps its database in the validators folder, but only the beacon node image has the tool for migrating it
+		image = cfg.Nimbus.GetBeaconNodeImage()
+		action := "import"
+		if export {
+			action = "export"
+		}
+		command = fmt.Sprintf("/home/user/nimbus-eth2/build/nimbus_beacon_node --data-dir=/validators/nimbus --validators-dir=/validators/nimbus/validators slashingdb %s %s", action, slashingProtectionFile)
+
+	default:
+		return fmt.Errorf("unsupported validator client [%s]", client)
+	}
+
+	// Run it in a throwaway container with access to the validators folder
+	validatorsPath, err := homedir.Expand(cfg.Smartnode.GetValidatorKeychainPathInCLI())
+	if err != nil {
+		return fmt.Errorf("error expanding validators path: %w", err)
+	}
+	projectName := cfg.Smartnode.ProjectName.Value.(string)
+	cmd := fmt.Sprintf("docker run --rm --user root --name %s%s --network %s_net -v %s:/validators --entrypoint sh %s -c %s",
+		projectName,
+		slashingProtectionContainerSuffix,
+		projectName,
+		shellescape.Quote(validatorsPath),
+		shellescape.Quote(image),
+		shellescape.Quote(command),
+	)
+	return c.printOutput(cmd)
+
+}
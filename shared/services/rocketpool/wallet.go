@@ -165,6 +165,38 @@ func (c *Client) RebuildWallet() (api.RebuildWalletResponse, error) {
 	return response, nil
 }
 
+// Get the genesis validators root and validator pubkeys that slashing protection data is checked against
+func (c *Client) GetSlashingProtectionInfo() (api.SlashingProtectionInfoResponse, error) {
+	responseBytes, err := c.callAPI("wallet get-slashing-protection-info")
+	if err != nil {
+		return api.SlashingProtectionInfoResponse{}, fmt.Errorf("Could not get slashing protection info: %w", err)
+	}
+	var response api.SlashingProtectionInfoResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.SlashingProtectionInfoResponse{}, fmt.Errorf("Could not decode slashing protection info response: %w", err)
+	}
+	if response.Error != "" {
+		return api.SlashingProtectionInfoResponse{}, fmt.Errorf("Could not get slashing protection info: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Remove all validator keys from the validator client through its Keymanager API
+func (c *Client) DeleteValidatorKeys() (api.DeleteValidatorKeysResponse, error) {
+	responseBytes, err := c.callAPI("wallet delete-validator-keys")
+	if err != nil {
+		return api.DeleteValidatorKeysResponse{}, fmt.Errorf("Could not delete validator keys: %w", err)
+	}
+	var response api.DeleteValidatorKeysResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.DeleteValidatorKeysResponse{}, fmt.Errorf("Could not decode delete validator keys response: %w", err)
+	}
+	if response.Error != "" {
+		return api.DeleteValidatorKeysResponse{}, fmt.Errorf("Could not delete validator keys: %s", response.Error)
+	}
+	return response, nil
+}
+
 // Estimate the gas required to set an ENS reverse record to a name
 func (c *Client) EstimateGasSetEnsName(name string) (api.SetEnsNameResponse, error) {
 	responseBytes, err := c.callAPI(fmt.Sprintf("wallet estimate-gas-set-ens-name %s", name))
@@ -244,3 +276,35 @@ func (c *Client) EndMasquerade() (api.EndMasqueradeResponse, error) {
 	}
 	return response, nil
 }
+
+// Sign a transaction that was exported in offline mode
+func (c *Client) SignOfflineTransaction(payload string) (api.SignOfflineTransactionResponse, error) {
+	responseBytes, err := c.callAPI("wallet sign-offline", payload)
+	if err != nil {
+		return api.SignOfflineTransactionResponse{}, fmt.Errorf("Could not sign offline transaction: %w", err)
+	}
+	var response api.SignOfflineTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.SignOfflineTransactionResponse{}, fmt.Errorf("Could not decode sign offline transaction response: %w", err)
+	}
+	if response.Error != "" {
+		return api.SignOfflineTransactionResponse{}, fmt.Errorf("Could not sign offline transaction: %s", response.Error)
+	}
+	return response, nil
+}
+
+// Submit a transaction that was signed offline
+func (c *Client) BroadcastTransaction(payload string) (api.BroadcastTransactionResponse, error) {
+	responseBytes, err := c.callAPI("wallet broadcast", payload)
+	if err != nil {
+		return api.BroadcastTransactionResponse{}, fmt.Errorf("Could not broadcast transaction: %w", err)
+	}
+	var response api.BroadcastTransactionResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return api.BroadcastTransactionResponse{}, fmt.Errorf("Could not decode broadcast transaction response: %w", err)
+	}
+	if response.Error != "" {
+		return api.BroadcastTransactionResponse{}, fmt.Errorf("Could not broadcast transaction: %s", response.Error)
+	}
+	return response, nil
+}
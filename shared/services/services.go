@@ -14,15 +14,19 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	beaconclient "github.com/rocket-pool/smartnode/shared/services/beacon/client"
 	"github.com/rocket-pool/smartnode/shared/services/config"
 	"github.com/rocket-pool/smartnode/shared/services/contracts"
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
 	"github.com/rocket-pool/smartnode/shared/services/passwords"
 	"github.com/rocket-pool/smartnode/shared/services/wallet"
+	kmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/keymanager"
 	lhkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/lighthouse"
 	lokeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/lodestar"
 	nmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/nimbus"
 	prkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/prysm"
 	tkkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/teku"
+	w3skeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/web3signer"
 	"github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
@@ -80,7 +84,15 @@ func GetWallet(c *cli.Context) (wallet.Wallet, error) {
 	}
 	pm := getPasswordManager(cfg)
 	am := getAddressManager(cfg)
-	return getWallet(c, cfg, pm, am, false)
+	w, err := getWallet(c, cfg, pm, am, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if c.GlobalBool("offline") {
+		// Export transactions for signing on an offline machine instead of sending them
+		return wallet.NewOfflineWallet(w, os.ExpandEnv(cfg.Smartnode.GetOfflineTransactionsPath())), nil
+	}
+	return w, nil
 }
 
 func GetHdWallet(c *cli.Context) (wallet.Wallet, error) {
@@ -90,7 +102,25 @@ func GetHdWallet(c *cli.Context) (wallet.Wallet, error) {
 	}
 	pm := getPasswordManager(cfg)
 	am := getAddressManager(cfg)
-	return getWallet(c, cfg, pm, am, true)
+
+	// Only the daemons use this wallet, and nobody is around to approve their requests on an external signer
+	return getWallet(c, cfg, pm, am, true, true)
+}
+
+// Wait until the transactions this process submitted with an expiring submission mode no longer need to fall back to the public mempool
+func WaitForTransactionFallbacks() {
+	if ecManager != nil {
+		ecManager.WaitForFallbacks()
+	}
+}
+
+// Drop the loaded node wallet so the next call loads it from disk again.
+// The API server runs every command in the same process, so it uses this to give each one the gas settings it was called with and the node's current masquerade state.
+func ResetWallet() {
+	nodeWallet = nil
+	addressManager = nil
+	initNodeWallet = sync.Once{}
+	initAddressManager = sync.Once{}
 }
 
 func GetEthClient(c *cli.Context) (*ExecutionClientManager, error) {
@@ -152,6 +182,19 @@ func GetBeaconClient(c *cli.Context) (*BeaconClientManager, error) {
 	return getBeaconClient(c, cfg)
 }
 
+// Get a Beacon client that caches finalized responses on disk if the Beacon response cache is enabled, for tasks that read a lot of historical data
+func GetCachingBeaconClient(c *cli.Context) (beacon.Client, error) {
+	cfg, err := getConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := getBeaconClient(c, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return getCachingBeaconClient(cfg, bc)
+}
+
 func GetDocker(c *cli.Context) (*client.Client, error) {
 	var err error
 	initDocker.Do(func() {
@@ -190,7 +233,7 @@ func getAddressManager(cfg *config.RocketPoolConfig) *wallet.AddressManager {
 	return addressManager
 }
 
-func getWallet(c *cli.Context, cfg *config.RocketPoolConfig, pm *passwords.PasswordManager, am *wallet.AddressManager, ignoreMasquerade bool) (wallet.Wallet, error) {
+func getWallet(c *cli.Context, cfg *config.RocketPoolConfig, pm *passwords.PasswordManager, am *wallet.AddressManager, ignoreMasquerade bool, readOnlySigner bool) (wallet.Wallet, error) {
 	var err error
 	initNodeWallet.Do(func() {
 		var maxFee *big.Int
@@ -222,6 +265,12 @@ func getWallet(c *cli.Context, cfg *config.RocketPoolConfig, pm *passwords.Passw
 			return
 		}
 
+		// Use the external signer for the node account if there is one
+		if externalSignerUrl := cfg.Smartnode.GetExternalSignerUrl(); externalSignerUrl != "" && !nodeWallet.IsNodeMasquerading() {
+			account := common.HexToAddress(cfg.Smartnode.ExternalSignerAccount.Value.(string))
+			nodeWallet = wallet.NewExternalWallet(nodeWallet, externalSignerUrl, account, readOnlySigner, chainId, maxFee, maxPriorityFee, 0)
+		}
+
 		// Keystores
 		lighthouseKeystore := lhkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.GetValidatorKeychainPath()), pm)
 		lodestarKeystore := lokeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.GetValidatorKeychainPath()), pm)
@@ -233,6 +282,18 @@ func getWallet(c *cli.Context, cfg *config.RocketPoolConfig, pm *passwords.Passw
 		nodeWallet.AddKeystore("nimbus", nimbusKeystore)
 		nodeWallet.AddKeystore("prysm", prysmKeystore)
 		nodeWallet.AddKeystore("teku", tekuKeystore)
+
+		// Keys held by Web3Signer are also imported into it, so keys that were stored locally before it was set up can still be loaded
+		web3SignerUrl := cfg.Smartnode.GetWeb3SignerUrl()
+		if web3SignerUrl != "" {
+			nodeWallet.AddKeystore("web3signer", w3skeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.GetValidatorKeychainPath()), web3SignerUrl))
+		}
+
+		// New keys are also imported into the running VC through its Keymanager API if the API is enabled
+		if cfg.Smartnode.EnableKeymanagerApi.Value.(bool) {
+			km := keymanager.NewClientFromTokenFile(cfg.Smartnode.GetKeymanagerApiUrl(), cfg.Smartnode.GetKeymanagerTokenPath())
+			nodeWallet.AddKeystore("keymanager", kmkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.GetValidatorKeychainPath()), km, web3SignerUrl))
+		}
 	})
 	return nodeWallet, err
 }
@@ -291,3 +352,20 @@ func getBeaconClient(c *cli.Context, cfg *config.RocketPoolConfig) (*BeaconClien
 	})
 	return bcManager, err
 }
+
+func getCachingBeaconClient(cfg *config.RocketPoolConfig, bc *BeaconClientManager) (beacon.Client, error) {
+	var err error
+	initBeaconClient.Do(func() {
+		cacheSize := cfg.Smartnode.BeaconResponseCacheSize.Value.(uint64)
+		if cacheSize == 0 {
+			beaconClient = bc
+			return
+		}
+		var cachingClient *beaconclient.CachingClient
+		cachingClient, err = beaconclient.NewCachingClient(bc, cfg.Smartnode.GetBeaconCacheFolder(true), cacheSize*1024*1024)
+		if err == nil {
+			beaconClient = cachingClient
+		}
+	})
+	return beaconClient, err
+}
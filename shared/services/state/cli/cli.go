@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rocket-pool/smartnode/bindings/rocketpool"
@@ -28,6 +29,8 @@ var prettyFlag = flag.Bool("p", false, "Pretty print the output")
 var inputFlag = flag.Bool("i", false, "Parse a network state from stdin instead of retrieving it from the network")
 var criticalDutiesSlotsFlag = flag.Bool("critical-duties-slots", false, "If passed, output a list of critical duties slots for the given state as if it were the final state in a 6300 epoch interval. This is outputted instead of the state json.")
 var criticalDutiesEpochCountFlag = flag.Uint64("critical-duties-epoch-count", 6300, "The number of epochs to consider when calculating critical duties")
+var recordIncrementalFromFlag = flag.Uint64("record-incremental-from", 0, "If passed, record the snapshots at this slot and the provided slot, and the chain data an incremental update between them reads, as test fixtures for the state package")
+var recordDirFlag = flag.String("record-dir", "testdata", "The directory to write the recorded incremental update fixtures to")
 
 func main() {
 	flag.Parse()
@@ -65,6 +68,16 @@ func main() {
 	bc := client.NewStandardHttpClient(*bnFlag)
 	sm := state.NewNetworkStateManager(rp, contracts, bc, nil)
 
+	if *recordIncrementalFromFlag != 0 {
+		err := recordIncrementalUpdate(sm, *recordIncrementalFromFlag, *slotFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording incremental update: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+		return
+	}
+
 	var networkState *state.NetworkState
 
 	if *inputFlag {
@@ -121,3 +134,52 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Record the snapshots before and after an incremental update, and the chain data the update reads
+func recordIncrementalUpdate(sm *state.NetworkStateManager, fromSlot uint64, toSlot uint64) error {
+	if toSlot <= fromSlot {
+		return fmt.Errorf("slot %d must be after slot %d", toSlot, fromSlot)
+	}
+	previous, err := sm.GetStateForSlot(fromSlot)
+	if err != nil {
+		return fmt.Errorf("error getting network state for slot %d: %w", fromSlot, err)
+	}
+	current, err := sm.GetStateForSlot(toSlot)
+	if err != nil {
+		return fmt.Errorf("error getting network state for slot %d: %w", toSlot, err)
+	}
+	recording, err := sm.RecordStateUpdate(previous, toSlot)
+	if err != nil {
+		return fmt.Errorf("error recording the update from slot %d to %d: %w", fromSlot, toSlot, err)
+	}
+
+	// The megapool details aren't serialized with the state, so they're recorded separately
+	megapools := map[string]any{
+		"previous": previous.MegapoolDetails,
+		"current":  current.MegapoolDetails,
+	}
+	contracts := map[string]any{
+		"global_contracts": recording.GlobalContracts,
+		"other_contracts":  recording.OtherContracts,
+	}
+	fixtures := map[string]any{
+		"incremental-previous.json":  previous,
+		"incremental-current.json":   current,
+		"incremental-megapools.json": megapools,
+		"incremental-contracts.json": contracts,
+		"incremental-logs.json":      recording.Logs,
+	}
+	for name, value := range fixtures {
+		bytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding %s: %w", name, err)
+		}
+		path := filepath.Join(*recordDirFlag, name)
+		err = os.WriteFile(path, bytes, 0644)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", path, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return nil
+}
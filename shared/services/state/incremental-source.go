@@ -0,0 +1,214 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/minipool"
+	"github.com/rocket-pool/smartnode/bindings/node"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	rpstate "github.com/rocket-pool/smartnode/bindings/utils/state"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+// The number of addresses to filter logs for in a single request
+const logAddressBatchSize int = 500
+
+// The contracts whose events minipools emit
+var minipoolAbiNames = []string{"rocketMinipool", "rocketMinipoolDelegate", "rocketMinipoolBase"}
+
+// Provides the data for an incremental update from the Execution and Beacon clients
+type rpStateSource struct {
+	rp         *rocketpool.RocketPool
+	bc         beacon.Client
+	contracts  *rpstate.NetworkContracts
+	opts       *bind.CallOpts
+	slotNumber uint64
+}
+
+func (s *rpStateSource) getNetworkDetails() (*rpstate.NetworkDetails, error) {
+	return rpstate.NewNetworkDetails(s.rp, s.contracts)
+}
+
+func (s *rpStateSource) getOracleDaoMemberDetails() ([]rpstate.OracleDaoMemberDetails, error) {
+	return rpstate.GetAllOracleDaoMemberDetails(s.rp, s.contracts)
+}
+
+func (s *rpStateSource) getContractAddresses() ([]common.Address, []common.Address) {
+	// These hold the prices and settings every node's details depend on
+	global := getContractAddresses(
+		s.contracts.RocketNetworkPrices,
+		s.contracts.RocketDAOProtocolSettingsMinipool,
+		s.contracts.RocketDAOProtocolSettingsNetwork,
+		s.contracts.RocketDAOProtocolSettingsNode,
+		s.contracts.RocketDAONodeTrustedSettingsMinipool,
+		s.contracts.RocketDAOProtocolProposal,
+	)
+	others := getContractAddresses(
+		s.contracts.RocketDAONodeTrusted,
+		s.contracts.RocketDepositPool,
+		s.contracts.RocketMinipoolManager,
+		s.contracts.RocketMinipoolQueue,
+		s.contracts.RocketNetworkBalances,
+		s.contracts.RocketNetworkFees,
+		s.contracts.RocketNodeDeposit,
+		s.contracts.RocketNodeDistributorFactory,
+		s.contracts.RocketNodeManager,
+		s.contracts.RocketNodeStaking,
+		s.contracts.RocketRewardsPool,
+		s.contracts.RocketSmoothingPool,
+		s.contracts.RocketTokenRETH,
+		s.contracts.RocketTokenRPL,
+		s.contracts.RocketMinipoolBondReducer,
+		s.contracts.RocketDAOProtocolVerifier,
+		s.contracts.RocketMegapoolFactory,
+		s.contracts.RocketMegapoolManager,
+	)
+	return global, others
+}
+
+func (s *rpStateSource) getLogs(fromBlock uint64, addresses []common.Address) ([]ethtypes.Log, error) {
+	logs := []ethtypes.Log{}
+	for i := 0; i < len(addresses); i += logAddressBatchSize {
+		max := min(i+logAddressBatchSize, len(addresses))
+		batch, err := s.rp.Client.FilterLogs(context.Background(), ethereum.FilterQuery{
+			Addresses: addresses[i:max],
+			FromBlock: big.NewInt(0).SetUint64(fromBlock),
+			ToBlock:   s.opts.BlockNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, batch...)
+	}
+	return logs, nil
+}
+
+func (s *rpStateSource) getMinipoolLogs(fromBlock uint64) ([]ethtypes.Log, error) {
+	// Match every event a minipool, or its delegate, can emit
+	topics := []common.Hash{}
+	var abiErr error
+	for _, contractName := range minipoolAbiNames {
+		minipoolAbi, err := s.rp.GetABI(contractName, nil)
+		if err != nil {
+			abiErr = err
+			continue
+		}
+		for _, event := range minipoolAbi.Events {
+			if !slices.Contains(topics, event.ID) {
+				topics = append(topics, event.ID)
+			}
+		}
+	}
+	if len(topics) == 0 {
+		// An empty topic filter would match every log on the chain
+		return nil, fmt.Errorf("couldn't get any minipool events from the contract ABIs (last error: %v)", abiErr)
+	}
+	return s.rp.Client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(0).SetUint64(fromBlock),
+		ToBlock:   s.opts.BlockNumber,
+		Topics:    [][]common.Hash{topics},
+	})
+}
+
+func (s *rpStateSource) getEthBalances(addresses []common.Address) ([]*big.Int, error) {
+	return s.contracts.BalanceBatcher.GetEthBalances(addresses, s.opts)
+}
+
+func (s *rpStateSource) getNodeAddresses(start uint64) ([]common.Address, error) {
+	count, err := node.GetNodeCount(s.rp, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting node count: %w", err)
+	}
+	addresses := []common.Address{}
+	for i := start; i < count; i++ {
+		address, err := node.GetNodeAt(s.rp, i, s.opts)
+		if err != nil {
+			return nil, fmt.Errorf("error getting node %d: %w", i, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+func (s *rpStateSource) getMinipoolAddresses(start uint64) ([]common.Address, error) {
+	count, err := minipool.GetMinipoolCount(s.rp, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting minipool count: %w", err)
+	}
+	addresses := []common.Address{}
+	for i := start; i < count; i++ {
+		address, err := minipool.GetMinipoolAt(s.rp, i, s.opts)
+		if err != nil {
+			return nil, fmt.Errorf("error getting minipool %d: %w", i, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+func (s *rpStateSource) getNodeDetails(addresses []common.Address) ([]rpstate.NativeNodeDetails, error) {
+	return rpstate.GetBulkNativeNodeDetails(s.rp, s.contracts, addresses)
+}
+
+func (s *rpStateSource) getMinipoolDetails(addresses []common.Address) ([]rpstate.NativeMinipoolDetails, error) {
+	return rpstate.GetBulkNativeMinipoolDetails(s.rp, s.contracts, addresses)
+}
+
+func (s *rpStateSource) getMegapoolValidatorCount() (uint64, error) {
+	count, err := megapool.GetValidatorCount(s.rp, s.opts)
+	return uint64(count), err
+}
+
+func (s *rpStateSource) getMegapoolValidators(indices []uint64) ([]megapool.ValidatorInfoFromGlobalIndex, error) {
+	validators := make([]megapool.ValidatorInfoFromGlobalIndex, len(indices))
+	for i, index := range indices {
+		var err error
+		validators[i], err = megapool.GetValidatorInfo(s.rp, uint32(index), s.opts)
+		if err != nil {
+			return nil, fmt.Errorf("error getting megapool validator %d: %w", index, err)
+		}
+	}
+	return validators, nil
+}
+
+func (s *rpStateSource) getMegapoolDetails(megapoolAddress common.Address) (rpstate.NativeMegapoolDetails, error) {
+	mp, err := megapool.NewMegaPoolV1(s.rp, megapoolAddress, s.opts)
+	if err != nil {
+		return rpstate.NativeMegapoolDetails{}, err
+	}
+	nodeAddress, err := mp.GetNodeAddress(s.opts)
+	if err != nil {
+		return rpstate.NativeMegapoolDetails{}, err
+	}
+	return rpstate.GetNodeMegapoolDetails(s.rp, nodeAddress)
+}
+
+func (s *rpStateSource) getValidatorStatuses(pubkeys []types.ValidatorPubkey) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+	return s.bc.GetValidatorStatuses(pubkeys, &beacon.ValidatorStatusOptions{
+		Slot: &s.slotNumber,
+	})
+}
+
+func (s *rpStateSource) calculateCompleteMinipoolShares(minipools []*rpstate.NativeMinipoolDetails, beaconBalances []*big.Int) error {
+	return rpstate.CalculateCompleteMinipoolShares(s.rp, s.contracts, minipools, beaconBalances)
+}
+
+// Get the addresses of the provided contracts, skipping the ones that aren't deployed yet
+func getContractAddresses(contracts ...*rocketpool.Contract) []common.Address {
+	addresses := make([]common.Address, 0, len(contracts))
+	for _, contract := range contracts {
+		if contract != nil && contract.Address != nil {
+			addresses = append(addresses, *contract.Address)
+		}
+	}
+	return addresses
+}
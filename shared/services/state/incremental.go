@@ -0,0 +1,629 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	rpstate "github.com/rocket-pool/smartnode/bindings/utils/state"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+const (
+	// The most blocks an incremental update will cover before a full rebuild is done instead
+	maxIncrementalBlockRange uint64 = 1000
+
+	// Logs are read from a few blocks before the previous snapshot, so changes in blocks that were reorged out are picked up again
+	logReorgMargin uint64 = 16
+)
+
+// How often a full rebuild is done to reconcile anything the incremental updates can't see
+var fullReconciliationInterval, _ = time.ParseDuration("6h")
+
+var errFullRebuildRequired = errors.New("a full rebuild is required")
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// Destroyed minipools are removed from the minipool list, which reorders it
+var minipoolDestroyedTopic = crypto.Keccak256Hash([]byte("MinipoolDestroyed(address,address,uint256)"))
+
+// Provides the data an incremental update needs from the Execution and Beacon layers, at the block being updated to
+type stateUpdateSource interface {
+	// Get the network and Oracle DAO details, which are always refreshed
+	getNetworkDetails() (*rpstate.NetworkDetails, error)
+	getOracleDaoMemberDetails() ([]rpstate.OracleDaoMemberDetails, error)
+
+	// Get the addresses of the network contracts. Events from the global ones can change every node, so they require a full rebuild.
+	getContractAddresses() (global []common.Address, others []common.Address)
+
+	// Get the logs emitted by the provided addresses from the provided block up to the block being updated to
+	getLogs(fromBlock uint64, addresses []common.Address) ([]ethtypes.Log, error)
+
+	// Get the minipool event logs from the provided block up to the block being updated to.
+	// These are matched by event signature rather than address, since there are too many minipools to list in a filter.
+	getMinipoolLogs(fromBlock uint64) ([]ethtypes.Log, error)
+
+	// Get the ETH balances of the provided addresses
+	getEthBalances(addresses []common.Address) ([]*big.Int, error)
+
+	// Get the addresses of the nodes and minipools from the provided index to the end of the list
+	getNodeAddresses(start uint64) ([]common.Address, error)
+	getMinipoolAddresses(start uint64) ([]common.Address, error)
+
+	// Get the complete details for a set of nodes or minipools
+	getNodeDetails(addresses []common.Address) ([]rpstate.NativeNodeDetails, error)
+	getMinipoolDetails(addresses []common.Address) ([]rpstate.NativeMinipoolDetails, error)
+
+	// Get the megapool validators and megapools
+	getMegapoolValidatorCount() (uint64, error)
+	getMegapoolValidators(indices []uint64) ([]megapool.ValidatorInfoFromGlobalIndex, error)
+	getMegapoolDetails(megapoolAddress common.Address) (rpstate.NativeMegapoolDetails, error)
+
+	// Get the Beacon statuses of a set of validators
+	getValidatorStatuses(pubkeys []types.ValidatorPubkey) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error)
+
+	// Calculate the node and user shares of a set of minipools, including the portion on the Beacon chain
+	calculateCompleteMinipoolShares(minipools []*rpstate.NativeMinipoolDetails, beaconBalances []*big.Int) error
+}
+
+// How much of the network an incremental update had to read, compared to a full rebuild
+type stateUpdateStats struct {
+	refreshedNodes     int
+	totalNodes         int
+	refreshedMinipools int
+	totalMinipools     int
+	refreshedMegapools int
+	totalMegapools     int
+	logQueries         int
+}
+
+// The parts of a snapshot that need to be refreshed
+type stateChanges struct {
+	nodes     map[common.Address]bool
+	minipools map[common.Address]bool
+	megapools map[common.Address]bool
+}
+
+// Find the nodes, minipools and megapools that were changed by a set of logs.
+// Returns an error wrapping errFullRebuildRequired if the logs contain a change that can't be applied incrementally.
+func getStateChanges(previous *NetworkState, globalContracts []common.Address, logs []ethtypes.Log) (*stateChanges, error) {
+	changes := &stateChanges{
+		nodes:     map[common.Address]bool{},
+		minipools: map[common.Address]bool{},
+		megapools: map[common.Address]bool{},
+	}
+
+	// Map the megapools to their nodes
+	megapoolNodes := map[common.Address]common.Address{}
+	for _, node := range previous.NodeDetails {
+		if _, exists := previous.MegapoolDetails[node.MegapoolAddress]; exists {
+			megapoolNodes[node.MegapoolAddress] = node.NodeAddress
+		}
+	}
+
+	// Mark an address as changed if it belongs to a known node, minipool or megapool
+	markAddress := func(address common.Address) {
+		if node, exists := previous.NodeDetailsByAddress[address]; exists {
+			changes.nodes[address] = true
+			if _, exists := megapoolNodes[node.MegapoolAddress]; exists {
+				changes.megapools[node.MegapoolAddress] = true
+			}
+			return
+		}
+		if minipool, exists := previous.MinipoolDetailsByAddress[address]; exists {
+			changes.minipools[address] = true
+			changes.nodes[minipool.NodeAddress] = true
+			return
+		}
+		if node, exists := megapoolNodes[address]; exists {
+			changes.megapools[address] = true
+			changes.nodes[node] = true
+		}
+	}
+
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		if slices.Contains(globalContracts, log.Address) {
+			return nil, fmt.Errorf("%w: contract %s emitted an event in block %d", errFullRebuildRequired, log.Address.Hex(), log.BlockNumber)
+		}
+		if len(log.Topics) > 0 && log.Topics[0] == minipoolDestroyedTopic {
+			return nil, fmt.Errorf("%w: a minipool was destroyed in block %d", errFullRebuildRequired, log.BlockNumber)
+		}
+
+		// Events from a minipool or megapool change it, and indexed addresses name the nodes, minipools and megapools the event is for
+		markAddress(log.Address)
+		for _, topic := range log.Topics[min(len(log.Topics), 1):] {
+			if bytes.Equal(topic[:common.HashLength-common.AddressLength], make([]byte, common.HashLength-common.AddressLength)) {
+				markAddress(common.BytesToAddress(topic[:]))
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Create a new snapshot by applying the changes since the previous one was taken.
+// The previous snapshot isn't modified, and the new one doesn't share any mutable values with it.
+// Returns an error wrapping errFullRebuildRequired if the changes can't be applied incrementally.
+func applyStateUpdate(previous *NetworkState, source stateUpdateSource, slotNumber uint64, elBlockNumber uint64) (*NetworkState, *stateUpdateStats, error) {
+	if elBlockNumber < previous.ElBlockNumber {
+		return nil, nil, fmt.Errorf("%w: EL block %d is before the previous snapshot's block %d", errFullRebuildRequired, elBlockNumber, previous.ElBlockNumber)
+	}
+	if elBlockNumber-previous.ElBlockNumber > maxIncrementalBlockRange {
+		return nil, nil, fmt.Errorf("%w: the previous snapshot is %d blocks old", errFullRebuildRequired, elBlockNumber-previous.ElBlockNumber)
+	}
+	stats := &stateUpdateStats{}
+
+	state := &NetworkState{
+		BeaconSlotNumber: slotNumber,
+		ElBlockNumber:    elBlockNumber,
+		BeaconConfig:     previous.BeaconConfig,
+		IsSaturnDeployed: previous.IsSaturnDeployed,
+	}
+
+	// Network and Oracle DAO details
+	var err error
+	state.NetworkDetails, err = source.getNetworkDetails()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting network details: %w", err)
+	}
+	state.OracleDaoMemberDetails, err = source.getOracleDaoMemberDetails()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting Oracle DAO details: %w", err)
+	}
+
+	// Get the logs from the network contracts, minipools and megapools since the previous snapshot
+	logs, globalContracts, _, err := getUpdateLogs(previous, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	stats.logQueries = 2
+	megapoolAddresses := getSortedAddresses(previous.MegapoolDetails)
+	changes, err := getStateChanges(previous, globalContracts, logs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get the new nodes and minipools
+	newNodes, err := source.getNodeAddresses(uint64(len(previous.NodeDetails)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting new nodes: %w", err)
+	}
+	newMinipools, err := getNewMinipools(previous, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Balances change without events, so refresh them all
+	nodeCount := len(previous.NodeDetails)
+	minipoolCount := len(previous.MinipoolDetails)
+	balanceAddresses := make([]common.Address, 0, 2*nodeCount+minipoolCount+len(megapoolAddresses))
+	for _, node := range previous.NodeDetails {
+		balanceAddresses = append(balanceAddresses, node.NodeAddress)
+	}
+	for _, node := range previous.NodeDetails {
+		balanceAddresses = append(balanceAddresses, node.FeeDistributorAddress)
+	}
+	for _, minipool := range previous.MinipoolDetails {
+		balanceAddresses = append(balanceAddresses, minipool.MinipoolAddress)
+	}
+	balanceAddresses = append(balanceAddresses, megapoolAddresses...)
+	balances, err := source.getEthBalances(balanceAddresses)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting balances: %w", err)
+	}
+	if len(balances) != len(balanceAddresses) {
+		return nil, nil, fmt.Errorf("expected %d balances but got %d", len(balanceAddresses), len(balances))
+	}
+	nodeBalances := balances[:nodeCount]
+	distributorBalances := balances[nodeCount : 2*nodeCount]
+	minipoolBalances := balances[2*nodeCount : 2*nodeCount+minipoolCount]
+	megapoolBalances := balances[2*nodeCount+minipoolCount:]
+
+	// Update the minipools; their balances are used to calculate their shares, so a minipool with a new balance is refreshed entirely
+	state.MinipoolDetails = make([]rpstate.NativeMinipoolDetails, len(previous.MinipoolDetails), len(previous.MinipoolDetails)+len(newMinipools))
+	for i, minipool := range previous.MinipoolDetails {
+		state.MinipoolDetails[i] = cloneDetails(minipool)
+	}
+	refreshedMinipools := []common.Address{}
+	refreshedMinipoolIndices := []int{}
+	for i, minipool := range previous.MinipoolDetails {
+		if changes.minipools[minipool.MinipoolAddress] || minipool.Balance.Cmp(minipoolBalances[i]) != 0 {
+			refreshedMinipools = append(refreshedMinipools, minipool.MinipoolAddress)
+			refreshedMinipoolIndices = append(refreshedMinipoolIndices, i)
+		}
+	}
+	refreshedMinipools = append(refreshedMinipools, newMinipools...)
+	minipoolDetails, err := source.getMinipoolDetails(refreshedMinipools)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting minipool details: %w", err)
+	}
+	stats.refreshedMinipools = len(refreshedMinipools)
+	for i, details := range minipoolDetails {
+		if i < len(refreshedMinipoolIndices) {
+			state.MinipoolDetails[refreshedMinipoolIndices[i]] = details
+		} else {
+			// A new minipool changes its node's details
+			state.MinipoolDetails = append(state.MinipoolDetails, details)
+			changes.nodes[details.NodeAddress] = true
+		}
+	}
+
+	// Update the nodes
+	state.NodeDetails = make([]rpstate.NativeNodeDetails, len(previous.NodeDetails), len(previous.NodeDetails)+len(newNodes))
+	for i, node := range previous.NodeDetails {
+		state.NodeDetails[i] = cloneDetails(node)
+	}
+	refreshedNodes := []common.Address{}
+	refreshedNodeIndices := []int{}
+	for i := range state.NodeDetails {
+		node := &state.NodeDetails[i]
+		node.BalanceETH = nodeBalances[i]
+		node.DistributorBalance = distributorBalances[i]
+		if changes.nodes[node.NodeAddress] {
+			refreshedNodes = append(refreshedNodes, node.NodeAddress)
+			refreshedNodeIndices = append(refreshedNodeIndices, i)
+		}
+	}
+	refreshedNodes = append(refreshedNodes, newNodes...)
+	nodeDetails, err := source.getNodeDetails(refreshedNodes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting node details: %w", err)
+	}
+	stats.refreshedNodes = len(refreshedNodes)
+	for i, details := range nodeDetails {
+		if i < len(refreshedNodeIndices) {
+			state.NodeDetails[refreshedNodeIndices[i]] = details
+		} else {
+			state.NodeDetails = append(state.NodeDetails, details)
+		}
+	}
+	state.buildLookups()
+
+	// Update the megapools
+	if state.IsSaturnDeployed {
+		for i, address := range megapoolAddresses {
+			balance := previous.MegapoolDetails[address].EthBalance
+			if balance == nil || balance.Cmp(megapoolBalances[i]) != 0 {
+				changes.megapools[address] = true
+			}
+		}
+		stats.refreshedMegapools, err = updateMegapools(previous, state, source, changes)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Validator balances change with every block's withdrawals and rewards, so the statuses are always refreshed
+	megapoolValidatorPubkeys := make([]types.ValidatorPubkey, 0, len(state.MegapoolValidatorGlobalIndex))
+	for _, validator := range state.MegapoolValidatorGlobalIndex {
+		if len(validator.Pubkey) > 0 {
+			megapoolValidatorPubkeys = append(megapoolValidatorPubkeys, types.ValidatorPubkey(validator.Pubkey))
+		}
+	}
+	if state.IsSaturnDeployed {
+		state.MegapoolValidatorDetails, err = source.getValidatorStatuses(megapoolValidatorPubkeys)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting megapool validator statuses: %w", err)
+		}
+	}
+	minipoolPubkeys := make([]types.ValidatorPubkey, 0, len(state.MinipoolDetails))
+	for _, minipool := range state.MinipoolDetails {
+		if minipool.Pubkey != (types.ValidatorPubkey{}) {
+			minipoolPubkeys = append(minipoolPubkeys, minipool.Pubkey)
+		}
+	}
+	state.MinipoolValidatorDetails, err = source.getValidatorStatuses(minipoolPubkeys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting minipool validator statuses: %w", err)
+	}
+
+	// Recalculate the complete shares of the minipools that were refreshed or have a new Beacon balance
+	refreshed := map[common.Address]bool{}
+	for _, address := range refreshedMinipools {
+		refreshed[address] = true
+	}
+	shareMinipools := []*rpstate.NativeMinipoolDetails{}
+	shareBalances := []*big.Int{}
+	for i := range state.MinipoolDetails {
+		minipool := &state.MinipoolDetails[i]
+		beaconBalance := getBeaconBalance(state.MinipoolValidatorDetails, minipool.Pubkey)
+		if !refreshed[minipool.MinipoolAddress] && beaconBalance.Cmp(getBeaconBalance(previous.MinipoolValidatorDetails, minipool.Pubkey)) == 0 {
+			continue
+		}
+
+		shareMinipools = append(shareMinipools, minipool)
+		shareBalances = append(shareBalances, beaconBalance)
+	}
+	err = source.calculateCompleteMinipoolShares(shareMinipools, shareBalances)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error calculating minipool shares: %w", err)
+	}
+
+	// Calculate avg node fees and distributor shares
+	for i := range state.NodeDetails {
+		node := &state.NodeDetails[i]
+		node.AverageNodeFee = big.NewInt(0)
+		node.DistributorBalanceUserETH = big.NewInt(0)
+		node.DistributorBalanceNodeETH = big.NewInt(0)
+	}
+	for _, details := range state.NodeDetails {
+		details.CalculateAverageFeeAndDistributorShares(state.MinipoolDetailsByNode[details.NodeAddress])
+	}
+
+	stats.totalNodes = len(state.NodeDetails)
+	stats.totalMinipools = len(state.MinipoolDetails)
+	stats.totalMegapools = len(state.MegapoolDetails)
+	return state, stats, nil
+}
+
+// Get the logs from the network contracts, minipools and megapools since the previous snapshot, along with the global and other contract addresses
+func getUpdateLogs(previous *NetworkState, source stateUpdateSource) ([]ethtypes.Log, []common.Address, []common.Address, error) {
+	globalContracts, otherContracts := source.getContractAddresses()
+	megapoolAddresses := getSortedAddresses(previous.MegapoolDetails)
+	logAddresses := make([]common.Address, 0, len(globalContracts)+len(otherContracts)+len(megapoolAddresses))
+	logAddresses = append(logAddresses, globalContracts...)
+	logAddresses = append(logAddresses, otherContracts...)
+	logAddresses = append(logAddresses, megapoolAddresses...)
+	fromBlock := previous.ElBlockNumber + 1
+	if fromBlock > logReorgMargin {
+		fromBlock -= logReorgMargin
+	}
+	logs, err := source.getLogs(fromBlock, logAddresses)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting logs: %w", err)
+	}
+	minipoolLogs, err := source.getMinipoolLogs(fromBlock)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting minipool logs: %w", err)
+	}
+
+	// Other contracts can emit events with the same signatures as minipools, so only keep the ones from known minipools
+	for _, log := range minipoolLogs {
+		if _, exists := previous.MinipoolDetailsByAddress[log.Address]; exists {
+			logs = append(logs, log)
+		}
+	}
+	return logs, globalContracts, otherContracts, nil
+}
+
+// Get the addresses of the minipools created since the previous snapshot
+func getNewMinipools(previous *NetworkState, source stateUpdateSource) ([]common.Address, error) {
+	count := uint64(len(previous.MinipoolDetails))
+	if count == 0 {
+		return source.getMinipoolAddresses(0)
+	}
+
+	// Include the last known minipool to make sure the list wasn't reordered
+	addresses, err := source.getMinipoolAddresses(count - 1)
+	if err != nil {
+		return nil, fmt.Errorf("error getting new minipools: %w", err)
+	}
+	if len(addresses) == 0 || addresses[0] != previous.MinipoolDetails[count-1].MinipoolAddress {
+		return nil, fmt.Errorf("%w: the minipool list changed order", errFullRebuildRequired)
+	}
+	return addresses[1:], nil
+}
+
+// Update the megapool validators and megapool details, returning the number of megapools that were refreshed
+func updateMegapools(previous *NetworkState, state *NetworkState, source stateUpdateSource, changes *stateChanges) (int, error) {
+	// Get the validators of the changed megapools, and the new validators
+	count, err := source.getMegapoolValidatorCount()
+	if err != nil {
+		return 0, fmt.Errorf("error getting megapool validator count: %w", err)
+	}
+	previousCount := uint64(len(previous.MegapoolValidatorGlobalIndex))
+	if count < previousCount {
+		return 0, fmt.Errorf("%w: there are fewer megapool validators than before", errFullRebuildRequired)
+	}
+	indices := []uint64{}
+	for i, validator := range previous.MegapoolValidatorGlobalIndex {
+		if changes.megapools[validator.MegapoolAddress] {
+			indices = append(indices, uint64(i))
+		}
+	}
+	for i := previousCount; i < count; i++ {
+		indices = append(indices, i)
+	}
+	validators, err := source.getMegapoolValidators(indices)
+	if err != nil {
+		return 0, fmt.Errorf("error getting megapool validators: %w", err)
+	}
+	state.MegapoolValidatorGlobalIndex = slices.Clone(previous.MegapoolValidatorGlobalIndex)
+	for i, validator := range validators {
+		if indices[i] < previousCount {
+			state.MegapoolValidatorGlobalIndex[indices[i]] = validator
+		} else {
+			state.MegapoolValidatorGlobalIndex = append(state.MegapoolValidatorGlobalIndex, validator)
+		}
+	}
+
+	// Map the megapools to their validators, and refresh the changed and new megapools
+	state.MegapoolToPubkeysMap = map[common.Address][]types.ValidatorPubkey{}
+	for _, validator := range state.MegapoolValidatorGlobalIndex {
+		if len(validator.Pubkey) > 0 {
+			state.MegapoolToPubkeysMap[validator.MegapoolAddress] = append(state.MegapoolToPubkeysMap[validator.MegapoolAddress], types.ValidatorPubkey(validator.Pubkey))
+		}
+	}
+	state.MegapoolDetails = make(map[common.Address]rpstate.NativeMegapoolDetails, len(state.MegapoolToPubkeysMap))
+	refreshed := 0
+	for _, address := range getSortedAddresses(state.MegapoolToPubkeysMap) {
+		details, exists := previous.MegapoolDetails[address]
+		if !exists || changes.megapools[address] {
+			details, err = source.getMegapoolDetails(address)
+			if err != nil {
+				return 0, fmt.Errorf("error getting details for megapool %s: %w", address.Hex(), err)
+			}
+			refreshed++
+		} else {
+			details = cloneDetails(details)
+		}
+		state.MegapoolDetails[address] = details
+	}
+	return refreshed, nil
+}
+
+// Rebuild the node and minipool lookups
+func (s *NetworkState) buildLookups() {
+	s.NodeDetailsByAddress = make(map[common.Address]*rpstate.NativeNodeDetails, len(s.NodeDetails))
+	for i, details := range s.NodeDetails {
+		s.NodeDetailsByAddress[details.NodeAddress] = &s.NodeDetails[i]
+	}
+	s.MinipoolDetailsByAddress = make(map[common.Address]*rpstate.NativeMinipoolDetails, len(s.MinipoolDetails))
+	s.MinipoolDetailsByNode = map[common.Address][]*rpstate.NativeMinipoolDetails{}
+	for i, details := range s.MinipoolDetails {
+		s.MinipoolDetailsByAddress[details.MinipoolAddress] = &s.MinipoolDetails[i]
+		s.MinipoolDetailsByNode[details.NodeAddress] = append(s.MinipoolDetailsByNode[details.NodeAddress], &s.MinipoolDetails[i])
+	}
+}
+
+// Copy a node, minipool or megapool's details, giving the copy its own big.Ints so updating one snapshot can't change another
+func cloneDetails[DetailsType any](details DetailsType) DetailsType {
+	value := reflect.ValueOf(&details).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Type() != bigIntType || field.IsNil() || !field.CanSet() {
+			continue
+		}
+		field.Set(reflect.ValueOf(big.NewInt(0).Set(field.Interface().(*big.Int))))
+	}
+	return details
+}
+
+// Get the balance a validator contributes to its minipool's shares
+func getBeaconBalance(statuses ValidatorDetailsMap, pubkey types.ValidatorPubkey) *big.Int {
+	validator := statuses[pubkey]
+	if !validator.Exists {
+		return big.NewInt(0)
+	}
+	return eth.GweiToWei(float64(validator.Balance))
+}
+
+// Get the keys of an address map in a stable order
+func getSortedAddresses[ValueType any](addresses map[common.Address]ValueType) []common.Address {
+	sorted := make([]common.Address, 0, len(addresses))
+	for address := range addresses {
+		sorted = append(sorted, address)
+	}
+	slices.SortFunc(sorted, func(a common.Address, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return sorted
+}
+
+// Get the state of the network at the provided Beacon slot by applying the changes since the previous call to its snapshot.
+// A full snapshot is created on the first call, periodically to reconcile the incremental updates, and whenever an update can't be applied.
+// The returned state is shared with the next update, so it must not be modified.
+// This is meant for the network-wide snapshots the watchtower builds every cycle; the node daemon's snapshots only cover its own node, so they're cheap to build in full.
+func (m *NetworkStateManager) GetUpdatedStateForSlot(slotNumber uint64) (*NetworkState, error) {
+	m.updateLock.Lock()
+	defer m.updateLock.Unlock()
+
+	if m.lastState != nil && time.Since(m.lastFullUpdate) < fullReconciliationInterval {
+		state, err := m.updateNetworkState(m.lastState, slotNumber)
+		if err == nil {
+			m.lastState = state
+			return state, nil
+		}
+		m.logLine("Couldn't update the network state incrementally (%s), getting the full state instead.", err.Error())
+	}
+
+	state, err := m.createNetworkState(slotNumber)
+	if err != nil {
+		return nil, err
+	}
+	m.lastState = state
+	m.lastFullUpdate = time.Now()
+	return state, nil
+}
+
+// Create a snapshot of the network at the provided Beacon slot from a previous one
+func (m *NetworkStateManager) updateNetworkState(previous *NetworkState, slotNumber uint64) (*NetworkState, error) {
+	source, err := m.getStateUpdateSource(previous, slotNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logLine("Updating network state for EL block %d, Beacon slot %d", source.opts.BlockNumber.Uint64(), slotNumber)
+	start := time.Now()
+	state, stats, err := applyStateUpdate(previous, source, slotNumber, source.opts.BlockNumber.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	m.logLine("Updated network state with %d log queries, re-reading %d of %d nodes, %d of %d minipools and %d of %d megapools (total time: %s)",
+		stats.logQueries, stats.refreshedNodes, stats.totalNodes, stats.refreshedMinipools, stats.totalMinipools, stats.refreshedMegapools, stats.totalMegapools, time.Since(start))
+	return state, nil
+}
+
+// The chain data an incremental update reads between two snapshots, recorded for tests
+type StateUpdateRecording struct {
+	GlobalContracts []common.Address `json:"global_contracts"`
+	OtherContracts  []common.Address `json:"other_contracts"`
+	Logs            []ethtypes.Log   `json:"logs"`
+}
+
+// Record the contract addresses and logs an incremental update from the previous snapshot to the provided Beacon slot would read
+func (m *NetworkStateManager) RecordStateUpdate(previous *NetworkState, slotNumber uint64) (*StateUpdateRecording, error) {
+	source, err := m.getStateUpdateSource(previous, slotNumber)
+	if err != nil {
+		return nil, err
+	}
+	logs, globalContracts, otherContracts, err := getUpdateLogs(previous, source)
+	if err != nil {
+		return nil, err
+	}
+	return &StateUpdateRecording{
+		GlobalContracts: globalContracts,
+		OtherContracts:  otherContracts,
+		Logs:            logs,
+	}, nil
+}
+
+// Get the source for an incremental update from the previous snapshot to the provided Beacon slot
+func (m *NetworkStateManager) getStateUpdateSource(previous *NetworkState, slotNumber uint64) (*rpStateSource, error) {
+	// Get the execution block for the given slot
+	beaconBlock, exists, err := m.bc.GetBeaconBlock(fmt.Sprintf("%d", slotNumber))
+	if err != nil {
+		return nil, fmt.Errorf("error getting Beacon block for slot %d: %w", slotNumber, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("slot %d did not have a Beacon block", slotNumber)
+	}
+	elBlockNumber := beaconBlock.ExecutionBlockNumber
+	opts := &bind.CallOpts{
+		BlockNumber: big.NewInt(0).SetUint64(elBlockNumber),
+	}
+
+	// The contracts in the state change when Saturn is deployed
+	isSaturnDeployed, err := IsSaturnDeployed(m.rp, opts)
+	if err != nil {
+		return nil, err
+	}
+	if isSaturnDeployed != previous.IsSaturnDeployed {
+		return nil, fmt.Errorf("%w: Saturn was deployed", errFullRebuildRequired)
+	}
+
+	contracts, err := rpstate.NewNetworkContracts(m.rp, isSaturnDeployed, m.multicaller, m.balanceBatcher, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting network contracts: %w", err)
+	}
+	return &rpStateSource{
+		rp:         m.rp,
+		bc:         m.bc,
+		contracts:  contracts,
+		opts:       opts,
+		slotNumber: slotNumber,
+	}, nil
+}
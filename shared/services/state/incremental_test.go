@@ -0,0 +1,458 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/rocket-pool/smartnode/bindings/megapool"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	rpstate "github.com/rocket-pool/smartnode/bindings/utils/state"
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+)
+
+// The megapool details aren't serialized with the state, so they're recorded separately
+type megapoolFixture struct {
+	Previous map[common.Address]rpstate.NativeMegapoolDetails `json:"previous"`
+	Current  map[common.Address]rpstate.NativeMegapoolDetails `json:"current"`
+}
+
+// Answers an incremental update from a recorded full snapshot of the network, and records what was requested
+type fixtureSource struct {
+	current   *NetworkState
+	megapools map[common.Address]rpstate.NativeMegapoolDetails
+	contracts StateUpdateRecording
+	logs      []ethtypes.Log
+
+	logQueries int
+
+	requestedNodes              []common.Address
+	requestedMinipools          []common.Address
+	requestedMegapoolValidators []uint64
+	requestedMegapools          []common.Address
+	requestedShares             []common.Address
+}
+
+// Load a recorded snapshot of the network state
+func loadTestState(t *testing.T, name string, megapools map[common.Address]rpstate.NativeMegapoolDetails) *NetworkState {
+	state := &NetworkState{}
+	loadTestFixture(t, name, state)
+	state.MegapoolDetails = megapools
+	state.MegapoolToPubkeysMap = map[common.Address][]types.ValidatorPubkey{}
+	for _, validator := range state.MegapoolValidatorGlobalIndex {
+		if len(validator.Pubkey) > 0 {
+			state.MegapoolToPubkeysMap[validator.MegapoolAddress] = append(state.MegapoolToPubkeysMap[validator.MegapoolAddress], types.ValidatorPubkey(validator.Pubkey))
+		}
+	}
+	return state
+}
+
+func loadTestFixture(t *testing.T, name string, value any) {
+	bytes, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(bytes, value); err != nil {
+		t.Fatalf("error decoding %s: %s", name, err)
+	}
+}
+
+// Load the recorded snapshots before and after the update, and a source that answers from the snapshot after it
+func loadIncrementalFixtures(t *testing.T) (*NetworkState, *NetworkState, *fixtureSource) {
+	var megapools megapoolFixture
+	loadTestFixture(t, "incremental-megapools.json", &megapools)
+	previous := loadTestState(t, "incremental-previous.json", megapools.Previous)
+	current := loadTestState(t, "incremental-current.json", megapools.Current)
+
+	source := &fixtureSource{
+		current:   loadTestState(t, "incremental-current.json", megapools.Current),
+		megapools: megapools.Current,
+	}
+	loadTestFixture(t, "incremental-contracts.json", &source.contracts)
+	loadTestFixture(t, "incremental-logs.json", &source.logs)
+	return previous, current, source
+}
+
+// Copy a value so the update can't share pointers with the recording
+func copyFixture[ValueType any](value ValueType) ValueType {
+	var copied ValueType
+	bytes, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(bytes, &copied)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+func (s *fixtureSource) getNetworkDetails() (*rpstate.NetworkDetails, error) {
+	return copyFixture(s.current.NetworkDetails), nil
+}
+
+func (s *fixtureSource) getOracleDaoMemberDetails() ([]rpstate.OracleDaoMemberDetails, error) {
+	return copyFixture(s.current.OracleDaoMemberDetails), nil
+}
+
+func (s *fixtureSource) getContractAddresses() ([]common.Address, []common.Address) {
+	return s.contracts.GlobalContracts, s.contracts.OtherContracts
+}
+
+func (s *fixtureSource) getLogs(fromBlock uint64, addresses []common.Address) ([]ethtypes.Log, error) {
+	s.logQueries++
+	logs := []ethtypes.Log{}
+	for _, log := range s.logs {
+		if log.BlockNumber >= fromBlock && slices.Contains(addresses, log.Address) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// Minipool events are filtered by signature only, so this returns the logs from every address that isn't a network contract
+func (s *fixtureSource) getMinipoolLogs(fromBlock uint64) ([]ethtypes.Log, error) {
+	s.logQueries++
+	logs := []ethtypes.Log{}
+	for _, log := range s.logs {
+		if log.BlockNumber >= fromBlock && !slices.Contains(s.contracts.GlobalContracts, log.Address) && !slices.Contains(s.contracts.OtherContracts, log.Address) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (s *fixtureSource) getEthBalances(addresses []common.Address) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(addresses))
+	for i, address := range addresses {
+		for _, node := range s.current.NodeDetails {
+			if node.NodeAddress == address {
+				balances[i] = node.BalanceETH
+			} else if node.FeeDistributorAddress == address {
+				balances[i] = node.DistributorBalance
+			}
+		}
+		if minipool, exists := s.current.MinipoolDetailsByAddress[address]; exists {
+			balances[i] = minipool.Balance
+		}
+		if megapool, exists := s.megapools[address]; exists {
+			balances[i] = megapool.EthBalance
+		}
+		if balances[i] == nil {
+			return nil, fmt.Errorf("no recorded balance for %s", address.Hex())
+		}
+		balances[i] = big.NewInt(0).Set(balances[i])
+	}
+	return balances, nil
+}
+
+func (s *fixtureSource) getNodeAddresses(start uint64) ([]common.Address, error) {
+	addresses := []common.Address{}
+	for _, node := range s.current.NodeDetails[start:] {
+		addresses = append(addresses, node.NodeAddress)
+	}
+	return addresses, nil
+}
+
+func (s *fixtureSource) getMinipoolAddresses(start uint64) ([]common.Address, error) {
+	addresses := []common.Address{}
+	for _, minipool := range s.current.MinipoolDetails[start:] {
+		addresses = append(addresses, minipool.MinipoolAddress)
+	}
+	return addresses, nil
+}
+
+func (s *fixtureSource) getNodeDetails(addresses []common.Address) ([]rpstate.NativeNodeDetails, error) {
+	s.requestedNodes = append(s.requestedNodes, addresses...)
+	details := make([]rpstate.NativeNodeDetails, len(addresses))
+	for i, address := range addresses {
+		node, exists := s.current.NodeDetailsByAddress[address]
+		if !exists {
+			return nil, fmt.Errorf("no recorded node %s", address.Hex())
+		}
+		details[i] = copyFixture(*node)
+	}
+	return details, nil
+}
+
+func (s *fixtureSource) getMinipoolDetails(addresses []common.Address) ([]rpstate.NativeMinipoolDetails, error) {
+	s.requestedMinipools = append(s.requestedMinipools, addresses...)
+	details := make([]rpstate.NativeMinipoolDetails, len(addresses))
+	for i, address := range addresses {
+		minipool, exists := s.current.MinipoolDetailsByAddress[address]
+		if !exists {
+			return nil, fmt.Errorf("no recorded minipool %s", address.Hex())
+		}
+
+		// The shares including the Beacon balance haven't been calculated yet when the details are retrieved
+		details[i] = copyFixture(*minipool)
+		details[i].NodeShareOfBeaconBalance = nil
+		details[i].UserShareOfBeaconBalance = nil
+		details[i].NodeShareOfBalanceIncludingBeacon = nil
+		details[i].UserShareOfBalanceIncludingBeacon = nil
+	}
+	return details, nil
+}
+
+func (s *fixtureSource) getMegapoolValidatorCount() (uint64, error) {
+	return uint64(len(s.current.MegapoolValidatorGlobalIndex)), nil
+}
+
+func (s *fixtureSource) getMegapoolValidators(indices []uint64) ([]megapool.ValidatorInfoFromGlobalIndex, error) {
+	s.requestedMegapoolValidators = append(s.requestedMegapoolValidators, indices...)
+	validators := make([]megapool.ValidatorInfoFromGlobalIndex, len(indices))
+	for i, index := range indices {
+		validators[i] = copyFixture(s.current.MegapoolValidatorGlobalIndex[index])
+	}
+	return validators, nil
+}
+
+func (s *fixtureSource) getMegapoolDetails(megapoolAddress common.Address) (rpstate.NativeMegapoolDetails, error) {
+	s.requestedMegapools = append(s.requestedMegapools, megapoolAddress)
+	details, exists := s.megapools[megapoolAddress]
+	if !exists {
+		return rpstate.NativeMegapoolDetails{}, fmt.Errorf("no recorded megapool %s", megapoolAddress.Hex())
+	}
+	return copyFixture(details), nil
+}
+
+func (s *fixtureSource) getValidatorStatuses(pubkeys []types.ValidatorPubkey) (map[types.ValidatorPubkey]beacon.ValidatorStatus, error) {
+	statuses := map[types.ValidatorPubkey]beacon.ValidatorStatus{}
+	for _, pubkey := range pubkeys {
+		status, exists := s.current.MinipoolValidatorDetails[pubkey]
+		if !exists {
+			status, exists = s.current.MegapoolValidatorDetails[pubkey]
+		}
+		if !exists {
+			return nil, fmt.Errorf("no recorded status for validator %s", pubkey.Hex())
+		}
+		statuses[pubkey] = status
+	}
+	return statuses, nil
+}
+
+func (s *fixtureSource) calculateCompleteMinipoolShares(minipools []*rpstate.NativeMinipoolDetails, beaconBalances []*big.Int) error {
+	for i, details := range minipools {
+		s.requestedShares = append(s.requestedShares, details.MinipoolAddress)
+		recorded := s.current.MinipoolDetailsByAddress[details.MinipoolAddress]
+		expectedBalance := getBeaconBalance(s.current.MinipoolValidatorDetails, details.Pubkey)
+		if beaconBalances[i].Cmp(expectedBalance) != 0 {
+			return fmt.Errorf("minipool %s has a Beacon balance of %s but it should be %s", details.MinipoolAddress.Hex(), beaconBalances[i], expectedBalance)
+		}
+		details.NodeShareOfBeaconBalance = big.NewInt(0).Set(recorded.NodeShareOfBeaconBalance)
+		details.UserShareOfBeaconBalance = big.NewInt(0).Set(recorded.UserShareOfBeaconBalance)
+		details.NodeShareOfBalanceIncludingBeacon = big.NewInt(0).Set(recorded.NodeShareOfBalanceIncludingBeacon)
+		details.UserShareOfBalanceIncludingBeacon = big.NewInt(0).Set(recorded.UserShareOfBalanceIncludingBeacon)
+	}
+	return nil
+}
+
+// Serialize everything a state holds, including the parts that aren't part of its JSON, in a stable order
+func snapshotTestState(t *testing.T, state *NetworkState) string {
+	stateWithoutValidators := *state
+	stateWithoutValidators.MinipoolValidatorDetails = nil
+	stateWithoutValidators.MegapoolValidatorDetails = nil
+	stateBytes, err := json.Marshal(&stateWithoutValidators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	megapoolBytes, err := json.Marshal(state.MegapoolDetails)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The validator maps are serialized as lists in map order, but fmt sorts them
+	return fmt.Sprintf("%s\n%s\n%v\n%v", stateBytes, megapoolBytes, map[types.ValidatorPubkey]beacon.ValidatorStatus(state.MinipoolValidatorDetails), map[types.ValidatorPubkey]beacon.ValidatorStatus(state.MegapoolValidatorDetails))
+}
+
+// Make sure two parts of a state serialize the same way
+func requireSameJson(t *testing.T, name string, expected any, actual any) {
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualBytes, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(expectedBytes) != string(actualBytes) {
+		t.Fatalf("%s doesn't match the full snapshot\nexpected: %s\nactual:   %s", name, expectedBytes, actualBytes)
+	}
+}
+
+func requireAddresses(t *testing.T, name string, expected []string, actual []common.Address) {
+	addresses := make([]common.Address, len(expected))
+	for i, address := range expected {
+		addresses[i] = common.HexToAddress(address)
+	}
+	if !slices.Equal(addresses, actual) {
+		t.Fatalf("expected %s to be requested for %v, but they were requested for %v", name, addresses, actual)
+	}
+}
+
+// Make sure an incremental update produces the same state as a full snapshot taken at the same slot, while only reading what changed
+func TestIncrementalUpdateMatchesFullSnapshot(t *testing.T) {
+	previous, expected, source := loadIncrementalFixtures(t)
+	previousSnapshot := snapshotTestState(t, previous)
+
+	state, stats, err := applyStateUpdate(previous, source, expected.BeaconSlotNumber, expected.ElBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.ElBlockNumber != expected.ElBlockNumber || state.BeaconSlotNumber != expected.BeaconSlotNumber || state.IsSaturnDeployed != expected.IsSaturnDeployed {
+		t.Fatalf("expected EL block %d and slot %d, got EL block %d and slot %d", expected.ElBlockNumber, expected.BeaconSlotNumber, state.ElBlockNumber, state.BeaconSlotNumber)
+	}
+	requireSameJson(t, "network details", expected.NetworkDetails, state.NetworkDetails)
+	requireSameJson(t, "node details", expected.NodeDetails, state.NodeDetails)
+	requireSameJson(t, "minipool details", expected.MinipoolDetails, state.MinipoolDetails)
+	requireSameJson(t, "megapool validators", expected.MegapoolValidatorGlobalIndex, state.MegapoolValidatorGlobalIndex)
+	requireSameJson(t, "megapool details", expected.MegapoolDetails, state.MegapoolDetails)
+	requireSameJson(t, "Oracle DAO details", expected.OracleDaoMemberDetails, state.OracleDaoMemberDetails)
+	if !reflect.DeepEqual(expected.MinipoolValidatorDetails, state.MinipoolValidatorDetails) {
+		t.Fatalf("minipool validator details don't match the full snapshot: %v", state.MinipoolValidatorDetails)
+	}
+	if !reflect.DeepEqual(expected.MegapoolValidatorDetails, state.MegapoolValidatorDetails) {
+		t.Fatalf("megapool validator details don't match the full snapshot: %v", state.MegapoolValidatorDetails)
+	}
+	if !reflect.DeepEqual(expected.MegapoolToPubkeysMap, state.MegapoolToPubkeysMap) {
+		t.Fatalf("megapool pubkeys don't match the full snapshot: %v", state.MegapoolToPubkeysMap)
+	}
+
+	// The lookups have to point into the new state
+	for i := range state.NodeDetails {
+		if state.NodeDetailsByAddress[state.NodeDetails[i].NodeAddress] != &state.NodeDetails[i] {
+			t.Fatalf("node lookup for %s doesn't point into the state", state.NodeDetails[i].NodeAddress.Hex())
+		}
+	}
+	for i := range state.MinipoolDetails {
+		minipool := &state.MinipoolDetails[i]
+		if state.MinipoolDetailsByAddress[minipool.MinipoolAddress] != minipool || !slices.Contains(state.MinipoolDetailsByNode[minipool.NodeAddress], minipool) {
+			t.Fatalf("minipool lookups for %s don't point into the state", minipool.MinipoolAddress.Hex())
+		}
+	}
+
+	// Only the entities that changed, or are new, should have been read
+	requireAddresses(t, "node details", []string{
+		"0x2222222222222222222222222222222222222222",
+		"0x3333333333333333333333333333333333333333",
+		"0x4444444444444444444444444444444444444444",
+	}, source.requestedNodes)
+	requireAddresses(t, "minipool details", []string{
+		"0xa3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3",
+		"0xa4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4",
+	}, source.requestedMinipools)
+	requireAddresses(t, "minipool shares", []string{
+		"0xa1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1a1",
+		"0xa3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3a3",
+		"0xa4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4",
+	}, source.requestedShares)
+	requireAddresses(t, "megapool details", []string{
+		"0xb3b3b3b3b3b3b3b3b3b3b3b3b3b3b3b3b3b3b3b3",
+	}, source.requestedMegapools)
+	if !slices.Equal(source.requestedMegapoolValidators, []uint64{0, 1, 2}) {
+		t.Fatalf("expected megapool validators 0 to 2 to be requested, but %v were", source.requestedMegapoolValidators)
+	}
+
+	// The logs take a fixed number of queries, no matter how many minipools there are
+	if source.logQueries != 2 || stats.logQueries != source.logQueries {
+		t.Fatalf("expected 2 log queries, but %d were made and %d were reported", source.logQueries, stats.logQueries)
+	}
+	if stats.refreshedNodes != 3 || stats.totalNodes != len(expected.NodeDetails) ||
+		stats.refreshedMinipools != 2 || stats.totalMinipools != len(expected.MinipoolDetails) ||
+		stats.refreshedMegapools != 1 || stats.totalMegapools != len(expected.MegapoolDetails) {
+		t.Fatalf("unexpected update stats: %+v", *stats)
+	}
+
+	// The previous state is shared with its users, so it can't be modified
+	if snapshotTestState(t, previous) != previousSnapshot {
+		t.Fatal("the update modified the previous state")
+	}
+}
+
+// Make sure modifying the values in an updated state doesn't modify the snapshot it was created from
+func TestIncrementalUpdateDoesNotShareValues(t *testing.T) {
+	previous, expected, source := loadIncrementalFixtures(t)
+	previousSnapshot := snapshotTestState(t, previous)
+
+	state, _, err := applyStateUpdate(previous, source, expected.BeaconSlotNumber, expected.ElBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Change every big.Int the new state holds for its nodes, minipools and megapools
+	for i := range state.NodeDetails {
+		incrementBigInts(reflect.ValueOf(&state.NodeDetails[i]).Elem())
+	}
+	for i := range state.MinipoolDetails {
+		incrementBigInts(reflect.ValueOf(&state.MinipoolDetails[i]).Elem())
+	}
+	for address, details := range state.MegapoolDetails {
+		incrementBigInts(reflect.ValueOf(&details).Elem())
+		state.MegapoolDetails[address] = details
+	}
+
+	if snapshotTestState(t, previous) != previousSnapshot {
+		t.Fatal("modifying the updated state modified the previous state")
+	}
+}
+
+// Add one to every big.Int field in a struct, in place
+func incrementBigInts(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		if field, ok := value.Field(i).Interface().(*big.Int); ok && field != nil {
+			field.Add(field, big.NewInt(1))
+		}
+	}
+}
+
+// Make sure changes that can't be applied incrementally cause a full rebuild
+func TestIncrementalUpdateRequiresRebuild(t *testing.T) {
+	tests := map[string]func(previous *NetworkState, source *fixtureSource) uint64{
+		"global contract event": func(previous *NetworkState, source *fixtureSource) uint64 {
+			source.logs = append(source.logs, ethtypes.Log{
+				Address:     source.contracts.GlobalContracts[0],
+				Topics:      []common.Hash{common.HexToHash("0x01")},
+				BlockNumber: previous.ElBlockNumber + 1,
+			})
+			return source.current.ElBlockNumber
+		},
+		"destroyed minipool": func(previous *NetworkState, source *fixtureSource) uint64 {
+			source.logs = append(source.logs, ethtypes.Log{
+				Address:     source.contracts.OtherContracts[0],
+				Topics:      []common.Hash{minipoolDestroyedTopic},
+				BlockNumber: previous.ElBlockNumber + 1,
+			})
+			return source.current.ElBlockNumber
+		},
+		"reordered minipools": func(previous *NetworkState, source *fixtureSource) uint64 {
+			minipools := source.current.MinipoolDetails
+			minipools[1], minipools[2] = minipools[2], minipools[1]
+			return source.current.ElBlockNumber
+		},
+		"old snapshot": func(previous *NetworkState, source *fixtureSource) uint64 {
+			return previous.ElBlockNumber + maxIncrementalBlockRange + 1
+		},
+		"earlier block": func(previous *NetworkState, source *fixtureSource) uint64 {
+			return previous.ElBlockNumber - 1
+		},
+	}
+
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			previous, _, source := loadIncrementalFixtures(t)
+			elBlockNumber := setup(previous, source)
+			_, _, err := applyStateUpdate(previous, source, source.current.BeaconSlotNumber, elBlockNumber)
+			if !errors.Is(err, errFullRebuildRequired) {
+				t.Fatalf("expected a full rebuild to be required, got %v", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -23,6 +24,11 @@ type NetworkStateManager struct {
 	// Multicaller and batch balance contract addresses
 	multicaller    common.Address
 	balanceBatcher common.Address
+
+	// The last snapshot created by GetUpdatedStateForSlot, which the next one is built from
+	lastState      *NetworkState
+	lastFullUpdate time.Time
+	updateLock     sync.Mutex
 }
 
 // Create a new manager for the network state
@@ -0,0 +1,188 @@
+package submission
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+)
+
+// Config
+const (
+	// The number of upcoming blocks to target with each bundle
+	DefaultBundleBlockCount uint64 = 10
+
+	bundleSignatureHeader string        = "X-Flashbots-Signature"
+	bundleRequestTimeout  time.Duration = 10 * time.Second
+)
+
+// Anything that can report the latest block number
+type BlockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Submits transactions to a block builder relay as single-transaction bundles with eth_sendBundle.
+// The transaction is bundled for each of the next few blocks, since a bundle is only valid for one block.
+type BundleSubmitter struct {
+	url         string
+	blockReader BlockNumberReader
+	blockCount  uint64
+	signingKey  *ecdsa.PrivateKey
+	client      *http.Client
+}
+
+type bundleParams struct {
+	Txs         []string `json:"txs"`
+	BlockNumber string   `json:"blockNumber"`
+}
+
+type jsonRpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Create a new bundle submitter.
+// Relays identify searchers by the key that signs their requests; since the Smartnode doesn't build up a reputation with them, a random key is used.
+func NewBundleSubmitter(url string, blockReader BlockNumberReader, blockCount uint64) (*BundleSubmitter, error) {
+	signingKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("error generating bundle signing key: %w", err)
+	}
+	if blockCount == 0 {
+		blockCount = DefaultBundleBlockCount
+	}
+	return &BundleSubmitter{
+		url:         url,
+		blockReader: blockReader,
+		blockCount:  blockCount,
+		signingKey:  signingKey,
+		client:      &http.Client{Timeout: bundleRequestTimeout},
+	}, nil
+}
+
+// Submit a signed transaction to the relay as a bundle targeting each of the next few blocks.
+// It succeeds if the relay accepts the bundle for at least one of them.
+func (s *BundleSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := s.SendExpiringTransaction(ctx, tx)
+	return err
+}
+
+// Submit a signed transaction to the relay as a bundle targeting each of the next few blocks.
+// Returns the last block a bundle was accepted for; the transaction won't be included through the relay after it.
+func (s *BundleSubmitter) SendExpiringTransaction(ctx context.Context, tx *types.Transaction) (uint64, error) {
+
+	// Encode the transaction
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("error encoding transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	encodedTx := hexutil.Encode(rawTx)
+
+	// Get the latest block
+	latestBlock, err := s.blockReader.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest block number: %w", err)
+	}
+
+	// Send a bundle for each target block
+	var lastErr error
+	lastAcceptedBlock := uint64(0)
+	for block := latestBlock + 1; block <= latestBlock+s.blockCount; block++ {
+		err = s.sendBundle(ctx, encodedTx, block)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lastAcceptedBlock = block
+	}
+	if lastAcceptedBlock == 0 {
+		return 0, fmt.Errorf("error submitting transaction %s to bundle relay [%s]: %w", tx.Hash().Hex(), s.url, lastErr)
+	}
+	return lastAcceptedBlock, nil
+
+}
+
+// Send a bundle with a single transaction targeting the given block
+func (s *BundleSubmitter) sendBundle(ctx context.Context, encodedTx string, blockNumber uint64) error {
+
+	// Build the request
+	body, err := json.Marshal(jsonRpcRequest{
+		JsonRpc: "2.0",
+		Id:      1,
+		Method:  "eth_sendBundle",
+		Params: []interface{}{bundleParams{
+			Txs:         []string{encodedTx},
+			BlockNumber: hexutil.EncodeUint64(blockNumber),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("error serializing bundle: %w", err)
+	}
+	signature, err := s.signBody(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating bundle request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(bundleSignatureHeader, signature)
+
+	// Send it
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending bundle for block %d: %w", blockNumber, err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("error reading bundle response for block %d: %w", blockNumber, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("relay rejected the bundle for block %d with status %d: %s", blockNumber, response.StatusCode, string(responseBody))
+	}
+
+	// Check for JSON-RPC errors
+	var rpcResponse jsonRpcResponse
+	err = json.Unmarshal(responseBody, &rpcResponse)
+	if err != nil {
+		return fmt.Errorf("error decoding bundle response for block %d: %w", blockNumber, err)
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf("relay rejected the bundle for block %d: %s (code %d)", blockNumber, rpcResponse.Error.Message, rpcResponse.Error.Code)
+	}
+	return nil
+
+}
+
+// Sign a request body the way Flashbots-style relays expect: an EIP-191 signature of the hex-encoded keccak256 hash of the body,
+// prefixed with the signer's address
+func (s *BundleSubmitter) signBody(body []byte) (string, error) {
+	hash := crypto.Keccak256Hash(body).Hex()
+	signature, err := crypto.Sign(accounts.TextHash([]byte(hash)), s.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("error signing bundle: %w", err)
+	}
+	address := crypto.PubkeyToAddress(s.signingKey.PublicKey)
+	return fmt.Sprintf("%s:%s", address.Hex(), hexutil.Encode(signature)), nil
+}
@@ -0,0 +1,68 @@
+package submission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// How often to check for new blocks while waiting to fall back
+const FallbackPollInterval = 6 * time.Second
+
+// Anything that can check whether a transaction has been included
+type ChainReader interface {
+	BlockNumberReader
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// Wait until the block after lastBlock, then send the transaction with the fallback if it still hasn't been included.
+// Nothing is sent if its nonce was used by a different transaction in the meantime (e.g. a replacement).
+// Returns true if the fallback was used.
+func FallBackAfterBlock(client ChainReader, tx *types.Transaction, lastBlock uint64, pollInterval time.Duration, fallback func(*types.Transaction) error) (bool, error) {
+
+	// Wait for the last block to pass, stopping early if the transaction is included
+	for {
+		block, err := client.BlockNumber(context.Background())
+		if err != nil {
+			return false, fmt.Errorf("error getting latest block number: %w", err)
+		}
+		_, err = client.TransactionReceipt(context.Background(), tx.Hash())
+		if err == nil {
+			return false, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return false, fmt.Errorf("error getting receipt for transaction %s: %w", tx.Hash().Hex(), err)
+		}
+		if block > lastBlock {
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+
+	// Check if the nonce was used by something else
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return false, fmt.Errorf("error getting sender of transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	nonce, err := client.NonceAt(context.Background(), sender, nil)
+	if err != nil {
+		return false, fmt.Errorf("error getting nonce of %s: %w", sender.Hex(), err)
+	}
+	if nonce > tx.Nonce() {
+		return false, nil
+	}
+
+	err = fallback(tx)
+	if err != nil {
+		return false, fmt.Errorf("error sending transaction %s with the fallback: %w", tx.Hash().Hex(), err)
+	}
+	return true, nil
+
+}
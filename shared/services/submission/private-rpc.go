@@ -0,0 +1,52 @@
+package submission
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Submits transactions to a private RPC endpoint (such as Flashbots Protect) with eth_sendRawTransaction
+type PrivateRpcSubmitter struct {
+	url    string
+	client *ethclient.Client
+	lock   sync.Mutex
+}
+
+// Create a new private RPC submitter
+func NewPrivateRpcSubmitter(url string) *PrivateRpcSubmitter {
+	return &PrivateRpcSubmitter{
+		url: url,
+	}
+}
+
+// Submit a signed transaction to the private RPC endpoint
+func (s *PrivateRpcSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	client, err := s.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	err = client.SendTransaction(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("error submitting transaction %s to private RPC [%s]: %w", tx.Hash().Hex(), s.url, err)
+	}
+	return nil
+}
+
+// Get the client for the endpoint, connecting to it the first time it's used
+func (s *PrivateRpcSubmitter) getClient(ctx context.Context) (*ethclient.Client, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client == nil {
+		client, err := ethclient.DialContext(ctx, s.url)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to private RPC [%s]: %w", s.url, err)
+		}
+		s.client = client
+	}
+	return s.client, nil
+}
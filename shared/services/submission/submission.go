@@ -0,0 +1,47 @@
+package submission
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+)
+
+// A way of getting a signed transaction to the network other than the public mempool
+type Submitter interface {
+	// Submit a signed transaction
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// A submitter whose transactions can only be included up to a certain block, such as a bundle relay
+type ExpiringSubmitter interface {
+	Submitter
+
+	// Submit a signed transaction, returning the last block it can be included in
+	SendExpiringTransaction(ctx context.Context, tx *types.Transaction) (uint64, error)
+}
+
+// Context key for the submission mode override
+type modeKey struct{}
+
+// Get a copy of the context that submits its transactions with the given mode instead of the configured default.
+// Pass it in the Context field of a TransactOpts to use it for a single task.
+func WithMode(ctx context.Context, mode cfgtypes.TxSubmissionMode) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, modeKey{}, mode)
+}
+
+// Get the submission mode override from a context, if it has one
+func GetMode(ctx context.Context) (cfgtypes.TxSubmissionMode, bool) {
+	if ctx == nil {
+		return cfgtypes.TxSubmissionMode_Default, false
+	}
+	mode, ok := ctx.Value(modeKey{}).(cfgtypes.TxSubmissionMode)
+	if !ok || mode == cfgtypes.TxSubmissionMode_Default {
+		return cfgtypes.TxSubmissionMode_Default, false
+	}
+	return mode, true
+}
@@ -0,0 +1,327 @@
+package submission
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+
+	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
+)
+
+// A minimal JSON-RPC endpoint that records the transactions and bundles sent to it
+type mockRelay struct {
+	lock         sync.Mutex
+	rejectBlocks map[uint64]bool
+	rawTxs       []string
+	bundleBlocks []uint64
+	signers      []common.Address
+}
+
+type mockRequest struct {
+	Id     uint64            `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func newMockRelay() *mockRelay {
+	return &mockRelay{
+		rejectBlocks: map[uint64]bool{},
+	}
+}
+
+func (m *mockRelay) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var request mockRequest
+	if err := json.Unmarshal(body, &request); err != nil || len(request.Params) != 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch request.Method {
+	case "eth_sendRawTransaction":
+		var rawTx string
+		if err := json.Unmarshal(request.Params[0], &rawTx); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.rawTxs = append(m.rawTxs, rawTx)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.Id,
+			"result":  crypto.Keccak256Hash([]byte(rawTx)).Hex(),
+		})
+
+	case "eth_sendBundle":
+		signer, ok := recoverBundleSigner(body, r.Header.Get(bundleSignatureHeader))
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var params bundleParams
+		if err := json.Unmarshal(request.Params[0], &params); err != nil || len(params.Txs) != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		block, err := hexutil.DecodeUint64(params.BlockNumber)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if m.rejectBlocks[block] {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bundle rejected"}}`))
+			return
+		}
+		m.rawTxs = append(m.rawTxs, params.Txs[0])
+		m.bundleBlocks = append(m.bundleBlocks, block)
+		m.signers = append(m.signers, signer)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Check the signature header of a bundle request and get the address that signed it
+func recoverBundleSigner(body []byte, header string) (common.Address, bool) {
+	address, signatureString, found := strings.Cut(header, ":")
+	if !found {
+		return common.Address{}, false
+	}
+	signature, err := hexutil.Decode(signatureString)
+	if err != nil {
+		return common.Address{}, false
+	}
+	pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), signature)
+	if err != nil {
+		return common.Address{}, false
+	}
+	signer := crypto.PubkeyToAddress(*pubkey)
+	return signer, signer == common.HexToAddress(address)
+}
+
+type staticBlockReader uint64
+
+func (r staticBlockReader) BlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(r), nil
+}
+
+// Create a signed transaction and its encoded form
+func newSignedTx(t *testing.T) (*types.Transaction, string) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(20e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, hexutil.Encode(rawTx)
+}
+
+func TestModeContext(t *testing.T) {
+	if _, ok := GetMode(context.Background()); ok {
+		t.Error("expected no mode override in a plain context")
+	}
+	if _, ok := GetMode(WithMode(context.Background(), cfgtypes.TxSubmissionMode_Default)); ok {
+		t.Error("expected the default mode not to count as an override")
+	}
+	mode, ok := GetMode(WithMode(nil, cfgtypes.TxSubmissionMode_Bundle))
+	if !ok || mode != cfgtypes.TxSubmissionMode_Bundle {
+		t.Errorf("expected mode %s, got %s (%t)", cfgtypes.TxSubmissionMode_Bundle, mode, ok)
+	}
+}
+
+func TestPrivateRpcSubmitter(t *testing.T) {
+	relay := newMockRelay()
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	tx, encodedTx := newSignedTx(t)
+	submitter := NewPrivateRpcSubmitter(server.URL)
+	if err := submitter.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("error submitting transaction: %s", err.Error())
+	}
+
+	if len(relay.rawTxs) != 1 || relay.rawTxs[0] != encodedTx {
+		t.Errorf("expected the endpoint to receive %s, got %v", encodedTx, relay.rawTxs)
+	}
+	if len(relay.bundleBlocks) != 0 {
+		t.Errorf("expected no bundles, got %d", len(relay.bundleBlocks))
+	}
+}
+
+func TestBundleSubmitter(t *testing.T) {
+	relay := newMockRelay()
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	// Reject one of the target blocks; the submission should still succeed
+	relay.rejectBlocks[102] = true
+
+	tx, encodedTx := newSignedTx(t)
+	submitter, err := NewBundleSubmitter(server.URL, staticBlockReader(100), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := submitter.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("error submitting transaction: %s", err.Error())
+	}
+
+	expectedBlocks := []uint64{101, 103}
+	if len(relay.bundleBlocks) != len(expectedBlocks) {
+		t.Fatalf("expected bundles for blocks %v, got %v", expectedBlocks, relay.bundleBlocks)
+	}
+	signer := crypto.PubkeyToAddress(submitter.signingKey.PublicKey)
+	for i, block := range expectedBlocks {
+		if relay.bundleBlocks[i] != block {
+			t.Errorf("expected bundle %d to target block %d, got %d", i, block, relay.bundleBlocks[i])
+		}
+		if relay.rawTxs[i] != encodedTx {
+			t.Errorf("expected bundle %d to contain %s, got %s", i, encodedTx, relay.rawTxs[i])
+		}
+		if relay.signers[i] != signer {
+			t.Errorf("expected bundle %d to be signed by %s, got %s", i, signer.Hex(), relay.signers[i].Hex())
+		}
+	}
+}
+
+func TestBundleSubmitterRejected(t *testing.T) {
+	relay := newMockRelay()
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	for block := uint64(51); block <= 50+DefaultBundleBlockCount; block++ {
+		relay.rejectBlocks[block] = true
+	}
+
+	tx, _ := newSignedTx(t)
+	submitter, err := NewBundleSubmitter(server.URL, staticBlockReader(50), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = submitter.SendTransaction(context.Background(), tx)
+	if err == nil {
+		t.Fatal("expected an error when the relay rejects every bundle")
+	}
+	if !strings.Contains(err.Error(), "bundle rejected") {
+		t.Errorf("expected the relay's error in the result, got: %s", err.Error())
+	}
+}
+
+func TestBundleSubmitterLastBlock(t *testing.T) {
+	relay := newMockRelay()
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	// The last accepted block is the one the transaction expires after
+	relay.rejectBlocks[103] = true
+
+	tx, _ := newSignedTx(t)
+	submitter, err := NewBundleSubmitter(server.URL, staticBlockReader(100), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastBlock, err := submitter.SendExpiringTransaction(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("error submitting transaction: %s", err.Error())
+	}
+	if lastBlock != 102 {
+		t.Errorf("expected the transaction to expire after block 102, got %d", lastBlock)
+	}
+}
+
+// A chain that advances one block each time its block number is read
+type mockChainReader struct {
+	block    uint64
+	included bool
+	nonce    uint64
+}
+
+func (m *mockChainReader) BlockNumber(ctx context.Context) (uint64, error) {
+	m.block++
+	return m.block, nil
+}
+
+func (m *mockChainReader) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if m.included {
+		return &types.Receipt{TxHash: txHash}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (m *mockChainReader) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return m.nonce, nil
+}
+
+func TestFallBackAfterBlock(t *testing.T) {
+	tx, _ := newSignedTx(t)
+	tests := []struct {
+		name     string
+		included bool
+		nonce    uint64
+		expected bool
+	}{
+		{name: "not included", included: false, nonce: tx.Nonce(), expected: true},
+		{name: "included", included: true, nonce: tx.Nonce() + 1, expected: false},
+		{name: "replaced", included: false, nonce: tx.Nonce() + 1, expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &mockChainReader{block: 100, included: test.included, nonce: test.nonce}
+			var sentBlock uint64
+			sent, err := FallBackAfterBlock(client, tx, 103, 0, func(fallbackTx *types.Transaction) error {
+				if fallbackTx.Hash() != tx.Hash() {
+					t.Errorf("fallback sent %s instead of %s", fallbackTx.Hash().Hex(), tx.Hash().Hex())
+				}
+				sentBlock = client.block
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if sent != test.expected {
+				t.Fatalf("expected fallback used to be %t, got %t", test.expected, sent)
+			}
+			if sent && sentBlock != 104 {
+				t.Errorf("expected the fallback after block 103, but it was used at block %d", sentBlock)
+			}
+			if test.included && client.block != 101 {
+				t.Errorf("expected to stop waiting once the transaction was included, but waited until block %d", client.block)
+			}
+		})
+	}
+}
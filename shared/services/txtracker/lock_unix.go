@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package txtracker
+
+import (
+	"os"
+	"syscall"
+)
+
+// Take an exclusive lock on a file, waiting for any other holder to release it
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// Release a lock taken with lockFile
+func unlockFile(file *os.File) {
+	_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
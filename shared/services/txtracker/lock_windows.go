@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package txtracker
+
+import (
+	"os"
+)
+
+// The store is only shared between processes inside the Smartnode's containers, so there's nothing to lock on Windows
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// Release a lock taken with lockFile
+func unlockFile(file *os.File) {
+}
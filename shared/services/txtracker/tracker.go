@@ -0,0 +1,621 @@
+package txtracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Config
+const (
+	// Replacement transactions need to raise both fees by at least 10% to be accepted by the mempool
+	FeeBumpPercent    int64 = 13
+	MinFeeBumpPercent int64 = 10
+
+	CancelGasLimit uint64 = 21000
+
+	lookupAttempts int         = 30
+	fileMode       os.FileMode = 0600
+)
+
+// How often pending transactions are checked
+var pollInterval time.Duration = 6 * time.Second
+
+// How long to wait for a transaction before giving up on it; it's still tracked afterwards, so it can be cancelled
+var waitTimeout time.Duration = 30 * time.Minute
+
+// Returned when a transaction couldn't be found on the Execution client, so it can't be tracked
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// Returned when a transaction still hasn't been included after waiting for it
+var ErrTransactionPending = errors.New("transaction is still pending")
+
+// The store is shared by every tracker in the process, and with other processes through a lock file next to it
+var storeLock sync.Mutex
+
+// A transaction sent by the node that hasn't been included in a block yet
+type PendingTransaction struct {
+	Nonce          uint64         `json:"nonce"`
+	From           common.Address `json:"from"`
+	Hash           common.Hash    `json:"hash"`
+	PreviousHashes []common.Hash  `json:"previousHashes"`
+	To             common.Address `json:"to"`
+	Value          *big.Int       `json:"value"`
+	Data           hexutil.Bytes  `json:"data"`
+	GasLimit       uint64         `json:"gasLimit"`
+	MaxFee         *big.Int       `json:"maxFee"`
+	MaxPriorityFee *big.Int       `json:"maxPriorityFee"`
+	SubmittedBlock uint64         `json:"submittedBlock"`
+	SubmittedTime  time.Time      `json:"submittedTime"`
+	Bumps          uint           `json:"bumps"`
+	Cancelled      bool           `json:"cancelled"`
+}
+
+// Tracks the node's pending transactions by nonce, so stuck ones can be re-broadcast with higher fees or cancelled.
+// The list of pending transactions is saved to disk so it's shared by the daemons and the CLI.
+type Tracker struct {
+	path       string
+	ec         rocketpool.ExecutionClient
+	bumpBlocks uint64
+}
+
+// Create a new transaction tracker
+func NewTracker(cfg *config.RocketPoolConfig, ec rocketpool.ExecutionClient) *Tracker {
+	return &Tracker{
+		path:       cfg.Smartnode.GetPendingTransactionsPath(),
+		ec:         ec,
+		bumpBlocks: cfg.Smartnode.TxFeeBumpBlocks.Value.(uint64),
+	}
+}
+
+// Start tracking a transaction that was just submitted
+func (t *Tracker) Track(hash common.Hash) (*PendingTransaction, error) {
+
+	// Get the transaction, retrying for a bit if the client hasn't seen it yet
+	var tx *types.Transaction
+	var err error
+	for i := 0; i < lookupAttempts; i++ {
+		tx, _, err = t.ec.TransactionByHash(context.Background(), hash)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), err)
+		}
+		time.Sleep(time.Second)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, hash.Hex())
+	}
+	if tx.To() == nil {
+		return nil, fmt.Errorf("transaction %s is a contract creation, which can't be tracked", hash.Hex())
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sender of transaction %s: %w", hash.Hex(), err)
+	}
+	block, err := t.ec.BlockNumber(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest block: %w", err)
+	}
+
+	pending := &PendingTransaction{
+		Nonce:          tx.Nonce(),
+		From:           from,
+		Hash:           hash,
+		PreviousHashes: []common.Hash{},
+		To:             *tx.To(),
+		Value:          tx.Value(),
+		Data:           tx.Data(),
+		GasLimit:       tx.Gas(),
+		MaxFee:         tx.GasFeeCap(),
+		MaxPriorityFee: tx.GasTipCap(),
+		SubmittedBlock: block,
+		SubmittedTime:  time.Now(),
+	}
+
+	// Keep the history if this replaces a transaction that's already tracked
+	existing, err := t.get(from, pending.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Hash != hash {
+		pending.PreviousHashes = append(existing.PreviousHashes, existing.Hash)
+		pending.Bumps = existing.Bumps
+		pending.Cancelled = existing.Cancelled
+	}
+	return pending, t.save(pending)
+
+}
+
+// Wait for a transaction to be included in a block, re-broadcasting it with higher fees if it's still pending after the configured number of blocks.
+// The fees are never raised above maxFeeCap and maxPriorityFeeCap; if either is nil, the transaction's original fee is used as that cap.
+// opts must be the transactor that sent the transaction, so the replacements can be signed with it.
+// Transactions that can't be found on the Execution client are waited on without tracking.
+// Returns ErrTransactionPending if it isn't included in time.
+func (t *Tracker) WaitForTransaction(hash common.Hash, opts *bind.TransactOpts, maxFeeCap *big.Int, maxPriorityFeeCap *big.Int, logger *log.ColorLogger) (*types.Receipt, error) {
+
+	deadline := time.Now().Add(waitTimeout)
+	pending, err := t.Track(hash)
+	if errors.Is(err, ErrTransactionNotFound) {
+		logger.Printlnf("WARNING: couldn't find transaction %s on the Execution client, so it won't be re-broadcast if it gets stuck.", hash.Hex())
+		return waitForReceipt(t.ec, hash, deadline)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if maxFeeCap == nil || maxFeeCap.Sign() == 0 {
+		maxFeeCap = pending.MaxFee
+	}
+	if maxPriorityFeeCap == nil || maxPriorityFeeCap.Sign() == 0 {
+		maxPriorityFeeCap = pending.MaxPriorityFee
+	}
+
+	for {
+		// Pick up any changes made by other processes, such as a cancellation
+		stored, err := t.get(pending.From, pending.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil {
+			pending = stored
+		}
+
+		// Check if any version of the transaction has been included
+		receipt, err := t.getReceipt(pending)
+		if err != nil {
+			return nil, err
+		}
+		if receipt == nil {
+			// Check if the nonce was used by a transaction that isn't tracked
+			nonce, err := t.ec.NonceAt(context.Background(), pending.From, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error getting nonce of %s: %w", pending.From.Hex(), err)
+			}
+			if nonce > pending.Nonce {
+				// Check again in case it was included in the meantime
+				receipt, err = t.getReceipt(pending)
+				if err != nil {
+					return nil, err
+				}
+				if receipt == nil {
+					_ = t.remove(pending.From, pending.Nonce)
+					return nil, fmt.Errorf("nonce %d was used by a different transaction than %s", pending.Nonce, pending.Hash.Hex())
+				}
+			}
+		}
+		if receipt != nil {
+			err = t.remove(pending.From, pending.Nonce)
+			if err != nil {
+				logger.Printlnf("WARNING: couldn't remove transaction %s from the list of pending transactions: %s", receipt.TxHash.Hex(), err.Error())
+			}
+			if pending.Cancelled {
+				return receipt, fmt.Errorf("transaction with nonce %d was cancelled by %s", pending.Nonce, receipt.TxHash.Hex())
+			}
+			if receipt.Status == types.ReceiptStatusFailed {
+				return receipt, fmt.Errorf("Transaction failed with status 0")
+			}
+			if receipt.TxHash != hash {
+				logger.Printlnf("Transaction was included as %s.", receipt.TxHash.Hex())
+			}
+			return receipt, nil
+		}
+
+		// Bump the fees if it's been waiting too long
+		if t.bumpBlocks > 0 && !pending.Cancelled {
+			block, err := t.ec.BlockNumber(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("error getting latest block: %w", err)
+			}
+			if block >= pending.SubmittedBlock+t.bumpBlocks {
+				err = t.bump(pending, opts, maxFeeCap, maxPriorityFeeCap, logger)
+				if err != nil {
+					logger.Printlnf("WARNING: couldn't re-broadcast transaction %s: %s", pending.Hash.Hex(), err.Error())
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s hasn't been included after %s", ErrTransactionPending, pending.Hash.Hex(), waitTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+
+}
+
+// Replace a pending transaction with a 0-value transfer to the sender using the same nonce.
+// The fees are the larger of the ones in opts and the minimum required to replace the tracked transaction, if there is one.
+func (t *Tracker) Cancel(nonce uint64, opts *bind.TransactOpts) (common.Hash, error) {
+
+	// Make sure the nonce is still pending
+	latestNonce, err := t.ec.NonceAt(context.Background(), opts.From, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error getting nonce of %s: %w", opts.From.Hex(), err)
+	}
+	if nonce < latestNonce {
+		return common.Hash{}, fmt.Errorf("nonce %d has already been used by a transaction that was included in a block", nonce)
+	}
+
+	// Get the fees
+	if opts.GasFeeCap == nil || opts.GasTipCap == nil {
+		return common.Hash{}, fmt.Errorf("the max fee and priority fee must be set to cancel a transaction")
+	}
+	cancellation := &PendingTransaction{
+		Nonce:          nonce,
+		From:           opts.From,
+		PreviousHashes: []common.Hash{},
+		To:             opts.From,
+		Value:          big.NewInt(0),
+		Data:           []byte{},
+		GasLimit:       CancelGasLimit,
+		MaxFee:         opts.GasFeeCap,
+		MaxPriorityFee: opts.GasTipCap,
+		Cancelled:      true,
+	}
+	existing, err := t.get(opts.From, nonce)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if existing != nil {
+		cancellation.MaxFee = bigMax(cancellation.MaxFee, GetBumpedFee(existing.MaxFee, FeeBumpPercent))
+		cancellation.MaxPriorityFee = bigMax(cancellation.MaxPriorityFee, GetBumpedFee(existing.MaxPriorityFee, FeeBumpPercent))
+		cancellation.PreviousHashes = append(existing.PreviousHashes, existing.Hash)
+		cancellation.Bumps = existing.Bumps
+	}
+	if cancellation.MaxPriorityFee.Cmp(cancellation.MaxFee) > 0 {
+		cancellation.MaxFee = cancellation.MaxPriorityFee
+	}
+
+	// Send it
+	err = t.sendReplacement(cancellation, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return cancellation.Hash, nil
+
+}
+
+// Get the transactions that are still pending, removing the ones that have been included since they were tracked
+func (t *Tracker) GetPendingTransactions() ([]PendingTransaction, error) {
+
+	unlock, err := t.lockStore()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	txs, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the ones with nonces that have been used
+	latestNonces := map[common.Address]uint64{}
+	pending := []PendingTransaction{}
+	for _, tx := range txs {
+		latestNonce, exists := latestNonces[tx.From]
+		if !exists {
+			latestNonce, err = t.ec.NonceAt(context.Background(), tx.From, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error getting nonce of %s: %w", tx.From.Hex(), err)
+			}
+			latestNonces[tx.From] = latestNonce
+		}
+		if tx.Nonce >= latestNonce {
+			pending = append(pending, tx)
+		}
+	}
+	if len(pending) != len(txs) {
+		err = t.write(pending)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+
+}
+
+// Get a fee raised by the given percentage, rounded up
+func GetBumpedFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// Re-broadcast a pending transaction with higher fees, without exceeding the caps.
+// If it can't be re-broadcast, it's tried again once another set of blocks has passed.
+func (t *Tracker) bump(pending *PendingTransaction, opts *bind.TransactOpts, maxFeeCap *big.Int, maxPriorityFeeCap *big.Int, logger *log.ColorLogger) error {
+
+	// Raise both fees, but never past the caps
+	maxFee := GetBumpedFee(pending.MaxFee, FeeBumpPercent)
+	if maxFee.Cmp(maxFeeCap) > 0 {
+		maxFee = new(big.Int).Set(maxFeeCap)
+	}
+	maxPriorityFee := GetBumpedFee(pending.MaxPriorityFee, FeeBumpPercent)
+	if maxPriorityFee.Cmp(maxPriorityFeeCap) > 0 {
+		maxPriorityFee = new(big.Int).Set(maxPriorityFeeCap)
+	}
+	if maxPriorityFee.Cmp(maxFee) > 0 {
+		maxPriorityFee = new(big.Int).Set(maxFee)
+	}
+
+	// The mempool won't accept the replacement unless both fees went up enough
+	if maxFee.Cmp(GetBumpedFee(pending.MaxFee, MinFeeBumpPercent)) < 0 || maxPriorityFee.Cmp(GetBumpedFee(pending.MaxPriorityFee, MinFeeBumpPercent)) < 0 {
+		logger.Printlnf("Transaction %s is still pending, but its fees can't be raised any further without exceeding the max fee of %.6f Gwei or the priority fee of %.6f Gwei.", pending.Hash.Hex(), eth.WeiToGwei(maxFeeCap), eth.WeiToGwei(maxPriorityFeeCap))
+		return t.postpone(pending)
+	}
+
+	logger.Printlnf("Transaction %s has been pending for %d blocks; re-broadcasting it with a max fee of %.6f Gwei and a priority fee of %.6f Gwei...", pending.Hash.Hex(), t.bumpBlocks, eth.WeiToGwei(maxFee), eth.WeiToGwei(maxPriorityFee))
+	replacement := *pending
+	replacement.PreviousHashes = append(append([]common.Hash{}, pending.PreviousHashes...), pending.Hash)
+	replacement.MaxFee = maxFee
+	replacement.MaxPriorityFee = maxPriorityFee
+	replacement.Bumps++
+	err := t.sendReplacement(&replacement, opts)
+	if err != nil {
+		postponeErr := t.postpone(pending)
+		if postponeErr != nil {
+			logger.Printlnf("WARNING: couldn't update transaction %s in the list of pending transactions: %s", pending.Hash.Hex(), postponeErr.Error())
+		}
+		return err
+	}
+	logger.Printlnf("Replacement transaction has been submitted with hash %s.", replacement.Hash.Hex())
+	*pending = replacement
+	return nil
+
+}
+
+// Restart the wait before a pending transaction is bumped again
+func (t *Tracker) postpone(pending *PendingTransaction) error {
+	block, err := t.ec.BlockNumber(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting latest block: %w", err)
+	}
+	pending.SubmittedBlock = block
+	return t.save(pending)
+}
+
+// Sign and send a new version of a pending transaction, then save it
+func (t *Tracker) sendReplacement(pending *PendingTransaction, opts *bind.TransactOpts) error {
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	chainID, err := t.ec.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting chain ID: %w", err)
+	}
+	to := pending.To
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      pending.Nonce,
+		GasTipCap:  pending.MaxPriorityFee,
+		GasFeeCap:  pending.MaxFee,
+		Gas:        pending.GasLimit,
+		To:         &to,
+		Value:      pending.Value,
+		Data:       pending.Data,
+		AccessList: []types.AccessTuple{},
+	})
+	signedTx, err := opts.Signer(opts.From, tx)
+	if err != nil {
+		return fmt.Errorf("error signing replacement transaction: %w", err)
+	}
+	err = t.ec.SendTransaction(ctx, signedTx)
+	if err != nil {
+		return fmt.Errorf("error sending replacement transaction: %w", err)
+	}
+
+	block, err := t.ec.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting latest block: %w", err)
+	}
+	pending.Hash = signedTx.Hash()
+	pending.SubmittedBlock = block
+	pending.SubmittedTime = time.Now()
+	return t.save(pending)
+
+}
+
+// Get the receipt of whichever version of a pending transaction was included, if any
+func (t *Tracker) getReceipt(pending *PendingTransaction) (*types.Receipt, error) {
+	hashes := append([]common.Hash{pending.Hash}, pending.PreviousHashes...)
+	for _, hash := range hashes {
+		receipt, err := t.ec.TransactionReceipt(context.Background(), hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("error getting receipt for transaction %s: %w", hash.Hex(), err)
+		}
+	}
+	return nil, nil
+}
+
+// Get the tracked transaction for a sender and nonce, or nil if there isn't one
+func (t *Tracker) get(from common.Address, nonce uint64) (*PendingTransaction, error) {
+	unlock, err := t.lockStore()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	txs, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		if tx.From == from && tx.Nonce == nonce {
+			return &tx, nil
+		}
+	}
+	return nil, nil
+}
+
+// Add or update a tracked transaction
+func (t *Tracker) save(pending *PendingTransaction) error {
+	unlock, err := t.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	txs, err := t.load()
+	if err != nil {
+		return err
+	}
+	updated := false
+	for i, tx := range txs {
+		if tx.From == pending.From && tx.Nonce == pending.Nonce {
+			txs[i] = *pending
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		txs = append(txs, *pending)
+	}
+	return t.write(txs)
+}
+
+// Stop tracking a transaction
+func (t *Tracker) remove(from common.Address, nonce uint64) error {
+	unlock, err := t.lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	txs, err := t.load()
+	if err != nil {
+		return err
+	}
+	remaining := make([]PendingTransaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.From != from || tx.Nonce != nonce {
+			remaining = append(remaining, tx)
+		}
+	}
+	return t.write(remaining)
+}
+
+// Read the tracked transactions from disk
+func (t *Tracker) load() ([]PendingTransaction, error) {
+	bytes, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []PendingTransaction{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading pending transactions from [%s]: %w", t.path, err)
+	}
+	txs := []PendingTransaction{}
+	err = json.Unmarshal(bytes, &txs)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding pending transactions from [%s]: %w", t.path, err)
+	}
+	return txs, nil
+}
+
+// Write the tracked transactions to disk, sorted by nonce
+func (t *Tracker) write(txs []PendingTransaction) error {
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Nonce < txs[j].Nonce
+	})
+	bytes, err := json.Marshal(txs)
+	if err != nil {
+		return fmt.Errorf("error encoding pending transactions: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial list; each write gets its own file so concurrent writers can't clobber each other's
+	tempFile, err := os.CreateTemp(filepath.Dir(t.path), "."+filepath.Base(t.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for pending transactions: %w", err)
+	}
+	tempPath := tempFile.Name()
+	_, err = tempFile.Write(bytes)
+	if err == nil {
+		err = tempFile.Chmod(fileMode)
+	}
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("error writing pending transactions to [%s]: %w", tempPath, err)
+	}
+	err = os.Rename(tempPath, t.path)
+	if err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("error saving pending transactions to [%s]: %w", t.path, err)
+	}
+	return nil
+}
+
+// Lock the store against changes from this process and any other process sharing it, such as the API and the node daemon.
+// Returns a function that releases the lock.
+func (t *Tracker) lockStore() (func(), error) {
+	storeLock.Lock()
+	file, err := os.OpenFile(t.path+".lock", os.O_CREATE|os.O_RDWR, fileMode)
+	if err != nil {
+		storeLock.Unlock()
+		return nil, fmt.Errorf("error opening pending transactions lock file: %w", err)
+	}
+	err = lockFile(file)
+	if err != nil {
+		file.Close()
+		storeLock.Unlock()
+		return nil, fmt.Errorf("error locking pending transactions: %w", err)
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+		storeLock.Unlock()
+	}, nil
+}
+
+// Wait for a transaction without tracking it, until the deadline
+func waitForReceipt(ec rocketpool.ExecutionClient, hash common.Hash, deadline time.Time) (*types.Receipt, error) {
+	for {
+		receipt, err := ec.TransactionReceipt(context.Background(), hash)
+		if err == nil {
+			if receipt.Status == types.ReceiptStatusFailed {
+				return receipt, fmt.Errorf("Transaction failed with status 0")
+			}
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("error getting receipt for transaction %s: %w", hash.Hex(), err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s hasn't been included after %s", ErrTransactionPending, hash.Hex(), waitTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func bigMax(a *big.Int, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,485 @@
+package txtracker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/rocket-pool/smartnode/bindings/rocketpool"
+
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+var testChainID = big.NewInt(1337)
+
+// A minimal Execution client with a mempool, where the chain advances a block every time the latest block is requested.
+// Transactions are only included when the include function allows it.
+type mockExecutionClient struct {
+	rocketpool.ExecutionClient
+
+	lock     sync.Mutex
+	block    uint64
+	txs      map[common.Hash]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	nonces   map[common.Address]uint64
+	sent     []*types.Transaction
+	include  func(tx *types.Transaction) bool
+}
+
+func newMockExecutionClient() *mockExecutionClient {
+	return &mockExecutionClient{
+		block:    100,
+		txs:      map[common.Hash]*types.Transaction{},
+		receipts: map[common.Hash]*types.Receipt{},
+		nonces:   map[common.Address]uint64{},
+		include:  func(tx *types.Transaction) bool { return false },
+	}
+}
+
+func (m *mockExecutionClient) BlockNumber(ctx context.Context) (uint64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.block++
+	return m.block, nil
+}
+
+func (m *mockExecutionClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return testChainID, nil
+}
+
+func (m *mockExecutionClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.nonces[account], nil
+}
+
+func (m *mockExecutionClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tx, exists := m.txs[hash]
+	if !exists {
+		return nil, false, ethereum.NotFound
+	}
+	_, included := m.receipts[hash]
+	return tx, !included, nil
+}
+
+func (m *mockExecutionClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	receipt, exists := m.receipts[hash]
+	if !exists {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func (m *mockExecutionClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return err
+	}
+	m.txs[tx.Hash()] = tx
+	m.sent = append(m.sent, tx)
+	if m.include(tx) {
+		m.receipts[tx.Hash()] = &types.Receipt{
+			TxHash:      tx.Hash(),
+			Status:      types.ReceiptStatusSuccessful,
+			BlockNumber: new(big.Int).SetUint64(m.block),
+		}
+		m.nonces[from] = tx.Nonce() + 1
+	}
+	return nil
+}
+
+// Create a tracker backed by a mock client, and a key and transactor to send transactions with
+func newTestTracker(t *testing.T, bumpBlocks uint64) (*Tracker, *mockExecutionClient, *ecdsa.PrivateKey, *bind.TransactOpts) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := newMockExecutionClient()
+	tracker := &Tracker{
+		path:       filepath.Join(t.TempDir(), "pending-transactions.json"),
+		ec:         ec,
+		bumpBlocks: bumpBlocks,
+	}
+	pollInterval = time.Millisecond
+	return tracker, ec, key, opts
+}
+
+// Sign and send a transaction through the mock client
+func sendTestTransaction(t *testing.T, ec *mockExecutionClient, key *ecdsa.PrivateKey, nonce uint64, maxFee int64, maxPriorityFee int64) *types.Transaction {
+	to := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(testChainID), &types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(maxPriorityFee),
+		GasFeeCap: big.NewInt(maxFee),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{0x01, 0x02},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ec.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestGetBumpedFee(t *testing.T) {
+	tests := []struct {
+		fee      int64
+		percent  int64
+		expected int64
+	}{
+		{fee: 100, percent: 13, expected: 113},
+		{fee: 1000000000, percent: 13, expected: 1130000000},
+		{fee: 7, percent: 10, expected: 8}, // Rounded up so the replacement is never underpriced
+		{fee: 0, percent: 13, expected: 0},
+	}
+	for _, test := range tests {
+		bumped := GetBumpedFee(big.NewInt(test.fee), test.percent)
+		if bumped.Int64() != test.expected {
+			t.Errorf("bumping %d by %d%%: expected %d, got %s", test.fee, test.percent, test.expected, bumped.String())
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	tracker := &Tracker{
+		path: filepath.Join(t.TempDir(), "pending-transactions.json"),
+	}
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	// Nothing is tracked yet
+	pending, err := tracker.get(from, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != nil {
+		t.Fatalf("expected no transaction, got %v", pending)
+	}
+
+	// Save a few, out of order
+	for _, tx := range []PendingTransaction{
+		{Nonce: 6, From: from, Hash: common.HexToHash("0x06"), MaxFee: big.NewInt(20), MaxPriorityFee: big.NewInt(2)},
+		{Nonce: 5, From: from, Hash: common.HexToHash("0x05"), MaxFee: big.NewInt(10), MaxPriorityFee: big.NewInt(1)},
+		{Nonce: 5, From: other, Hash: common.HexToHash("0x55"), MaxFee: big.NewInt(30), MaxPriorityFee: big.NewInt(3)},
+	} {
+		if err := tracker.save(&tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	txs, err := tracker.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 || txs[len(txs)-1].Nonce != 6 {
+		t.Fatalf("expected 3 transactions sorted by nonce, got %v", txs)
+	}
+
+	// Replace one
+	replacement := PendingTransaction{Nonce: 5, From: from, Hash: common.HexToHash("0x0505"), PreviousHashes: []common.Hash{common.HexToHash("0x05")}, MaxFee: big.NewInt(12), MaxPriorityFee: big.NewInt(2), Bumps: 1}
+	if err := tracker.save(&replacement); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = tracker.get(from, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending == nil || pending.Hash != replacement.Hash || pending.MaxFee.Int64() != 12 || len(pending.PreviousHashes) != 1 {
+		t.Fatalf("expected the replacement, got %v", pending)
+	}
+
+	// The same nonce from a different sender is kept separately
+	pending, err = tracker.get(other, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending == nil || pending.Hash != common.HexToHash("0x55") {
+		t.Fatalf("expected the other sender's transaction, got %v", pending)
+	}
+
+	// Remove one
+	if err := tracker.remove(from, 5); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = tracker.get(from, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != nil {
+		t.Fatalf("expected the transaction to be removed, got %v", pending)
+	}
+	txs, err = tracker.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 remaining transactions, got %d", len(txs))
+	}
+}
+
+func TestWaitForTransactionBumpsStuckTransaction(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 3)
+	logger := log.NewColorLogger(color.FgWhite)
+
+	// The original is stuck, but the first replacement gets included
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	ec.include = func(tx *types.Transaction) bool {
+		return tx.Hash() != original.Hash()
+	}
+
+	receipt, err := tracker.WaitForTransaction(original.Hash(), opts, big.NewInt(2000), big.NewInt(200), &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.sent) != 2 {
+		t.Fatalf("expected one replacement to be sent, got %d transactions", len(ec.sent))
+	}
+	replacement := ec.sent[1]
+	if receipt.TxHash != replacement.Hash() {
+		t.Fatalf("expected the replacement's receipt, got %s", receipt.TxHash.Hex())
+	}
+
+	// The replacement must be the same transaction with fees raised enough to replace the original
+	if replacement.Nonce() != original.Nonce() || *replacement.To() != *original.To() || replacement.Value().Cmp(original.Value()) != 0 ||
+		string(replacement.Data()) != string(original.Data()) || replacement.Gas() != original.Gas() {
+		t.Fatal("replacement doesn't match the original transaction")
+	}
+	if replacement.GasFeeCap().Int64() != 1130 || replacement.GasTipCap().Int64() != 113 {
+		t.Fatalf("expected fees of 1130 / 113, got %s / %s", replacement.GasFeeCap().String(), replacement.GasTipCap().String())
+	}
+
+	// It isn't pending anymore
+	pending, err := tracker.get(opts.From, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != nil {
+		t.Fatalf("expected the transaction to stop being tracked, got %v", pending)
+	}
+}
+
+func TestBumpRespectsMaxFeeCap(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 3)
+	logger := log.NewColorLogger(color.FgWhite)
+
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	pending, err := tracker.Track(original.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cap below the minimum replacement fee means it can't be re-broadcast
+	if err := tracker.bump(pending, opts, big.NewInt(1050), big.NewInt(200), &logger); err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.sent) != 1 || pending.Hash != original.Hash() {
+		t.Fatal("a replacement was sent even though the cap doesn't allow it")
+	}
+
+	// A cap between the minimum and the usual bump limits the max fee
+	if err := tracker.bump(pending, opts, big.NewInt(1120), big.NewInt(200), &logger); err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.sent) != 2 {
+		t.Fatal("expected a replacement to be sent")
+	}
+	replacement := ec.sent[1]
+	if replacement.GasFeeCap().Int64() != 1120 || replacement.GasTipCap().Int64() != 113 {
+		t.Fatalf("expected fees of 1120 / 113, got %s / %s", replacement.GasFeeCap().String(), replacement.GasTipCap().String())
+	}
+	stored, err := tracker.get(opts.From, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.Hash != replacement.Hash() || stored.Bumps != 1 || len(stored.PreviousHashes) != 1 || stored.PreviousHashes[0] != original.Hash() {
+		t.Fatalf("expected the replacement to be tracked with the original's hash, got %v", stored)
+	}
+}
+
+func TestBumpRespectsMaxPriorityFeeCap(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 3)
+	logger := log.NewColorLogger(color.FgWhite)
+
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	pending, err := tracker.Track(original.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The priority fee can't be raised past its cap, even when the max fee allows it
+	if err := tracker.bump(pending, opts, big.NewInt(2000), big.NewInt(111), &logger); err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.sent) != 2 {
+		t.Fatal("expected a replacement to be sent")
+	}
+	if ec.sent[1].GasFeeCap().Int64() != 1130 || ec.sent[1].GasTipCap().Int64() != 111 {
+		t.Fatalf("expected fees of 1130 / 111, got %s / %s", ec.sent[1].GasFeeCap().String(), ec.sent[1].GasTipCap().String())
+	}
+
+	// Once it's at the cap, it can't be replaced anymore
+	if err := tracker.bump(pending, opts, big.NewInt(2000), big.NewInt(111), &logger); err != nil {
+		t.Fatal(err)
+	}
+	if len(ec.sent) != 2 {
+		t.Fatal("a replacement was sent even though the priority fee cap doesn't allow it")
+	}
+}
+
+// A client that rejects every transaction
+type rejectingExecutionClient struct {
+	*mockExecutionClient
+}
+
+func (m *rejectingExecutionClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return errors.New("replacement transaction underpriced")
+}
+
+func TestFailedBumpWaitsForNextAttempt(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 3)
+	logger := log.NewColorLogger(color.FgWhite)
+
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	pending, err := tracker.Track(original.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	submittedBlock := pending.SubmittedBlock
+
+	// A failed replacement shouldn't be retried until another set of blocks has passed
+	tracker.ec = &rejectingExecutionClient{ec}
+	if err := tracker.bump(pending, opts, big.NewInt(2000), big.NewInt(200), &logger); err == nil {
+		t.Fatal("expected the replacement to fail")
+	}
+	stored, err := tracker.get(opts.From, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.Hash != original.Hash() || stored.SubmittedBlock <= submittedBlock {
+		t.Fatalf("expected the original to stay tracked with a later submitted block, got %v", stored)
+	}
+}
+
+func TestWaitForTransactionTimesOut(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 0)
+	logger := log.NewColorLogger(color.FgWhite)
+	waitTimeout = 20 * time.Millisecond
+	defer func() { waitTimeout = 30 * time.Minute }()
+
+	// A transaction that's never included shouldn't block forever, and should stay tracked
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	_, err := tracker.WaitForTransaction(original.Hash(), opts, nil, nil, &logger)
+	if !errors.Is(err, ErrTransactionPending) {
+		t.Fatalf("expected a pending error, got %v", err)
+	}
+	stored, err := tracker.get(opts.From, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil {
+		t.Fatal("expected the transaction to still be tracked")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	tracker, ec, key, opts := newTestTracker(t, 0)
+	logger := log.NewColorLogger(color.FgWhite)
+
+	original := sendTestTransaction(t, ec, key, 0, 1000, 100)
+	if _, err := tracker.Track(original.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	ec.include = func(tx *types.Transaction) bool {
+		return tx.Hash() != original.Hash()
+	}
+
+	// The cancellation must outbid the tracked transaction even if lower fees were requested
+	opts.GasFeeCap = big.NewInt(500)
+	opts.GasTipCap = big.NewInt(50)
+	hash, err := tracker.Cancel(0, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancellation := ec.sent[len(ec.sent)-1]
+	if cancellation.Hash() != hash {
+		t.Fatal("the returned hash isn't the cancellation that was sent")
+	}
+	if *cancellation.To() != opts.From || cancellation.Value().Sign() != 0 || len(cancellation.Data()) != 0 || cancellation.Gas() != CancelGasLimit {
+		t.Fatal("expected a 0-value transfer to the sender")
+	}
+	if cancellation.Nonce() != 0 || cancellation.GasFeeCap().Int64() != 1130 || cancellation.GasTipCap().Int64() != 113 {
+		t.Fatalf("expected nonce 0 with fees of 1130 / 113, got %d with %s / %s", cancellation.Nonce(), cancellation.GasFeeCap().String(), cancellation.GasTipCap().String())
+	}
+
+	// Waiting on the original should report that it was cancelled
+	_, err = tracker.WaitForTransaction(original.Hash(), opts, nil, nil, &logger)
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+
+	// The nonce has been used now, so it can't be cancelled again
+	if _, err := tracker.Cancel(0, opts); err == nil {
+		t.Fatal("expected cancelling a used nonce to fail")
+	}
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending-transactions.json")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// Every tracker sharing the file should see the others' changes
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(nonce uint64) {
+			defer wg.Done()
+			tracker := &Tracker{path: path}
+			if err := tracker.save(&PendingTransaction{Nonce: nonce, From: from, MaxFee: big.NewInt(1), MaxPriorityFee: big.NewInt(1)}); err != nil {
+				t.Error(err)
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	tracker := &Tracker{path: path}
+	txs, err := tracker.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 20 {
+		t.Fatalf("expected 20 transactions, got %d", len(txs))
+	}
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), ".*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("temporary files were left behind: %v", matches)
+	}
+}
@@ -0,0 +1,265 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrExternalSignerReadOnly = errors.New("The node account is held by an external signer, which is only used for transactions made with the 'rocketpool' CLI. Please make this transaction manually.")
+var ErrExternalSignerKey = errors.New("The node account is held by an external signer, so its private key is not available.")
+
+// A wallet that holds the node account in an external signer that speaks Clef's external API (such as Clef backed by a hardware wallet).
+// Validator keys are still handled by the underlying wallet.
+type externalWallet struct {
+	Wallet
+	url      string
+	chainID  *big.Int
+	readOnly bool
+
+	// The connection to the signer, which is opened on first use and shared by every request
+	signer     *external.ExternalSigner
+	signerLock sync.Mutex
+
+	// The node account, which is loaded from the signer if it isn't provided
+	account     common.Address
+	accountLock sync.Mutex
+
+	// Desired gas price & limit from config
+	maxFee         *big.Int
+	maxPriorityFee *big.Int
+	gasLimit       uint64
+}
+
+// Create a new external signer wallet wrapping the provided wallet.
+// If the account is the zero address, the signer's first account will be used.
+// Read-only wallets report the node account but refuse to sign anything, for processes that run unattended.
+func NewExternalWallet(w Wallet, url string, account common.Address, readOnly bool, chainId uint, maxFee *big.Int, maxPriorityFee *big.Int, gasLimit uint64) Wallet {
+	return &externalWallet{
+		Wallet:         w,
+		url:            url,
+		chainID:        big.NewInt(int64(chainId)),
+		readOnly:       readOnly,
+		account:        account,
+		maxFee:         maxFee,
+		maxPriorityFee: maxPriorityFee,
+		gasLimit:       gasLimit,
+	}
+}
+
+// The node account is always available from the signer
+func (w *externalWallet) IsInitialized() bool {
+	return true
+}
+
+// Check that the signer can be reached and has the node account
+func (w *externalWallet) GetInitialized() (bool, error) {
+	if _, err := w.GetNodeAccount(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Gets the address of the node account on the signer
+func (w *externalWallet) GetAddress() (common.Address, error) {
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// Get the node account
+func (w *externalWallet) GetNodeAccount() (accounts.Account, error) {
+	w.accountLock.Lock()
+	defer w.accountLock.Unlock()
+
+	if w.account == (common.Address{}) {
+		signer, err := w.getSigner()
+		if err != nil {
+			return accounts.Account{}, err
+		}
+
+		// The signer only logs errors when listing accounts, so a failure looks the same as an empty list
+		signerAccounts := signer.Accounts()
+		if len(signerAccounts) == 0 {
+			return accounts.Account{}, fmt.Errorf("The external signer at %s didn't return any accounts; make sure it has one and that listing accounts was approved.", w.url)
+		}
+		w.account = signerAccounts[0].Address
+	}
+
+	return accounts.Account{
+		Address: w.account,
+		URL: accounts.URL{
+			Scheme: "extapi",
+			Path:   w.url,
+		},
+	}, nil
+}
+
+// Get a transactor for the node account that has the external signer sign each transaction
+func (w *externalWallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
+	if w.readOnly {
+		return nil, ErrExternalSignerReadOnly
+	}
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create & return transactor
+	transactor := &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return w.signTransaction(tx)
+		},
+		GasFeeCap: w.maxFee,
+		GasTipCap: w.maxPriorityFee,
+		GasLimit:  w.gasLimit,
+		Context:   context.Background(),
+	}
+	return transactor, nil
+}
+
+// The node account's private key never leaves the signer
+func (w *externalWallet) GetNodePrivateKeyBytes() ([]byte, error) {
+	return nil, ErrExternalSignerKey
+}
+
+// Gets the wallet's chain ID
+func (w *externalWallet) GetChainID() *big.Int {
+	copy := big.NewInt(0).Set(w.chainID)
+	return copy
+}
+
+// Sign a serialized transaction with the external signer
+func (w *externalWallet) Sign(serializedTx []byte) ([]byte, error) {
+	if w.readOnly {
+		return nil, ErrExternalSignerReadOnly
+	}
+	tx := types.Transaction{}
+	err := tx.UnmarshalBinary(serializedTx)
+	if err != nil {
+		return nil, fmt.Errorf("Error unmarshalling TX: %w", err)
+	}
+
+	signedTx, err := w.signTransaction(&tx)
+	if err != nil {
+		return nil, err
+	}
+	signedData, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling signed TX to binary: %w", err)
+	}
+	return signedData, nil
+}
+
+// Sign an arbitrary message with the external signer
+func (w *externalWallet) SignMessage(message string) ([]byte, error) {
+	if w.readOnly {
+		return nil, ErrExternalSignerReadOnly
+	}
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := w.getSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	// The signer applies the EIP-191 prefix and returns a signature with a 'v' of 0 or 1
+	signature, err := signer.SignText(account, []byte(message))
+	if err != nil {
+		return nil, fmt.Errorf("Error signing message with the external signer: %w", err)
+	}
+
+	// Use a 'v' of 27 or 28, just like the node wallet does
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// Masquerading takes priority over the external signer, so this wallet is never masquerading
+func (w *externalWallet) IsNodeMasquerading() bool {
+	return false
+}
+
+// Have the external signer sign a transaction, and make sure it signed the one that was requested
+func (w *externalWallet) signTransaction(tx *types.Transaction) (*types.Transaction, error) {
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("The external signer only supports EIP-1559 transactions.")
+	}
+
+	signer, err := w.getSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request; this blocks until the user approves or rejects it
+	signedTx, err := signer.SignTx(account, tx, w.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("Error signing TX with the external signer: %w", err)
+	}
+
+	// Check the result
+	if signedTx == nil {
+		return nil, fmt.Errorf("The external signer didn't return a signed TX.")
+	}
+	if signedTx.ChainId().Cmp(w.chainID) != 0 ||
+		signedTx.Nonce() != tx.Nonce() ||
+		signedTx.Gas() != tx.Gas() ||
+		signedTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 ||
+		signedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0 ||
+		signedTx.Value().Cmp(tx.Value()) != 0 ||
+		!equalAddresses(signedTx.To(), tx.To()) ||
+		!bytes.Equal(signedTx.Data(), tx.Data()) {
+		return nil, fmt.Errorf("The external signer returned a different transaction than the one it was asked to sign.")
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(w.chainID), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("Error recovering the sender of the TX from the external signer: %w", err)
+	}
+	if from != account.Address {
+		return nil, fmt.Errorf("The external signer signed the TX with %s instead of the node account %s.", from.Hex(), account.Address.Hex())
+	}
+	return signedTx, nil
+}
+
+// Get the connection to the external signer, opening it if it isn't open yet
+func (w *externalWallet) getSigner() (*external.ExternalSigner, error) {
+	w.signerLock.Lock()
+	defer w.signerLock.Unlock()
+
+	if w.signer == nil {
+		signer, err := external.NewExternalSigner(w.url)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to the external signer at %s: %w", w.url, err)
+		}
+		w.signer = signer
+	}
+	return w.signer, nil
+}
+
+func equalAddresses(a *common.Address, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,188 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// A stub of Clef's external API holding a single key
+type stubSigner struct {
+	key      *ecdsa.PrivateKey
+	reject   bool
+	tamper   bool
+	chainID  *big.Int
+	versions int
+}
+
+// The result of an account_signTransaction request
+type stubSignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (s *stubSigner) Version() string {
+	s.versions++
+	return "6.0.0"
+}
+
+func (s *stubSigner) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *stubSigner) SignTransaction(args apitypes.SendTxArgs) (*stubSignTxResult, error) {
+	if s.reject {
+		return nil, errors.New("request denied")
+	}
+	if args.ChainID == nil || args.ChainID.ToInt().Cmp(s.chainID) != 0 {
+		return nil, errors.New("wrong chain ID")
+	}
+	if s.tamper {
+		args.Value = hexutil.Big(*big.NewInt(1))
+	}
+	signedTx, err := types.SignTx(args.ToTransaction(), types.NewLondonSigner(s.chainID), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &stubSignTxResult{Raw: raw, Tx: signedTx}, nil
+}
+
+func (s *stubSigner) SignData(mimeType string, address common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if mimeType != accounts.MimetypeTextPlain {
+		return nil, fmt.Errorf("unsupported content type %s", mimeType)
+	}
+	signature, err := crypto.Sign(accounts.TextHash(data), s.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+func newStubSigner(t *testing.T) (*stubSigner, string) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &stubSigner{key: key, chainID: big.NewInt(17000)}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", signer); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return signer, httpServer.URL
+}
+
+func TestExternalWalletTransactor(t *testing.T) {
+	signer, url := newStubSigner(t)
+	address := signer.List()[0]
+	w := NewExternalWallet(nil, url, common.Address{}, false, 17000, nil, nil, 0)
+
+	// The account should come from the signer
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Address != address {
+		t.Fatalf("expected node account %s, got %s", address.Hex(), account.Address.Hex())
+	}
+
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x1234")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     5,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(20e9),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(1e18),
+		Data:      []byte{0x01, 0x02},
+	})
+	signedTx, err := opts.Signer(address, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := types.Sender(types.NewLondonSigner(big.NewInt(17000)), signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != address {
+		t.Fatalf("expected sender %s, got %s", address.Hex(), from.Hex())
+	}
+
+	// Rejected and tampered requests should fail
+	signer.reject = true
+	if _, err := opts.Signer(address, tx); err == nil {
+		t.Fatal("expected a rejected request to fail")
+	}
+	signer.reject = false
+	signer.tamper = true
+	if _, err := opts.Signer(address, tx); err == nil {
+		t.Fatal("expected a tampered transaction to be caught")
+	}
+
+	// Every request should have gone through the same connection
+	if signer.versions != 1 {
+		t.Fatalf("expected one connection to the signer, but it was opened %d times", signer.versions)
+	}
+}
+
+func TestExternalWalletSignMessage(t *testing.T) {
+	signer, url := newStubSigner(t)
+	w := NewExternalWallet(nil, url, common.Address{}, false, 17000, nil, nil, 0)
+
+	message := "hello"
+	signature, err := w.SignMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[crypto.RecoveryIDOffset] -= 27
+	pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pubkey) != signer.List()[0] {
+		t.Fatal("message was signed by the wrong key")
+	}
+}
+
+func TestExternalWalletReadOnly(t *testing.T) {
+	signer, url := newStubSigner(t)
+	w := NewExternalWallet(nil, url, common.Address{}, true, 17000, nil, nil, 0)
+
+	account, err := w.GetNodeAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Address != signer.List()[0] {
+		t.Fatal("read-only wallet reported the wrong node account")
+	}
+	if _, err := w.GetNodeAccountTransactor(); !errors.Is(err, ErrExternalSignerReadOnly) {
+		t.Fatalf("expected a read-only error, got %v", err)
+	}
+	if _, err := w.SignMessage("hello"); !errors.Is(err, ErrExternalSignerReadOnly) {
+		t.Fatalf("expected a read-only error, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package keymanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
+	keystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
+	hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+// Config
+const (
+	KeystoreDir   = "keymanager"
+	SecretsDir    = "passwords"
+	ValidatorsDir = "keys"
+	DirMode       = 0770
+	FileMode      = 0640
+)
+
+// Keymanager API keystore.
+// Keys are imported into the running validator client through its Keymanager API, so it can use them without a restart; they're still stored in its keystore folders too.
+// A copy of each key is kept in a folder that no validator client reads, so it can be imported again if the validator client loses it or was offline when it was stored.
+// If Web3Signer holds the keys, they're registered with the validator client as remote keys instead and no copy is kept.
+type Keystore struct {
+	keystorePath  string
+	client        *keymanager.Client
+	web3SignerUrl string
+	encryptor     *eth2ks.Encryptor
+}
+
+// Encrypted validator key store
+type validatorKey struct {
+	Crypto  map[string]interface{} `json:"crypto"`
+	Version uint                   `json:"version"`
+	UUID    uuid.UUID              `json:"uuid"`
+	Path    string                 `json:"path"`
+	Pubkey  types.ValidatorPubkey  `json:"pubkey"`
+}
+
+// Create new Keymanager API keystore
+func NewKeystore(keystorePath string, client *keymanager.Client, web3SignerUrl string) *Keystore {
+	return &Keystore{
+		keystorePath:  keystorePath,
+		client:        client,
+		web3SignerUrl: web3SignerUrl,
+		encryptor:     eth2ks.New(eth2ks.WithCipher("scrypt")),
+	}
+}
+
+// Get the keystore directory
+func (ks *Keystore) GetKeystoreDir() string {
+	return filepath.Join(ks.keystorePath, KeystoreDir)
+}
+
+// Keys stored here are imported into the running validator client
+func (ks *Keystore) ImportsValidatorKeys() {}
+
+// Store a validator key and import it into the validator client
+func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error {
+
+	// Get validator pubkey
+	pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+
+	// Keys held by Web3Signer only need to be registered
+	if ks.web3SignerUrl != "" {
+		return ks.importRemoteKey(pubkey)
+	}
+
+	// Create a new password
+	password, err := keystore.GenerateRandomPassword()
+	if err != nil {
+		return fmt.Errorf("Could not generate random password: %w", err)
+	}
+
+	// Encrypt key
+	encryptedKey, err := ks.encryptor.Encrypt(key.Marshal(), password)
+	if err != nil {
+		return fmt.Errorf("Could not encrypt validator key: %w", err)
+	}
+
+	// Create key store
+	keyStore := validatorKey{
+		Crypto:  encryptedKey,
+		Version: ks.encryptor.Version(),
+		UUID:    uuid.New(),
+		Path:    derivationPath,
+		Pubkey:  pubkey,
+	}
+
+	// Encode key store
+	keyStoreBytes, err := json.Marshal(keyStore)
+	if err != nil {
+		return fmt.Errorf("Could not encode validator key: %w", err)
+	}
+
+	// Get secret file path
+	secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex())+".txt")
+
+	// Create secrets dir
+	if err := os.MkdirAll(filepath.Dir(secretFilePath), DirMode); err != nil {
+		return fmt.Errorf("Could not create validator secrets folder: %w", err)
+	}
+
+	// Write secret to disk
+	if err := os.WriteFile(secretFilePath, []byte(password), FileMode); err != nil {
+		return fmt.Errorf("Could not write validator secret to disk: %w", err)
+	}
+
+	// Get key file path
+	keyFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json")
+
+	// Create key dir
+	if err := os.MkdirAll(filepath.Dir(keyFilePath), DirMode); err != nil {
+		return fmt.Errorf("Could not create validator key folder: %w", err)
+	}
+
+	// Write key store to disk
+	if err := os.WriteFile(keyFilePath, keyStoreBytes, FileMode); err != nil {
+		return fmt.Errorf("Could not write validator key to disk: %w", err)
+	}
+
+	// Import it into the validator client
+	return ks.importKeystore(pubkey, keyStoreBytes, password)
+
+}
+
+// Load a private key
+func (ks *Keystore) LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+
+	keyStore, password, err := ks.loadKeystore(pubkey)
+	if err != nil || keyStore == nil {
+		return nil, err
+	}
+
+	// Decrypt key
+	decryptedKey, err := ks.encryptor.Decrypt(keyStore.Crypto, password)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt keystore for pubkey %s: %w", pubkey.Hex(), err)
+	}
+	privateKey, err := eth2types.BLSPrivateKeyFromBytes(decryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error recreating private key for validator %s: %w", keyStore.Pubkey.Hex(), err)
+	}
+
+	// Verify the private key matches the public key
+	reconstructedPubkey := types.BytesToValidatorPubkey(privateKey.PublicKey().Marshal())
+	if reconstructedPubkey != pubkey {
+		return nil, fmt.Errorf("private keystore file for validator %s is actually for validator %s", pubkey.Hex(), reconstructedPubkey.Hex())
+	}
+
+	return privateKey, nil
+
+}
+
+// Import a key that was stored previously into the validator client again.
+// Returns false if there is no stored copy of the key to import.
+func (ks *Keystore) ReimportValidatorKey(pubkey types.ValidatorPubkey) (bool, error) {
+
+	if ks.web3SignerUrl != "" {
+		return true, ks.importRemoteKey(pubkey)
+	}
+
+	keyStore, password, err := ks.loadKeystore(pubkey)
+	if err != nil || keyStore == nil {
+		return false, err
+	}
+	keyStoreBytes, err := json.Marshal(keyStore)
+	if err != nil {
+		return false, fmt.Errorf("Could not encode validator key: %w", err)
+	}
+	return true, ks.importKeystore(pubkey, keyStoreBytes, password)
+
+}
+
+// Read the stored copy of a key and its password, returning nil if there isn't one
+func (ks *Keystore) loadKeystore(pubkey types.ValidatorPubkey) (*validatorKey, string, error) {
+
+	// Read the key file
+	keyFilePath := filepath.Join(ks.keystorePath, KeystoreDir, ValidatorsDir, hexutil.AddPrefix(pubkey.Hex())+".json")
+	bytes, err := os.ReadFile(keyFilePath)
+	if os.IsNotExist(err) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", fmt.Errorf("couldn't read the Keymanager keystore for pubkey %s: %w", pubkey.Hex(), err)
+	}
+
+	// Unmarshal the keystore
+	var keyStore validatorKey
+	err = json.Unmarshal(bytes, &keyStore)
+	if err != nil {
+		return nil, "", fmt.Errorf("error deserializing Keymanager keystore for pubkey %s: %w", pubkey.Hex(), err)
+	}
+
+	// Read the secret
+	secretFilePath := filepath.Join(ks.keystorePath, KeystoreDir, SecretsDir, hexutil.AddPrefix(pubkey.Hex())+".txt")
+	bytes, err = os.ReadFile(secretFilePath)
+	if os.IsNotExist(err) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", fmt.Errorf("couldn't read the Keymanager secret for pubkey %s: %w", pubkey.Hex(), err)
+	}
+
+	return &keyStore, string(bytes), nil
+
+}
+
+// Import an encrypted key into the validator client
+func (ks *Keystore) importKeystore(pubkey types.ValidatorPubkey, keyStoreBytes []byte, password string) error {
+	results, err := ks.client.ImportKeystores([]string{string(keyStoreBytes)}, []string{password}, "")
+	if err != nil {
+		return fmt.Errorf("Could not import validator key %s into the validator client: %w", pubkey.Hex(), err)
+	}
+	return checkImportResults(pubkey, results)
+}
+
+// Register a key held by Web3Signer with the validator client
+func (ks *Keystore) importRemoteKey(pubkey types.ValidatorPubkey) error {
+	results, err := ks.client.ImportRemoteKeys([]types.ValidatorPubkey{pubkey}, ks.web3SignerUrl)
+	if err != nil {
+		return fmt.Errorf("Could not register validator key %s with the validator client: %w", pubkey.Hex(), err)
+	}
+	return checkImportResults(pubkey, results)
+}
+
+// Make sure a key was either imported or already present
+func checkImportResults(pubkey types.ValidatorPubkey, results []keymanager.KeyStatusResult) error {
+	if len(results) != 1 {
+		return fmt.Errorf("Could not import validator key %s into the validator client: expected 1 result but got %d", pubkey.Hex(), len(results))
+	}
+	switch results[0].Status {
+	case keymanager.KeyStatus_Imported, keymanager.KeyStatus_Duplicate:
+		return nil
+	default:
+		return fmt.Errorf("Could not import validator key %s into the validator client: %s (%s)", pubkey.Hex(), results[0].Status, results[0].Message)
+	}
+}
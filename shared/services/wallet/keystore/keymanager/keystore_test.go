@@ -0,0 +1,280 @@
+package keymanager
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
+)
+
+const testToken = "api-token"
+const testSlashingProtection = `{"metadata":{"interchange_format_version":"5"},"data":[]}`
+
+// A minimal validator client that implements the Keymanager keystore and remote key import routes
+type mockValidatorClient struct {
+	lock       sync.Mutex
+	keys       map[types.ValidatorPubkey][]byte
+	remoteKeys map[types.ValidatorPubkey]string
+}
+
+func newMockValidatorClient() *mockValidatorClient {
+	return &mockValidatorClient{
+		keys:       map[types.ValidatorPubkey][]byte{},
+		remoteKeys: map[types.ValidatorPubkey]string{},
+	}
+}
+
+func (m *mockValidatorClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		m.deleteKeys(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := keymanager.KeyStatusResponse{}
+	switch r.URL.Path {
+	case keymanager.RequestKeystoresPath:
+		var request keymanager.ImportKeystoresRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		encryptor := eth2ks.New()
+		for i, keystoreString := range request.Keystores {
+			var keystore validatorKey
+			if err := json.Unmarshal([]byte(keystoreString), &keystore); err != nil {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Error, Message: err.Error()})
+				continue
+			}
+			if _, exists := m.keys[keystore.Pubkey]; exists {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Duplicate})
+				continue
+			}
+			secret, err := encryptor.Decrypt(keystore.Crypto, request.Passwords[i])
+			if err != nil {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Error, Message: err.Error()})
+				continue
+			}
+			m.keys[keystore.Pubkey] = secret
+			response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Imported})
+		}
+
+	case keymanager.RequestRemoteKeysPath:
+		var request keymanager.ImportRemoteKeysRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, remoteKey := range request.RemoteKeys {
+			pubkey, err := keymanager.ParsePubkey(remoteKey.Pubkey)
+			if err != nil {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Error, Message: err.Error()})
+				continue
+			}
+			m.remoteKeys[pubkey] = remoteKey.Url
+			response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Imported})
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// Handle the keystore and remote key delete routes
+func (m *mockValidatorClient) deleteKeys(w http.ResponseWriter, r *http.Request) {
+	var request keymanager.DeleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	results := []keymanager.DeleteKeyResult{}
+	for _, pubkeyString := range request.Pubkeys {
+		pubkey, err := keymanager.ParsePubkey(pubkeyString)
+		if err != nil {
+			results = append(results, keymanager.DeleteKeyResult{Status: keymanager.DeleteKeyStatus_Error, Message: err.Error()})
+			continue
+		}
+		status := keymanager.DeleteKeyStatus_NotFound
+		switch r.URL.Path {
+		case keymanager.RequestKeystoresPath:
+			if _, exists := m.keys[pubkey]; exists {
+				delete(m.keys, pubkey)
+				status = keymanager.DeleteKeyStatus_Deleted
+			}
+		case keymanager.RequestRemoteKeysPath:
+			if _, exists := m.remoteKeys[pubkey]; exists {
+				delete(m.remoteKeys, pubkey)
+				status = keymanager.DeleteKeyStatus_Deleted
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		results = append(results, keymanager.DeleteKeyResult{Status: status})
+	}
+
+	if r.URL.Path == keymanager.RequestRemoteKeysPath {
+		_ = json.NewEncoder(w).Encode(keymanager.DeleteRemoteKeysResponse{Data: results})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(keymanager.DeleteKeystoresResponse{Data: results, SlashingProtection: testSlashingProtection})
+}
+
+func newTestKey(t *testing.T) (*eth2types.BLSPrivateKey, types.ValidatorPubkey) {
+	if err := eth2types.InitBLS(); err != nil {
+		t.Fatalf("error initializing BLS: %s", err.Error())
+	}
+	key, err := eth2types.GenerateBLSPrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %s", err.Error())
+	}
+	return key, types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+}
+
+func newTestClient(t *testing.T, url string) *keymanager.Client {
+	tokenPath := filepath.Join(t.TempDir(), "api-token.txt")
+	if err := os.WriteFile(tokenPath, []byte(testToken+"\n"), 0600); err != nil {
+		t.Fatalf("error writing token file: %s", err.Error())
+	}
+	return keymanager.NewClientFromTokenFile(url, tokenPath)
+}
+
+func TestStoreValidatorKey(t *testing.T) {
+	key, pubkey := newTestKey(t)
+	vc := newMockValidatorClient()
+	server := httptest.NewServer(vc)
+	defer server.Close()
+	ks := NewKeystore(t.TempDir(), newTestClient(t, server.URL), "")
+
+	// Storing the key should import it into the VC
+	if err := ks.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing key: %s", err.Error())
+	}
+	if !bytes.Equal(vc.keys[pubkey], key.Marshal()) {
+		t.Fatal("validator client received the wrong key")
+	}
+
+	// The stored copy should load back
+	loadedKey, err := ks.LoadValidatorKey(pubkey)
+	if err != nil {
+		t.Fatalf("error loading key: %s", err.Error())
+	}
+	if loadedKey == nil || !bytes.Equal(loadedKey.Marshal(), key.Marshal()) {
+		t.Fatal("loaded the wrong key")
+	}
+
+	// If the VC loses the key, it should be imported again from the stored copy
+	delete(vc.keys, pubkey)
+	imported, err := ks.ReimportValidatorKey(pubkey)
+	if err != nil {
+		t.Fatalf("error reimporting key: %s", err.Error())
+	}
+	if !imported || !bytes.Equal(vc.keys[pubkey], key.Marshal()) {
+		t.Fatal("key wasn't reimported")
+	}
+
+	// Keys that were never stored can't be reimported
+	_, otherPubkey := newTestKey(t)
+	imported, err = ks.ReimportValidatorKey(otherPubkey)
+	if err != nil {
+		t.Fatalf("error reimporting unknown key: %s", err.Error())
+	}
+	if imported {
+		t.Fatal("reimported a key that was never stored")
+	}
+}
+
+func TestStoreValidatorKeyWithWeb3Signer(t *testing.T) {
+	key, pubkey := newTestKey(t)
+	vc := newMockValidatorClient()
+	server := httptest.NewServer(vc)
+	defer server.Close()
+	keystorePath := t.TempDir()
+	ks := NewKeystore(keystorePath, newTestClient(t, server.URL), "http://web3signer:9000")
+
+	// The key should only be registered as a remote key
+	if err := ks.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing key: %s", err.Error())
+	}
+	if vc.remoteKeys[pubkey] != "http://web3signer:9000" {
+		t.Fatal("key wasn't registered with the signer's URL")
+	}
+	if len(vc.keys) != 0 {
+		t.Fatal("private key was imported into the validator client")
+	}
+	if _, err := os.Stat(ks.GetKeystoreDir()); !os.IsNotExist(err) {
+		t.Fatal("a local copy of a key held by Web3Signer was kept")
+	}
+}
+
+func TestStoreValidatorKeyFailsWithoutToken(t *testing.T) {
+	key, _ := newTestKey(t)
+	server := httptest.NewServer(newMockValidatorClient())
+	defer server.Close()
+	client := keymanager.NewClientFromTokenFile(server.URL, filepath.Join(t.TempDir(), "missing.txt"))
+	ks := NewKeystore(t.TempDir(), client, "")
+
+	if err := ks.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err == nil {
+		t.Fatal("storing a key succeeded without an API token")
+	}
+}
+
+func TestDeleteKeys(t *testing.T) {
+	key, pubkey := newTestKey(t)
+	_, remotePubkey := newTestKey(t)
+	vc := newMockValidatorClient()
+	vc.keys[pubkey] = key.Marshal()
+	vc.remoteKeys[remotePubkey] = "http://web3signer:9000"
+	server := httptest.NewServer(vc)
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	// Deleting a keystore should remove it and return its slashing protection data
+	results, slashingProtection, err := client.DeleteKeystores([]types.ValidatorPubkey{pubkey, remotePubkey})
+	if err != nil {
+		t.Fatalf("error deleting keystores: %s", err.Error())
+	}
+	if len(results) != 2 || results[0].Status != keymanager.DeleteKeyStatus_Deleted || results[1].Status != keymanager.DeleteKeyStatus_NotFound {
+		t.Fatalf("unexpected delete results: %v", results)
+	}
+	if slashingProtection != testSlashingProtection {
+		t.Fatal("slashing protection data wasn't returned")
+	}
+	if len(vc.keys) != 0 {
+		t.Fatal("keystore wasn't deleted from the validator client")
+	}
+
+	// Deleting a remote key should only remove its registration
+	results, err = client.DeleteRemoteKeys([]types.ValidatorPubkey{remotePubkey})
+	if err != nil {
+		t.Fatalf("error deleting remote keys: %s", err.Error())
+	}
+	if len(results) != 1 || results[0].Status != keymanager.DeleteKeyStatus_Deleted {
+		t.Fatalf("unexpected delete results: %v", results)
+	}
+	if len(vc.remoteKeys) != 0 {
+		t.Fatal("remote key wasn't deleted from the validator client")
+	}
+}
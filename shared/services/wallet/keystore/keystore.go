@@ -24,3 +24,15 @@ type Keystore interface {
 	LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error)
 	GetKeystoreDir() string
 }
+
+// Validator keystore backed by a remote signer, which can't export the private keys it holds
+type RemoteKeystore interface {
+	Keystore
+	HasValidatorKey(pubkey types.ValidatorPubkey) (bool, error)
+}
+
+// Validator keystore that imports keys into the running validator client, on top of the keystore folders it reads at startup
+type ImportingKeystore interface {
+	Keystore
+	ImportsValidatorKeys()
+}
@@ -0,0 +1,130 @@
+package web3signer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
+	keystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
+)
+
+// Config
+const (
+	KeystoreDir = "web3signer"
+)
+
+var ErrKeyNotExportable = errors.New("Web3Signer can't export the private keys it holds")
+
+// Web3Signer keystore.
+// Keys are imported into the signer through its Keymanager API; nothing but the key's public information is kept locally.
+type Keystore struct {
+	keystorePath string
+	client       *keymanager.Client
+	encryptor    *eth2ks.Encryptor
+}
+
+// Encrypted validator key store
+type validatorKey struct {
+	Crypto  map[string]interface{} `json:"crypto"`
+	Version uint                   `json:"version"`
+	UUID    uuid.UUID              `json:"uuid"`
+	Path    string                 `json:"path"`
+	Pubkey  types.ValidatorPubkey  `json:"pubkey"`
+}
+
+// Create new Web3Signer keystore
+func NewKeystore(keystorePath string, signerUrl string) *Keystore {
+	return &Keystore{
+		keystorePath: keystorePath,
+		client:       keymanager.NewClient(signerUrl, ""),
+		encryptor:    eth2ks.New(eth2ks.WithCipher("scrypt")),
+	}
+}
+
+// Get the keystore directory
+func (ks *Keystore) GetKeystoreDir() string {
+	return filepath.Join(ks.keystorePath, KeystoreDir)
+}
+
+// Store a validator key
+func (ks *Keystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error {
+
+	// Get validator pubkey
+	pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+
+	// Create a new password; the signer stores it alongside the key, so it isn't needed again afterwards
+	password, err := keystore.GenerateRandomPassword()
+	if err != nil {
+		return fmt.Errorf("Could not generate random password: %w", err)
+	}
+
+	// Encrypt key
+	encryptedKey, err := ks.encryptor.Encrypt(key.Marshal(), password)
+	if err != nil {
+		return fmt.Errorf("Could not encrypt validator key: %w", err)
+	}
+
+	// Create key store
+	keyStore := validatorKey{
+		Crypto:  encryptedKey,
+		Version: ks.encryptor.Version(),
+		UUID:    uuid.New(),
+		Path:    derivationPath,
+		Pubkey:  pubkey,
+	}
+
+	// Encode key store
+	keyStoreBytes, err := json.Marshal(keyStore)
+	if err != nil {
+		return fmt.Errorf("Could not encode validator key: %w", err)
+	}
+
+	// Import it into the signer
+	results, err := ks.client.ImportKeystores([]string{string(keyStoreBytes)}, []string{password}, "")
+	if err != nil {
+		return fmt.Errorf("Could not import validator key %s into Web3Signer: %w", pubkey.Hex(), err)
+	}
+	if len(results) != 1 {
+		return fmt.Errorf("Could not import validator key %s into Web3Signer: expected 1 result but got %d", pubkey.Hex(), len(results))
+	}
+	switch results[0].Status {
+	case keymanager.KeyStatus_Imported, keymanager.KeyStatus_Duplicate:
+	default:
+		return fmt.Errorf("Could not import validator key %s into Web3Signer: %s (%s)", pubkey.Hex(), results[0].Status, results[0].Message)
+	}
+
+	// Return
+	return nil
+
+}
+
+// Load a private key.
+// Web3Signer never exports private keys, so this always returns ErrKeyNotExportable; use HasValidatorKey to check if the signer holds a key.
+func (ks *Keystore) LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+	return nil, fmt.Errorf("couldn't load the key for validator %s: %w", pubkey.Hex(), ErrKeyNotExportable)
+}
+
+// Check if the signer holds the key for a validator
+func (ks *Keystore) HasValidatorKey(pubkey types.ValidatorPubkey) (bool, error) {
+	keystores, err := ks.client.ListKeystores()
+	if err != nil {
+		return false, fmt.Errorf("couldn't get the keys loaded into Web3Signer: %w", err)
+	}
+	for _, signerKey := range keystores {
+		signerPubkey, err := keymanager.ParsePubkey(signerKey.Pubkey)
+		if err != nil {
+			return false, fmt.Errorf("Web3Signer reported an invalid pubkey %s: %w", signerKey.Pubkey, err)
+		}
+		if signerPubkey == pubkey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
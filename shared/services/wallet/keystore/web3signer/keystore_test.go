@@ -0,0 +1,136 @@
+package web3signer
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+	eth2ks "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
+	hexutil "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+// A minimal Web3Signer that implements the Keymanager keystore routes
+type mockSigner struct {
+	lock sync.Mutex
+	keys map[types.ValidatorPubkey][]byte
+}
+
+func newMockSigner() *mockSigner {
+	return &mockSigner{
+		keys: map[types.ValidatorPubkey][]byte{},
+	}
+}
+
+func (m *mockSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if r.URL.Path != keymanager.RequestKeystoresPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		response := keymanager.ListKeystoresResponse{
+			Data: []keymanager.Keystore{},
+		}
+		for pubkey := range m.keys {
+			response.Data = append(response.Data, keymanager.Keystore{Pubkey: hexutil.AddPrefix(pubkey.Hex())})
+		}
+		_ = json.NewEncoder(w).Encode(response)
+
+	case http.MethodPost:
+		var request keymanager.ImportKeystoresRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := keymanager.KeyStatusResponse{}
+		encryptor := eth2ks.New()
+		for i, keystoreString := range request.Keystores {
+			var keystore validatorKey
+			if err := json.Unmarshal([]byte(keystoreString), &keystore); err != nil {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Error, Message: err.Error()})
+				continue
+			}
+			if _, exists := m.keys[keystore.Pubkey]; exists {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Duplicate})
+				continue
+			}
+			secret, err := encryptor.Decrypt(keystore.Crypto, request.Passwords[i])
+			if err != nil {
+				response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Error, Message: err.Error()})
+				continue
+			}
+			m.keys[keystore.Pubkey] = secret
+			response.Data = append(response.Data, keymanager.KeyStatusResult{Status: keymanager.KeyStatus_Imported})
+		}
+		_ = json.NewEncoder(w).Encode(response)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestStoreValidatorKey(t *testing.T) {
+	if err := eth2types.InitBLS(); err != nil {
+		t.Fatalf("error initializing BLS: %s", err.Error())
+	}
+	key, err := eth2types.GenerateBLSPrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %s", err.Error())
+	}
+	pubkey := types.BytesToValidatorPubkey(key.PublicKey().Marshal())
+
+	signer := newMockSigner()
+	server := httptest.NewServer(signer)
+	defer server.Close()
+	ks := NewKeystore(t.TempDir(), server.URL)
+
+	// The signer shouldn't have the key yet
+	hasKey, err := ks.HasValidatorKey(pubkey)
+	if err != nil {
+		t.Fatalf("error checking for key: %s", err.Error())
+	}
+	if hasKey {
+		t.Fatal("signer reported a key before it was stored")
+	}
+
+	// Store it
+	if err := ks.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing key: %s", err.Error())
+	}
+	if !bytes.Equal(signer.keys[pubkey], key.Marshal()) {
+		t.Fatal("signer received the wrong key")
+	}
+	hasKey, err = ks.HasValidatorKey(pubkey)
+	if err != nil {
+		t.Fatalf("error checking for key: %s", err.Error())
+	}
+	if !hasKey {
+		t.Fatal("signer didn't report the stored key")
+	}
+
+	// Storing it again should be a no-op
+	if err := ks.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing duplicate key: %s", err.Error())
+	}
+
+	// Private keys can't be exported from the signer
+	loadedKey, err := ks.LoadValidatorKey(pubkey)
+	if !errors.Is(err, ErrKeyNotExportable) {
+		t.Fatalf("expected an error saying the key can't be exported, got %v", err)
+	}
+	if loadedKey != nil {
+		t.Fatal("keystore returned a private key that should only exist in the signer")
+	}
+}
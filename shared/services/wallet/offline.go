@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+)
+
+// An unsigned (or signed) transaction that's been exported for signing on an offline machine.
+// The payload is the hex-encoded binary transaction, which is compact enough to transfer as a QR code;
+// the other fields are there so the transaction can be reviewed before it's signed.
+type OfflineTransaction struct {
+	ChainID        *big.Int        `json:"chainId"`
+	From           common.Address  `json:"from"`
+	Nonce          uint64          `json:"nonce"`
+	To             *common.Address `json:"to"`
+	Value          *big.Int        `json:"value"`
+	Data           hexutil.Bytes   `json:"data"`
+	GasLimit       uint64          `json:"gasLimit"`
+	MaxFee         *big.Int        `json:"maxFee"`
+	MaxPriorityFee *big.Int        `json:"maxPriorityFee"`
+	Signed         bool            `json:"signed"`
+	Hash           common.Hash     `json:"hash"`
+	Payload        string          `json:"payload"`
+}
+
+// Wraps a transaction for export
+func NewOfflineTransaction(tx *types.Transaction, from common.Address, signed bool) (*OfflineTransaction, error) {
+	payload, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling TX to binary: %w", err)
+	}
+	return &OfflineTransaction{
+		ChainID:        tx.ChainId(),
+		From:           from,
+		Nonce:          tx.Nonce(),
+		To:             tx.To(),
+		Value:          tx.Value(),
+		Data:           tx.Data(),
+		GasLimit:       tx.Gas(),
+		MaxFee:         tx.GasFeeCap(),
+		MaxPriorityFee: tx.GasTipCap(),
+		Signed:         signed,
+		Hash:           tx.Hash(),
+		Payload:        hexutil.Encode(payload),
+	}, nil
+}
+
+// Parses an exported transaction, which can either be the JSON export or just its hex payload.
+// Only the payload is trusted; the other fields of a JSON export are informational.
+func ParseOfflineTransaction(data []byte) (*types.Transaction, error) {
+	payload := strings.TrimSpace(string(data))
+	if strings.HasPrefix(payload, "{") {
+		var export OfflineTransaction
+		if err := json.Unmarshal([]byte(payload), &export); err != nil {
+			return nil, fmt.Errorf("Error decoding offline transaction: %w", err)
+		}
+		payload = export.Payload
+	}
+
+	bytes, err := hexutil.Decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding offline transaction payload: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(bytes); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling TX: %w", err)
+	}
+	return tx, nil
+}
+
+// Writes an exported transaction to the provided folder, named after the transaction hash
+func SaveOfflineTransaction(exportPath string, tx *OfflineTransaction) (string, error) {
+	bytes, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Error serializing offline transaction: %w", err)
+	}
+	if err := os.MkdirAll(exportPath, 0700); err != nil {
+		return "", fmt.Errorf("Error creating offline transaction folder [%s]: %w", exportPath, err)
+	}
+	path := filepath.Join(exportPath, tx.Hash.Hex()+".json")
+	if err := os.WriteFile(path, bytes, FileMode); err != nil {
+		return "", fmt.Errorf("Error writing offline transaction to [%s]: %w", path, err)
+	}
+	return path, nil
+}
+
+// Finds an exported transaction from the same account with the same nonce that does something different, and returns its path.
+// Exports of the same call are allowed, so a command can be run again after its export was abandoned.
+func findConflictingOfflineTransaction(exportPath string, tx *OfflineTransaction) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(exportPath, "*.json"))
+	if err != nil {
+		return "", fmt.Errorf("Error listing offline transactions in [%s]: %w", exportPath, err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("Error reading offline transaction [%s]: %w", path, err)
+		}
+		var existing OfflineTransaction
+		if err := json.Unmarshal(data, &existing); err != nil {
+			continue
+		}
+		if existing.From != tx.From || existing.Nonce != tx.Nonce || existing.Hash == tx.Hash {
+			continue
+		}
+		sameCall := existing.To != nil && tx.To != nil && *existing.To == *tx.To &&
+			existing.Value != nil && tx.Value != nil && existing.Value.Cmp(tx.Value) == 0 &&
+			bytes.Equal(existing.Data, tx.Data)
+		if !sameCall {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// A wallet that exports unsigned transactions instead of signing them, for nodes whose key lives on an offline machine.
+// Everything other than transaction signing is passed through to the underlying wallet.
+type offlineWallet struct {
+	Wallet
+	exportPath string
+}
+
+// Create a new offline wallet wrapping the provided wallet
+func NewOfflineWallet(w Wallet, exportPath string) Wallet {
+	return &offlineWallet{
+		Wallet:     w,
+		exportPath: exportPath,
+	}
+}
+
+// Get a transactor that builds transactions for the node account and exports them instead of sending them
+func (w *offlineWallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
+	transactor, err := w.Wallet.GetNodeAccountTransactor()
+	if err != nil {
+		return nil, err
+	}
+
+	from := transactor.From
+	chainID := w.GetChainID()
+	transactor.NoSend = true
+	transactor.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != from {
+			return nil, bind.ErrNotAuthorized
+		}
+
+		// The chain ID is normally filled in during signing, so set it here to make the export self-contained
+		if tx.Type() == types.DynamicFeeTxType {
+			tx = types.NewTx(&types.DynamicFeeTx{
+				ChainID:    chainID,
+				Nonce:      tx.Nonce(),
+				GasTipCap:  tx.GasTipCap(),
+				GasFeeCap:  tx.GasFeeCap(),
+				Gas:        tx.Gas(),
+				To:         tx.To(),
+				Value:      tx.Value(),
+				Data:       tx.Data(),
+				AccessList: tx.AccessList(),
+			})
+		}
+		export, err := NewOfflineTransaction(tx, address, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nothing is submitted, so transactions built after this one would get the same nonce and replace it
+		conflict, err := findConflictingOfflineTransaction(w.exportPath, export)
+		if err != nil {
+			return nil, err
+		}
+		if conflict != "" {
+			return nil, fmt.Errorf("a different transaction with nonce %d has already been exported to [%s]; broadcast it and wait for it to be included (or delete it if you don't want to submit it) before building another one", export.Nonce, conflict)
+		}
+		if _, err := SaveOfflineTransaction(w.exportPath, export); err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+	return transactor, nil
+}
+
+// Offline wallets never sign anything themselves
+func (w *offlineWallet) Sign(serializedTx []byte) ([]byte, error) {
+	return nil, fmt.Errorf("transactions can't be signed in offline mode; use `rocketpool wallet sign-offline` on the machine with the node key")
+}
@@ -0,0 +1,147 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A wallet that only provides what the offline wallet needs
+type mockWallet struct {
+	Wallet
+	address common.Address
+	chainID *big.Int
+}
+
+func (w *mockWallet) GetChainID() *big.Int {
+	return w.chainID
+}
+
+func (w *mockWallet) GetNodeAccountTransactor() (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From:    w.address,
+		Context: context.Background(),
+	}, nil
+}
+
+func TestOfflineWalletExport(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(17000)
+	exportPath := t.TempDir()
+
+	w := NewOfflineWallet(&mockWallet{address: address, chainID: chainID}, exportPath)
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.NoSend {
+		t.Fatal("offline transactor would send transactions")
+	}
+
+	// Build a transaction the same way bind does, without a chain ID
+	to := common.HexToAddress("0x1234")
+	tx, err := opts.Signer(address, types.NewTx(&types.DynamicFeeTx{
+		Nonce:     3,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(20e9),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0xde, 0xad, 0xbe, 0xef},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainId().Cmp(chainID) != 0 {
+		t.Fatalf("expected chain ID %s, got %s", chainID, tx.ChainId())
+	}
+	if _, err := opts.Signer(common.HexToAddress("0x5678"), tx); err == nil {
+		t.Fatal("signed a transaction for the wrong account")
+	}
+
+	// Read the export back
+	data, err := os.ReadFile(filepath.Join(exportPath, tx.Hash().Hex()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseOfflineTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Fatalf("expected hash %s, got %s", tx.Hash().Hex(), parsed.Hash().Hex())
+	}
+
+	// Sign it and make sure the raw payload round-trips too
+	signedTx, err := types.SignTx(parsed, types.NewLondonSigner(chainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	export, err := NewOfflineTransaction(signedTx, address, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = ParseOfflineTransaction([]byte(export.Payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(parsed.ChainId()), parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != address {
+		t.Fatalf("expected sender %s, got %s", address.Hex(), from.Hex())
+	}
+}
+
+func TestOfflineWalletRejectsNonceConflicts(t *testing.T) {
+	address := common.HexToAddress("0x1111")
+	exportPath := t.TempDir()
+	w := NewOfflineWallet(&mockWallet{address: address, chainID: big.NewInt(17000)}, exportPath)
+	opts, err := w.GetNodeAccountTransactor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to := common.HexToAddress("0x1234")
+	newTx := func(nonce uint64, data []byte, maxFee int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(maxFee),
+			Gas:       100000,
+			To:        &to,
+			Value:     big.NewInt(0),
+			Data:      data,
+		})
+	}
+	if _, err := opts.Signer(address, newTx(3, []byte{0x01}, 20e9)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Exporting the same call again, e.g. with a new fee, is fine
+	if _, err := opts.Signer(address, newTx(3, []byte{0x01}, 30e9)); err != nil {
+		t.Fatalf("re-exporting the same call failed: %s", err)
+	}
+
+	// A different call with the same nonce would replace the first one
+	if _, err := opts.Signer(address, newTx(3, []byte{0x02}, 20e9)); err == nil {
+		t.Fatal("exported a second transaction with the same nonce")
+	}
+
+	// The next nonce is fine
+	if _, err := opts.Signer(address, newTx(4, []byte{0x02}, 20e9)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rocket-pool/smartnode/bindings/types"
 	rptypes "github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
 	"github.com/rocket-pool/smartnode/shared/utils/validator"
 	eth2types "github.com/wealdtech/go-eth2-types/v2"
 	eth2util "github.com/wealdtech/go-eth2-util"
@@ -96,12 +97,13 @@ func (w *hdWallet) CreateValidatorKey() (*eth2types.BLSPrivateKey, error) {
 
 }
 
-// Stores a validator key into all of the wallet's keystores
+// Stores a validator key into all of the wallet's keystores.
+// If a remote signer is in use, the key isn't stored in the validator client's keystore folders so the VC doesn't load it from both places.
 func (w *hdWallet) StoreValidatorKey(key *eth2types.BLSPrivateKey, path string) error {
 
-	for name := range w.keystores {
+	for _, name := range w.getTargetKeystores() {
 		// Update the keystore in the wallet - using an iterator variable only runs it on the local copy
-		if err := w.keystores[name].StoreValidatorKey(key, path); err != nil {
+		if err := w.storeInKeystore(name, key, path); err != nil {
 			return fmt.Errorf("Could not store %s validator key: %w", name, err)
 		}
 	}
@@ -111,12 +113,50 @@ func (w *hdWallet) StoreValidatorKey(key *eth2types.BLSPrivateKey, path string)
 
 }
 
+// Get the names of the keystores new validator keys should be stored in
+func (w *hdWallet) getTargetKeystores() []string {
+
+	hasRemoteKeystore := false
+	for name := range w.keystores {
+		if _, ok := w.keystores[name].(keystore.RemoteKeystore); ok {
+			hasRemoteKeystore = true
+		}
+	}
+
+	names := []string{}
+	for name := range w.keystores {
+		_, isRemote := w.keystores[name].(keystore.RemoteKeystore)
+		_, isImporting := w.keystores[name].(keystore.ImportingKeystore)
+		if hasRemoteKeystore && !isRemote && !isImporting {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+
+}
+
+// Store a validator key in one of the wallet's keystores.
+// Keys are also stored in the validator client's keystore folders, so failing to import one into the running validator client (e.g. because it's offline) isn't an error;
+// it will load the key from its folder when it starts, and newly staked keys it's missing are imported again before they need to attest.
+func (w *hdWallet) storeInKeystore(name string, key *eth2types.BLSPrivateKey, path string) error {
+	err := w.keystores[name].StoreValidatorKey(key, path)
+	if _, ok := w.keystores[name].(keystore.ImportingKeystore); ok {
+		return nil
+	}
+	return err
+}
+
 // Loads a validator key from the wallet's keystores
 func (w *hdWallet) LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
 
 	errors := []string{}
 	// Try loading the key from all of the keystores, caching errors but not breaking on them
 	for name := range w.keystores {
+		// Remote signers are handled below, since they can't export keys
+		if _, ok := w.keystores[name].(keystore.RemoteKeystore); ok {
+			continue
+		}
 		key, err := w.keystores[name].LoadValidatorKey(pubkey)
 		if err != nil {
 			errors = append(errors, err.Error())
@@ -126,6 +166,32 @@ func (w *hdWallet) LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BL
 		}
 	}
 
+	// Remote signers can't export their keys, so re-derive the key from the wallet if one of them holds it
+	for name := range w.keystores {
+		remoteKeystore, ok := w.keystores[name].(keystore.RemoteKeystore)
+		if !ok {
+			continue
+		}
+		hasKey, err := remoteKeystore.HasValidatorKey(pubkey)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		if !hasKey {
+			continue
+		}
+		for index := uint(0); index < w.ws.NextAccount; index++ {
+			key, _, err := w.getValidatorPrivateKey(index)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(pubkey.Bytes(), key.PublicKey().Marshal()) {
+				return key, nil
+			}
+		}
+		errors = append(errors, fmt.Sprintf("the %s keystore holds the key for validator %s, but it wasn't derived from this wallet", name, pubkey.Hex()))
+	}
+
 	if len(errors) > 0 {
 		// If there were errors, return them
 		return nil, fmt.Errorf("encountered the following errors while trying to load the key for validator %s:\n%s", pubkey.Hex(), strings.Join(errors, "\n"))
@@ -209,9 +275,9 @@ func (w *hdWallet) SaveValidatorKey(key ValidatorKey) error {
 	}
 
 	// Update keystores
-	for name := range w.keystores {
+	for _, name := range w.getTargetKeystores() {
 		// Update the keystore in the wallet - using an iterator variable only runs it on the local copy
-		if err := w.keystores[name].StoreValidatorKey(key.PrivateKey, key.DerivationPath); err != nil {
+		if err := w.storeInKeystore(name, key.PrivateKey, key.DerivationPath); err != nil {
 			return fmt.Errorf("could not store validator key %s in %s keystore: %w", key.PublicKey.Hex(), name, err)
 		}
 	}
@@ -255,9 +321,9 @@ func (w *hdWallet) RecoverValidatorKey(pubkey rptypes.ValidatorPubkey, startInde
 	}
 
 	// Update keystores
-	for name := range w.keystores {
+	for _, name := range w.getTargetKeystores() {
 		// Update the keystore in the wallet - using an iterator variable only runs it on the local copy
-		if err := w.keystores[name].StoreValidatorKey(validatorKey, derivationPath); err != nil {
+		if err := w.storeInKeystore(name, validatorKey, derivationPath); err != nil {
 			return 0, fmt.Errorf("Could not store %s validator key: %w", name, err)
 		}
 	}
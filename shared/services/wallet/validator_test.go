@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rocket-pool/smartnode/bindings/types"
+	eth2types "github.com/wealdtech/go-eth2-types/v2"
+
+	"github.com/rocket-pool/smartnode/shared/services/wallet/keystore"
+)
+
+// A keystore that records the keys stored in it
+type mockKeystore struct {
+	stored []types.ValidatorPubkey
+	err    error
+}
+
+func (ks *mockKeystore) StoreValidatorKey(key *eth2types.BLSPrivateKey, derivationPath string) error {
+	if ks.err != nil {
+		return ks.err
+	}
+	ks.stored = append(ks.stored, types.BytesToValidatorPubkey(key.PublicKey().Marshal()))
+	return nil
+}
+
+func (ks *mockKeystore) LoadValidatorKey(pubkey types.ValidatorPubkey) (*eth2types.BLSPrivateKey, error) {
+	return nil, nil
+}
+
+func (ks *mockKeystore) GetKeystoreDir() string {
+	return ""
+}
+
+// A keystore that imports keys into the running validator client
+type mockImportingKeystore struct {
+	mockKeystore
+}
+
+func (ks *mockImportingKeystore) ImportsValidatorKeys() {}
+
+// A keystore backed by a remote signer
+type mockRemoteKeystore struct {
+	mockKeystore
+}
+
+func (ks *mockRemoteKeystore) HasValidatorKey(pubkey types.ValidatorPubkey) (bool, error) {
+	return false, nil
+}
+
+func newTestValidatorKey(t *testing.T) *eth2types.BLSPrivateKey {
+	if err := eth2types.InitBLS(); err != nil {
+		t.Fatalf("error initializing BLS: %s", err.Error())
+	}
+	key, err := eth2types.GenerateBLSPrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %s", err.Error())
+	}
+	return key
+}
+
+func TestStoreValidatorKeyKeepsFolderKeystores(t *testing.T) {
+	folder := &mockKeystore{}
+	importing := &mockImportingKeystore{mockKeystore{err: errors.New("validator client is offline")}}
+	w := &hdWallet{keystores: map[string]keystore.Keystore{
+		"lighthouse": folder,
+		"keymanager": importing,
+	}}
+
+	// A failed import shouldn't fail the store, since the validator client loads the key from its folder
+	key := newTestValidatorKey(t)
+	if err := w.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing key: %s", err.Error())
+	}
+	if len(folder.stored) != 1 {
+		t.Fatal("key wasn't stored in the keystore folder")
+	}
+
+	// Failing to store it in a keystore folder is still an error
+	folder.err = errors.New("disk full")
+	if err := w.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err == nil {
+		t.Fatal("storing succeeded even though the keystore folder couldn't be written")
+	}
+}
+
+func TestStoreValidatorKeyWithRemoteSigner(t *testing.T) {
+	folder := &mockKeystore{}
+	importing := &mockImportingKeystore{}
+	remote := &mockRemoteKeystore{}
+	w := &hdWallet{keystores: map[string]keystore.Keystore{
+		"lighthouse": folder,
+		"keymanager": importing,
+		"web3signer": remote,
+	}}
+
+	// Keys held by a remote signer shouldn't be left in the keystore folders too
+	key := newTestValidatorKey(t)
+	if err := w.StoreValidatorKey(key, "m/12381/3600/0/0/0"); err != nil {
+		t.Fatalf("error storing key: %s", err.Error())
+	}
+	if len(folder.stored) != 0 {
+		t.Fatal("key was stored in the keystore folder")
+	}
+	if len(remote.stored) != 1 || len(importing.stored) != 1 {
+		t.Fatal("key wasn't stored in the remote signer and registered with the validator client")
+	}
+}
@@ -4,3 +4,31 @@ type APIResponse struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
 }
+
+// The body of a request to the Smartnode's HTTP API server
+type ServerRequest struct {
+	Args            []string `json:"args"`
+	MaxFee          float64  `json:"maxFee,omitempty"`
+	MaxPrioFee      float64  `json:"maxPrioFee,omitempty"`
+	GasLimit        uint64   `json:"gasLimit,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+	IgnoreSyncCheck bool     `json:"ignoreSyncCheck,omitempty"`
+	ForceFallbacks  bool     `json:"forceFallbacks,omitempty"`
+	Offline         bool     `json:"offline,omitempty"`
+}
+
+// The API server routes that return the node's secrets (its mnemonic, password or private key)
+var SecretServerRoutes = [][2]string{
+	{"wallet", "init"},
+	{"wallet", "export"},
+}
+
+// Check if an API server route returns the node's secrets
+func IsSecretServerRoute(group string, command string) bool {
+	for _, route := range SecretServerRoutes {
+		if group == route[0] && command == route[1] {
+			return true
+		}
+	}
+	return false
+}
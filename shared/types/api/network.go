@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/shared/services/rewards"
 )
 
 type NodeFeeResponse struct {
@@ -110,6 +111,33 @@ type DownloadRewardsFileResponse struct {
 	Error  string `json:"error"`
 }
 
+type NetworkVerifyRewardsTreeResponse struct {
+	Status                    string                                `json:"status"`
+	Error                     string                                `json:"error"`
+	Interval                  uint64                                `json:"interval"`
+	NodeAddress               common.Address                        `json:"nodeAddress"`
+	TreeFilePath              string                                `json:"treeFilePath"`
+	TreeFileExists            bool                                  `json:"treeFileExists"`
+	CanonicalMerkleRoot       common.Hash                           `json:"canonicalMerkleRoot"`
+	Verification              rewards.TreeVerification              `json:"verification"`
+	NodeCollateralRpl         *big.Int                              `json:"nodeCollateralRpl"`
+	NodeOracleDaoRpl          *big.Int                              `json:"nodeOracleDaoRpl"`
+	NodeSmoothingPoolEth      *big.Int                              `json:"nodeSmoothingPoolEth"`
+	PerformanceFileExists     bool                                  `json:"performanceFileExists"`
+	Validators                []rewards.ValidatorPerformance        `json:"validators"`
+	ValidatorSmoothingPoolEth *big.Int                              `json:"validatorSmoothingPoolEth"`
+	AttestationRecordExists   bool                                  `json:"attestationRecordExists"`
+	AttestationRecords        []rewards.AttestationRecordComparison `json:"attestationRecords"`
+}
+
+type NetworkCompareRewardsTreeResponse struct {
+	Status       string                          `json:"status"`
+	Error        string                          `json:"error"`
+	TreeFilePath string                          `json:"treeFilePath"`
+	ComparedPath string                          `json:"comparedPath"`
+	Differences  []rewards.RewardsFileDifference `json:"differences"`
+}
+
 type GetLatestDelegateResponse struct {
 	Status  string         `json:"status"`
 	Error   string         `json:"error"`
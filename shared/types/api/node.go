@@ -11,6 +11,7 @@ import (
 	"github.com/rocket-pool/smartnode/bindings/tokens"
 	rptypes "github.com/rocket-pool/smartnode/bindings/types"
 	"github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/services/txtracker"
 	"github.com/rocket-pool/smartnode/shared/utils/rp"
 )
 
@@ -501,6 +502,30 @@ type NodeSendMessageResponse struct {
 	TxHash common.Hash `json:"txHash"`
 }
 
+type NodePendingTransactionsResponse struct {
+	Status       string                         `json:"status"`
+	Error        string                         `json:"error"`
+	LatestNonce  uint64                         `json:"latestNonce"`
+	PendingNonce uint64                         `json:"pendingNonce"`
+	Transactions []txtracker.PendingTransaction `json:"transactions"`
+}
+
+type CanNodeCancelTransactionResponse struct {
+	Status      string             `json:"status"`
+	Error       string             `json:"error"`
+	CanCancel   bool               `json:"canCancel"`
+	NonceUsed   bool               `json:"nonceUsed"`
+	NonceTooNew bool               `json:"nonceTooNew"`
+	IsTracked   bool               `json:"isTracked"`
+	MinMaxFee   *big.Int           `json:"minMaxFee"`
+	GasInfo     rocketpool.GasInfo `json:"gasInfo"`
+}
+type NodeCancelTransactionResponse struct {
+	Status string      `json:"status"`
+	Error  string      `json:"error"`
+	TxHash common.Hash `json:"txHash"`
+}
+
 type CanNodeBurnResponse struct {
 	Status                 string             `json:"status"`
 	Error                  string             `json:"error"`
@@ -557,6 +582,17 @@ type NodeRewardsResponse struct {
 	TxHash                      common.Hash   `json:"txHash"`
 }
 
+type NodeRewardsHistoryResponse struct {
+	Status           string                       `json:"status"`
+	Error            string                       `json:"error"`
+	NodeAddress      common.Address               `json:"nodeAddress"`
+	Entries          []rewards.RewardsLedgerEntry `json:"entries"`
+	MissingIntervals []uint64                     `json:"missingIntervals"`
+	LastScannedBlock uint64                       `json:"lastScannedBlock"`
+	LatestBlock      uint64                       `json:"latestBlock"`
+	Complete         bool                         `json:"complete"`
+}
+
 type DepositContractInfoResponse struct {
 	Status                string         `json:"status"`
 	Error                 string         `json:"error"`
@@ -5,6 +5,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rocket-pool/smartnode/bindings/rocketpool"
 	"github.com/rocket-pool/smartnode/bindings/types"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
 )
 
 // Encrypted validator keystore following the EIP-2335 standard
@@ -103,3 +104,30 @@ type EndMasqueradeResponse struct {
 	Status string `json:"status"`
 	Error  string `json:"error"`
 }
+
+type DeleteValidatorKeysResponse struct {
+	Status                  string `json:"status"`
+	Error                   string `json:"error"`
+	DeletedKeys             int    `json:"deletedKeys"`
+	SavedSlashingProtection bool   `json:"savedSlashingProtection"`
+}
+
+type SlashingProtectionInfoResponse struct {
+	Status                string                  `json:"status"`
+	Error                 string                  `json:"error"`
+	GenesisValidatorsRoot common.Hash             `json:"genesisValidatorsRoot"`
+	Pubkeys               []types.ValidatorPubkey `json:"pubkeys"`
+}
+
+type SignOfflineTransactionResponse struct {
+	Status      string                    `json:"status"`
+	Error       string                    `json:"error"`
+	Transaction wallet.OfflineTransaction `json:"transaction"`
+}
+
+type BroadcastTransactionResponse struct {
+	Status string         `json:"status"`
+	Error  string         `json:"error"`
+	From   common.Address `json:"from"`
+	TxHash common.Hash    `json:"txHash"`
+}
@@ -10,6 +10,8 @@ type RewardsMode string
 type MevRelayID string
 type MevSelectionMode string
 type NimbusPruningMode string
+type TxSubmissionMode string
+type LogFormat string
 type PBSubmissionRef int
 
 // Enum to describe which container(s) a parameter impacts, so the Smartnode knows which
@@ -122,6 +124,20 @@ const (
 	NimbusPruningMode_Prune   NimbusPruningMode = "prune"
 )
 
+// Enum to describe how transactions are submitted to the network
+const (
+	TxSubmissionMode_Default    TxSubmissionMode = "default"
+	TxSubmissionMode_Public     TxSubmissionMode = "public"
+	TxSubmissionMode_PrivateRpc TxSubmissionMode = "privateRpc"
+	TxSubmissionMode_Bundle     TxSubmissionMode = "bundle"
+)
+
+// Enum to describe how the daemons format their logs
+const (
+	LogFormat_Color LogFormat = "color"
+	LogFormat_Json  LogFormat = "json"
+)
+
 type Config interface {
 	GetConfigTitle() string
 	GetParameters() []*Parameter
@@ -0,0 +1,92 @@
+package eth2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/deneb"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/electra"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/fulu"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
+)
+
+// A Beacon state that can be decoded from SSZ
+type sszBeaconState interface {
+	BeaconState
+	UnmarshalSSZ(buf []byte) error
+}
+
+// A signed Beacon block that can be decoded from SSZ
+type sszSignedBeaconBlock interface {
+	SignedBeaconBlock
+	UnmarshalSSZ(buf []byte) error
+}
+
+// A consensus fork with SSZ types for its Beacon states and blocks
+type Fork struct {
+	// The fork's name, as reported by the Beacon Node in the Eth-Consensus-Version header
+	Name string
+
+	// The positions of the fields used for proofs in the fork's states and blocks
+	Layout generic.ForkLayout
+
+	newBeaconState       func() sszBeaconState
+	newSignedBeaconBlock func() sszSignedBeaconBlock
+}
+
+// The forks with SSZ types, by name. Fulu only changed the state, so its blocks are Electra blocks.
+var forks = map[string]*Fork{
+	"deneb": {
+		Name:                 "deneb",
+		Layout:               deneb.Layout,
+		newBeaconState:       func() sszBeaconState { return &deneb.BeaconState{} },
+		newSignedBeaconBlock: func() sszSignedBeaconBlock { return &deneb.SignedBeaconBlock{} },
+	},
+	"electra": {
+		Name:                 "electra",
+		Layout:               electra.Layout,
+		newBeaconState:       func() sszBeaconState { return &electra.BeaconState{} },
+		newSignedBeaconBlock: func() sszSignedBeaconBlock { return &electra.SignedBeaconBlock{} },
+	},
+	"fulu": {
+		Name:                 "fulu",
+		Layout:               fulu.Layout,
+		newBeaconState:       func() sszBeaconState { return &fulu.BeaconState{} },
+		newSignedBeaconBlock: func() sszSignedBeaconBlock { return &electra.SignedBeaconBlock{} },
+	},
+}
+
+// Get a fork by its name
+func GetFork(name string) (*Fork, error) {
+	fork, exists := forks[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unsupported fork: %s", name)
+	}
+	return fork, nil
+}
+
+// Check if a fork has SSZ types for its Beacon states and blocks
+func IsSupportedFork(name string) bool {
+	_, exists := forks[strings.ToLower(name)]
+	return exists
+}
+
+// Decode an SSZ-encoded Beacon state from this fork
+func (f *Fork) NewBeaconState(data []byte) (BeaconState, error) {
+	out := f.newBeaconState()
+	err := out.UnmarshalSSZ(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Decode an SSZ-encoded signed Beacon block from this fork
+func (f *Fork) NewSignedBeaconBlock(data []byte) (SignedBeaconBlock, error) {
+	out := f.newSignedBeaconBlock()
+	err := out.UnmarshalSSZ(data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -11,18 +11,7 @@ func (b *SignedBeaconBlock) ProveWithdrawal(indexInWithdrawalsArray uint64) ([][
 		return nil, err
 	}
 
-	gid := uint64(1)
-	// Navigate to the body
-	gid = gid*generic.BeaconBlockChunksCeil + generic.BeaconBlockBodyIndex
-	// Then to the ExecutionPayload
-	gid = gid*BeaconBlockBodyChunksCeil + generic.BeaconBlockBodyExecutionPayloadIndex
-	// Then to the withdrawals array
-	gid = gid*generic.BeaconBlockBodyExecutionPayloadChunksCeil + generic.BeaconBlockBodyExecutionPayloadWithdrawalsIndex
-	// Then to the array contents
-	gid = gid * 2
-	// Finally to the withdrawal in question
-	gid = gid*generic.BeaconBlockWithdrawalsArrayMax + indexInWithdrawalsArray
-
+	gid := Layout.WithdrawalGeneralizedIndex(indexInWithdrawalsArray)
 	proof, err := tree.Prove(int(gid))
 	if err != nil {
 		return nil, err
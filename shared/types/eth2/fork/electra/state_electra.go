@@ -4,14 +4,21 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"reflect"
-	"sync/atomic"
 
 	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
-	"github.com/rocket-pool/smartnode/shared/utils/math"
 )
 
-const beaconStateChunkCeil uint64 = 64
+// The positions of the fields used for proofs in Electra states and blocks. The state has 37 fields, so it has 64 chunks.
+var Layout = generic.ForkLayout{
+	BeaconStateChunksCeil:                64,
+	BeaconStateValidatorsIndex:           generic.BeaconStateValidatorsIndex,
+	BeaconStateBlockRootsIndex:           generic.BeaconStateBlockRootsFieldIndex,
+	BeaconStateHistoricalSummariesIndex:  generic.BeaconStateHistoricalSummariesFieldIndex,
+	BeaconBlockBodyChunksCeil:            BeaconBlockBodyChunksCeil,
+	BeaconBlockBodyExecutionPayloadIndex: generic.BeaconBlockBodyExecutionPayloadIndex,
+	ExecutionPayloadChunksCeil:           generic.BeaconBlockBodyExecutionPayloadChunksCeil,
+	ExecutionPayloadWithdrawalsIndex:     generic.BeaconBlockBodyExecutionPayloadWithdrawalsIndex,
+}
 
 // Taken from https://github.com/OffchainLabs/prysm/blob/a0071826c5daf7dc3a6e76874fdaa76481a3c665/proto/prysm/v1alpha1/beacon_state.pb.go#L1955
 // Unexported fields stripped, as well as proto-related field tags. JSON and ssz-size tags are preserved, and nested types are replaced with local copies as well.
@@ -57,23 +64,8 @@ type BeaconState struct {
 	PendingConsolidations         []*generic.PendingConsolidation     `json:"pending_consolidations,omitempty" ssz-max:"262144"`
 }
 
-var beaconStateChunkSize atomic.Uint64
-
-func getStateChunkSize() uint64 {
-	// Use a static value to avoid multiple reflection calls
-	storedChunkSize := beaconStateChunkSize.Load()
-	if storedChunkSize == 0 {
-		s := reflect.TypeOf(BeaconState{}).NumField()
-		beaconStateChunkSize.Store(uint64(s))
-		storedChunkSize = uint64(s)
-	}
-	return storedChunkSize
-}
-
 func GetGeneralizedIndexForValidators() uint64 {
-	// There's 28 fields, so rounding up to the next power of two is 32, a left-aligned node
-	// BeaconStateValidatorsIndex is the 11th field, so its generalized index is 32 + 11 = 43
-	return math.GetPowerOfTwoCeil(getStateChunkSize()) + generic.BeaconStateValidatorsIndex
+	return Layout.ValidatorsGeneralizedIndex()
 }
 
 func (state *BeaconState) validatorStateProof(index uint64) ([][]byte, error) {
@@ -85,7 +77,7 @@ func (state *BeaconState) validatorStateProof(index uint64) ([][]byte, error) {
 	}
 
 	// Find the validator's generalized index
-	generalizedIndex := generic.GetGeneralizedIndexForValidator(index, GetGeneralizedIndexForValidators())
+	generalizedIndex := Layout.ValidatorGeneralizedIndex(index)
 
 	// Grab the proof for that index
 	proof, err := root.Prove(int(generalizedIndex))
@@ -154,12 +146,8 @@ func (state *BeaconState) HistoricalSummaryProof(slot uint64) ([][]byte, error)
 		return nil, fmt.Errorf("could not get state tree: %w", err)
 	}
 
-	// Navigate to the historical_summaries
-	gid := uint64(1)
-	gid = gid*beaconStateChunkCeil + generic.BeaconStateHistoricalSummariesFieldIndex
-	// Navigate into the historical summaries vector.
-	arrayIndex := (slot / generic.SlotsPerHistoricalRoot)
-	gid = gid*2*generic.BeaconStateHistoricalSummariesMaxLength + arrayIndex
+	// Navigate to the historical summary for the slot
+	gid := Layout.HistoricalSummaryGeneralizedIndex(slot)
 
 	proof, err := tree.Prove(int(gid))
 	if err != nil {
@@ -215,14 +203,8 @@ func (state *BeaconState) BlockRootProof(slot uint64) ([][]byte, error) {
 		return nil, fmt.Errorf("could not get state tree: %w", err)
 	}
 
-	gid := uint64(1)
-
-	// Navigate to the block_roots
-	gid = gid*beaconStateChunkCeil + generic.BeaconStateBlockRootsFieldIndex
-
-	// We're now at the block_roots vector, which is the root of a slotsPerHistoricalRoot slots vector.
-	// The index we care about is given by slot % slotsPerHistoricalRoot.
-	gid = gid*generic.BeaconStateBlockRootsMaxLength + (slot % generic.SlotsPerHistoricalRoot)
+	// Navigate to the block root for the slot in the block_roots vector
+	gid := Layout.BlockRootGeneralizedIndex(slot)
 
 	proof, err := tree.Prove(int(gid))
 	if err != nil {
@@ -242,6 +224,10 @@ func (state *BeaconState) GetValidators() []*generic.Validator {
 	return state.Validators
 }
 
+func (state *BeaconState) GetBalances() []uint64 {
+	return state.Balances
+}
+
 func (state *BeaconState) GetSlot() uint64 {
 	return state.Slot
 }
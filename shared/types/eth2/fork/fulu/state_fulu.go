@@ -0,0 +1,238 @@
+package fulu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/electra"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
+)
+
+// The positions of the fields used for proofs in Fulu states and blocks. The state has 38 fields, so it still has 64 chunks,
+// and blocks are unchanged from Electra.
+var Layout = generic.ForkLayout{
+	BeaconStateChunksCeil:                64,
+	BeaconStateValidatorsIndex:           generic.BeaconStateValidatorsIndex,
+	BeaconStateBlockRootsIndex:           generic.BeaconStateBlockRootsFieldIndex,
+	BeaconStateHistoricalSummariesIndex:  generic.BeaconStateHistoricalSummariesFieldIndex,
+	BeaconBlockBodyChunksCeil:            electra.BeaconBlockBodyChunksCeil,
+	BeaconBlockBodyExecutionPayloadIndex: generic.BeaconBlockBodyExecutionPayloadIndex,
+	ExecutionPayloadChunksCeil:           generic.BeaconBlockBodyExecutionPayloadChunksCeil,
+	ExecutionPayloadWithdrawalsIndex:     generic.BeaconBlockBodyExecutionPayloadWithdrawalsIndex,
+}
+
+// The Electra state with the proposer lookahead added by EIP-7917, as defined in the consensus specs for Fulu.
+// JSON and ssz-size tags follow the Electra state, and nested types are replaced with local copies as well.
+type BeaconState struct {
+	GenesisTime                  uint64                          `json:"genesis_time"`
+	GenesisValidatorsRoot        []byte                          `json:"genesis_validators_root" ssz-size:"32"`
+	Slot                         uint64                          `json:"slot"`
+	Fork                         *generic.Fork                   `json:"fork"`
+	LatestBlockHeader            *generic.BeaconBlockHeader      `json:"latest_block_header"`
+	BlockRoots                   [8192][32]byte                  `json:"block_roots" ssz-size:"8192,32"`
+	StateRoots                   [8192][32]byte                  `json:"state_roots" ssz-size:"8192,32"`
+	HistoricalRoots              [][]byte                        `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
+	Eth1Data                     *generic.Eth1Data               `json:"eth1_data"`
+	Eth1DataVotes                []*generic.Eth1Data             `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DepositIndex             uint64                          `json:"eth1_deposit_index"`
+	Validators                   []*generic.Validator            `json:"validators" ssz-max:"1099511627776"`
+	Balances                     []uint64                        `json:"balances" ssz-max:"1099511627776"`
+	RandaoMixes                  [][]byte                        `json:"randao_mixes" ssz-size:"65536,32"`
+	Slashings                    []uint64                        `json:"slashings" ssz-size:"8192"`
+	PreviousEpochParticipation   []byte                          `json:"previous_epoch_participation" ssz-max:"1099511627776"`
+	CurrentEpochParticipation    []byte                          `json:"current_epoch_participation" ssz-max:"1099511627776"`
+	JustificationBits            [1]byte                         `json:"justification_bits" ssz-size:"1"`
+	PreviousJustifiedCheckpoint  *generic.Checkpoint             `json:"previous_justified_checkpoint"`
+	CurrentJustifiedCheckpoint   *generic.Checkpoint             `json:"current_justified_checkpoint"`
+	FinalizedCheckpoint          *generic.Checkpoint             `json:"finalized_checkpoint"`
+	InactivityScores             []uint64                        `json:"inactivity_scores" ssz-max:"1099511627776"`
+	CurrentSyncCommittee         *generic.SyncCommittee          `json:"current_sync_committee"`
+	NextSyncCommittee            *generic.SyncCommittee          `json:"next_sync_committee"`
+	LatestExecutionPayloadHeader *generic.ExecutionPayloadHeader `json:"latest_execution_payload_header"`
+	NextWithdrawalIndex          uint64                          `json:"next_withdrawal_index"`
+	NextWithdrawalValidatorIndex uint64                          `json:"next_withdrawal_validator_index"`
+	HistoricalSummaries          []*generic.HistoricalSummary    `json:"historical_summaries" ssz-max:"16777216"`
+
+	// New in Electra
+	DepositRequestsStartIndex     uint64                              `json:"deposit_requests_start_index"`
+	DepositBalanceToConsume       uint64                              `json:"deposit_balance_to_consume"`
+	ExitBalanceToConsume          uint64                              `json:"exit_balance_to_consume"`
+	EarliestExitEpoch             uint64                              `json:"earliest_exit_epoch"`
+	ConsolidationBalanceToConsume uint64                              `json:"consolidation_balance_to_consume"`
+	EarliestConsolidationEpoch    uint64                              `json:"earliest_consolidation_epoch"`
+	PendingDeposits               []*generic.PendingDeposit           `json:"pending_deposits,omitempty" ssz-max:"134217728"`
+	PendingPartialWithdrawals     []*generic.PendingPartialWithdrawal `json:"pending_partial_withdrawals,omitempty" ssz-max:"134217728"`
+	PendingConsolidations         []*generic.PendingConsolidation     `json:"pending_consolidations,omitempty" ssz-max:"262144"`
+
+	// New in Fulu
+	ProposerLookahead []uint64 `json:"proposer_lookahead" ssz-size:"64"`
+}
+
+func GetGeneralizedIndexForValidators() uint64 {
+	return Layout.ValidatorsGeneralizedIndex()
+}
+
+func (state *BeaconState) validatorStateProof(index uint64) ([][]byte, error) {
+
+	// Convert the state to a proof tree
+	root, err := state.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get state tree: %w", err)
+	}
+
+	// Find the validator's generalized index
+	generalizedIndex := Layout.ValidatorGeneralizedIndex(index)
+
+	// Grab the proof for that index
+	proof, err := root.Prove(int(generalizedIndex))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for validator: %w", err)
+	}
+
+	// Sanity check that the proof leaf matches the expected validator
+	validatorHashTreeRoot, err := state.Validators[index].HashTreeRoot()
+	if err != nil {
+		return nil, fmt.Errorf("could not get hash tree root for validator: %w", err)
+	}
+	if !bytes.Equal(proof.Leaf, validatorHashTreeRoot[:]) {
+		return nil, fmt.Errorf("proof leaf does not match expected validator")
+	}
+
+	return proof.Hashes, nil
+
+}
+
+func (state *BeaconState) ValidatorProof(index uint64) ([][]byte, error) {
+
+	if index >= uint64(len(state.Validators)) {
+		return nil, errors.New("validator index out of bounds")
+	}
+
+	proof, err := state.validatorStateProof(index)
+	if err != nil {
+		return nil, fmt.Errorf("could not get validator state proof: %w", err)
+	}
+
+	// The EL proves against BeaconBlockHeader root, so we need to merge the state proof with that.
+	generalizedIndex := generic.BeaconBlockHeaderStateRootGeneralizedIndex
+	root, err := state.LatestBlockHeader.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header tree: %w", err)
+	}
+	blockHeaderProof, err := root.Prove(int(generalizedIndex))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for block header: %w", err)
+	}
+
+	return append(proof, blockHeaderProof.Hashes...), nil
+}
+
+func (state *BeaconState) blockHeaderToStateProof(blockHeader *generic.BeaconBlockHeader) ([][]byte, error) {
+	generalizedIndex := generic.BeaconBlockHeaderStateRootGeneralizedIndex
+	root, err := blockHeader.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header tree: %w", err)
+	}
+	blockHeaderProof, err := root.Prove(int(generalizedIndex))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for block header: %w", err)
+	}
+	return blockHeaderProof.Hashes, nil
+}
+
+func (state *BeaconState) HistoricalSummaryProof(slot uint64) ([][]byte, error) {
+	isHistorical := slot+generic.SlotsPerHistoricalRoot <= state.Slot
+	if !isHistorical {
+		return nil, fmt.Errorf("slot %d is less than %d slots in the past from the state at slot %d, you must build a proof from the block_roots field instead", slot, generic.SlotsPerHistoricalRoot, state.Slot)
+	}
+	tree, err := state.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get state tree: %w", err)
+	}
+
+	// Navigate to the historical summary for the slot
+	gid := Layout.HistoricalSummaryGeneralizedIndex(slot)
+
+	proof, err := tree.Prove(int(gid))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for historical block root: %w", err)
+	}
+
+	// The EL proves against BeaconBlockHeader root, so we need to merge the state proof with that.
+	blockHeaderProof, err := state.blockHeaderToStateProof(state.LatestBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header proof: %w", err)
+	}
+	return append(proof.Hashes, blockHeaderProof...), nil
+}
+
+func (state *BeaconState) HistoricalSummaryBlockRootProof(slot int) ([][]byte, error) {
+	// If the state isn't aligned at the end of an 8192 slot era, throw an error
+	if state.Slot%generic.SlotsPerHistoricalRoot != 0 {
+		return nil, fmt.Errorf("state is not aligned at the end of an 8192 slot era")
+	}
+
+	hsls := generic.HistoricalSummaryLists{
+		BlockRoots: state.BlockRoots,
+		StateRoots: state.StateRoots,
+	}
+
+	idx := slot % int(generic.SlotsPerHistoricalRoot)
+	tree, err := hsls.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get historical summary lists tree: %w", err)
+	}
+
+	gid := uint64(1)
+	gid = gid * 2                              // Now at block_roots
+	gid = gid * generic.SlotsPerHistoricalRoot // Now at the first block_root
+	gid = gid + uint64(idx)                    // Now at the correct block_root
+
+	proof, err := tree.Prove(int(gid))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for historical summary: %w", err)
+	}
+
+	return proof.Hashes, nil
+}
+
+func (state *BeaconState) BlockRootProof(slot uint64) ([][]byte, error) {
+	isHistorical := slot+generic.SlotsPerHistoricalRoot <= state.Slot
+	if isHistorical {
+		return nil, fmt.Errorf("slot %d is more than %d slots in the past from the state at slot %d, you must build a proof from the historical_summaries instead", slot, generic.SlotsPerHistoricalRoot, state.Slot)
+	}
+
+	tree, err := state.GetTree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get state tree: %w", err)
+	}
+
+	// Navigate to the block root for the slot in the block_roots vector
+	gid := Layout.BlockRootGeneralizedIndex(slot)
+
+	proof, err := tree.Prove(int(gid))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proof for block root: %w", err)
+	}
+
+	// Finally, prove from the block header to the state root.
+	blockHeaderProof, err := state.blockHeaderToStateProof(state.LatestBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header proof: %w", err)
+	}
+
+	return append(proof.Hashes, blockHeaderProof...), nil
+}
+
+func (state *BeaconState) GetValidators() []*generic.Validator {
+	return state.Validators
+}
+
+func (state *BeaconState) GetBalances() []uint64 {
+	return state.Balances
+}
+
+func (state *BeaconState) GetSlot() uint64 {
+	return state.Slot
+}
@@ -0,0 +1,182 @@
+package fulu
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
+)
+
+// Header proofs go from the state root to the root of a BeaconBlockHeader, which has 8 chunks
+const blockHeaderProofLength int = 3
+
+func newRoot(value byte) []byte {
+	return bytes.Repeat([]byte{value}, 32)
+}
+
+func newSyncCommittee() *generic.SyncCommittee {
+	committee := &generic.SyncCommittee{
+		PubKeys: make([][]byte, 512),
+	}
+	for i := range committee.PubKeys {
+		committee.PubKeys[i] = make([]byte, 48)
+	}
+	return committee
+}
+
+// Build a small but complete Fulu state
+func newTestState() *BeaconState {
+	state := &BeaconState{
+		GenesisValidatorsRoot: newRoot(1),
+		Slot:                  100,
+		Fork: &generic.Fork{
+			PreviousVersion: []byte{5, 0, 0, 0},
+			CurrentVersion:  []byte{6, 0, 0, 0},
+			Epoch:           3,
+		},
+		LatestBlockHeader: &generic.BeaconBlockHeader{
+			Slot:       100,
+			ParentRoot: newRoot(2),
+			StateRoot:  make([]byte, 32),
+			BodyRoot:   newRoot(3),
+		},
+		Eth1Data: &generic.Eth1Data{
+			DepositRoot: newRoot(4),
+			BlockHash:   newRoot(5),
+		},
+		RandaoMixes:                  make([][]byte, 65536),
+		Slashings:                    make([]uint64, 8192),
+		PreviousJustifiedCheckpoint:  &generic.Checkpoint{Root: newRoot(6)},
+		CurrentJustifiedCheckpoint:   &generic.Checkpoint{Root: newRoot(7)},
+		FinalizedCheckpoint:          &generic.Checkpoint{Root: newRoot(8)},
+		CurrentSyncCommittee:         newSyncCommittee(),
+		NextSyncCommittee:            newSyncCommittee(),
+		LatestExecutionPayloadHeader: &generic.ExecutionPayloadHeader{},
+		ProposerLookahead:            make([]uint64, 64),
+	}
+	for i := range state.RandaoMixes {
+		state.RandaoMixes[i] = make([]byte, 32)
+	}
+	for i := range state.BlockRoots {
+		state.BlockRoots[i][0] = byte(i)
+		state.BlockRoots[i][1] = byte(i >> 8)
+	}
+	for i := range state.ProposerLookahead {
+		state.ProposerLookahead[i] = uint64(i * 7)
+	}
+	for i := 0; i < 4; i++ {
+		state.Validators = append(state.Validators, &generic.Validator{
+			Pubkey:                bytes.Repeat([]byte{byte(i + 1)}, 48),
+			WithdrawalCredentials: newRoot(byte(i + 10)),
+			EffectiveBalance:      32e9,
+			ExitEpoch:             1<<64 - 1,
+			WithdrawableEpoch:     1<<64 - 1,
+		})
+		state.Balances = append(state.Balances, 32e9+uint64(i))
+		state.PreviousEpochParticipation = append(state.PreviousEpochParticipation, 7)
+		state.CurrentEpochParticipation = append(state.CurrentEpochParticipation, 7)
+		state.InactivityScores = append(state.InactivityScores, 0)
+	}
+	return state
+}
+
+// Walk a proof from a leaf up to the root of the tree, returning the root
+func getProofRoot(t *testing.T, leaf []byte, proof [][]byte, gid uint64) []byte {
+	current := leaf
+	for _, sibling := range proof {
+		var pair []byte
+		if gid%2 == 1 {
+			pair = append(append(pair, sibling...), current...)
+		} else {
+			pair = append(append(pair, current...), sibling...)
+		}
+		hash := sha256.Sum256(pair)
+		current = hash[:]
+		gid /= 2
+	}
+	if gid != 1 {
+		t.Fatalf("proof ended at generalized index %d instead of the root", gid)
+	}
+	return current
+}
+
+func TestBeaconStateRoundTrip(t *testing.T) {
+	state := newTestState()
+	data, err := state.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != state.SizeSSZ() {
+		t.Fatalf("expected %d bytes, got %d", state.SizeSSZ(), len(data))
+	}
+
+	decoded := &BeaconState{}
+	if err := decoded.UnmarshalSSZ(data); err != nil {
+		t.Fatal(err)
+	}
+	for i, proposer := range decoded.ProposerLookahead {
+		if proposer != state.ProposerLookahead[i] {
+			t.Fatalf("expected proposer %d at lookahead position %d, got %d", state.ProposerLookahead[i], i, proposer)
+		}
+	}
+	if len(decoded.Validators) != len(state.Validators) || decoded.Slot != state.Slot {
+		t.Fatal("decoded state doesn't match the original")
+	}
+
+	expectedRoot, err := state.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := decoded.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != expectedRoot {
+		t.Fatalf("expected state root %x, got %x", expectedRoot, root)
+	}
+
+	// A truncated state is invalid
+	if err := decoded.UnmarshalSSZ(data[:len(data)-1]); err == nil {
+		t.Fatal("expected an error decoding a truncated state")
+	}
+}
+
+func TestStateProofs(t *testing.T) {
+	state := newTestState()
+	stateRoot, err := state.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The proposer lookahead is the 38th field, so the state still has 64 chunks and the validators stay where they were in Electra
+	if gid := Layout.ValidatorsGeneralizedIndex(); gid != 75 {
+		t.Fatalf("expected the validators to be at generalized index 75, got %d", gid)
+	}
+
+	for i, validator := range state.Validators {
+		proof, err := state.ValidatorProof(uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := validator.HashTreeRoot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := getProofRoot(t, leaf[:], proof[:len(proof)-blockHeaderProofLength], Layout.ValidatorGeneralizedIndex(uint64(i)))
+		if !bytes.Equal(root, stateRoot[:]) {
+			t.Fatalf("validator %d proof leads to %x instead of the state root %x", i, root, stateRoot)
+		}
+	}
+
+	for _, slot := range []uint64{0, 50, 99} {
+		proof, err := state.BlockRootProof(slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := getProofRoot(t, state.BlockRoots[slot][:], proof[:len(proof)-blockHeaderProofLength], Layout.BlockRootGeneralizedIndex(slot))
+		if !bytes.Equal(root, stateRoot[:]) {
+			t.Fatalf("block root proof for slot %d leads to %x instead of the state root %x", slot, root, stateRoot)
+		}
+	}
+}
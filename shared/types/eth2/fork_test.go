@@ -0,0 +1,38 @@
+package eth2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rocket-pool/smartnode/shared/utils/math"
+)
+
+// Make sure each fork's layout matches the shape of its types, since the chunk counts are used to navigate the proof trees
+func TestForkLayouts(t *testing.T) {
+	for name, fork := range forks {
+		t.Run(name, func(t *testing.T) {
+			if fork.Name != name {
+				t.Fatalf("fork %s is registered as %s", fork.Name, name)
+			}
+
+			stateFields := reflect.TypeOf(fork.newBeaconState()).Elem().NumField()
+			if expected := math.GetPowerOfTwoCeil(uint64(stateFields)); fork.Layout.BeaconStateChunksCeil != expected {
+				t.Fatalf("state has %d fields so it should have %d chunks, but the layout has %d", stateFields, expected, fork.Layout.BeaconStateChunksCeil)
+			}
+
+			blockField, _ := reflect.TypeOf(fork.newSignedBeaconBlock()).Elem().FieldByName("Block")
+			bodyField, _ := blockField.Type.Elem().FieldByName("Body")
+			bodyFields := bodyField.Type.Elem().NumField()
+			if expected := math.GetPowerOfTwoCeil(uint64(bodyFields)); fork.Layout.BeaconBlockBodyChunksCeil != expected {
+				t.Fatalf("block body has %d fields so it should have %d chunks, but the layout has %d", bodyFields, expected, fork.Layout.BeaconBlockBodyChunksCeil)
+			}
+		})
+	}
+
+	if _, err := GetFork("Electra"); err != nil {
+		t.Fatalf("fork names should be case-insensitive: %s", err)
+	}
+	if IsSupportedFork("phase0") {
+		t.Fatal("phase0 doesn't have SSZ types")
+	}
+}
@@ -0,0 +1,72 @@
+package generic
+
+// The positions of the fields used for proofs in a fork's Beacon state and block.
+// Each fork package provides its own layout, so proofs are built against the right tree shape for the fork a state or block came from.
+type ForkLayout struct {
+	// The number of fields in the BeaconState container, rounded up to the next power of two
+	BeaconStateChunksCeil uint64
+
+	// The field indices in the BeaconState container
+	BeaconStateValidatorsIndex          uint64
+	BeaconStateBlockRootsIndex          uint64
+	BeaconStateHistoricalSummariesIndex uint64
+
+	// The number of fields in the BeaconBlockBody container, rounded up to the next power of two
+	BeaconBlockBodyChunksCeil uint64
+
+	// The field index of the ExecutionPayload in the BeaconBlockBody container
+	BeaconBlockBodyExecutionPayloadIndex uint64
+
+	// The number of fields in the ExecutionPayload container, rounded up to the next power of two, and the index of its withdrawals
+	ExecutionPayloadChunksCeil       uint64
+	ExecutionPayloadWithdrawalsIndex uint64
+}
+
+// Get the generalized index of the validators list in the BeaconState
+func (l ForkLayout) ValidatorsGeneralizedIndex() uint64 {
+	return l.BeaconStateChunksCeil + l.BeaconStateValidatorsIndex
+}
+
+// Get the generalized index of a validator in the BeaconState
+func (l ForkLayout) ValidatorGeneralizedIndex(index uint64) uint64 {
+	return GetGeneralizedIndexForValidator(index, l.ValidatorsGeneralizedIndex())
+}
+
+// Get the generalized index of a slot's block root in the BeaconState's block_roots vector
+func (l ForkLayout) BlockRootGeneralizedIndex(slot uint64) uint64 {
+	gid := uint64(1)
+
+	// Navigate to the block_roots
+	gid = gid*l.BeaconStateChunksCeil + l.BeaconStateBlockRootsIndex
+
+	// We're now at the block_roots vector, which is the root of a slotsPerHistoricalRoot slots vector.
+	// The index we care about is given by slot % slotsPerHistoricalRoot.
+	return gid*BeaconStateBlockRootsMaxLength + (slot % SlotsPerHistoricalRoot)
+}
+
+// Get the generalized index of the historical summary covering a slot in the BeaconState
+func (l ForkLayout) HistoricalSummaryGeneralizedIndex(slot uint64) uint64 {
+	gid := uint64(1)
+
+	// Navigate to the historical_summaries
+	gid = gid*l.BeaconStateChunksCeil + l.BeaconStateHistoricalSummariesIndex
+
+	// Navigate into the historical summaries vector.
+	arrayIndex := slot / SlotsPerHistoricalRoot
+	return gid*2*BeaconStateHistoricalSummariesMaxLength + arrayIndex
+}
+
+// Get the generalized index of a withdrawal in a BeaconBlock
+func (l ForkLayout) WithdrawalGeneralizedIndex(indexInWithdrawalsArray uint64) uint64 {
+	gid := uint64(1)
+	// Navigate to the body
+	gid = gid*BeaconBlockChunksCeil + BeaconBlockBodyIndex
+	// Then to the ExecutionPayload
+	gid = gid*l.BeaconBlockBodyChunksCeil + l.BeaconBlockBodyExecutionPayloadIndex
+	// Then to the withdrawals array
+	gid = gid*l.ExecutionPayloadChunksCeil + l.ExecutionPayloadWithdrawalsIndex
+	// Then to the array contents
+	gid = gid * 2
+	// Finally to the withdrawal in question
+	return gid*BeaconBlockWithdrawalsArrayMax + indexInWithdrawalsArray
+}
@@ -1,17 +1,16 @@
 package eth2
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/deneb"
 	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/electra"
+	"github.com/rocket-pool/smartnode/shared/types/eth2/fork/fulu"
 	"github.com/rocket-pool/smartnode/shared/types/eth2/generic"
 )
 
 // State type assertions
 var _ BeaconState = &deneb.BeaconState{}
 var _ BeaconState = &electra.BeaconState{}
+var _ BeaconState = &fulu.BeaconState{}
 
 // Block type assertions
 var _ SignedBeaconBlock = &deneb.SignedBeaconBlock{}
@@ -24,6 +23,7 @@ type BeaconState interface {
 	HistoricalSummaryBlockRootProof(slot int) ([][]byte, error)
 	BlockRootProof(slot uint64) ([][]byte, error)
 	GetValidators() []*generic.Validator
+	GetBalances() []uint64
 }
 
 type SignedBeaconBlock interface {
@@ -32,48 +32,20 @@ type SignedBeaconBlock interface {
 	Withdrawals() []*generic.Withdrawal
 }
 
+// Decode an SSZ-encoded Beacon state from the provided fork
 func NewBeaconState(data []byte, fork string) (BeaconState, error) {
-	fork = strings.ToLower(fork)
-
-	switch fork {
-	case "deneb":
-		out := &deneb.BeaconState{}
-		err := out.UnmarshalSSZ(data)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
-	case "electra":
-		out := &electra.BeaconState{}
-		err := out.UnmarshalSSZ(data)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
-	default:
-		return nil, fmt.Errorf("unsupported fork: %s", fork)
+	f, err := GetFork(fork)
+	if err != nil {
+		return nil, err
 	}
+	return f.NewBeaconState(data)
 }
 
+// Decode an SSZ-encoded signed Beacon block from the provided fork
 func NewSignedBeaconBlock(data []byte, fork string) (SignedBeaconBlock, error) {
-	fork = strings.ToLower(fork)
-
-	switch fork {
-	case "deneb":
-		out := &deneb.SignedBeaconBlock{}
-		err := out.UnmarshalSSZ(data)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
-	case "electra":
-		out := &electra.SignedBeaconBlock{}
-		err := out.UnmarshalSSZ(data)
-		if err != nil {
-			return nil, err
-		}
-		return out, nil
-	default:
-		return nil, fmt.Errorf("unsupported fork: %s", fork)
+	f, err := GetFork(fork)
+	if err != nil {
+		return nil, err
 	}
+	return f.NewSignedBeaconBlock(data)
 }
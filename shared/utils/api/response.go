@@ -3,7 +3,9 @@ package api
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
 	"reflect"
 
 	"github.com/goccy/go-json"
@@ -11,6 +13,14 @@ import (
 	"github.com/rocket-pool/smartnode/shared/types/api"
 )
 
+// Where API responses are printed; the API server captures them here instead of stdout
+var responseOutput io.Writer = os.Stdout
+
+// Set where API responses are printed
+func SetResponseOutput(output io.Writer) {
+	responseOutput = output
+}
+
 func ZeroIfNil(in **big.Int) {
 	if *in == nil {
 		*in = big.NewInt(0)
@@ -62,7 +72,7 @@ func PrintResponse(response interface{}, responseError error) {
 	}
 
 	// Print
-	fmt.Println(string(responseBytes))
+	fmt.Fprintln(responseOutput, string(responseBytes))
 
 }
 
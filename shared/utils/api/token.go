@@ -0,0 +1,57 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The number of random bytes in a freshly generated API token
+const apiTokenLength int = 32
+
+// Reads the API server's authentication token from disk
+func ReadApiToken(path string) (string, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(bytes))
+	if token == "" {
+		return "", fmt.Errorf("API token file [%s] is empty", path)
+	}
+	return token, nil
+}
+
+// Reads the API server's authentication token from disk, creating a new random one if it doesn't exist yet
+func LoadOrCreateApiToken(path string) (string, error) {
+	token, err := ReadApiToken(path)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("error reading API token file [%s]: %w", path, err)
+	}
+
+	// Generate a new token
+	buffer := make([]byte, apiTokenLength)
+	_, err = rand.Read(buffer)
+	if err != nil {
+		return "", fmt.Errorf("error generating API token: %w", err)
+	}
+	token = hex.EncodeToString(buffer)
+
+	// Save it
+	err = os.MkdirAll(filepath.Dir(path), 0775)
+	if err != nil {
+		return "", fmt.Errorf("error creating API token directory: %w", err)
+	}
+	err = os.WriteFile(path, []byte(token), 0600)
+	if err != nil {
+		return "", fmt.Errorf("error writing API token file [%s]: %w", path, err)
+	}
+	return token, nil
+}
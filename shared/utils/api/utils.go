@@ -8,12 +8,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rocket-pool/smartnode/bindings/rocketpool"
 	"github.com/rocket-pool/smartnode/bindings/settings/protocol"
 	"github.com/rocket-pool/smartnode/bindings/utils"
 	"github.com/rocket-pool/smartnode/bindings/utils/eth"
 	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/txtracker"
 	"github.com/rocket-pool/smartnode/shared/utils/log"
 	"github.com/rocket-pool/smartnode/shared/utils/math"
 )
@@ -66,18 +68,26 @@ func PrintAndCheckGasInfo(gasInfo rocketpool.GasInfo, checkThreshold bool, gasTh
 // Print a TX's details to the logger and waits for it to validated.
 func PrintAndWaitForTransaction(cfg *config.RocketPoolConfig, hash common.Hash, ec rocketpool.ExecutionClient, logger *log.ColorLogger) error {
 
-	txWatchUrl := cfg.Smartnode.GetTxWatchUrl()
-	hashString := hash.String()
+	printTransactionHash(cfg, hash, logger)
 
-	logger.Printlnf("Transaction has been submitted with hash %s.", hashString)
-	if txWatchUrl != "" {
-		logger.Printlnf("You may follow its progress by visiting:")
-		logger.Printlnf("%s/%s\n", txWatchUrl, hashString)
+	// Wait for the TX to be included in a block
+	if _, err := utils.WaitForTransaction(ec, hash); err != nil {
+		return fmt.Errorf("Error waiting for transaction: %w", err)
 	}
-	logger.Println("Waiting for the transaction to be validated...")
+
+	return nil
+
+}
+
+// Print a TX's details to the logger and waits for it to validated.
+// If it's still pending after the configured number of blocks, it's re-broadcast with higher fees (up to maxFeeCap and maxPriorityFeeCap) using the transactor that sent it.
+func PrintAndWaitForTrackedTransaction(cfg *config.RocketPoolConfig, hash common.Hash, ec rocketpool.ExecutionClient, opts *bind.TransactOpts, maxFeeCap *big.Int, maxPriorityFeeCap *big.Int, logger *log.ColorLogger) error {
+
+	printTransactionHash(cfg, hash, logger)
 
 	// Wait for the TX to be included in a block
-	if _, err := utils.WaitForTransaction(ec, hash); err != nil {
+	tracker := txtracker.NewTracker(cfg, ec)
+	if _, err := tracker.WaitForTransaction(hash, opts, maxFeeCap, maxPriorityFeeCap, logger); err != nil {
 		return fmt.Errorf("Error waiting for transaction: %w", err)
 	}
 
@@ -85,6 +95,21 @@ func PrintAndWaitForTransaction(cfg *config.RocketPoolConfig, hash common.Hash,
 
 }
 
+func printTransactionHash(cfg *config.RocketPoolConfig, hash common.Hash, logger *log.ColorLogger) {
+
+	txWatchUrl := cfg.Smartnode.GetTxWatchUrl()
+	hashString := hash.String()
+	txLogger := logger.WithTxHash(hashString)
+
+	txLogger.Printlnf("Transaction has been submitted with hash %s.", hashString)
+	if txWatchUrl != "" {
+		txLogger.Printlnf("You may follow its progress by visiting:")
+		txLogger.Printlnf("%s/%s\n", txWatchUrl, hashString)
+	}
+	txLogger.Println("Waiting for the transaction to be validated...")
+
+}
+
 // True if a transaction is due and needs to bypass the gas threshold
 func IsTransactionDue(rp *rocketpool.RocketPool, startTime time.Time) (bool, time.Duration, error) {
 
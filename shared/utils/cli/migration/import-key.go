@@ -40,6 +40,14 @@ func ImportKey(c *cli.Context, rp *rocketpool.Client, minipoolAddress common.Add
 	}
 	fmt.Println("done!")
 
+	// Keys are imported into the VC directly when its Keymanager API is enabled, so it doesn't need to be restarted
+	cfg, _, err := rp.LoadConfig()
+	if err == nil && cfg.Smartnode.EnableKeymanagerApi.Value.(bool) {
+		fmt.Println("The key was loaded into the Smartnode's Validator Client through its Keymanager API.")
+		fmt.Println()
+		return true
+	}
+
 	// Restart the VC if necessary
 	if c.Bool("no-restart") {
 		return true
@@ -2,11 +2,16 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/mitchellh/go-homedir"
 	"github.com/rocket-pool/smartnode/shared/services/rocketpool"
+	"github.com/rocket-pool/smartnode/shared/services/wallet"
 	cfgtypes "github.com/rocket-pool/smartnode/shared/types/config"
 )
 
@@ -32,6 +37,15 @@ func PrintTransactionHashNoCancel(rp *rocketpool.Client, hash common.Hash) {
 
 }
 
+// Print a warning to the console if transactions are being exported for offline signing, but this operation involves multiple transactions
+func PrintMultiTransactionOfflineWarning() {
+
+	fmt.Printf("%sNOTE: You have specified the `offline` flag, but this operation requires multiple transactions.\n"+
+		"Each one depends on the one before it, so only the first will be exported now.\n"+
+		"Once it has been signed, broadcast and included in a block, run this command again to export the next one.%s\n\n", colorYellow, colorReset)
+
+}
+
 // Print a warning to the console if the user set a custom nonce, but this operation involves multiple transactions
 func PrintMultiTransactionNonceWarning() {
 
@@ -56,6 +70,12 @@ func printTransactionHashImpl(rp *rocketpool.Client, hash common.Hash, finalMess
 		return
 	}
 
+	// Transactions built in offline mode were exported instead of submitted
+	if rp.IsOffline() {
+		printOfflineTransaction(cfg.Smartnode.GetOfflineTransactionsPathInCLI(), hash)
+		return
+	}
+
 	txWatchUrl := cfg.Smartnode.GetTxWatchUrl()
 	hashString := hash.String()
 
@@ -68,6 +88,30 @@ func printTransactionHashImpl(rp *rocketpool.Client, hash common.Hash, finalMess
 
 }
 
+// Print the location and payload of a transaction that was exported for offline signing
+func printOfflineTransaction(exportPath string, hash common.Hash) {
+
+	path, err := homedir.Expand(filepath.Join(exportPath, hash.Hex()+".json"))
+	if err != nil {
+		fmt.Printf("Warning: couldn't expand the offline transaction path (%s).\n", err)
+		return
+	}
+
+	// The hash of the unsigned transaction only identifies the export; the real hash is printed when it's signed
+	fmt.Printf("%sUnsigned transaction %s has been exported for offline signing to:\n%s%s\n\n", colorGreen, hash.Hex(), path, colorReset)
+	bytes, err := os.ReadFile(path)
+	if err == nil {
+		var export wallet.OfflineTransaction
+		if err := json.Unmarshal(bytes, &export); err == nil {
+			fmt.Printf("Payload (e.g. for a QR code):\n%s\n\n", export.Payload)
+		}
+	}
+	fmt.Println("Copy it to the machine with your node wallet and sign it with `rocketpool wallet sign-offline`, which prints the signed transaction's hash, then submit the signed transaction with `rocketpool wallet broadcast`.")
+	fmt.Println("It has NOT been submitted to the network yet.")
+	fmt.Println()
+
+}
+
 // Convert a Unix datetime to a string, or `---` if it's zero
 func GetDateTimeString(dateTime uint64) string {
 	timeString := time.Unix(int64(dateTime), 0).Format(time.RFC822)
@@ -1,16 +1,54 @@
 package log
 
 import (
+	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/goccy/go-json"
 )
 
-// Logger with ANSI color output
+// Log levels
+type Level string
+
+const (
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+// Output settings shared by all loggers; these should be set once at startup, before any tasks start logging
+var (
+	jsonOutput  bool
+	nodeAddress string
+	writeLock   sync.Mutex
+)
+
+// A structured log entry
+type jsonEntry struct {
+	Time    string  `json:"time"`
+	Level   Level   `json:"level"`
+	Task    string  `json:"task,omitempty"`
+	Node    string  `json:"node,omitempty"`
+	TxHash  string  `json:"txHash,omitempty"`
+	Slot    *uint64 `json:"slot,omitempty"`
+	Message string  `json:"msg"`
+}
+
+// Logger with ANSI color output, or structured JSON output if it's enabled
 type ColorLogger struct {
 	Color       color.Attribute
 	sprintFunc  func(a ...interface{}) string
 	sprintfFunc func(format string, a ...interface{}) string
+
+	// Fields for structured output
+	level  Level
+	task   string
+	txHash string
+	slot   *uint64
 }
 
 // Create new color logger
@@ -19,25 +57,102 @@ func NewColorLogger(colorAttr color.Attribute) ColorLogger {
 		Color:       colorAttr,
 		sprintFunc:  color.New(colorAttr).SprintFunc(),
 		sprintfFunc: color.New(colorAttr).SprintfFunc(),
+		level:       LevelInfo,
 	}
 }
 
+// Switch all loggers between colored lines (the default) and one JSON object per line
+func SetJsonOutput(enabled bool) {
+	jsonOutput = enabled
+}
+
+// Set the node address to include in structured log entries
+func SetNodeAddress(address string) {
+	nodeAddress = address
+}
+
+// Get a copy of the logger that logs at the provided level
+func (l ColorLogger) WithLevel(level Level) ColorLogger {
+	l.level = level
+	return l
+}
+
+// Get a copy of the logger that includes the name of the task in structured log entries
+func (l ColorLogger) WithTask(task string) ColorLogger {
+	l.task = task
+	return l
+}
+
+// Get a copy of the logger that includes a transaction hash in structured log entries
+func (l ColorLogger) WithTxHash(txHash string) ColorLogger {
+	l.txHash = txHash
+	return l
+}
+
+// Get a copy of the logger that includes a slot in structured log entries
+func (l ColorLogger) WithSlot(slot uint64) ColorLogger {
+	l.slot = &slot
+	return l
+}
+
 // Print values
-func (l *ColorLogger) Print(v ...interface{}) {
+func (l ColorLogger) Print(v ...interface{}) {
+	if jsonOutput {
+		l.writeJson(fmt.Sprint(v...))
+		return
+	}
 	log.Print(l.sprintFunc(v...))
 }
 
 // Print values with a newline
-func (l *ColorLogger) Println(v ...interface{}) {
+func (l ColorLogger) Println(v ...interface{}) {
+	if jsonOutput {
+		l.writeJson(fmt.Sprintln(v...))
+		return
+	}
 	log.Println(l.sprintFunc(v...))
 }
 
 // Print a formatted string
-func (l *ColorLogger) Printf(format string, v ...interface{}) {
+func (l ColorLogger) Printf(format string, v ...interface{}) {
+	if jsonOutput {
+		l.writeJson(fmt.Sprintf(format, v...))
+		return
+	}
 	log.Print(l.sprintfFunc(format, v...))
 }
 
 // Print a formatted string with a newline
-func (l *ColorLogger) Printlnf(format string, v ...interface{}) {
+func (l ColorLogger) Printlnf(format string, v ...interface{}) {
+	if jsonOutput {
+		l.writeJson(fmt.Sprintf(format, v...))
+		return
+	}
 	log.Println(l.sprintfFunc(format, v...))
 }
+
+// Write a message as a single-line JSON entry
+func (l ColorLogger) writeJson(message string) {
+	level := l.level
+	if level == "" {
+		level = LevelInfo
+	}
+	entry := jsonEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   level,
+		Task:    l.task,
+		Node:    nodeAddress,
+		TxHash:  l.txHash,
+		Slot:    l.slot,
+		Message: strings.TrimRight(message, "\n"),
+	}
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("error serializing log entry: %s", err.Error())
+		return
+	}
+
+	writeLock.Lock()
+	defer writeLock.Unlock()
+	_, _ = log.Writer().Write(append(bytes, '\n'))
+}
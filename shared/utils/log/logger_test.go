@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/goccy/go-json"
+)
+
+func TestJsonOutput(t *testing.T) {
+	var buffer bytes.Buffer
+	log.SetOutput(&buffer)
+	SetJsonOutput(true)
+	SetNodeAddress("0x1234")
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		SetJsonOutput(false)
+		SetNodeAddress("")
+	})
+
+	logger := NewColorLogger(color.FgRed).WithLevel(LevelError).WithTask("submit-rpl-price")
+	logger.WithTxHash("0xabcd").WithSlot(42).Printlnf("Submitted price for block %d.", 100)
+	logger.Println("Second line")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %q", len(lines), buffer.String())
+	}
+
+	var entry jsonEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("error parsing log line [%s]: %s", lines[0], err.Error())
+	}
+	if entry.Level != LevelError || entry.Task != "submit-rpl-price" || entry.Node != "0x1234" || entry.TxHash != "0xabcd" {
+		t.Fatalf("unexpected log entry: %+v", entry)
+	}
+	if entry.Slot == nil || *entry.Slot != 42 {
+		t.Fatalf("expected slot 42, got %v", entry.Slot)
+	}
+	if entry.Message != "Submitted price for block 100." {
+		t.Fatalf("unexpected message [%s]", entry.Message)
+	}
+
+	// Fields added to a copy shouldn't leak into the original logger
+	entry = jsonEntry{}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("error parsing log line [%s]: %s", lines[1], err.Error())
+	}
+	if entry.TxHash != "" || entry.Slot != nil {
+		t.Fatalf("unexpected tx hash or slot in log entry: %+v", entry)
+	}
+}
@@ -0,0 +1,337 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rocket-pool/smartnode/bindings/types"
+
+	"github.com/rocket-pool/smartnode/shared/services/beacon"
+	"github.com/rocket-pool/smartnode/shared/services/config"
+	"github.com/rocket-pool/smartnode/shared/services/keymanager"
+	kmkeystore "github.com/rocket-pool/smartnode/shared/services/wallet/keystore/keymanager"
+	"github.com/rocket-pool/smartnode/shared/utils/log"
+)
+
+// Get a client for the VC's Keymanager API.
+// Returns nil if the Keymanager API is disabled.
+func GetKeymanagerClient(cfg *config.RocketPoolConfig) (*keymanager.Client, error) {
+	if !cfg.Smartnode.EnableKeymanagerApi.Value.(bool) {
+		return nil, nil
+	}
+
+	return keymanager.NewClientFromTokenFile(cfg.Smartnode.GetKeymanagerApiUrl(), cfg.Smartnode.GetKeymanagerTokenPath()), nil
+}
+
+// Set the fee recipient for every key the VC is using through its Keymanager API
+func SetFeeRecipient(cfg *config.RocketPoolConfig, feeRecipient common.Address) error {
+
+	km, err := GetKeymanagerClient(cfg)
+	if err != nil {
+		return err
+	}
+	if km == nil {
+		return fmt.Errorf("the Keymanager API is disabled")
+	}
+
+	// Get the keys the VC is using
+	keystores, err := km.ListKeystores()
+	if err != nil {
+		return err
+	}
+	remoteKeys, err := km.ListRemoteKeys()
+	if err != nil {
+		return err
+	}
+	pubkeys := make([]string, 0, len(keystores)+len(remoteKeys))
+	for _, ks := range keystores {
+		pubkeys = append(pubkeys, ks.Pubkey)
+	}
+	for _, remoteKey := range remoteKeys {
+		pubkeys = append(pubkeys, remoteKey.Pubkey)
+	}
+
+	// Update them
+	for _, pubkeyString := range pubkeys {
+		pubkey, err := keymanager.ParsePubkey(pubkeyString)
+		if err != nil {
+			return fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", pubkeyString, err)
+		}
+		err = km.SetFeeRecipient(pubkey, feeRecipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// Register every key held by Web3Signer with the VC as a remote key, for clients like Lighthouse that can't fetch them from the signer themselves.
+// Returns the number of keys that were registered.
+func RegisterWeb3SignerKeys(cfg *config.RocketPoolConfig) (int, error) {
+
+	web3SignerUrl := cfg.Smartnode.GetWeb3SignerUrl()
+	if web3SignerUrl == "" {
+		return 0, nil
+	}
+	km, err := GetKeymanagerClient(cfg)
+	if err != nil {
+		return 0, err
+	}
+	if km == nil {
+		return 0, fmt.Errorf("the Keymanager API is disabled")
+	}
+
+	// Get the keys the VC already knows about
+	remoteKeys, err := km.ListRemoteKeys()
+	if err != nil {
+		return 0, err
+	}
+	registered := map[types.ValidatorPubkey]bool{}
+	for _, remoteKey := range remoteKeys {
+		pubkey, err := keymanager.ParsePubkey(remoteKey.Pubkey)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", remoteKey.Pubkey, err)
+		}
+		registered[pubkey] = true
+	}
+
+	// Find the signer's keys that are missing
+	signerKeys, err := keymanager.NewClient(web3SignerUrl, "").ListKeystores()
+	if err != nil {
+		return 0, fmt.Errorf("error getting the keys loaded into Web3Signer: %w", err)
+	}
+	missing := []types.ValidatorPubkey{}
+	for _, signerKey := range signerKeys {
+		pubkey, err := keymanager.ParsePubkey(signerKey.Pubkey)
+		if err != nil {
+			return 0, fmt.Errorf("Web3Signer reported an invalid pubkey %s: %w", signerKey.Pubkey, err)
+		}
+		if !registered[pubkey] {
+			missing = append(missing, pubkey)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	results, err := km.ImportRemoteKeys(missing, web3SignerUrl)
+	if err != nil {
+		return 0, err
+	}
+	return len(missing), checkKeyStatusResults(missing, results)
+
+}
+
+// Remove every key the VC is using from it through its Keymanager API, so it stops validating with them right away.
+// The slashing protection data the VC returns for the removed keystores is written to the provided path, so it can be imported again if the keys are used somewhere else.
+// Keys the VC reports as read-only can't be removed this way and are left for the caller to delete from its keystore folders.
+// Returns the number of keys that were removed and whether any slashing protection data was written.
+func DeleteValidatorKeys(cfg *config.RocketPoolConfig, slashingProtectionPath string) (int, bool, error) {
+
+	km, err := GetKeymanagerClient(cfg)
+	if err != nil {
+		return 0, false, err
+	}
+	if km == nil {
+		return 0, false, fmt.Errorf("the Keymanager API is disabled")
+	}
+
+	// Get the keys the VC is using
+	keystores, err := km.ListKeystores()
+	if err != nil {
+		return 0, false, err
+	}
+	remoteKeys, err := km.ListRemoteKeys()
+	if err != nil {
+		return 0, false, err
+	}
+	localPubkeys := []types.ValidatorPubkey{}
+	for _, ks := range keystores {
+		if ks.ReadOnly {
+			continue
+		}
+		pubkey, err := keymanager.ParsePubkey(ks.Pubkey)
+		if err != nil {
+			return 0, false, fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", ks.Pubkey, err)
+		}
+		localPubkeys = append(localPubkeys, pubkey)
+	}
+	remotePubkeys := []types.ValidatorPubkey{}
+	for _, remoteKey := range remoteKeys {
+		if remoteKey.ReadOnly {
+			continue
+		}
+		pubkey, err := keymanager.ParsePubkey(remoteKey.Pubkey)
+		if err != nil {
+			return 0, false, fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", remoteKey.Pubkey, err)
+		}
+		remotePubkeys = append(remotePubkeys, pubkey)
+	}
+
+	// Remove the local keystores, saving their slashing protection data before checking the results so it isn't lost if some of them failed
+	deleted := 0
+	savedSlashingProtection := false
+	if len(localPubkeys) > 0 {
+		results, slashingProtection, err := km.DeleteKeystores(localPubkeys)
+		if err != nil {
+			return 0, false, err
+		}
+		if slashingProtection != "" {
+			err = os.WriteFile(slashingProtectionPath, []byte(slashingProtection), 0600)
+			if err != nil {
+				return 0, false, fmt.Errorf("error saving the slashing protection data of the deleted keys to [%s]: %w", slashingProtectionPath, err)
+			}
+			savedSlashingProtection = true
+		}
+		err = checkDeleteResults(localPubkeys, results)
+		if err != nil {
+			return 0, savedSlashingProtection, err
+		}
+		deleted += len(localPubkeys)
+	}
+
+	// Remove the remote keys; their slashing protection data stays in the remote signer
+	if len(remotePubkeys) > 0 {
+		results, err := km.DeleteRemoteKeys(remotePubkeys)
+		if err != nil {
+			return deleted, savedSlashingProtection, err
+		}
+		err = checkDeleteResults(remotePubkeys, results)
+		if err != nil {
+			return deleted, savedSlashingProtection, err
+		}
+		deleted += len(remotePubkeys)
+	}
+
+	return deleted, savedSlashingProtection, nil
+
+}
+
+// Make sure every key in a delete request was removed or wasn't in use
+func checkDeleteResults(pubkeys []types.ValidatorPubkey, results []keymanager.DeleteKeyResult) error {
+	if len(results) != len(pubkeys) {
+		return fmt.Errorf("the Keymanager API returned %d results for %d keys", len(results), len(pubkeys))
+	}
+	for i, result := range results {
+		if result.Status == keymanager.DeleteKeyStatus_Error {
+			return fmt.Errorf("could not delete validator key %s: %s", pubkeys[i].Hex(), result.Message)
+		}
+	}
+	return nil
+}
+
+// Make sure every key in an import request was either imported or already present
+func checkKeyStatusResults(pubkeys []types.ValidatorPubkey, results []keymanager.KeyStatusResult) error {
+	if len(results) != len(pubkeys) {
+		return fmt.Errorf("the Keymanager API returned %d results for %d keys", len(results), len(pubkeys))
+	}
+	for i, result := range results {
+		if result.Status != keymanager.KeyStatus_Imported && result.Status != keymanager.KeyStatus_Duplicate {
+			return fmt.Errorf("could not import validator key %s: %s (%s)", pubkeys[i].Hex(), result.Status, result.Message)
+		}
+	}
+	return nil
+}
+
+// Update the VC's fee recipient through the Keymanager API, restarting the VC instead if that isn't possible
+func ApplyFeeRecipient(cfg *config.RocketPoolConfig, bc beacon.Client, log *log.ColorLogger, d *client.Client, feeRecipient common.Address) error {
+	err := SetFeeRecipient(cfg, feeRecipient)
+	if err == nil {
+		if log != nil {
+			log.Println("Updated the validator client's fee recipient through the Keymanager API.")
+		}
+		return nil
+	}
+
+	if log != nil {
+		log.Printlnf("Couldn't update the fee recipient through the Keymanager API (%s), restarting the validator client instead.", err.Error())
+	}
+	return RestartValidator(cfg, bc, log, d)
+}
+
+// Make sure the VC has loaded the keys for newly staked validators.
+// Keys stored while the Keymanager API is enabled are already imported into the VC, so this only re-imports keys it's missing;
+// the VC is restarted instead if the API is disabled or a key can only be loaded from its keystore folders.
+func EnsureValidatorKeysLoaded(cfg *config.RocketPoolConfig, bc beacon.Client, log *log.ColorLogger, d *client.Client, pubkeys []types.ValidatorPubkey) error {
+	if len(pubkeys) == 0 {
+		return nil
+	}
+
+	km, err := GetKeymanagerClient(cfg)
+	if err != nil {
+		return err
+	}
+	if km == nil {
+		return RestartValidator(cfg, bc, log, d)
+	}
+
+	missing, err := reimportMissingKeys(cfg, km, pubkeys)
+	if err != nil {
+		if log != nil {
+			log.Printlnf("Couldn't check the validator client's keys through the Keymanager API (%s), restarting the validator client instead.", err.Error())
+		}
+		return RestartValidator(cfg, bc, log, d)
+	}
+	if missing > 0 {
+		if log != nil {
+			log.Printlnf("%d validator key(s) can't be imported through the Keymanager API, restarting the validator client so it loads them from disk.", missing)
+		}
+		return RestartValidator(cfg, bc, log, d)
+	}
+
+	if log != nil {
+		log.Printlnf("The validator client has loaded all %d new validator key(s).", len(pubkeys))
+	}
+	return nil
+}
+
+// Import any of the given keys that the VC isn't using from the Keymanager keystore, returning the number that couldn't be imported
+func reimportMissingKeys(cfg *config.RocketPoolConfig, km *keymanager.Client, pubkeys []types.ValidatorPubkey) (int, error) {
+
+	// Get the keys the VC is using
+	keystores, err := km.ListKeystores()
+	if err != nil {
+		return 0, err
+	}
+	remoteKeys, err := km.ListRemoteKeys()
+	if err != nil {
+		return 0, err
+	}
+	loaded := map[types.ValidatorPubkey]bool{}
+	for _, ks := range keystores {
+		pubkey, err := keymanager.ParsePubkey(ks.Pubkey)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", ks.Pubkey, err)
+		}
+		loaded[pubkey] = true
+	}
+	for _, remoteKey := range remoteKeys {
+		pubkey, err := keymanager.ParsePubkey(remoteKey.Pubkey)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing pubkey %s reported by the Keymanager API: %w", remoteKey.Pubkey, err)
+		}
+		loaded[pubkey] = true
+	}
+
+	// Import the missing ones
+	ks := kmkeystore.NewKeystore(os.ExpandEnv(cfg.Smartnode.GetValidatorKeychainPath()), km, cfg.Smartnode.GetWeb3SignerUrl())
+	missing := 0
+	for _, pubkey := range pubkeys {
+		if loaded[pubkey] {
+			continue
+		}
+		imported, err := ks.ReimportValidatorKey(pubkey)
+		if err != nil {
+			return 0, err
+		}
+		if !imported {
+			missing++
+		}
+	}
+	return missing, nil
+
+}
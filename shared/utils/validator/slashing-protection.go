@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/rocket-pool/smartnode/bindings/types"
+
+	hexutils "github.com/rocket-pool/smartnode/shared/utils/hex"
+)
+
+// The only version of the interchange format in use
+const SlashingProtectionInterchangeVersion string = "5"
+
+// Slashing protection history following the EIP-3076 interchange format
+// (https://eips.ethereum.org/EIPS/eip-3076)
+type SlashingProtectionInterchange struct {
+	Metadata SlashingProtectionMetadata `json:"metadata"`
+	Data     []SlashingProtectionRecord `json:"data"`
+}
+
+type SlashingProtectionMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// The signing history of a single validator
+type SlashingProtectionRecord struct {
+	Pubkey             string                          `json:"pubkey"`
+	SignedBlocks       []SlashingProtectionBlock       `json:"signed_blocks"`
+	SignedAttestations []SlashingProtectionAttestation `json:"signed_attestations"`
+}
+
+// EIP-3076 encodes all integers as decimal strings
+type SlashingProtectionBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+type SlashingProtectionAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// Parse EIP-3076 slashing protection data and check that it's well-formed
+func ParseSlashingProtection(data []byte) (*SlashingProtectionInterchange, error) {
+	var interchange SlashingProtectionInterchange
+	if err := json.Unmarshal(data, &interchange); err != nil {
+		return nil, fmt.Errorf("error decoding slashing protection data: %w", err)
+	}
+	if interchange.Metadata.InterchangeFormatVersion != SlashingProtectionInterchangeVersion {
+		return nil, fmt.Errorf("unsupported slashing protection interchange format version [%s], expected [%s]", interchange.Metadata.InterchangeFormatVersion, SlashingProtectionInterchangeVersion)
+	}
+	if _, err := hexutil.Decode(interchange.Metadata.GenesisValidatorsRoot); err != nil {
+		return nil, fmt.Errorf("invalid genesis validators root [%s]: %w", interchange.Metadata.GenesisValidatorsRoot, err)
+	}
+	for _, record := range interchange.Data {
+		if _, err := types.HexToValidatorPubkey(hexutils.RemovePrefix(record.Pubkey)); err != nil {
+			return nil, fmt.Errorf("invalid pubkey in slashing protection data: %w", err)
+		}
+	}
+	return &interchange, nil
+}
+
+// Check if the data was produced on the chain with the provided genesis validators root
+func (i *SlashingProtectionInterchange) MatchesGenesisValidatorsRoot(genesisValidatorsRoot []byte) bool {
+	root, err := hexutil.Decode(i.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(root, genesisValidatorsRoot)
+}
+
+// Get the pubkeys of the validators with signing history in the data
+func (i *SlashingProtectionInterchange) GetPubkeys() []types.ValidatorPubkey {
+	pubkeys := make([]types.ValidatorPubkey, 0, len(i.Data))
+	for _, record := range i.Data {
+		// Pubkeys were already validated during parsing
+		pubkey, _ := types.HexToValidatorPubkey(hexutils.RemovePrefix(record.Pubkey))
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys
+}
+
+// The result of checking slashing protection data against the node's validators
+type SlashingProtectionCheck struct {
+	GenesisValidatorsRootMatches bool
+	RecordCount                  int
+	UnknownPubkeys               []types.ValidatorPubkey
+	MissingPubkeys               []types.ValidatorPubkey
+}
+
+// Check the data against the chain's genesis validators root and the node's validator pubkeys
+func (i *SlashingProtectionInterchange) Check(genesisValidatorsRoot []byte, nodePubkeys []types.ValidatorPubkey) SlashingProtectionCheck {
+	check := SlashingProtectionCheck{
+		GenesisValidatorsRootMatches: i.MatchesGenesisValidatorsRoot(genesisValidatorsRoot),
+		RecordCount:                  len(i.Data),
+		UnknownPubkeys:               []types.ValidatorPubkey{},
+		MissingPubkeys:               []types.ValidatorPubkey{},
+	}
+
+	nodePubkeyMap := make(map[types.ValidatorPubkey]bool, len(nodePubkeys))
+	for _, pubkey := range nodePubkeys {
+		nodePubkeyMap[pubkey] = false
+	}
+	for _, pubkey := range i.GetPubkeys() {
+		if _, exists := nodePubkeyMap[pubkey]; !exists {
+			check.UnknownPubkeys = append(check.UnknownPubkeys, pubkey)
+			continue
+		}
+		nodePubkeyMap[pubkey] = true
+	}
+	for _, pubkey := range nodePubkeys {
+		if !nodePubkeyMap[pubkey] {
+			check.MissingPubkeys = append(check.MissingPubkeys, pubkey)
+		}
+	}
+	return check
+}
+
+// Get an error explaining why the data can't be imported, or nil if it can.
+// Validators that are missing from the data don't prevent an import, since they may not have signed anything yet.
+func (c SlashingProtectionCheck) Validate() error {
+	if !c.GenesisValidatorsRootMatches {
+		return fmt.Errorf("the slashing protection data is for a different network")
+	}
+	if len(c.UnknownPubkeys) > 0 {
+		return fmt.Errorf("the slashing protection data contains %d validator(s) that don't belong to this node", len(c.UnknownPubkeys))
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rocket-pool/smartnode/bindings/utils/eth"
+	rprewards "github.com/rocket-pool/smartnode/shared/services/rewards"
+	"github.com/rocket-pool/smartnode/shared/services/rewards/ssz_types"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	colorGreen  string = "\033[32m"
+	colorYellow string = "\033[33m"
+)
+
+// The change in a single node's rewards between two rewards files
+type nodeRewardsDelta struct {
+	Address             common.Address `json:"address"`
+	OldCollateralRpl    *big.Int       `json:"oldCollateralRpl"`
+	NewCollateralRpl    *big.Int       `json:"newCollateralRpl"`
+	CollateralRplDelta  *big.Int       `json:"collateralRplDelta"`
+	OldOracleDaoRpl     *big.Int       `json:"oldOracleDaoRpl"`
+	NewOracleDaoRpl     *big.Int       `json:"newOracleDaoRpl"`
+	OracleDaoRplDelta   *big.Int       `json:"oracleDaoRplDelta"`
+	OldSmoothingPoolEth *big.Int       `json:"oldSmoothingPoolEth"`
+	NewSmoothingPoolEth *big.Int       `json:"newSmoothingPoolEth"`
+	SmoothingPoolDelta  *big.Int       `json:"smoothingPoolEthDelta"`
+}
+
+// The change in a single minipool's Smoothing Pool performance between two minipool performance files
+type minipoolPerformanceDelta struct {
+	Address                     common.Address `json:"address"`
+	OldEthEarned                *big.Int       `json:"oldEthEarned"`
+	NewEthEarned                *big.Int       `json:"newEthEarned"`
+	EthEarnedDelta              *big.Int       `json:"ethEarnedDelta"`
+	OldSuccessfulAttestations   uint64         `json:"oldSuccessfulAttestations"`
+	NewSuccessfulAttestations   uint64         `json:"newSuccessfulAttestations"`
+	SuccessfulAttestationsDelta int64          `json:"successfulAttestationsDelta"`
+	OldMissedAttestations       uint64         `json:"oldMissedAttestations"`
+	NewMissedAttestations       uint64         `json:"newMissedAttestations"`
+	MissedAttestationsDelta     int64          `json:"missedAttestationsDelta"`
+}
+
+// The change in a rewards file's totals
+type rewardsTotalsDelta struct {
+	Field string   `json:"field"`
+	Old   *big.Int `json:"old"`
+	New   *big.Int `json:"new"`
+	Delta *big.Int `json:"delta"`
+}
+
+// The full comparison of two rewards files
+type rewardsDiffReport struct {
+	OldSource string                     `json:"oldSource"`
+	NewSource string                     `json:"newSource"`
+	Totals    []rewardsTotalsDelta       `json:"totals"`
+	Nodes     []nodeRewardsDelta         `json:"nodes"`
+	Minipools []minipoolPerformanceDelta `json:"minipools"`
+}
+
+// One side of a comparison
+type diffSource struct {
+	name            string
+	rewardsFile     rprewards.IRewardsFile
+	performanceFile rprewards.IMinipoolPerformanceFile
+}
+
+// Compares two rewards files, either loaded from disk or generated for the same interval with two different rulesets
+func DiffTrees(c *cli.Context) error {
+	var oldSource, newSource *diffSource
+	var err error
+
+	oldRuleset := c.Uint64("old-ruleset")
+	newRuleset := c.Uint64("new-ruleset")
+	if oldRuleset != 0 || newRuleset != 0 {
+		if oldRuleset == 0 || newRuleset == 0 {
+			return fmt.Errorf("both --old-ruleset and --new-ruleset must be provided to compare rulesets")
+		}
+		if c.Args().Len() != 0 {
+			return fmt.Errorf("rewards files can't be provided when comparing rulesets")
+		}
+		oldSource, newSource, err = generateDiffSources(c, oldRuleset, newRuleset)
+		if err != nil {
+			return err
+		}
+	} else {
+		if c.Args().Len() != 2 {
+			return fmt.Errorf("diff requires two rewards files, or --old-ruleset and --new-ruleset")
+		}
+		oldSource, err = loadDiffSource(c.Args().Get(0), c.String("old-performance"))
+		if err != nil {
+			return err
+		}
+		newSource, err = loadDiffSource(c.Args().Get(1), c.String("new-performance"))
+		if err != nil {
+			return err
+		}
+	}
+
+	if oldSource.rewardsFile.GetIndex() != newSource.rewardsFile.GetIndex() {
+		fmt.Printf("%sWARNING: comparing rewards files from different intervals (%d and %d).%s\n", colorYellow, oldSource.rewardsFile.GetIndex(), newSource.rewardsFile.GetIndex(), colorReset)
+	}
+
+	report := diffRewards(oldSource, newSource)
+	printDiffReport(report, c.Int("top"))
+
+	// Save the full report if requested
+	reportPath := c.String("report")
+	if reportPath != "" {
+		reportBytes, err := json.MarshalIndent(report, "", "\t")
+		if err != nil {
+			return fmt.Errorf("error serializing diff report: %w", err)
+		}
+		err = os.WriteFile(reportPath, reportBytes, 0644)
+		if err != nil {
+			return fmt.Errorf("error saving diff report to %s: %w", reportPath, err)
+		}
+		fmt.Printf("Saved the full diff report to %s\n", reportPath)
+	}
+
+	return nil
+}
+
+// Generates the targeted interval under both rulesets
+func generateDiffSources(c *cli.Context, oldRuleset uint64, newRuleset uint64) (*diffSource, *diffSource, error) {
+	generator, err := newTreeGenerator(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := generator.getTreegenArgs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error compiling treegen arguments: %w", err)
+	}
+	treegen, err := generator.getGenerator(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make([]*diffSource, 0, 2)
+	for _, ruleset := range []uint64{oldRuleset, newRuleset} {
+		generator.log.Printlnf("Generating interval %d with ruleset v%d...", args.index, ruleset)
+		start := time.Now()
+		result, err := treegen.GenerateTreeWithRuleset(ruleset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error generating Merkle tree with ruleset v%d: %w", ruleset, err)
+		}
+		generator.log.Printlnf("Finished in %s", time.Since(start).String())
+		sources = append(sources, &diffSource{
+			name:            fmt.Sprintf("interval %d, ruleset v%d", args.index, ruleset),
+			rewardsFile:     result.RewardsFile,
+			performanceFile: result.MinipoolPerformanceFile,
+		})
+	}
+	return sources[0], sources[1], nil
+}
+
+// Loads a rewards file and, optionally, its minipool performance file from disk
+func loadDiffSource(rewardsPath string, performancePath string) (*diffSource, error) {
+	source := &diffSource{
+		name: rewardsPath,
+	}
+
+	fileBytes, err := readDiffFile(rewardsPath)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(fileBytes, ssz_types.Magic[:]) {
+		source.rewardsFile, err = ssz_types.ParseSSZFile(fileBytes)
+	} else {
+		source.rewardsFile, err = rprewards.DeserializeRewardsFile(fileBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rewards file %s: %w", rewardsPath, err)
+	}
+
+	if performancePath == "" {
+		return source, nil
+	}
+	fileBytes, err = readDiffFile(performancePath)
+	if err != nil {
+		return nil, err
+	}
+	source.performanceFile, err = rprewards.DeserializeMinipoolPerformanceFile(fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing minipool performance file %s: %w", performancePath, err)
+	}
+	return source, nil
+}
+
+// Reads a file from disk, decompressing it if it was compressed with zstd
+func readDiffFile(path string) ([]byte, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	if !strings.HasSuffix(path, ".zst") {
+		return fileBytes, nil
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating zstd decoder: %w", err)
+	}
+	defer decoder.Close()
+	fileBytes, err = decoder.DecodeAll(fileBytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing %s: %w", path, err)
+	}
+	return fileBytes, nil
+}
+
+// Builds the per-node and per-minipool deltas between two rewards files
+func diffRewards(oldSource *diffSource, newSource *diffSource) *rewardsDiffReport {
+	oldFile := oldSource.rewardsFile
+	newFile := newSource.rewardsFile
+	report := &rewardsDiffReport{
+		OldSource: oldSource.name,
+		NewSource: newSource.name,
+		Nodes:     []nodeRewardsDelta{},
+		Minipools: []minipoolPerformanceDelta{},
+	}
+
+	// Totals
+	addTotal := func(field string, oldValue *big.Int, newValue *big.Int) {
+		report.Totals = append(report.Totals, rewardsTotalsDelta{
+			Field: field,
+			Old:   oldValue,
+			New:   newValue,
+			Delta: big.NewInt(0).Sub(newValue, oldValue),
+		})
+	}
+	addTotal("Collateral RPL", oldFile.GetTotalCollateralRpl(), newFile.GetTotalCollateralRpl())
+	addTotal("Oracle DAO RPL", oldFile.GetTotalOracleDaoRpl(), newFile.GetTotalOracleDaoRpl())
+	addTotal("Protocol DAO RPL", oldFile.GetTotalProtocolDaoRpl(), newFile.GetTotalProtocolDaoRpl())
+	addTotal("Node Operator SP ETH", oldFile.GetTotalNodeOperatorSmoothingPoolEth(), newFile.GetTotalNodeOperatorSmoothingPoolEth())
+	addTotal("Pool Staker SP ETH", oldFile.GetTotalPoolStakerSmoothingPoolEth(), newFile.GetTotalPoolStakerSmoothingPoolEth())
+
+	// Nodes
+	for _, address := range mergeAddresses(oldFile.GetNodeAddresses(), newFile.GetNodeAddresses()) {
+		delta := nodeRewardsDelta{
+			Address:             address,
+			OldCollateralRpl:    oldFile.GetNodeCollateralRpl(address),
+			NewCollateralRpl:    newFile.GetNodeCollateralRpl(address),
+			OldOracleDaoRpl:     oldFile.GetNodeOracleDaoRpl(address),
+			NewOracleDaoRpl:     newFile.GetNodeOracleDaoRpl(address),
+			OldSmoothingPoolEth: oldFile.GetNodeSmoothingPoolEth(address),
+			NewSmoothingPoolEth: newFile.GetNodeSmoothingPoolEth(address),
+		}
+		delta.CollateralRplDelta = big.NewInt(0).Sub(delta.NewCollateralRpl, delta.OldCollateralRpl)
+		delta.OracleDaoRplDelta = big.NewInt(0).Sub(delta.NewOracleDaoRpl, delta.OldOracleDaoRpl)
+		delta.SmoothingPoolDelta = big.NewInt(0).Sub(delta.NewSmoothingPoolEth, delta.OldSmoothingPoolEth)
+		if delta.CollateralRplDelta.Sign() == 0 && delta.OracleDaoRplDelta.Sign() == 0 && delta.SmoothingPoolDelta.Sign() == 0 {
+			continue
+		}
+		report.Nodes = append(report.Nodes, delta)
+	}
+
+	// Minipools
+	if oldSource.performanceFile == nil || newSource.performanceFile == nil {
+		return report
+	}
+	oldPerformance := getPerformanceByAddress(oldSource.performanceFile)
+	newPerformance := getPerformanceByAddress(newSource.performanceFile)
+	for _, address := range mergeAddresses(oldSource.performanceFile.GetMinipoolAddresses(), newSource.performanceFile.GetMinipoolAddresses()) {
+		oldValue := getPerformance(oldPerformance, address)
+		newValue := getPerformance(newPerformance, address)
+		delta := minipoolPerformanceDelta{
+			Address:                     address,
+			OldEthEarned:                oldValue.EthEarned,
+			NewEthEarned:                newValue.EthEarned,
+			EthEarnedDelta:              big.NewInt(0).Sub(newValue.EthEarned, oldValue.EthEarned),
+			OldSuccessfulAttestations:   oldValue.SuccessfulAttestations,
+			NewSuccessfulAttestations:   newValue.SuccessfulAttestations,
+			SuccessfulAttestationsDelta: int64(newValue.SuccessfulAttestations) - int64(oldValue.SuccessfulAttestations),
+			OldMissedAttestations:       oldValue.MissedAttestations,
+			NewMissedAttestations:       newValue.MissedAttestations,
+			MissedAttestationsDelta:     int64(newValue.MissedAttestations) - int64(oldValue.MissedAttestations),
+		}
+		if delta.EthEarnedDelta.Sign() == 0 && delta.SuccessfulAttestationsDelta == 0 && delta.MissedAttestationsDelta == 0 {
+			continue
+		}
+		report.Minipools = append(report.Minipools, delta)
+	}
+
+	return report
+}
+
+// Gets the Smoothing Pool performance of every minipool in a performance file, keyed by address
+func getPerformanceByAddress(performanceFile rprewards.IMinipoolPerformanceFile) map[common.Address]rprewards.ValidatorPerformance {
+	performance, _ := rprewards.GetValidatorPerformance(performanceFile, performanceFile.GetMinipoolAddresses())
+	byAddress := make(map[common.Address]rprewards.ValidatorPerformance, len(performance))
+	for _, validator := range performance {
+		byAddress[validator.Address] = validator
+	}
+	return byAddress
+}
+
+// Gets a minipool's performance, treating minipools that aren't in the file as having earned nothing
+func getPerformance(performance map[common.Address]rprewards.ValidatorPerformance, address common.Address) rprewards.ValidatorPerformance {
+	validator, exists := performance[address]
+	if !exists {
+		return rprewards.ValidatorPerformance{
+			Address:   address,
+			EthEarned: big.NewInt(0),
+		}
+	}
+	return validator
+}
+
+// Combines two lists of addresses into a single sorted list without duplicates
+func mergeAddresses(first []common.Address, second []common.Address) []common.Address {
+	seen := map[common.Address]bool{}
+	addresses := []common.Address{}
+	for _, list := range [][]common.Address{first, second} {
+		for _, address := range list {
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Cmp(addresses[j]) < 0
+	})
+	return addresses
+}
+
+// Prints the aggregate totals, a summary of what changed, and the largest movers in each category
+func printDiffReport(report *rewardsDiffReport, top int) {
+	fmt.Printf("Comparing %s (old) to %s (new)\n\n", report.OldSource, report.NewSource)
+
+	fmt.Println("=== Totals ===")
+	for _, total := range report.Totals {
+		fmt.Printf("%-22s %.6f -> %.6f (%s)\n", total.Field+":", eth.WeiToEth(total.Old), eth.WeiToEth(total.New), formatWeiDelta(total.Delta))
+	}
+	fmt.Println()
+
+	fmt.Printf("%d nodes and %d minipools changed.\n\n", len(report.Nodes), len(report.Minipools))
+	if top <= 0 {
+		return
+	}
+
+	printTopNodes := func(title string, getDelta func(delta nodeRewardsDelta) *big.Int) {
+		nodes := make([]nodeRewardsDelta, 0, len(report.Nodes))
+		for _, node := range report.Nodes {
+			if getDelta(node).Sign() != 0 {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) == 0 {
+			return
+		}
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return big.NewInt(0).Abs(getDelta(nodes[i])).Cmp(big.NewInt(0).Abs(getDelta(nodes[j]))) > 0
+		})
+		fmt.Printf("=== Largest %s movers ===\n", title)
+		for i := 0; i < len(nodes) && i < top; i++ {
+			fmt.Printf("%s  %s\n", nodes[i].Address.Hex(), formatWeiDelta(getDelta(nodes[i])))
+		}
+		fmt.Println()
+	}
+	printTopNodes("collateral RPL", func(delta nodeRewardsDelta) *big.Int { return delta.CollateralRplDelta })
+	printTopNodes("Oracle DAO RPL", func(delta nodeRewardsDelta) *big.Int { return delta.OracleDaoRplDelta })
+	printTopNodes("Smoothing Pool ETH", func(delta nodeRewardsDelta) *big.Int { return delta.SmoothingPoolDelta })
+
+	if len(report.Minipools) == 0 {
+		return
+	}
+	minipools := make([]minipoolPerformanceDelta, len(report.Minipools))
+	copy(minipools, report.Minipools)
+	sort.SliceStable(minipools, func(i, j int) bool {
+		return big.NewInt(0).Abs(minipools[i].EthEarnedDelta).Cmp(big.NewInt(0).Abs(minipools[j].EthEarnedDelta)) > 0
+	})
+	fmt.Println("=== Largest minipool movers ===")
+	for i := 0; i < len(minipools) && i < top; i++ {
+		minipool := minipools[i]
+		fmt.Printf("%s  %s, attestations %d -> %d successful, %d -> %d missed\n",
+			minipool.Address.Hex(), formatWeiDelta(minipool.EthEarnedDelta),
+			minipool.OldSuccessfulAttestations, minipool.NewSuccessfulAttestations,
+			minipool.OldMissedAttestations, minipool.NewMissedAttestations)
+	}
+	fmt.Println()
+}
+
+// Formats a change in wei as a signed, colored amount in whole units
+func formatWeiDelta(delta *big.Int) string {
+	switch delta.Sign() {
+	case 1:
+		return fmt.Sprintf("%s+%.6f%s", colorGreen, eth.WeiToEth(delta), colorReset)
+	case -1:
+		return fmt.Sprintf("%s%.6f%s", colorRed, eth.WeiToEth(delta), colorReset)
+	default:
+		return "no change"
+	}
+}
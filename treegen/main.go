@@ -102,6 +102,27 @@ func main() {
 			Usage:   "If Enabled, a file containing the voting power breakdown of all nodes will be saved to the output directory.",
 			Value:   false,
 		},
+		&cli.StringFlag{
+			Name:    "spill-dir",
+			Aliases: []string{"sd"},
+			Usage:   "If provided, attestation duty records will be spilled to temporary files in this directory during generation instead of being kept in memory. This greatly reduces memory usage for large intervals at the cost of some speed; the generated files are identical.",
+		},
+		&cli.StringFlag{
+			Name:    "checkpoint-dir",
+			Aliases: []string{"cd"},
+			Usage:   "If provided, the progress of attestation processing will be periodically saved to this directory. If generation fails partway through (e.g. because the Beacon Node went down), running treegen again with the same directory will resume from the last checkpoint instead of starting over.",
+		},
+		&cli.StringFlag{
+			Name:    "bn-cache-dir",
+			Aliases: []string{"bc"},
+			Usage:   "If provided, finalized Beacon Node responses (committees, blocks, attestations and validator statuses) will be cached in this directory, so regenerating an interval doesn't have to download them again.",
+		},
+		&cli.Uint64Flag{
+			Name:    "bn-cache-size",
+			Aliases: []string{"bs"},
+			Usage:   "The maximum size of the Beacon Node response cache in MB. The least recently used responses are evicted once it's full.",
+			Value:   20480,
+		},
 		&cli.StringFlag{
 			Name:    "cpuprofile",
 			Aliases: []string{"c"},
@@ -119,6 +140,43 @@ func main() {
 		},
 	}
 
+	// Set application commands
+	app.Commands = []*cli.Command{
+		{
+			Name:      "diff",
+			Usage:     "Compare two rewards files, or one interval generated with two different rulesets, and report the change in each node's and minipool's rewards.",
+			ArgsUsage: "[old-rewards-file new-rewards-file]",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "old-performance",
+					Usage: "The minipool performance file that goes with the old rewards file, used to compare minipool attestations and Smoothing Pool earnings.",
+				},
+				&cli.StringFlag{
+					Name:  "new-performance",
+					Usage: "The minipool performance file that goes with the new rewards file, used to compare minipool attestations and Smoothing Pool earnings.",
+				},
+				&cli.Uint64Flag{
+					Name:  "old-ruleset",
+					Usage: "Instead of reading rewards files, generate the interval selected with -i / -t using this ruleset as the old side of the comparison. Requires --new-ruleset.",
+				},
+				&cli.Uint64Flag{
+					Name:  "new-ruleset",
+					Usage: "Instead of reading rewards files, generate the interval selected with -i / -t using this ruleset as the new side of the comparison. Requires --old-ruleset.",
+				},
+				&cli.IntFlag{
+					Name:  "top",
+					Usage: "The number of largest movers to show in each category.",
+					Value: 10,
+				},
+				&cli.StringFlag{
+					Name:  "report",
+					Usage: "Path to which to save the full per-node and per-minipool report as JSON.",
+				},
+			},
+			Action: DiffTrees,
+		},
+	}
+
 	app.Action = func(c *cli.Context) error {
 		cpuprofile := c.String("cpuprofile")
 		if cpuprofile != "" {
@@ -101,10 +101,32 @@ type treeGenerator struct {
 	prettyPrint         bool
 	ruleset             uint64
 	generateVotingPower bool
+	dutySpillFolder     string
+	checkpointFolder    string
 }
 
 // Generates a new rewards tree based on the command line flags
 func GenerateTree(c *cli.Context) error {
+	generator, err := newTreeGenerator(c)
+	if err != nil {
+		return err
+	}
+
+	// Run the tree generation or the rETH SP approximation
+	if c.Bool("approximate-only") {
+		return generator.approximateRethSpRewards()
+	}
+
+	// Print the network info and exit if requested
+	if c.Bool("network-info") {
+		return generator.printNetworkInfo()
+	}
+
+	return generator.generateTree()
+}
+
+// Creates a tree generator from the command line flags, targeting the requested interval
+func newTreeGenerator(c *cli.Context) (*treeGenerator, error) {
 	// Configure
 	configureHTTP()
 
@@ -117,28 +139,36 @@ func GenerateTree(c *cli.Context) error {
 	// URL acquisiton
 	ecUrl := c.String("ec-endpoint")
 	if ecUrl == "" {
-		return fmt.Errorf("ec-endpoint must be provided")
+		return nil, fmt.Errorf("ec-endpoint must be provided")
 	}
 	bnUrl := c.String("bn-endpoint")
 	if ecUrl == "" {
-		return fmt.Errorf("bn-endpoint must be provided")
+		return nil, fmt.Errorf("bn-endpoint must be provided")
 	}
 
 	// Create the EC and BN clients
 	ec, err := services.NewEthClient(ecUrl)
 	if err != nil {
-		return fmt.Errorf("error connecting to the EC: %w", err)
+		return nil, fmt.Errorf("error connecting to the EC: %w", err)
+	}
+	var bn beacon.Client = client.NewStandardHttpClient(bnUrl)
+	cacheDir := c.String("bn-cache-dir")
+	if cacheDir != "" {
+		bn, err = client.NewCachingClient(bn, cacheDir, c.Uint64("bn-cache-size")*1024*1024)
+		if err != nil {
+			return nil, fmt.Errorf("error creating the BN response cache: %w", err)
+		}
+		logger.Printlnf("Caching finalized BN responses in %s.", cacheDir)
 	}
-	bn := client.NewStandardHttpClient(bnUrl)
 	beaconConfig, err := bn.GetEth2Config()
 	if err != nil {
-		return fmt.Errorf("error getting beacon config from the BN at %s - %w", bnUrl, err)
+		return nil, fmt.Errorf("error getting beacon config from the BN at %s - %w", bnUrl, err)
 	}
 
 	// Check which network we're on via the BN
 	depositContract, err := bn.GetEth2DepositContract()
 	if err != nil {
-		return fmt.Errorf("error getting deposit contract from the BN: %w", err)
+		return nil, fmt.Errorf("error getting deposit contract from the BN: %w", err)
 	}
 	var network cfgtypes.Network
 	switch depositContract.ChainID {
@@ -149,7 +179,7 @@ func GenerateTree(c *cli.Context) error {
 		network = cfgtypes.Network_Testnet
 		logger.Printlnf("Beacon node is configured for Testnet.")
 	default:
-		return fmt.Errorf("your Beacon node is configured for an unknown network with Chain ID [%d]", depositContract.ChainID)
+		return nil, fmt.Errorf("your Beacon node is configured for an unknown network with Chain ID [%d]", depositContract.ChainID)
 	}
 
 	// Create a new config on the proper network
@@ -160,14 +190,14 @@ func GenerateTree(c *cli.Context) error {
 	storageContract := cfg.Smartnode.GetStorageAddress()
 	rp, err := rocketpool.NewRocketPool(ec, common.HexToAddress(storageContract))
 	if err != nil {
-		return fmt.Errorf("error creating Rocket Pool wrapper: %w", err)
+		return nil, fmt.Errorf("error creating Rocket Pool wrapper: %w", err)
 	}
 
 	// Create the NetworkStateManager
 	mgr := state.NewNetworkStateManager(rp, cfg.Smartnode.GetStateManagerContracts(), bn, &logger)
 
 	// Create the generator
-	generator := treeGenerator{
+	generator := &treeGenerator{
 		log:                 &logger,
 		errLog:              &errLogger,
 		rp:                  rprewards.NewRewardsExecutionClient(rp),
@@ -180,24 +210,16 @@ func GenerateTree(c *cli.Context) error {
 		prettyPrint:         c.Bool("pretty-print"),
 		ruleset:             c.Uint64("ruleset"),
 		generateVotingPower: c.Bool("generate-voting-power"),
+		dutySpillFolder:     c.String("spill-dir"),
+		checkpointFolder:    c.String("checkpoint-dir"),
 	}
 
 	// initialize the generator targets
 	if err := generator.setTargets(interval, targetEpoch); err != nil {
-		return fmt.Errorf("error setting the targeted consensus epoch and block: %w", err)
-	}
-
-	// Run the tree generation or the rETH SP approximation
-	if c.Bool("approximate-only") {
-		return generator.approximateRethSpRewards()
+		return nil, fmt.Errorf("error setting the targeted consensus epoch and block: %w", err)
 	}
 
-	// Print the network info and exit if requested
-	if c.Bool("network-info") {
-		return generator.printNetworkInfo()
-	}
-
-	return generator.generateTree()
+	return generator, nil
 }
 
 func (g *treeGenerator) getTreegenArgs() (*treegenArguments, error) {
@@ -533,6 +555,12 @@ func (g *treeGenerator) getGenerator(args *treegenArguments) (*rprewards.TreeGen
 	if err != nil {
 		return nil, fmt.Errorf("error creating tree generator: %w", err)
 	}
+	if g.dutySpillFolder != "" {
+		out.SetDutySpillFolder(g.dutySpillFolder)
+	}
+	if g.checkpointFolder != "" {
+		out.SetCheckpointFolder(g.checkpointFolder)
+	}
 
 	return out, nil
 }